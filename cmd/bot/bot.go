@@ -2,52 +2,98 @@ package main
 
 import (
 	"log"
+	"log/slog"
 	"os"
 
 	"github.com/abelzeko/water-bot/internal/api"
+	"github.com/abelzeko/water-bot/internal/config"
+	"github.com/abelzeko/water-bot/internal/health"
 	"github.com/abelzeko/water-bot/internal/integration"
-	"github.com/abelzeko/water-bot/internal/integration/openai" // Updated import
+	"github.com/abelzeko/water-bot/internal/integration/openai"
+	"github.com/abelzeko/water-bot/internal/logging"
 	"github.com/abelzeko/water-bot/internal/repository"
+	"github.com/abelzeko/water-bot/internal/scheduler"
 	"github.com/abelzeko/water-bot/internal/usecases"
 )
 
 func main() {
-	// Configure logging
-	log.SetOutput(os.Stdout)
+	// Read and validate the whole configuration up front, reporting every problem at once
 	log.SetFlags(log.Ldate | log.Ltime | log.Lshortfile)
-	log.Println("Starting Water Bot...")
+	cfg, err := config.Load(config.RequireBotToken())
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
+	// Configure logging, LOG_FORMAT=json switches to structured output and LOG_LEVEL sets the minimum level
+	if err := logging.Setup(cfg.LogFormat, cfg.LogLevel, os.Stdout); err != nil {
+		log.Fatalf("Failed to configure logging: %v", err)
+	}
+	slog.Info("Starting Water Bot...")
 
-	// Initialize OpenAI Service
-	openAIService, err := openai.NewOpenAIService() // Updated constructor call
+	// Initialize OpenAI Service, natural-language mode is optional
+	openAIService, err := openai.NewOpenAIService(openai.Config{
+		APIKey:       cfg.OpenAIAPIKey,
+		Model:        cfg.OpenAIModel,
+		Temperature:  cfg.OpenAITemperature,
+		CacheSize:    cfg.OpenAICacheSize,
+		CacheTTL:     cfg.OpenAICacheTTL,
+		SystemPrompt: cfg.OpenAISystemPrompt,
+	})
 	if err != nil {
-		log.Fatalf("Failed to initialize OpenAI service: %v", err)
+		slog.Warn("Natural-language mode is disabled", "error", err)
+		openAIService = nil
 	}
 
-	// Initialize repository
-	repo, err := repository.NewSQLiteRiverRepository("")
+	// Initialize repository, SQLite unless DB_DRIVER selects Postgres
+	repo, err := repository.NewRiverRepository(cfg.DBDriver, cfg.PostgresDSN, cfg.DBPath)
 	if err != nil {
-		log.Fatalf("Failed to initialize repository: %v", err)
+		fatal("Failed to initialize repository", err)
 	}
 	defer repo.Close()
 
 	// Initialize scraper
-	scraper := integration.NewWaterScraper("")
+	scraper := integration.NewWaterScraperWithConfig(integration.ScraperConfig(cfg.Sources))
 
 	// Initialize use case with OpenAI service
 	useCase := usecases.NewRiverUseCase(repo, scraper, openAIService)
-
-	// Get the bot token from environment variable
-	botToken := os.Getenv("TELEGRAM_BOT_TOKEN")
-	if botToken == "" {
-		log.Fatal("TELEGRAM_BOT_TOKEN environment variable is not set")
-	}
+	useCase.SetStaleAfter(cfg.StaleAfter)
+	useCase.SetReadingBounds(usecases.ReadingBounds{
+		WaterLevel: usecases.Bounds(cfg.LevelRange),
+		WaterTemp:  usecases.Bounds(cfg.TempRange),
+	})
+	useCase.SetDefaultRiver(cfg.DefaultRiver)
+	useCase.SetRefreshWait(cfg.RefreshWait)
+	useCase.SetSummaryThreshold(cfg.SummaryThreshold)
 
 	// Initialize Telegram bot
-	telegramBot, err := api.NewTelegramBot(botToken, useCase)
+	telegramBot, err := api.NewTelegramBot(cfg.TelegramBotToken, api.BotConfig{
+		AdminChatIDs: cfg.AdminChatIDs,
+		RateLimit:    cfg.RateLimit,
+		RateBurst:    cfg.RateBurst,
+	}, useCase)
 	if err != nil {
-		log.Fatalf("Failed to initialize Telegram bot: %v", err)
+		fatal("Failed to initialize Telegram bot", err)
+	}
+
+	// Expose the health endpoint, HEALTH_ADDR overrides the listen address
+	checker := health.NewChecker()
+	checker.AddCheck("repository", repo.Ping)
+	checker.AddCheck("telegram", telegramBot.CheckAuthorized)
+	health.Serve(cfg.HealthAddr, checker)
+
+	// Optionally run the scraper's refresh schedule in this process, for single-binary deployments
+	if cfg.RunScraperInBot {
+		if _, err := scheduler.Start(useCase, cfg.RefreshSchedule, checker); err != nil {
+			fatal("Failed to schedule data refresh", err)
+		}
 	}
 
 	// Start the bot
 	telegramBot.Start()
 }
+
+// fatal logs err and exits, for failures after logging is configured
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
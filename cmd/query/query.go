@@ -0,0 +1,205 @@
+// Command query prints the river data stored in the bot's SQLite database, for
+// debugging and scripting without writing SQL.
+//
+// Usage:
+//
+//	query [-db path] [-json] rivers
+//	query [-db path] [-json] river NAME
+//	query [-db path] [-json] latest
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/config"
+	"github.com/abelzeko/water-bot/internal/entities"
+	"github.com/abelzeko/water-bot/internal/repository"
+	"github.com/abelzeko/water-bot/internal/usecases"
+)
+
+// usage describes the commands, printed when they are missing or unknown
+const usage = `Usage: query [-db path] [-json] <command>
+
+Commands:
+  rivers       List the available rivers
+  river NAME   Show the current readings of a river
+  latest       Show the latest reading of every station
+
+Flags:
+`
+
+// errUsage is returned for a missing or unknown command, after printing the usage
+var errUsage = errors.New("invalid usage")
+
+// reading is the JSON form of a stored reading
+type reading struct {
+	River      string    `json:"river"`
+	Station    string    `json:"station"`
+	WaterLevel string    `json:"water_level"`
+	WaterTemp  string    `json:"water_temp,omitempty"`
+	Discharge  string    `json:"discharge,omitempty"`
+	Tendency   string    `json:"tendency,omitempty"`
+	Source     string    `json:"source,omitempty"`
+	URL        string    `json:"url,omitempty"`
+	GaugeZero  string    `json:"gauge_zero,omitempty"`
+	Timestamp  time.Time `json:"timestamp"`
+	Stale      bool      `json:"stale"`
+}
+
+func main() {
+	// Progress logs go to stderr so stdout only carries the result
+	log.SetOutput(os.Stderr)
+	log.SetFlags(log.Ldate | log.Ltime | log.Lshortfile)
+
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		if !errors.Is(err, errUsage) {
+			fmt.Fprintf(os.Stderr, "query: %v\n", err)
+		}
+		os.Exit(2)
+	}
+}
+
+// run executes one query command with its arguments, writing the result to stdout
+func run(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("query", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	dbPath := flags.String("db", "", "SQLite database path, defaults to DB_PATH or data/riverdata.db")
+	asJSON := flags.Bool("json", false, "Print machine-readable JSON instead of text")
+	flags.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errUsage
+	}
+
+	// Check the command before opening, which would create a missing database
+	command, rest := flags.Arg(0), flags.Args()[1:]
+	switch {
+	case command != "rivers" && command != "river" && command != "latest":
+		fmt.Fprintf(stderr, "Unknown command %q\n\n", command)
+		flags.Usage()
+		return errUsage
+	case command == "river" && len(rest) == 0:
+		flags.Usage()
+		return errUsage
+	}
+
+	if *dbPath == "" {
+		*dbPath = config.DBPath()
+	}
+	repo, err := repository.NewSQLiteRiverRepository(*dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %v", err)
+	}
+	defer repo.Close()
+	useCase := usecases.NewRiverUseCase(repo, nil, nil)
+
+	switch command {
+	case "rivers":
+		return queryRivers(useCase, stdout, *asJSON)
+	case "river":
+		return queryRiver(useCase, strings.Join(rest, " "), stdout, *asJSON)
+	default:
+		return queryLatest(useCase, stdout, *asJSON)
+	}
+}
+
+// queryRivers prints the available rivers, one per line
+func queryRivers(useCase *usecases.RiverUseCase, w io.Writer, asJSON bool) error {
+	rivers, err := useCase.GetAvailableRivers()
+	if err != nil {
+		return err
+	}
+	if asJSON {
+		if rivers == nil {
+			rivers = []string{}
+		}
+		return writeJSON(w, rivers)
+	}
+	for _, river := range rivers {
+		if _, err := fmt.Fprintln(w, river); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// queryRiver prints the current readings of a river as the bot formats them
+func queryRiver(useCase *usecases.RiverUseCase, name string, w io.Writer, asJSON bool) error {
+	riverData, err := useCase.GetRiverDataByName(name)
+	if err != nil {
+		return err
+	}
+	if len(riverData) == 0 {
+		return fmt.Errorf("no information found for river %q", name)
+	}
+	if asJSON {
+		return writeJSON(w, toReadings(riverData))
+	}
+	_, err = fmt.Fprint(w, useCase.FormatRiverInfo(riverData))
+	return err
+}
+
+// queryLatest prints the latest reading of every station as a table
+func queryLatest(useCase *usecases.RiverUseCase, w io.Writer, asJSON bool) error {
+	latest, err := useCase.GetLatestReadings()
+	if err != nil {
+		return err
+	}
+	if asJSON {
+		return writeJSON(w, toReadings(latest))
+	}
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "RIVER\tSTATION\tLEVEL (cm)\tTEMP (°C)\tTENDENCY\tUPDATED")
+	for _, rd := range latest {
+		updated := rd.Timestamp.Format("2006-01-02 15:04 MST")
+		if rd.Stale {
+			updated += " (outdated)"
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\n", rd.River, rd.Station, rd.WaterLevel, rd.WaterTemp, rd.Tendency.Label(), updated)
+	}
+	return table.Flush()
+}
+
+// toReadings converts readings to their JSON form
+func toReadings(data []entities.RiverData) []reading {
+	result := make([]reading, 0, len(data))
+	for _, rd := range data {
+		result = append(result, reading{
+			River:      rd.River,
+			Station:    rd.Station,
+			WaterLevel: rd.WaterLevel,
+			WaterTemp:  rd.WaterTemp,
+			Discharge:  rd.Discharge,
+			Tendency:   string(rd.Tendency),
+			Source:     rd.Source,
+			URL:        rd.URL,
+			GaugeZero:  rd.GaugeZero,
+			Timestamp:  rd.Timestamp,
+			Stale:      rd.Stale,
+		})
+	}
+	return result
+}
+
+// writeJSON writes v as indented JSON
+func writeJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
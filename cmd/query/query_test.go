@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+	"github.com/abelzeko/water-bot/internal/repository"
+)
+
+// newTestDB creates a SQLite database with readings of two rivers and returns its path
+func newTestDB(t *testing.T) string {
+	t.Helper()
+	dbPath := filepath.Join(t.TempDir(), "riverdata.db")
+	repo, err := repository.NewSQLiteRiverRepository(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to initialize repository: %v", err)
+	}
+	defer repo.Close()
+
+	recorded := time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC)
+	err = repo.SaveRiverData([]entities.RiverData{
+		{River: "ДУНАВ", Station: "Земун", WaterLevel: "310", WaterTemp: "12.5", Tendency: entities.Rising, Timestamp: recorded.Add(-time.Hour)},
+		{River: "ДУНАВ", Station: "Земун", WaterLevel: "320", WaterTemp: "12.7", Tendency: entities.Rising, Timestamp: recorded},
+		{River: "ДРИНА", Station: "Радаљ", WaterLevel: "142", Timestamp: recorded},
+	})
+	if err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+	return dbPath
+}
+
+// TestRunCommands verifies each command prints the stored data as text
+func TestRunCommands(t *testing.T) {
+	dbPath := newTestDB(t)
+
+	tests := []struct {
+		args    []string
+		want    []string
+		wantNot []string
+	}{
+		{args: []string{"rivers"}, want: []string{"ДРИНА\nДУНАВ\n"}},
+		{args: []string{"river", "ДУНАВ"}, want: []string{"Information for river ДУНАВ", "📍 Station: Земун", "💧 Water Level: 320 cm"}, wantNot: []string{"310"}},
+		{args: []string{"river", "danube"}, want: []string{"Information for river ДУНАВ"}},
+		{args: []string{"latest"}, want: []string{"RIVER", "ДРИНА  ", "Радаљ", "320", "rising"}, wantNot: []string{"310"}},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		if err := run(append([]string{"-db", dbPath}, tt.args...), &stdout, &stderr); err != nil {
+			t.Errorf("%v: unexpected error %v, stderr:\n%s", tt.args, err, stderr.String())
+			continue
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(stdout.String(), want) {
+				t.Errorf("%v: expected %q in:\n%s", tt.args, want, stdout.String())
+			}
+		}
+		for _, unwanted := range tt.wantNot {
+			if strings.Contains(stdout.String(), unwanted) {
+				t.Errorf("%v: expected no %q in:\n%s", tt.args, unwanted, stdout.String())
+			}
+		}
+	}
+}
+
+// TestRunJSON verifies -json prints machine-readable readings
+func TestRunJSON(t *testing.T) {
+	dbPath := newTestDB(t)
+
+	var stdout, stderr bytes.Buffer
+	if err := run([]string{"-db", dbPath, "-json", "latest"}, &stdout, &stderr); err != nil {
+		t.Fatalf("Unexpected error %v, stderr:\n%s", err, stderr.String())
+	}
+	var readings []reading
+	if err := json.Unmarshal(stdout.Bytes(), &readings); err != nil {
+		t.Fatalf("Expected JSON output, got %v:\n%s", err, stdout.String())
+	}
+	if len(readings) != 2 || readings[0].River != "ДРИНА" || readings[1].WaterLevel != "320" || readings[1].Tendency != "rising" {
+		t.Errorf("Expected the latest reading of both stations, got %+v", readings)
+	}
+
+	stdout.Reset()
+	if err := run([]string{"-db", dbPath, "-json", "rivers"}, &stdout, &stderr); err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	var rivers []string
+	if err := json.Unmarshal(stdout.Bytes(), &rivers); err != nil || len(rivers) != 2 {
+		t.Errorf("Expected a JSON list of 2 rivers, got %v:\n%s", err, stdout.String())
+	}
+}
+
+// TestRunErrors verifies bad usage and unknown rivers are reported
+func TestRunErrors(t *testing.T) {
+	dbPath := newTestDB(t)
+
+	for _, args := range [][]string{{}, {"bogus"}, {"river"}} {
+		var stdout, stderr bytes.Buffer
+		err := run(append([]string{"-db", dbPath}, args...), &stdout, &stderr)
+		if !errors.Is(err, errUsage) || !strings.Contains(stderr.String(), "Usage: query") {
+			t.Errorf("%v: expected usage, got %v and:\n%s", args, err, stderr.String())
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	if err := run([]string{"-db", dbPath, "river", "НИЛ"}, &stdout, &stderr); err == nil || !strings.Contains(err.Error(), "no information found") {
+		t.Errorf("Expected an unknown river error, got %v", err)
+	}
+}
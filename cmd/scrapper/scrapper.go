@@ -1,54 +1,79 @@
-// filepath: /Users/abelezeko/Projects/water-bot/cmd/scrapper/scrapper.go
 package main
 
 import (
 	"log"
+	"log/slog"
 	"os"
+	"path/filepath"
 
+	"github.com/abelzeko/water-bot/internal/config"
+	"github.com/abelzeko/water-bot/internal/health"
 	"github.com/abelzeko/water-bot/internal/integration"
+	"github.com/abelzeko/water-bot/internal/logging"
 	"github.com/abelzeko/water-bot/internal/repository"
+	"github.com/abelzeko/water-bot/internal/scheduler"
 	"github.com/abelzeko/water-bot/internal/usecases"
-	"github.com/robfig/cron/v3"
 )
 
 func main() {
-	// Configure logging
-	log.SetOutput(os.Stdout)
+	// Read and validate the whole configuration before touching the database
 	log.SetFlags(log.Ldate | log.Ltime | log.Lshortfile)
-	log.Println("Starting Water Bot Scraper...")
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
+	// Configure logging, LOG_FORMAT=json switches to structured output and LOG_LEVEL sets the minimum level
+	if err := logging.Setup(cfg.LogFormat, cfg.LogLevel, os.Stdout); err != nil {
+		log.Fatalf("Failed to configure logging: %v", err)
+	}
+	slog.Info("Starting Water Bot Scraper...")
 
-	// Initialize repository
-	repo, err := repository.NewSQLiteRiverRepository("")
+	// Initialize repository, SQLite unless DB_DRIVER selects Postgres
+	repo, err := repository.NewRiverRepository(cfg.DBDriver, cfg.PostgresDSN, cfg.DBPath)
 	if err != nil {
-		log.Fatalf("Failed to initialize repository: %v", err)
+		fatal("Failed to initialize repository", err)
 	}
 	defer repo.Close()
 
 	// Initialize scraper
-	scraper := integration.NewWaterScraper("")
+	scraper := integration.NewWaterScraperWithConfig(integration.ScraperConfig(cfg.Sources))
 
 	// Initialize use case
 	useCase := usecases.NewRiverUseCase(repo, scraper, nil)
+	useCase.SetReadingBounds(usecases.ReadingBounds{
+		WaterLevel: usecases.Bounds(cfg.LevelRange),
+		WaterTemp:  usecases.Bounds(cfg.TempRange),
+	})
+	useCase.SetRefreshWait(cfg.RefreshWait)
 
-	// Run use case immediately on startup
-	if err := useCase.RefreshRiverData(); err != nil {
-		log.Printf("Initial data refresh failed: %v", err)
-	}
-
-	// Set up cron scheduler to run every hour
-	c := cron.New()
-	_, err = c.AddFunc("0 * * * *", func() {
-		if err := useCase.RefreshRiverData(); err != nil {
-			log.Printf("Scheduled data refresh failed: %v", err)
+	// Back up a SQLite database daily into BACKUP_DIR, keeping the newest BACKUP_KEEP copies
+	if sqliteRepo, ok := repo.(*repository.SQLiteRiverRepository); ok && cfg.BackupKeep > 0 {
+		backupDir := cfg.BackupDir
+		if backupDir == "" {
+			backupDir = filepath.Join(filepath.Dir(sqliteRepo.DBPath), "backups")
+		}
+		if _, err := scheduler.StartBackups(sqliteRepo, backupDir, cfg.BackupKeep); err != nil {
+			fatal("Failed to schedule database backups", err)
 		}
-	})
-	if err != nil {
-		log.Fatalf("Failed to set up cron job: %v", err)
 	}
 
-	log.Println("Scraper has been scheduled to run hourly")
-	c.Start()
+	// Expose the health endpoint, HEALTH_ADDR overrides the listen address
+	checker := health.NewChecker()
+	checker.AddCheck("repository", repo.Ping)
+	health.Serve(cfg.HealthAddr, checker)
+
+	// Refresh now and then hourly, unless REFRESH_CRON or REFRESH_INTERVAL say otherwise
+	if _, err := scheduler.Start(useCase, cfg.RefreshSchedule, checker); err != nil {
+		fatal("Failed to schedule data refresh", err)
+	}
 
 	// Keep the program running
 	select {}
 }
+
+// fatal logs err and exits, for failures after logging is configured
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
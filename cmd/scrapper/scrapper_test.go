@@ -1,13 +1,17 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -15,6 +19,7 @@ import (
 	"github.com/abelzeko/water-bot/internal/entities"
 	"github.com/abelzeko/water-bot/internal/integration"
 	"github.com/abelzeko/water-bot/internal/repository"
+	"golang.org/x/text/encoding/charmap"
 )
 
 // TestFetchWaterData tests the ability to extract water data and timestamps from the website
@@ -28,7 +33,7 @@ func TestFetchWaterData(t *testing.T) {
 	scraper := integration.NewWaterScraper("")
 
 	// Fetch data from website with proper error handling
-	data, err := scraper.FetchWaterData()
+	data, err := scraper.FetchWaterData(context.Background())
 	if err != nil {
 		// Don't fail the test completely if it's just a temporary network issue
 		t.Logf("Warning: Failed to fetch water data: %v", err)
@@ -102,10 +107,10 @@ func TestTimestampExtractionWithMock(t *testing.T) {
 	}
 
 	// Extract timestamp
-	timestamp := scraper.ExtractTimestamp(doc)
+	timestamp, ok := scraper.ExtractTimestamp(doc)
 
 	// Verify the timestamp
-	if timestamp.IsZero() {
+	if !ok {
 		t.Fatal("Failed to extract timestamp from mock data")
 	}
 
@@ -177,8 +182,8 @@ func TestTimestampExtraction(t *testing.T) {
 				scraper := integration.NewWaterScraper("")
 
 				// Try to extract timestamp from the HTML document
-				timestamp := scraper.ExtractTimestamp(doc)
-				if !timestamp.IsZero() {
+				timestamp, ok := scraper.ExtractTimestamp(doc)
+				if ok {
 					t.Logf("Successfully parsed timestamp: %s", timestamp.Format(time.RFC3339))
 				} else {
 					t.Logf("Failed to parse timestamp from text")
@@ -292,10 +297,19 @@ func TestDatabaseIntegration(t *testing.T) {
 		}
 	}
 
-	// Skip the GetLastUpdateTime test due to SQLite timestamp format issues
-	// This is a known SQLite issue where timestamps are stored as strings
-	// In a real application we would need to add proper parsing in the repository
-	t.Log("Skipping LastUpdateTime test due to SQLite timestamp format compatibility")
+	// Verify the last update time matches what we inserted
+	lastUpdate, err := repo.GetLastUpdateTime()
+	if err != nil {
+		t.Errorf("Failed to get last update time: %v", err)
+	} else {
+		timeDiff := now.Sub(lastUpdate)
+		if timeDiff < 0 {
+			timeDiff = -timeDiff
+		}
+		if timeDiff > time.Second {
+			t.Errorf("Expected last update time %v, got %v", now, lastUpdate)
+		}
+	}
 }
 
 // TestGradacRiverIntegration tests the integration for the ГРАДАЦ river
@@ -309,7 +323,7 @@ func TestGradacRiverIntegration(t *testing.T) {
 	scraper := integration.NewWaterScraper("")
 
 	// Fetch ГРАДАЦ river data
-	data, err := scraper.FetchGradacRiverData()
+	data, err := scraper.FetchGradacRiverData(context.Background())
 	if err != nil {
 		// Don't fail the test completely if it's just a temporary network issue
 		t.Logf("Warning: Failed to fetch ГРАДАЦ river data: %v", err)
@@ -410,7 +424,7 @@ func TestRhmzRsIntegration(t *testing.T) {
 	scraper := integration.NewWaterScraper("")
 
 	// Fetch RHMZ RS data
-	data, err := scraper.FetchRhmzRsData()
+	data, err := scraper.FetchRhmzRsData(context.Background())
 	if err != nil {
 		// Don't fail the test completely if it's just a temporary network issue
 		t.Logf("Warning: Failed to fetch RHMZ RS data: %v", err)
@@ -599,7 +613,7 @@ func TestRhmzRsWithMockData(t *testing.T) {
 
 	// Create a scraper and fetch the data
 	scraper := integration.NewWaterScraper("")
-	data, err := scraper.FetchRhmzRsData()
+	data, err := scraper.FetchRhmzRsData(context.Background())
 	if err != nil {
 		t.Fatalf("Failed to fetch data from mock server: %v", err)
 	}
@@ -616,10 +630,12 @@ func TestRhmzRsWithMockData(t *testing.T) {
 		Station    string
 		WaterLevel string
 		WaterTemp  string
+		Tendency   entities.Tendency
+		GaugeZero  string
 	}{
-		{"ДРИНА", "ХЕ Зворник", "145", "10.2"},
-		{"ДРИНА", "Радаљ", "142", "9.5"},
-		{"САВА", "Сремска Митровица", "325", "11.8"},
+		{"ДРИНА", "ХЕ Зворник", "145", "10.2", entities.Falling, "140.00"},
+		{"ДРИНА", "Радаљ", "142", "9.5", entities.Falling, "129.47"},
+		{"САВА", "Сремска Митровица", "325", "11.8", entities.Rising, "72.22"},
 	}
 
 	for i, e := range expected {
@@ -640,6 +656,12 @@ func TestRhmzRsWithMockData(t *testing.T) {
 		if data[i].WaterTemp != e.WaterTemp {
 			t.Errorf("Entry %d: Expected water temperature %s, got %s", i, e.WaterTemp, data[i].WaterTemp)
 		}
+		if data[i].Tendency != e.Tendency {
+			t.Errorf("Entry %d: Expected tendency %s, got %s", i, e.Tendency, data[i].Tendency)
+		}
+		if data[i].GaugeZero != e.GaugeZero {
+			t.Errorf("Entry %d: Expected gauge zero %s, got %s", i, e.GaugeZero, data[i].GaugeZero)
+		}
 
 		// Check timestamp
 		expectedDate := time.Date(2025, 4, 20, 7, 0, 0, 0, data[i].Timestamp.Location())
@@ -685,3 +707,710 @@ func (c *customTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	// Send the request to the test server
 	return http.DefaultTransport.RoundTrip(newReq)
 }
+
+// TestDhmzWithMockData tests the DHMZ integration with mock data
+func TestDhmzWithMockData(t *testing.T) {
+	// Create mock HTML content that simulates the DHMZ hydrology page
+	mockHTML := `
+<!DOCTYPE html>
+<html>
+<body>
+    <table>
+        <tr>
+            <th>Rijeka</th>
+            <th>Postaja</th>
+            <th>Vrijeme mjerenja</th>
+            <th>Vodostaj (cm)</th>
+            <th>Tendencija</th>
+            <th>Temp. vode (°C)</th>
+        </tr>
+        <tr>
+            <td>Sava</td>
+            <td>Zagreb</td>
+            <td>20.04.2025. 07:00</td>
+            <td>-105</td>
+            <td><img src="strelica_gore.png" alt="u porastu"></td>
+            <td>11.4</td>
+        </tr>
+        <tr>
+            <td>Drava</td>
+            <td>Osijek</td>
+            <td>20.04.2025. 07:00</td>
+            <td>88</td>
+            <td>▼</td>
+            <td>-</td>
+        </tr>
+        <tr>
+            <td>Kupa</td>
+            <td>Karlovac</td>
+            <td>20.04.2025. 06:00</td>
+            <td>-</td>
+            <td>►</td>
+            <td>9.8</td>
+        </tr>
+    </table>
+</body>
+</html>`
+
+	server := mockHTMLServer(mockHTML)
+	defer server.Close()
+
+	// Route the DHMZ request to the mock server
+	defaultClient := http.DefaultClient
+	http.DefaultClient = &http.Client{Transport: &redirectTransport{server: server}}
+	defer func() {
+		http.DefaultClient = defaultClient
+	}()
+
+	scraper := integration.NewWaterScraper("")
+	data, err := scraper.FetchDhmzData(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to fetch data from mock server: %v", err)
+	}
+
+	// The row without a water level is skipped
+	if len(data) != 2 {
+		t.Fatalf("Expected 2 river data entries, but got %d", len(data))
+	}
+
+	expected := []struct {
+		River      string
+		Station    string
+		WaterLevel string
+		WaterTemp  string
+		Tendency   entities.Tendency
+	}{
+		{"SAVA", "Zagreb", "-105", "11.4", entities.Rising},
+		{"DRAVA", "Osijek", "88", "", entities.Falling},
+	}
+
+	loc, _ := time.LoadLocation("Europe/Zagreb")
+	expectedDate := time.Date(2025, 4, 20, 7, 0, 0, 0, loc)
+
+	for i, e := range expected {
+		if data[i].River != e.River {
+			t.Errorf("Entry %d: Expected river %s, got %s", i, e.River, data[i].River)
+		}
+		if data[i].Station != e.Station {
+			t.Errorf("Entry %d: Expected station %s, got %s", i, e.Station, data[i].Station)
+		}
+		if data[i].WaterLevel != e.WaterLevel {
+			t.Errorf("Entry %d: Expected water level %s, got %s", i, e.WaterLevel, data[i].WaterLevel)
+		}
+		if data[i].WaterTemp != e.WaterTemp {
+			t.Errorf("Entry %d: Expected water temperature %s, got %s", i, e.WaterTemp, data[i].WaterTemp)
+		}
+		if data[i].Tendency != e.Tendency {
+			t.Errorf("Entry %d: Expected tendency %s, got %s", i, e.Tendency, data[i].Tendency)
+		}
+		if data[i].Source != integration.SourceDhmz {
+			t.Errorf("Entry %d: Expected source %s, got %s", i, integration.SourceDhmz, data[i].Source)
+		}
+		if !data[i].Timestamp.Equal(expectedDate) {
+			t.Errorf("Entry %d: Expected timestamp %v, got %v", i, expectedDate, data[i].Timestamp)
+		}
+	}
+}
+
+// redirectTransport is a http.RoundTripper that sends every request to a single test server
+type redirectTransport struct {
+	server *httptest.Server
+}
+
+// RoundTrip implements the http.RoundTripper interface
+func (r *redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	newReq, err := http.NewRequest(req.Method, r.server.URL, req.Body)
+	if err != nil {
+		return nil, err
+	}
+	newReq.Header = req.Header
+	return http.DefaultTransport.RoundTrip(newReq)
+}
+
+// TestSourceURLsFromEnvironment verifies the scraper uses source URLs from the environment
+func TestSourceURLsFromEnvironment(t *testing.T) {
+	var mu sync.Mutex
+	requested := make(map[string]bool)
+
+	mux := http.NewServeMux()
+	handle := func(path, body string) {
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			requested[path] = true
+			mu.Unlock()
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprintln(w, body)
+		})
+	}
+	handle("/hidmet", `<html><body><div>Хидролошки подаци: 18.04.2025. време: 8:00</div>
+		<table><tbody><tr><td>ДУНАВ</td><td></td><td><a href="station.php?hm_id=42010">Земун</a></td><td></td><td></td><td>350</td><td></td><td></td><td>12.1</td><td></td></tr></tbody></table></body></html>`)
+	handle("/gradac", `<html><body><table><tr><td>18.04.2025 06:00</td><td>42</td></tr></table></body></html>`)
+	handle("/listing", `<html><body><a href="/bulletin">Редован хидролошки билтен</a></body></html>`)
+	handle("/bulletin", `<html><body><table>
+		<tr><td>РИЈЕКА</td><td>СТАНИЦА</td><td></td><td></td><td></td><td></td><td></td><td></td></tr>
+		<tr><td>ДРИНА</td><td>Радаљ</td><td></td><td>142</td><td></td><td></td><td></td><td>▼</td></tr>
+		</table></body></html>`)
+	handle("/dhmz", `<html><body><table><tr><td>Sava</td><td>Zagreb</td><td>18.04.2025. 08:00</td><td>-105</td><td>▲</td></tr></table></body></html>`)
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	scraper := integration.NewWaterScraperWithConfig(integration.ScraperConfig{
+		HidmetURL:        server.URL + "/hidmet",
+		GradacURL:        server.URL + "/gradac",
+		RhmzRsListingURL: server.URL + "/listing",
+		DhmzURL:          server.URL + "/dhmz",
+	})
+
+	// Every reading records the page it was published on
+	fetchers := map[string]struct {
+		fetch   func(context.Context) ([]entities.RiverData, error)
+		wantURL string
+	}{
+		"hidmet": {scraper.FetchWaterData, server.URL + "/station.php?hm_id=42010"},
+		"gradac": {scraper.FetchGradacRiverData, server.URL + "/gradac"},
+		"rhmzrs": {scraper.FetchRhmzRsData, server.URL + "/bulletin"},
+		"dhmz":   {scraper.FetchDhmzData, server.URL + "/dhmz"},
+	}
+	for name, fetcher := range fetchers {
+		data, err := fetcher.fetch(context.Background())
+		if err != nil {
+			t.Errorf("%s: failed to fetch from test server: %v", name, err)
+			continue
+		}
+		if len(data) != 1 {
+			t.Errorf("%s: expected 1 entry from test server, got %d", name, len(data))
+			continue
+		}
+		if data[0].URL != fetcher.wantURL {
+			t.Errorf("%s: expected source URL %s, got %s", name, fetcher.wantURL, data[0].URL)
+		}
+	}
+
+	// The relative bulletin link must be resolved against the configured listing URL
+	for _, path := range []string{"/hidmet", "/gradac", "/listing", "/bulletin", "/dhmz"} {
+		if !requested[path] {
+			t.Errorf("Expected a request to %s", path)
+		}
+	}
+}
+
+// TestRhmzRsBulletinErrors verifies RHMZ RS bulletin failures are reported instead of yielding no rows
+func TestRhmzRsBulletinErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr string
+	}{
+		{"server error", http.StatusInternalServerError, "<html><body>Internal error</body></html>", "500"},
+		{"empty body", http.StatusOK, "", "empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/listing", func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintln(w, `<html><body><a href="/bulletin">Редован хидролошки билтен</a></body></html>`)
+			})
+			mux.HandleFunc("/bulletin", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, tt.body)
+			})
+			server := httptest.NewServer(mux)
+			defer server.Close()
+
+			scraper := integration.NewWaterScraperWithConfig(integration.ScraperConfig{RhmzRsListingURL: server.URL + "/listing"})
+
+			data, err := scraper.FetchRhmzRsData(context.Background())
+			if err == nil {
+				t.Fatalf("Expected an error, got %d entries", len(data))
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error mentioning %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+// TestRhmzRsRowspanRivers verifies stations under a river cell spanning several rows, or a blank
+// river cell, are all attributed to that river
+func TestRhmzRsRowspanRivers(t *testing.T) {
+	mockBulletinHTML := `<html><body><table>
+		<tr><td colspan="8">НА ДАН 20.04.2025. ГОДИНЕ, У 7:00 ЧАСОВА</td></tr>
+		<tr>
+			<td>РИЈЕКА</td><td>СТАНИЦА</td><td>КОТА„О"</td><td>ВОДОСТАЈ H (cm)</td>
+			<td>ПРОМЈ. ВОДОСТ</td><td>ТЕМП. ВОДЕ</td><td>ПРОТИЦАЈ Q (m3/s)</td><td>ТЕНДЕНЦИЈА ВОДОСТАЈА</td>
+		</tr>
+		<tr><td rowspan="3">ДРИНА</td><td>Фоча</td><td>380.00</td><td>98</td><td>1</td><td>8.9</td><td>120.10</td><td>▲</td></tr>
+		<tr><td>ХЕ Зворник</td><td>140.00</td><td>145</td><td>-2</td><td>10.2</td><td>350.50</td><td>▼</td></tr>
+		<tr><td>Радаљ</td><td>129.47</td><td>142</td><td>-3</td><td>9.5</td><td>320.20</td><td>▼</td></tr>
+		<tr><td colspan="8">ВРБАС</td></tr>
+		<tr><td></td><td>Бања Лука</td><td>150.00</td><td>110</td><td>0</td><td>9.1</td><td>80.00</td><td>=</td></tr>
+		<tr><td></td><td>Делибашино Село</td><td>140.00</td><td>120</td><td>0</td><td>9.3</td><td>85.00</td><td>=</td></tr>
+		<tr><td>САВА</td><td>Градишка</td><td>86.00</td><td>325</td><td>5</td><td>11.8</td><td>1890.40</td><td>▲</td></tr>
+	</table></body></html>`
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/listing", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `<html><body><a href="/bulletin">Редован хидролошки билтен</a></body></html>`)
+	})
+	mux.HandleFunc("/bulletin", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, mockBulletinHTML)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	scraper := integration.NewWaterScraperWithConfig(integration.ScraperConfig{RhmzRsListingURL: server.URL + "/listing"})
+	data, err := scraper.FetchRhmzRsData(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to fetch data from mock server: %v", err)
+	}
+
+	want := []struct{ river, station, level string }{
+		{"ДРИНА", "Фоча", "98"},
+		{"ДРИНА", "ХЕ Зворник", "145"},
+		{"ДРИНА", "Радаљ", "142"},
+		{"ВРБАС", "Бања Лука", "110"},
+		{"ВРБАС", "Делибашино Село", "120"},
+		{"САВА", "Градишка", "325"},
+	}
+	if len(data) != len(want) {
+		t.Fatalf("Expected %d entries, got %d: %+v", len(want), len(data), data)
+	}
+	for i, w := range want {
+		if data[i].River != w.river || data[i].Station != w.station || data[i].WaterLevel != w.level {
+			t.Errorf("Entry %d: expected %s/%s at %s cm, got %s/%s at %s cm",
+				i, w.river, w.station, w.level, data[i].River, data[i].Station, data[i].WaterLevel)
+		}
+	}
+}
+
+// TestRhmzRsColumnsFromHeaders verifies the RHMZ RS columns are found by their labels when an extra column shifts them
+func TestRhmzRsColumnsFromHeaders(t *testing.T) {
+	mockBulletinHTML := `<html><body><table>
+		<tr><td colspan="9">НА ДАН 20.04.2025. ГОДИНЕ, У 7:00 ЧАСОВА</td></tr>
+		<tr>
+			<td>Р.БР.</td><td>РИЈЕКА</td><td>СТАНИЦА</td><td>КОТА„О"</td><td>ВОДОСТАЈ H (cm)</td>
+			<td>ПРОМЈ. ВОДОСТ</td><td>ТЕМП. ВОДЕ</td><td>ПРОТИЦАЈ Q (m3/s)</td><td>ТЕНДЕНЦИЈА ВОДОСТАЈА</td>
+		</tr>
+		<tr><td>1</td><td>ДРИНА</td><td>ХЕ Зворник</td><td>140.00</td><td>145</td><td>-2</td><td>10.2</td><td>350.50</td><td>▼</td></tr>
+		<tr><td>2</td><td>САВА</td><td>Градишка</td><td>86.00</td><td>325</td><td>5</td><td>-</td><td>1890.40</td><td>▲</td></tr>
+	</table></body></html>`
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/listing", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `<html><body><a href="/bulletin">Редован хидролошки билтен</a></body></html>`)
+	})
+	mux.HandleFunc("/bulletin", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, mockBulletinHTML)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	scraper := integration.NewWaterScraperWithConfig(integration.ScraperConfig{RhmzRsListingURL: server.URL + "/listing"})
+	data, err := scraper.FetchRhmzRsData(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to fetch data from mock server: %v", err)
+	}
+
+	want := []entities.RiverData{
+		{River: "ДРИНА", Station: "ХЕ Зворник", WaterLevel: "145", WaterTemp: "10.2", Discharge: "350.50", Tendency: entities.Falling},
+		{River: "САВА", Station: "Градишка", WaterLevel: "325", WaterTemp: "", Discharge: "1890.40", Tendency: entities.Rising},
+	}
+	if len(data) != len(want) {
+		t.Fatalf("Expected %d entries, got %d: %+v", len(want), len(data), data)
+	}
+	for i, w := range want {
+		got := data[i]
+		if got.River != w.River || got.Station != w.Station || got.WaterLevel != w.WaterLevel ||
+			got.WaterTemp != w.WaterTemp || got.Discharge != w.Discharge || got.Tendency != w.Tendency {
+			t.Errorf("Entry %d: expected %+v, got %+v", i, w, got)
+		}
+	}
+}
+
+// headerRecordingTransport records the headers of every request and serves a fixed page
+type headerRecordingTransport struct {
+	mu      sync.Mutex
+	headers []http.Header
+}
+
+// RoundTrip implements the http.RoundTripper interface
+func (h *headerRecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	h.mu.Lock()
+	h.headers = append(h.headers, req.Header.Clone())
+	h.mu.Unlock()
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Status:     "200 OK",
+		Body:       io.NopCloser(strings.NewReader("<html><body><table></table></body></html>")),
+		Header:     make(http.Header),
+		Request:    req,
+	}, nil
+}
+
+// TestScraperSendsIdentifyingHeaders verifies every scraper request carries the User-Agent and Accept-Language headers
+func TestScraperSendsIdentifyingHeaders(t *testing.T) {
+	transport := &headerRecordingTransport{}
+	defaultClient := http.DefaultClient
+	http.DefaultClient = &http.Client{Transport: transport}
+	defer func() {
+		http.DefaultClient = defaultClient
+	}()
+
+	scraper := integration.NewWaterScraperWithConfig(integration.ScraperConfig{
+		HidmetURL: "https://example.com/hidmet",
+		UserAgent: "test-agent/2.0",
+	})
+
+	scraper.FetchWaterData(context.Background())
+	scraper.FetchGradacRiverData(context.Background())
+	scraper.FetchRhmzRsData(context.Background()) // Fails after the listing page since it has no bulletin link
+	scraper.FetchDhmzData(context.Background())
+
+	if len(transport.headers) != 4 {
+		t.Fatalf("Expected 4 requests, got %d", len(transport.headers))
+	}
+	for i, header := range transport.headers {
+		if got := header.Get("User-Agent"); got != "test-agent/2.0" {
+			t.Errorf("Request %d: expected User-Agent test-agent/2.0, got %q", i, got)
+		}
+		if header.Get("Accept-Language") == "" {
+			t.Errorf("Request %d: expected an Accept-Language header", i)
+		}
+	}
+}
+
+// TestParseHidmetTimestamp tests parsing of the hidmet data header
+func TestParseHidmetTimestamp(t *testing.T) {
+	belgrade, err := time.LoadLocation("Europe/Belgrade")
+	if err != nil {
+		t.Fatalf("Failed to load time zone: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		text    string
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name: "with day name and UTC hint",
+			text: "Хидролошки подаци: ПЕТАК 18.04.2025. време: 8:00 (06:00 UTC)",
+			want: time.Date(2025, 4, 18, 8, 0, 0, 0, belgrade),
+		},
+		{
+			name: "date and time only",
+			text: "Хидролошки подаци: 18.04.2025. време: 8:00",
+			want: time.Date(2025, 4, 18, 8, 0, 0, 0, belgrade),
+		},
+		{
+			name: "surrounded by other page text",
+			text: "Осмотрене вредности\n  Хидролошки подаци: СУБОТА 01.03.2025. време: 14:30 (13:30 UTC)\n Легенда",
+			want: time.Date(2025, 3, 1, 14, 30, 0, 0, belgrade),
+		},
+		{name: "missing time", text: "Хидролошки подаци: 18.04.2025.", wantErr: true},
+		{name: "missing date", text: "Хидролошки подаци: време: 8:00", wantErr: true},
+		{name: "invalid date", text: "Хидролошки подаци: 32.13.2025. време: 8:00", wantErr: true},
+		{name: "garbage", text: "lorem ipsum", wantErr: true},
+		{name: "empty", text: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := integration.ParseHidmetTimestamp(tt.text)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestFetchStationHistoryWithMockData tests fetching a station's history by hm_id and period
+func TestFetchStationHistoryWithMockData(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintln(w, `<html><body><table>
+			<tr><td>Датум и време</td><td>Водостај</td></tr>
+			<tr><td>18.04.2025 07:00</td><td>45</td></tr>
+			<tr><td>18.04.2025 06:00</td><td>44</td></tr>
+			<tr><td>17.04.2025 06:00</td><td>-</td></tr>
+			<tr><td>16.04.2025 06:00</td><td>40</td></tr>
+			</table></body></html>`)
+	}))
+	defer server.Close()
+
+	scraper := integration.NewWaterScraperWithConfig(integration.ScraperConfig{HidmetHistoryURL: server.URL + "/nrt_tabela_grafik.php"})
+
+	data, err := scraper.FetchStationHistory(context.Background(), 45902, 3)
+	if err != nil {
+		t.Fatalf("Failed to fetch station history: %v", err)
+	}
+	if query.Get("hm_id") != "45902" || query.Get("period") != "3" {
+		t.Errorf("Expected hm_id=45902 and period=3, got %v", query)
+	}
+
+	if len(data) != 3 {
+		t.Fatalf("Expected 3 valid readings, got %d: %+v", len(data), data)
+	}
+	wantLevels := []string{"40", "44", "45"}
+	for i, rd := range data {
+		if rd.River != "ГРАДАЦ" || rd.Station != "ДЕГУРИЋ" || rd.Source != integration.SourceGradac {
+			t.Errorf("Reading %d: unexpected station %s/%s from %s", i, rd.River, rd.Station, rd.Source)
+		}
+		if rd.WaterLevel != wantLevels[i] {
+			t.Errorf("Reading %d: expected level %s, got %s", i, wantLevels[i], rd.WaterLevel)
+		}
+	}
+	if want := time.Date(2025, 4, 18, 7, 0, 0, 0, time.UTC); !data[2].Timestamp.Equal(want) {
+		t.Errorf("Expected the newest reading at %v, got %v", want, data[2].Timestamp)
+	}
+
+	if _, err := scraper.FetchStationHistory(context.Background(), 1, 3); err == nil {
+		t.Error("Expected an error for an unknown hm_id")
+	}
+	if _, err := scraper.FetchStationHistory(context.Background(), 45902, 0); err == nil {
+		t.Error("Expected an error for an empty period")
+	}
+}
+
+// TestFetchGradacDeduplicatesTimestamps tests a ГРАДАЦ table repeating timestamps keeps
+// the last row of each
+func TestFetchGradacDeduplicatesTimestamps(t *testing.T) {
+	server := mockHTMLServer(`<html><body><table>
+		<tr><td>Датум и време</td><td>Водостај</td></tr>
+		<tr><td>18.04.2025 07:00</td><td>45</td></tr>
+		<tr><td>18.04.2025 06:00</td><td>44</td></tr>
+		<tr><td>18.04.2025 07:00</td><td>46</td></tr>
+		<tr><td>18.04.2025 06:00</td><td>43</td></tr>
+		<tr><td>18.04.2025 05:00</td><td>42</td></tr>
+		</table></body></html>`)
+	defer server.Close()
+
+	scraper := integration.NewWaterScraperWithConfig(integration.ScraperConfig{GradacURL: server.URL})
+
+	data, err := scraper.FetchGradacRiverData(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to fetch ГРАДАЦ data: %v", err)
+	}
+
+	wantLevels := []string{"42", "43", "46"}
+	if len(data) != len(wantLevels) {
+		t.Fatalf("Expected %d readings, one per timestamp, got %d: %+v", len(wantLevels), len(data), data)
+	}
+	for i, rd := range data {
+		if rd.WaterLevel != wantLevels[i] {
+			t.Errorf("Reading %d at %v: expected level %s, got %s", i, rd.Timestamp, wantLevels[i], rd.WaterLevel)
+		}
+		if i > 0 && !rd.Timestamp.After(data[i-1].Timestamp) {
+			t.Errorf("Expected readings oldest first with distinct timestamps, got %v after %v", rd.Timestamp, data[i-1].Timestamp)
+		}
+	}
+}
+
+// TestFetchWaterDataDetectsColumnsFromHeaders tests parsing a hidmet table whose columns were reordered
+func TestFetchWaterDataDetectsColumnsFromHeaders(t *testing.T) {
+	mockHTML := `<html><body>
+		<div>Хидролошки подаци: 18.04.2025. време: 8:00</div>
+		<table>
+			<thead><tr>
+				<th>Станица</th><th>Тенденција</th><th>Температура воде (°C)</th>
+				<th colspan="2">Водостај (cm)</th><th>Промена водостаја</th><th>Проток (m³/s)</th><th>Река</th>
+			</tr></thead>
+			<tbody>
+				<tr><td><a>Земун</a></td><td><img alt="пораст"></td><td>12.1</td><td>350</td><td>cm</td><td>+5</td><td>4820</td><td>ДУНАВ</td></tr>
+				<tr><td><a>Шабац</a></td><td><img alt="опадање"></td><td>11.4</td><td>325</td><td>cm</td><td>-3</td><td>-</td><td>САВА</td></tr>
+			</tbody>
+		</table></body></html>`
+	server := mockHTMLServer(mockHTML)
+	defer server.Close()
+
+	data, err := integration.NewWaterScraper(server.URL).FetchWaterData(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to fetch water data: %v", err)
+	}
+
+	want := []entities.RiverData{
+		{River: "ДУНАВ", Station: "Земун", WaterLevel: "350", WaterTemp: "12.1", Discharge: "4820", Tendency: entities.Rising},
+		{River: "САВА", Station: "Шабац", WaterLevel: "325", WaterTemp: "11.4", Discharge: "", Tendency: entities.Falling},
+	}
+	if len(data) != len(want) {
+		t.Fatalf("Expected %d entries, got %d: %+v", len(want), len(data), data)
+	}
+	for i, w := range want {
+		got := data[i]
+		if got.River != w.River || got.Station != w.Station || got.WaterLevel != w.WaterLevel ||
+			got.WaterTemp != w.WaterTemp || got.Discharge != w.Discharge || got.Tendency != w.Tendency {
+			t.Errorf("Entry %d: expected %+v, got %+v", i, w, got)
+		}
+	}
+}
+
+// TestFetchWaterDataWithoutTbody verifies rows directly under the table are read and
+// header rows, whether made of th or td cells, are not taken for readings
+func TestFetchWaterDataWithoutTbody(t *testing.T) {
+	mockHTML := `<html><body>
+		<div>Хидролошки подаци: 18.04.2025. време: 8:00</div>
+		<table>
+			<tr><td>Река</td><td></td><td>Станица</td><td></td><td></td><td>Водостај (cm)</td><td></td><td></td><td>Температура</td><td>Тенденција</td></tr>
+			<tr><td>ДУНАВ</td><td></td><td><a>Земун</a></td><td></td><td></td><td>350</td><td></td><td></td><td>12.1</td><td><img alt="пораст"></td></tr>
+			<tr><td>САВА</td><td></td><td><a>Шабац</a></td><td></td><td></td><td>325</td><td></td><td></td><td>11.4</td><td><img alt="опадање"></td></tr>
+		</table></body></html>`
+	server := mockHTMLServer(mockHTML)
+	defer server.Close()
+
+	data, err := integration.NewWaterScraper(server.URL).FetchWaterData(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to fetch water data: %v", err)
+	}
+
+	want := []entities.RiverData{
+		{River: "ДУНАВ", Station: "Земун", WaterLevel: "350", WaterTemp: "12.1", Tendency: entities.Rising},
+		{River: "САВА", Station: "Шабац", WaterLevel: "325", WaterTemp: "11.4", Tendency: entities.Falling},
+	}
+	if len(data) != len(want) {
+		t.Fatalf("Expected %d entries, got %d: %+v", len(want), len(data), data)
+	}
+	for i, w := range want {
+		got := data[i]
+		if got.River != w.River || got.Station != w.Station || got.WaterLevel != w.WaterLevel ||
+			got.WaterTemp != w.WaterTemp || got.Tendency != w.Tendency {
+			t.Errorf("Entry %d: expected %+v, got %+v", i, w, got)
+		}
+	}
+}
+
+// TestFetchWaterDataWithoutTimestamp verifies rows aren't stamped with the current time when the page has no data timestamp
+func TestFetchWaterDataWithoutTimestamp(t *testing.T) {
+	mockHTML := `<html><body>
+		<div>Подаци тренутно нису доступни</div>
+		<table><tbody>
+			<tr><td>ДУНАВ</td><td></td><td><a>Земун</a></td><td></td><td></td><td>350</td><td></td><td></td><td>12.1</td><td><img alt="пораст"></td></tr>
+		</tbody></table></body></html>`
+	server := mockHTMLServer(mockHTML)
+	defer server.Close()
+
+	data, err := integration.NewWaterScraper(server.URL).FetchWaterData(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to fetch water data: %v", err)
+	}
+	if len(data) != 1 {
+		t.Fatalf("Expected 1 entry, got %d: %+v", len(data), data)
+	}
+	if !data[0].Timestamp.IsZero() {
+		t.Errorf("Expected the reading to be left without a timestamp, got %v", data[0].Timestamp)
+	}
+}
+
+// TestFetchersReturnErrNoData verifies each parser reports ErrNoData when its table is present but has no data rows
+func TestFetchersReturnErrNoData(t *testing.T) {
+	const emptyTable = `<html><body><p>Стање на дан 20.04.2025. у 07:00</p><table>
+		<thead><tr><th>Река</th><th>Станица</th><th>Водостај</th></tr></thead>
+		<tbody></tbody>
+	</table></body></html>`
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/hidmet", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, emptyTable)
+	})
+	mux.HandleFunc("/listing", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `<html><body><a href="/bulletin">Редован хидролошки билтен</a></body></html>`)
+	})
+	mux.HandleFunc("/bulletin", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, emptyTable)
+	})
+	mux.HandleFunc("/dhmz", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, emptyTable)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	scraper := integration.NewWaterScraperWithConfig(integration.ScraperConfig{
+		HidmetURL:        server.URL + "/hidmet",
+		RhmzRsListingURL: server.URL + "/listing",
+		DhmzURL:          server.URL + "/dhmz",
+	})
+
+	tests := []struct {
+		name  string
+		fetch func(context.Context) ([]entities.RiverData, error)
+	}{
+		{"hidmet", scraper.FetchWaterData},
+		{"RHMZ RS", scraper.FetchRhmzRsData},
+		{"DHMZ", scraper.FetchDhmzData},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.fetch(context.Background())
+			if !errors.Is(err, integration.ErrNoData) {
+				t.Fatalf("Expected ErrNoData, got %d entries and error %v", len(data), err)
+			}
+		})
+	}
+}
+
+// TestFetchWaterDataDecodesLegacyCharsets verifies pages in windows-1251 are transcoded
+// before parsing, whether the charset is declared in the header or in a <meta> tag, and
+// that undeclared UTF-8 pages are left alone
+func TestFetchWaterDataDecodesLegacyCharsets(t *testing.T) {
+	page := func(head string) string {
+		// The padding pushes all Cyrillic past the 1024 bytes charset sniffing looks at
+		return `<html><head>` + head + `</head><body><!--` + strings.Repeat(" ", 1100) + `-->
+		<div>Хидролошки подаци: 18.04.2025. време: 8:00</div>
+		<table><tbody><tr><td>ЈУЖНА МОРАВА</td><td></td><td><a>Ђунис</a></td><td></td><td></td><td>150</td><td></td><td></td><td>11.2</td><td></td></tr></tbody></table>
+		</body></html>`
+	}
+	windows1251 := func(text string) string {
+		encoded, err := charmap.Windows1251.NewEncoder().String(text)
+		if err != nil {
+			t.Fatalf("Failed to encode page: %v", err)
+		}
+		return encoded
+	}
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+	}{
+		{"charset in header", "text/html; charset=windows-1251", windows1251(page(""))},
+		{"charset in meta tag", "text/html", windows1251(page(`<meta charset="windows-1251">`))},
+		{"legacy meta http-equiv", "text/html", windows1251(page(`<meta http-equiv="Content-Type" content="text/html; charset=windows-1251">`))},
+		{"undeclared utf-8", "text/html", page("")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				io.WriteString(w, tt.body)
+			}))
+			defer server.Close()
+
+			data, err := integration.NewWaterScraper(server.URL).FetchWaterData(context.Background())
+			if err != nil {
+				t.Fatalf("Failed to fetch water data: %v", err)
+			}
+			if len(data) != 1 {
+				t.Fatalf("Expected 1 entry, got %d: %+v", len(data), data)
+			}
+			if data[0].River != "ЈУЖНА МОРАВА" || data[0].Station != "Ђунис" {
+				t.Errorf("Expected ЈУЖНА МОРАВА/Ђунис, got %q/%q", data[0].River, data[0].Station)
+			}
+			if data[0].Timestamp.IsZero() {
+				t.Error("Expected the Cyrillic page timestamp to be recognized")
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/usecases"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// manualRefreshTimeout bounds how long a /refresh run may take
+const manualRefreshTimeout = 10 * time.Minute
+
+// adminSet returns the chats allowed to run admin commands as a set
+func adminSet(chatIDs []int64) map[int64]bool {
+	admins := make(map[int64]bool, len(chatIDs))
+	for _, id := range chatIDs {
+		admins[id] = true
+	}
+	return admins
+}
+
+// isAdmin reports whether a chat may run admin commands
+func (t *TelegramBot) isAdmin(chatID int64) bool {
+	return t.admins[chatID]
+}
+
+// handleRefreshCommand processes the admin-only /refresh command
+func (t *TelegramBot) handleRefreshCommand(message *tgbotapi.Message, msg *tgbotapi.MessageConfig) {
+	if !t.isAdmin(message.Chat.ID) {
+		msg.Text = "You are not authorized to use this command."
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), manualRefreshTimeout)
+	defer cancel()
+	summary, err := t.useCase.RefreshRiverData(ctx)
+	if err != nil {
+		slog.Error("Manual data refresh failed", "error", err)
+	}
+
+	msg.Text = t.useCase.FormatRefreshResult(summary, err)
+}
+
+// handleWatchSourcesCommand processes the admin-only /watchsources [off] command,
+// subscribing the chat to notifications of source outages and recoveries
+func (t *TelegramBot) handleWatchSourcesCommand(message *tgbotapi.Message, msg *tgbotapi.MessageConfig) {
+	switch strings.ToLower(strings.TrimSpace(message.CommandArguments())) {
+	case "":
+		added, err := t.useCase.WatchSources(message.Chat.ID)
+		switch {
+		case err != nil:
+			msg.Text = "Error saving the subscription. Please try again later."
+			slog.Error("Error watching sources", "error", err)
+		case !added:
+			msg.Text = "You are already notified of source outages. Use /watchsources off to stop."
+		default:
+			msg.Text = "You'll be notified when a data source fails to refresh and when it recovers.\nUse /watchsources off to stop."
+		}
+	case "off":
+		removed, err := t.useCase.UnwatchSources(message.Chat.ID)
+		switch {
+		case err != nil:
+			msg.Text = "Error updating the subscription. Please try again later."
+			slog.Error("Error unwatching sources", "error", err)
+		case !removed:
+			msg.Text = "You are not notified of source outages."
+		default:
+			msg.Text = "You will no longer be notified of source outages."
+		}
+	default:
+		msg.Text = "Usage: /watchsources to be notified of source outages, /watchsources off to stop"
+	}
+}
+
+// dispatchSourceOutages sends the source outages and recoveries since the last check.
+// Chats removed from ADMIN_CHAT_IDS since subscribing are skipped.
+func (t *TelegramBot) dispatchSourceOutages() {
+	notifications, err := t.useCase.CheckSourceOutages()
+	if err != nil {
+		slog.Error("Error checking source outages", "error", err)
+		return
+	}
+	var allowed []usecases.Notification
+	for _, n := range notifications {
+		if t.isAdmin(n.ChatID) {
+			allowed = append(allowed, n)
+		}
+	}
+	t.sendNotifications(allowed)
+}
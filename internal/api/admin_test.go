@@ -0,0 +1,128 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+	"github.com/abelzeko/water-bot/internal/usecases"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// newRefreshTestBot creates a bot refreshing from a working and a failing source, with chat 42 as admin
+func newRefreshTestBot(t *testing.T) *TelegramBot {
+	t.Helper()
+	repo := newTestRepository(t)
+	sources := []usecases.DataSource{
+		usecases.NewDataSource("primary", func(ctx context.Context) ([]entities.RiverData, error) {
+			return []entities.RiverData{
+				{River: "ДРИНА", Station: "Радаљ", WaterLevel: "142", Timestamp: time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC)},
+				{River: "САВА", Station: "Шабац", WaterLevel: "325", Timestamp: time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC)},
+			}, nil
+		}),
+		usecases.NewDataSource("broken", func(ctx context.Context) ([]entities.RiverData, error) {
+			return nil, errors.New("site down")
+		}),
+	}
+
+	return &TelegramBot{
+		useCase: usecases.NewRiverUseCaseWithSources(repo, sources, nil),
+		admins:  map[int64]bool{42: true},
+	}
+}
+
+// TestHandleRefreshCommandRequiresAdmin verifies non-admin chats can't trigger a refresh
+func TestHandleRefreshCommandRequiresAdmin(t *testing.T) {
+	bot := newRefreshTestBot(t)
+
+	msg := tgbotapi.NewMessage(1, "")
+	bot.handleRefreshCommand(textMessage("/refresh"), &msg)
+
+	if msg.Text != "You are not authorized to use this command." {
+		t.Errorf("Expected the not-authorized reply, got %q", msg.Text)
+	}
+	if rivers, _ := bot.useCase.GetAvailableRivers(); len(rivers) != 0 {
+		t.Errorf("Expected no refresh to run, got rivers %v", rivers)
+	}
+}
+
+// TestHandleRefreshCommandReportsSummary verifies admins get the saved rows and per-source errors
+func TestHandleRefreshCommandReportsSummary(t *testing.T) {
+	bot := newRefreshTestBot(t)
+
+	message := textMessage("/refresh")
+	message.Chat.ID = 42
+	msg := tgbotapi.NewMessage(42, "")
+	bot.handleRefreshCommand(message, &msg)
+
+	for _, want := range []string{"saved 2 readings", "primary: 2 rows", "broken: error: site down"} {
+		if !strings.Contains(msg.Text, want) {
+			t.Errorf("Expected the reply to contain %q, got:\n%s", want, msg.Text)
+		}
+	}
+	if rivers, _ := bot.useCase.GetAvailableRivers(); len(rivers) != 2 {
+		t.Errorf("Expected the refresh to save both rivers, got %v", rivers)
+	}
+}
+
+// TestBroadcastPacesAndRemovesBlockedUsers verifies sends are spaced out and chats that blocked the bot are dropped
+func TestBroadcastPacesAndRemovesBlockedUsers(t *testing.T) {
+	users := []entities.User{{ChatID: 1}, {ChatID: 2}, {ChatID: 3}, {ChatID: 4}}
+
+	var sent, removed []int64
+	var pauses []time.Duration
+	b := broadcaster{
+		send: func(chatID int64, text string) error {
+			switch chatID {
+			case 2:
+				return &tgbotapi.Error{Code: 403, Message: "Forbidden: bot was blocked by the user"}
+			case 3:
+				return errors.New("network unreachable")
+			}
+			sent = append(sent, chatID)
+			return nil
+		},
+		remove: func(chatID int64) error {
+			removed = append(removed, chatID)
+			return nil
+		},
+		interval: broadcastInterval,
+		sleep:    func(d time.Duration) { pauses = append(pauses, d) },
+	}
+
+	result := b.broadcast(users, "Maintenance tonight")
+
+	if want := (broadcastResult{Delivered: 2, Failed: 2, Removed: 1}); result != want {
+		t.Errorf("Expected %+v, got %+v", want, result)
+	}
+	if !reflect.DeepEqual(sent, []int64{1, 4}) {
+		t.Errorf("Expected delivery to chats 1 and 4, got %v", sent)
+	}
+	if !reflect.DeepEqual(removed, []int64{2}) {
+		t.Errorf("Expected only the blocked chat to be removed, got %v", removed)
+	}
+	if len(pauses) != len(users)-1 {
+		t.Fatalf("Expected a pause between each send, got %d pauses", len(pauses))
+	}
+	for _, pause := range pauses {
+		if pause < time.Second/30 {
+			t.Errorf("Expected pauses keeping under 30 messages per second, got %v", pause)
+		}
+	}
+}
+
+// TestHandleBroadcastCommandRequiresAdmin verifies non-admin chats can't broadcast
+func TestHandleBroadcastCommandRequiresAdmin(t *testing.T) {
+	bot := newRefreshTestBot(t)
+
+	msg := tgbotapi.NewMessage(1, "")
+	bot.handleBroadcastCommand(textMessage("/broadcast hello"), &msg)
+
+	if msg.Text != "You are not authorized to use this command." {
+		t.Errorf("Expected the not-authorized reply, got %q", msg.Text)
+	}
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// broadcastInterval paces broadcast messages below Telegram's limit of about 30 messages per second
+const broadcastInterval = time.Second / 25
+
+// broadcastResult counts the outcome of sending a broadcast
+type broadcastResult struct {
+	Delivered int
+	Failed    int
+	Removed   int // Users unregistered because they blocked the bot
+}
+
+// broadcaster sends a message to many chats, pausing between sends
+type broadcaster struct {
+	send     func(chatID int64, text string) error
+	remove   func(chatID int64) error
+	interval time.Duration
+	sleep    func(time.Duration)
+}
+
+// isBlockedError reports whether a send failed because the bot can no longer write to the chat,
+// e.g. the user blocked it or deleted their account
+func isBlockedError(err error) bool {
+	var apiErr *tgbotapi.Error
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusForbidden
+}
+
+// broadcast sends text to every user, unregistering the ones that blocked the bot
+func (b broadcaster) broadcast(users []entities.User, text string) broadcastResult {
+	var result broadcastResult
+	for i, user := range users {
+		if i > 0 {
+			b.sleep(b.interval)
+		}
+
+		err := b.send(user.ChatID, text)
+		if err == nil {
+			result.Delivered++
+			continue
+		}
+
+		result.Failed++
+		if !isBlockedError(err) {
+			slog.Error("Error broadcasting", "chat_id", user.ChatID, "error", err)
+			continue
+		}
+		if err := b.remove(user.ChatID); err != nil {
+			slog.Error("Error removing blocked chat", "chat_id", user.ChatID, "error", err)
+			continue
+		}
+		result.Removed++
+	}
+	return result
+}
+
+// handleBroadcastCommand processes the admin-only /broadcast <message> command
+func (t *TelegramBot) handleBroadcastCommand(message *tgbotapi.Message, msg *tgbotapi.MessageConfig) {
+	if !t.isAdmin(message.Chat.ID) {
+		msg.Text = "You are not authorized to use this command."
+		return
+	}
+
+	text := strings.TrimSpace(message.CommandArguments())
+	if text == "" {
+		msg.Text = "Please specify a message. Example: /broadcast Data from RHMZ RS is delayed today."
+		return
+	}
+
+	users, err := t.useCase.GetAllUsers()
+	if err != nil {
+		slog.Error("Error fetching users for broadcast", "error", err)
+		msg.Text = "Error fetching users. Please try again later."
+		return
+	}
+
+	b := broadcaster{
+		send:     t.sendLong,
+		remove:   t.useCase.RemoveUser,
+		interval: broadcastInterval,
+		sleep:    time.Sleep,
+	}
+	result := b.broadcast(users, text)
+
+	slog.Info("Broadcast finished", "delivered", result.Delivered, "failed", result.Failed, "removed", result.Removed)
+	msg.Text = fmt.Sprintf("Broadcast delivered to %d of %d users, %d failed.", result.Delivered, len(users), result.Failed)
+	if result.Removed > 0 {
+		msg.Text += fmt.Sprintf("\n%d users who blocked the bot were removed.", result.Removed)
+	}
+}
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// botCommand is a registered bot command. Both the router and /help read the registry,
+// so adding a command here is all it takes to route and document it.
+type botCommand struct {
+	name        string   // Command name without the leading slash
+	aliases     []string // Alternative names routed to the same handler, not listed in /help
+	args        string   // Argument synopsis shown in /help, e.g. "[river]"
+	description string
+	adminOnly   bool         // Hidden from and refused to chats not in ADMIN_CHAT_IDS
+	middleware  []middleware // Run around the handler after the admin check and logging
+	handle      commandHandler
+}
+
+// usage returns the command as listed in /help, e.g. "/river [name]"
+func (c botCommand) usage() string {
+	if c.args == "" {
+		return "/" + c.name
+	}
+	return "/" + c.name + " " + c.args
+}
+
+// commands lists the registered commands in the order /help shows them
+var commands []botCommand
+
+// router dispatches commands to their registered handlers
+var router *commandRouter
+
+// The registry is filled in init since the /help handler reads it
+func init() {
+	commands = []botCommand{
+		{name: "start", description: "Start the bot and register your chat",
+			handle: (*TelegramBot).handleStartCommand},
+		{name: "rivers", description: "Show the list of rivers",
+			handle: func(t *TelegramBot, _ *tgbotapi.Message, msg *tgbotapi.MessageConfig) { t.handleRiversCommand(msg) }},
+		{name: "river", args: "[name] [--image]", description: "Show information for a specific river",
+			handle: withArgs((*TelegramBot).handleRiverCommand)},
+		{name: "raw", args: "[river]", description: "Show every stored field of a river's readings",
+			handle: withArgs((*TelegramBot).handleRawCommand)},
+		{name: "yesterday", args: "[river]", description: "Compare a river's levels with 24 hours ago",
+			handle: withArgs((*TelegramBot).handleYesterdayCommand)},
+		{name: "diff", args: "[river]", description: "Compare a river's levels with the previous reading",
+			handle: withArgs((*TelegramBot).handleDiffCommand)},
+		{name: "level", args: "[river] [station]", description: "Show the latest water level of one station",
+			handle: (*TelegramBot).handleLevelCommand},
+		{name: "station", args: "[name]", description: "Show the latest water level of a station on any river",
+			handle: (*TelegramBot).handleStationCommand},
+		{name: "link", args: "[river]", description: "Show the source pages a river's data comes from",
+			handle: withArgs((*TelegramBot).handleLinkCommand)},
+		{name: "top", args: "[rising]", description: "Show the stations with the highest water level",
+			handle: withArgs((*TelegramBot).handleTopCommand)},
+		{name: "floodstatus", description: "Show the stations above their flood warning or danger level",
+			handle: (*TelegramBot).handleFloodStatusCommand},
+		{name: "watertemp", args: "[min] [max]", description: "Show the stations with water temperature in a range",
+			handle: withArgs((*TelegramBot).handleWaterTempCommand)},
+		{name: "discharge", args: "[min]", description: "Show the stations with discharge above a threshold in m³/s",
+			handle: withArgs((*TelegramBot).handleDischargeCommand)},
+		{name: "convert", args: "[value] cm|m|ft", description: "Convert a water level between units",
+			handle: withArgs((*TelegramBot).handleConvertCommand)},
+		{name: "chart", args: "[river] [station]", description: "Show a water level chart for the last 7 days",
+			handle: (*TelegramBot).handleChartCommand},
+		{name: "trend", args: "[river]", description: "Show how each station changed over the last 24 hours",
+			handle: withArgs((*TelegramBot).handleTrendCommand)},
+		{name: "stats", args: "[river] [station] [days]", description: "Show water level statistics of a station, 30 days by default",
+			handle: (*TelegramBot).handleStatsCommand},
+		{name: "gaps", args: "[river] [station]", description: "Show when a station's data had gaps over the last 7 days",
+			handle: (*TelegramBot).handleGapsCommand},
+		{name: "subscribe", args: "[river] above|below [cm]", description: "Get alerted when a river crosses a level",
+			handle: (*TelegramBot).handleSubscribeCommand},
+		{name: "subscribe_temp", args: "[river] above|below [°C]", description: "Get alerted when a river's water temperature crosses a threshold",
+			handle: (*TelegramBot).handleSubscribeTempCommand},
+		{name: "myalerts", aliases: []string{"alerts_list"}, description: "List and delete your alerts",
+			handle: (*TelegramBot).handleMyAlertsCommand},
+		{name: "follow", args: "[river] [station]", description: "Get a station's new water level whenever it changes",
+			handle: (*TelegramBot).handleFollowCommand},
+		{name: "unfollow", args: "[river] [station]", description: "Stop the updates of a followed station",
+			handle: (*TelegramBot).handleUnfollowCommand},
+		{name: "digest", aliases: []string{"subscribe_daily"}, args: "add [river] [hour]", description: "Get a daily summary of a river at the given hour",
+			handle: (*TelegramBot).handleDigestCommand},
+		{name: "favorite", args: "add|remove [river]", description: "Save a river to your favorites",
+			handle: (*TelegramBot).handleFavoriteCommand},
+		{name: "favorites", description: "Show the current levels of your favorite rivers",
+			handle: (*TelegramBot).handleFavoritesCommand},
+		{name: "export", args: "[river] [since YYYY-MM-DD]", description: "Download a river's readings as CSV",
+			handle: (*TelegramBot).handleExportCommand},
+		{name: "tz", args: "[zone]", description: "Set the time zone timestamps are shown in",
+			handle: (*TelegramBot).handleTimeZoneCommand},
+		{name: "forget", description: "Delete everything the bot stores about this chat",
+			handle: (*TelegramBot).handleForgetCommand},
+		{name: "status", description: "Show when each data source was last refreshed",
+			handle: func(t *TelegramBot, _ *tgbotapi.Message, msg *tgbotapi.MessageConfig) { t.handleStatusCommand(msg) }},
+		{name: "sources", description: "Show where the data comes from",
+			handle: func(t *TelegramBot, _ *tgbotapi.Message, msg *tgbotapi.MessageConfig) { t.handleSourcesCommand(msg) }},
+		{name: "refresh", description: "Fetch fresh data from all sources now", adminOnly: true,
+			handle: (*TelegramBot).handleRefreshCommand},
+		{name: "watchsources", args: "[off]", description: "Get notified when a data source goes down or recovers", adminOnly: true,
+			handle: (*TelegramBot).handleWatchSourcesCommand},
+		{name: "broadcast", args: "[message]", description: "Send an announcement to every registered user", adminOnly: true,
+			handle: (*TelegramBot).handleBroadcastCommand},
+		{name: "help", description: "Show this help message",
+			handle: (*TelegramBot).handleHelpCommand},
+	}
+
+	router = newCommandRouter(unknownCommand)
+	for _, c := range commands {
+		var wrappers []middleware
+		if c.adminOnly {
+			wrappers = append(wrappers, requireAdmin)
+		}
+		wrappers = append(wrappers, logCommand)
+		wrappers = append(wrappers, c.middleware...)
+		router.handle(append([]string{c.name}, c.aliases...), c.handle, wrappers...)
+	}
+}
+
+// withArgs adapts a handler that only needs the command arguments
+func withArgs(handle func(t *TelegramBot, args string, msg *tgbotapi.MessageConfig)) commandHandler {
+	return func(t *TelegramBot, message *tgbotapi.Message, msg *tgbotapi.MessageConfig) {
+		handle(t, message.CommandArguments(), msg)
+	}
+}
+
+// handleCommand dispatches a command to its registered handler
+func (t *TelegramBot) handleCommand(message *tgbotapi.Message, msg *tgbotapi.MessageConfig) {
+	router.dispatch(t, message, msg)
+}
+
+// handleHelpCommand processes the /help command, listing the commands available to the chat
+func (t *TelegramBot) handleHelpCommand(message *tgbotapi.Message, msg *tgbotapi.MessageConfig) {
+	msg.Text = helpText(t.isAdmin(message.Chat.ID))
+}
+
+// helpText lists the registered commands, including admin commands only when admin is set
+func helpText(admin bool) string {
+	var sb strings.Builder
+	sb.WriteString("Available commands:")
+	for _, c := range commands {
+		if c.adminOnly && !admin {
+			continue
+		}
+		sb.WriteString("\n" + c.usage() + " - " + c.description)
+	}
+	return sb.String()
+}
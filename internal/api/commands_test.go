@@ -0,0 +1,54 @@
+package api
+
+import (
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// TestHelpListsRegisteredCommands verifies /help lists every command a chat may run
+func TestHelpListsRegisteredCommands(t *testing.T) {
+	bot := newTestBot(t, nil)
+	bot.admins = map[int64]bool{42: true}
+
+	for _, tt := range []struct {
+		name   string
+		chatID int64
+		admin  bool
+	}{
+		{"user", 1, false},
+		{"admin", 42, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			message := commandMessage("/help")
+			message.Chat.ID = tt.chatID
+			msg := tgbotapi.NewMessage(tt.chatID, "")
+			bot.handleCommand(message, &msg)
+
+			for _, c := range commands {
+				listed := strings.Contains(msg.Text, "\n"+c.usage()+" - "+c.description)
+				if want := !c.adminOnly || tt.admin; listed != want {
+					t.Errorf("Expected /%s listed %v, got:\n%s", c.name, want, msg.Text)
+				}
+			}
+		})
+	}
+}
+
+// TestHandleCommandRoutesAliasesAndRefusesAdminCommands verifies dispatching through the registry
+func TestHandleCommandRoutesAliasesAndRefusesAdminCommands(t *testing.T) {
+	bot := newTestBot(t, nil)
+
+	msg := tgbotapi.NewMessage(1, "")
+	bot.handleCommand(commandMessage("/alerts_list"), &msg)
+	if !strings.Contains(msg.Text, "You have no active alerts") {
+		t.Errorf("Expected /alerts_list to list alerts, got: %s", msg.Text)
+	}
+
+	msg = tgbotapi.NewMessage(1, "")
+	bot.handleCommand(commandMessage("/broadcast hello"), &msg)
+	if msg.Text != "You are not authorized to use this command." {
+		t.Errorf("Expected the not-authorized reply, got %q", msg.Text)
+	}
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/abelzeko/water-bot/internal/usecases"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// handleDiffCommand processes the /diff [river] command, comparing each station's
+// latest level with the reading stored before it
+func (t *TelegramBot) handleDiffCommand(args string, msg *tgbotapi.MessageConfig) {
+	river, err := usecases.SanitizeRiverName(args)
+	if err != nil {
+		msg.Text = "Please specify a river name. Example: /diff ДРИНА"
+		return
+	}
+
+	riverData, err := t.useCase.GetRiverDataByName(river)
+	if err != nil {
+		msg.Text = "Error fetching river data. Please try again later."
+		slog.Error("Error fetching river data", "error", err)
+		return
+	}
+	if len(riverData) == 0 {
+		msg.Text = fmt.Sprintf("No information found for river '%s'. Use /rivers to see the available rivers.", river)
+		return
+	}
+	t.useCase.LocalizeForChat(msg.ChatID, riverData)
+
+	diffs, err := t.useCase.DiffLastReadings(riverData)
+	if err != nil {
+		msg.Text = "Error fetching river data. Please try again later."
+		slog.Error("Error fetching previous readings", "error", err)
+		return
+	}
+	msg.Text = t.useCase.FormatDiff(diffs)
+}
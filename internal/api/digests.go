@@ -0,0 +1,134 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/usecases"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/robfig/cron/v3"
+)
+
+// digestSchedule runs the digest dispatch a few minutes past every hour,
+// after the scraper's hourly refresh has stored fresh data
+const digestSchedule = "5 * * * *"
+
+// digestUsage explains the /digest command
+const digestUsage = "Usage:\n" +
+	"/digest add [river] [hour] - Add a river to your daily digest sent at the given hour\n" +
+	"/digest remove [river] - Remove a river from your daily digest\n" +
+	"/digest - Show your daily digest\n" +
+	"Example: /digest add ДРИНА 8"
+
+// handleDigestCommand processes the /digest [add|remove] command
+func (t *TelegramBot) handleDigestCommand(message *tgbotapi.Message, msg *tgbotapi.MessageConfig) {
+	fields := strings.Fields(message.CommandArguments())
+	if len(fields) == 0 {
+		t.showDigest(message.Chat.ID, msg)
+		return
+	}
+
+	action := strings.ToLower(fields[0])
+	args := strings.Join(fields[1:], " ")
+	switch action {
+	case "add":
+		t.addDigestRiver(message.Chat.ID, args, msg)
+	case "remove", "delete":
+		t.removeDigestRiver(message.Chat.ID, args, msg)
+	default:
+		msg.Text = digestUsage
+	}
+}
+
+// showDigest replies with the chat's current digest settings
+func (t *TelegramBot) showDigest(chatID int64, msg *tgbotapi.MessageConfig) {
+	digest, found, err := t.useCase.GetDigest(chatID)
+	if err != nil {
+		msg.Text = "Error fetching your digest. Please try again later."
+		slog.Error("Error fetching digest", "error", err)
+		return
+	}
+	if !found {
+		msg.Text = "You have no daily digest.\n\n" + digestUsage
+		return
+	}
+	msg.Text = t.useCase.FormatDigest(digest)
+}
+
+// addDigestRiver handles /digest add [river] [hour]
+func (t *TelegramBot) addDigestRiver(chatID int64, args string, msg *tgbotapi.MessageConfig) {
+	river, rest, err := t.splitRiverArgs(args)
+	if err != nil {
+		msg.Text = "Error fetching river data. Please try again later."
+		slog.Error("Error resolving river name", "error", err)
+		return
+	}
+
+	hour, err := strconv.Atoi(strings.TrimSpace(rest))
+	if river == "" || err != nil {
+		msg.Text = digestUsage
+		return
+	}
+
+	digest, err := t.useCase.AddDigestRiver(chatID, river, hour)
+	switch {
+	case errors.Is(err, usecases.ErrInvalidHour):
+		msg.Text = "Please specify an hour between 0 and 23."
+	case errors.Is(err, usecases.ErrUnknownRiver):
+		msg.Text = fmt.Sprintf("No information found for river '%s'. Use /rivers to see the available rivers.", river)
+	case errors.Is(err, usecases.ErrTooManyDigestRivers):
+		msg.Text = "Your digest already includes the maximum number of rivers. Use /digest remove to drop some."
+	case err != nil:
+		msg.Text = "Error saving your digest. Please try again later."
+		slog.Error("Error saving digest", "error", err)
+	default:
+		msg.Text = "Digest saved!\n\n" + t.useCase.FormatDigest(digest)
+	}
+}
+
+// removeDigestRiver handles /digest remove [river]
+func (t *TelegramBot) removeDigestRiver(chatID int64, river string, msg *tgbotapi.MessageConfig) {
+	if river == "" {
+		msg.Text = digestUsage
+		return
+	}
+
+	removed, err := t.useCase.RemoveDigestRiver(chatID, river)
+	if err != nil {
+		msg.Text = "Error updating your digest. Please try again later."
+		slog.Error("Error removing digest river", "error", err)
+		return
+	}
+	if !removed {
+		msg.Text = fmt.Sprintf("River %s is not in your daily digest.", river)
+		return
+	}
+	msg.Text = fmt.Sprintf("River %s removed from your daily digest.", river)
+}
+
+// startDigestScheduler schedules the hourly daily digest dispatch
+func (t *TelegramBot) startDigestScheduler() {
+	c := cron.New()
+	_, err := c.AddFunc(digestSchedule, func() {
+		t.dispatchDigests(time.Now())
+	})
+	if err != nil {
+		slog.Error("Failed to schedule daily digests", "error", err)
+		return
+	}
+	c.Start()
+}
+
+// dispatchDigests sends the digests due during the hour containing now
+func (t *TelegramBot) dispatchDigests(now time.Time) {
+	notifications, err := t.useCase.DueDigests(now)
+	if err != nil {
+		slog.Error("Error building daily digests", "error", err)
+		return
+	}
+	t.sendNotifications(notifications)
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// defaultExportPeriod is how much history /export includes when no start date is given
+const defaultExportPeriod = 30 * 24 * time.Hour
+
+// exportDateLayout is the format of the /export start date
+const exportDateLayout = "2006-01-02"
+
+// handleExportCommand processes the /export [river] [since] command by replying with a CSV document
+func (t *TelegramBot) handleExportCommand(message *tgbotapi.Message, msg *tgbotapi.MessageConfig) {
+	usage := "Usage: /export [river] [since YYYY-MM-DD]\nExample: /export ДРИНА 2025-01-01"
+
+	river, rest, err := t.splitRiverArgs(message.CommandArguments())
+	if err != nil {
+		msg.Text = "Error fetching river data. Please try again later."
+		slog.Error("Error resolving river name", "error", err)
+		return
+	}
+	if river == "" {
+		msg.Text = usage
+		return
+	}
+
+	since := time.Now().Add(-defaultExportPeriod)
+	if rest = strings.TrimSpace(rest); rest != "" {
+		since, err = time.Parse(exportDateLayout, rest)
+		if err != nil {
+			msg.Text = usage
+			return
+		}
+	}
+
+	riverData, err := t.useCase.GetRiverDataByName(river)
+	if err != nil {
+		msg.Text = "Error fetching river data. Please try again later."
+		slog.Error("Error fetching river data", "error", err)
+		return
+	}
+	if len(riverData) == 0 {
+		msg.Text = fmt.Sprintf("No information found for river '%s'. Use /rivers to see the available rivers.", river)
+		return
+	}
+
+	// Stream the CSV straight into the upload instead of building it in memory
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(t.useCase.ExportRiverData(writer, river, since))
+	}()
+
+	document := tgbotapi.NewDocument(message.Chat.ID, tgbotapi.FileReader{
+		Name:   fmt.Sprintf("%s_%s.csv", river, since.Format(exportDateLayout)),
+		Reader: reader,
+	})
+	document.Caption = fmt.Sprintf("Readings for river %s since %s", river, since.Format(exportDateLayout))
+
+	slog.Debug("Sending export", "user", message.From.UserName)
+	_, err = t.sender.Send(document)
+	reader.Close() // Unblocks the export if the upload stopped reading early
+	if err != nil {
+		slog.Error("Error sending export", "error", err)
+		msg.Text = "Error exporting the data. Please try again later."
+	}
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/abelzeko/water-bot/internal/usecases"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// favoriteUsage explains the /favorite command
+const favoriteUsage = "Usage:\n" +
+	"/favorite add [river] - Save a river to your favorites\n" +
+	"/favorite remove [river] - Remove a river from your favorites\n" +
+	"/favorites - Show the current levels of your favorite rivers\n" +
+	"Example: /favorite add ДРИНА"
+
+// handleFavoriteCommand processes the /favorite add|remove [river] command
+func (t *TelegramBot) handleFavoriteCommand(message *tgbotapi.Message, msg *tgbotapi.MessageConfig) {
+	fields := strings.Fields(message.CommandArguments())
+	if len(fields) == 0 {
+		t.showFavorites(message.Chat.ID, msg)
+		return
+	}
+
+	action := strings.ToLower(fields[0])
+	river := strings.Join(fields[1:], " ")
+	switch action {
+	case "add":
+		t.addFavorite(message.Chat.ID, river, msg)
+	case "remove", "delete":
+		t.removeFavorite(message.Chat.ID, river, msg)
+	default:
+		msg.Text = favoriteUsage
+	}
+}
+
+// handleFavoritesCommand processes the /favorites command
+func (t *TelegramBot) handleFavoritesCommand(message *tgbotapi.Message, msg *tgbotapi.MessageConfig) {
+	t.showFavorites(message.Chat.ID, msg)
+}
+
+// showFavorites replies with the current levels of the chat's favorite rivers
+func (t *TelegramBot) showFavorites(chatID int64, msg *tgbotapi.MessageConfig) {
+	favorites, err := t.useCase.GetFavorites(chatID)
+	if err != nil {
+		msg.Text = "Error fetching your favorites. Please try again later."
+		slog.Error("Error fetching favorites", "error", err)
+		return
+	}
+	if len(favorites) == 0 {
+		msg.Text = "You have no favorite rivers.\n\n" + favoriteUsage
+		return
+	}
+
+	text, err := t.useCase.FormatFavorites(chatID, favorites)
+	if err != nil {
+		msg.Text = "Error fetching river data. Please try again later."
+		slog.Error("Error formatting favorites", "error", err)
+		return
+	}
+	msg.Text = text
+}
+
+// addFavorite handles /favorite add [river]
+func (t *TelegramBot) addFavorite(chatID int64, river string, msg *tgbotapi.MessageConfig) {
+	if river == "" {
+		msg.Text = favoriteUsage
+		return
+	}
+
+	saved, err := t.useCase.AddFavorite(chatID, river)
+	switch {
+	case errors.Is(err, usecases.ErrUnknownRiver):
+		msg.Text = fmt.Sprintf("No information found for river '%s'. Use /rivers to see the available rivers.", river)
+	case errors.Is(err, usecases.ErrTooManyFavorites):
+		msg.Text = "You already have the maximum number of favorites. Use /favorite remove to drop some."
+	case err != nil:
+		msg.Text = "Error saving your favorites. Please try again later."
+		slog.Error("Error adding favorite", "error", err)
+	default:
+		msg.Text = fmt.Sprintf("River %s saved to your favorites. Use /favorites to see their levels.", saved)
+	}
+}
+
+// removeFavorite handles /favorite remove [river]
+func (t *TelegramBot) removeFavorite(chatID int64, river string, msg *tgbotapi.MessageConfig) {
+	if river == "" {
+		msg.Text = favoriteUsage
+		return
+	}
+
+	removed, err := t.useCase.RemoveFavorite(chatID, river)
+	if err != nil {
+		msg.Text = "Error updating your favorites. Please try again later."
+		slog.Error("Error removing favorite", "error", err)
+		return
+	}
+	if !removed {
+		msg.Text = fmt.Sprintf("River %s is not in your favorites.", river)
+		return
+	}
+	msg.Text = fmt.Sprintf("River %s removed from your favorites.", river)
+}
@@ -0,0 +1,139 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+	"github.com/abelzeko/water-bot/internal/usecases"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// TestFavoriteCommands verifies favorites can be added and removed repeatedly and are listed with their top station
+func TestFavoriteCommands(t *testing.T) {
+	repo := newTestRepository(t)
+	timestamp := time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC)
+	err := repo.SaveRiverData([]entities.RiverData{
+		{River: "ДРИНА", Station: "Радаљ", WaterLevel: "142", Timestamp: timestamp},
+		{River: "ДРИНА", Station: "Бајина Башта", WaterLevel: "310", WaterTemp: "11.2", Timestamp: timestamp},
+		{River: "САВА", Station: "Шабац", WaterLevel: "250", Timestamp: timestamp},
+	})
+	if err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+	bot := &TelegramBot{sender: &fakeSender{}, useCase: usecases.NewRiverUseCase(repo, nil, nil)}
+
+	steps := []struct {
+		text    string
+		want    []string
+		notWant []string
+	}{
+		{"/favorites", []string{"You have no favorite rivers", "/favorite add"}, nil},
+		{"/favorite add ДРИНА", []string{"River ДРИНА saved to your favorites"}, nil},
+		{"/favorite add ДРИНА", []string{"River ДРИНА saved to your favorites"}, nil},
+		{"/favorite add САВА", []string{"River САВА saved to your favorites"}, nil},
+		{"/favorite add НИЛ", []string{"No information found for river 'НИЛ'"}, nil},
+		{"/favorites", []string{"River ДРИНА:", "Бајина Башта", "310 cm", "11.2 °C", "River САВА:", "Шабац"}, []string{"Радаљ"}},
+		{"/favorite remove ДРИНА", []string{"River ДРИНА removed from your favorites"}, nil},
+		{"/favorite remove ДРИНА", []string{"River ДРИНА is not in your favorites"}, nil},
+		{"/favorites", []string{"River САВА:"}, []string{"ДРИНА"}},
+		{"/favorite fly", []string{"Usage:"}, nil},
+	}
+	for _, step := range steps {
+		msg := tgbotapi.NewMessage(1, "")
+		bot.handleCommand(commandMessage(step.text), &msg)
+		for _, want := range step.want {
+			if !strings.Contains(msg.Text, want) {
+				t.Errorf("%s: expected %q in:\n%s", step.text, want, msg.Text)
+			}
+		}
+		for _, notWant := range step.notWant {
+			if strings.Contains(msg.Text, notWant) {
+				t.Errorf("%s: did not expect %q in:\n%s", step.text, notWant, msg.Text)
+			}
+		}
+	}
+
+	favorites, err := repo.GetFavorites(1)
+	if err != nil {
+		t.Fatalf("Failed to get favorites: %v", err)
+	}
+	if len(favorites) != 1 || favorites[0] != "САВА" {
+		t.Errorf("Expected only САВА to remain, got %v", favorites)
+	}
+}
+
+// TestFavoriteLimit verifies a chat cannot save more than the maximum number of favorites
+func TestFavoriteLimit(t *testing.T) {
+	repo := newTestRepository(t)
+	timestamp := time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC)
+	var data []entities.RiverData
+	for i := 0; i <= 10; i++ {
+		data = append(data, entities.RiverData{River: fmt.Sprintf("RIVER%d", i), Station: "Station", WaterLevel: "100", Timestamp: timestamp})
+	}
+	if err := repo.SaveRiverData(data); err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+	bot := &TelegramBot{sender: &fakeSender{}, useCase: usecases.NewRiverUseCase(repo, nil, nil)}
+
+	for i := 0; i < 10; i++ {
+		msg := tgbotapi.NewMessage(1, "")
+		bot.handleCommand(commandMessage(fmt.Sprintf("/favorite add RIVER%d", i)), &msg)
+		if !strings.Contains(msg.Text, "saved") {
+			t.Fatalf("Expected favorite %d to be saved, got: %s", i, msg.Text)
+		}
+	}
+
+	msg := tgbotapi.NewMessage(1, "")
+	bot.handleCommand(commandMessage("/favorite add RIVER10"), &msg)
+	if !strings.Contains(msg.Text, "maximum number of favorites") {
+		t.Errorf("Expected the limit to be enforced, got: %s", msg.Text)
+	}
+
+	// Re-adding an existing favorite doesn't count against the limit
+	msg = tgbotapi.NewMessage(1, "")
+	bot.handleCommand(commandMessage("/favorite add RIVER0"), &msg)
+	if !strings.Contains(msg.Text, "saved") {
+		t.Errorf("Expected re-adding a favorite to succeed, got: %s", msg.Text)
+	}
+}
+
+// TestHandleForgetCommand verifies /forget deletes the caller's data and nobody else's
+func TestHandleForgetCommand(t *testing.T) {
+	bot := newTestBot(t, nil)
+
+	for _, chatID := range []int64{1, 2} {
+		if _, err := bot.useCase.AddFavorite(chatID, "ДРИНА"); err != nil {
+			t.Fatalf("Failed to add favorite: %v", err)
+		}
+		if _, err := bot.useCase.SetUserTimeZone(chatID, "Europe/Moscow"); err != nil {
+			t.Fatalf("Failed to set time zone: %v", err)
+		}
+	}
+
+	steps := []struct {
+		text string
+		want string
+	}{
+		{"/forget", "Deleted 2 records"},
+		{"/favorites", "You have no favorite rivers."},
+		{"/tz", "Timestamps are shown in Europe/Belgrade"},
+		{"/forget", "There was no data stored about this chat."},
+	}
+	for _, step := range steps {
+		msg := tgbotapi.NewMessage(1, "")
+		bot.handleCommand(commandMessage(step.text), &msg)
+		if !strings.Contains(msg.Text, step.want) {
+			t.Errorf("%s: expected %q in:\n%s", step.text, step.want, msg.Text)
+		}
+	}
+
+	if favorites, err := bot.useCase.GetFavorites(2); err != nil || len(favorites) != 1 {
+		t.Errorf("Expected the other chat's favorite to be kept, got %v, %v", favorites, err)
+	}
+	if zone, err := bot.useCase.UserTimeZone(2); err != nil || zone != "Europe/Moscow" {
+		t.Errorf("Expected the other chat's time zone to be kept, got %q, %v", zone, err)
+	}
+}
@@ -0,0 +1,19 @@
+package api
+
+import (
+	"log/slog"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// handleFloodStatusCommand processes the /floodstatus command, listing the stations
+// currently above their flood warning or danger level
+func (t *TelegramBot) handleFloodStatusCommand(message *tgbotapi.Message, msg *tgbotapi.MessageConfig) {
+	statuses, err := t.useCase.GetFloodStatus()
+	if err != nil {
+		msg.Text = "Error fetching flood levels. Please try again later."
+		slog.Error("Error checking flood levels", "error", err)
+		return
+	}
+	msg.Text = t.useCase.FormatFloodStatus(statuses, t.useCase.UserLocation(message.Chat.ID))
+}
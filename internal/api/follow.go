@@ -0,0 +1,93 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/abelzeko/water-bot/internal/usecases"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// handleFollowCommand processes the /follow [river] [station] command. Without
+// arguments it lists the stations the chat follows.
+func (t *TelegramBot) handleFollowCommand(message *tgbotapi.Message, msg *tgbotapi.MessageConfig) {
+	if message.CommandArguments() == "" {
+		t.showFollows(message.Chat.ID, msg)
+		return
+	}
+
+	river, station, err := t.splitRiverArgs(message.CommandArguments())
+	if err != nil {
+		msg.Text = "Error fetching river data. Please try again later."
+		slog.Error("Error resolving river name", "error", err)
+		return
+	}
+	if river == "" || station == "" {
+		msg.Text = "Please specify a river and a station. Example: /follow ДРИНА Радаљ"
+		return
+	}
+
+	follow, err := t.useCase.Follow(message.Chat.ID, river, station)
+	switch {
+	case errors.Is(err, usecases.ErrUnknownRiver):
+		msg.Text = fmt.Sprintf("No information found for river '%s'. Use /rivers to see the available rivers.", river)
+	case errors.Is(err, usecases.ErrUnknownStation):
+		msg.Text = fmt.Sprintf("No station '%s' found on river %s. Use /river %s to see its stations.", station, river, river)
+	case errors.Is(err, usecases.ErrTooManyFollows):
+		msg.Text = "You already follow the maximum number of stations. Use /unfollow to drop some."
+	case err != nil:
+		msg.Text = "Error saving the follow. Please try again later."
+		slog.Error("Error following station", "error", err)
+	default:
+		msg.Text = fmt.Sprintf("Following %s - %s. You'll get its new water level after each refresh in which it changes.\nUse /unfollow %s %s to stop.",
+			follow.River, follow.Station, follow.River, follow.Station)
+	}
+}
+
+// handleUnfollowCommand processes the /unfollow [river] [station] command
+func (t *TelegramBot) handleUnfollowCommand(message *tgbotapi.Message, msg *tgbotapi.MessageConfig) {
+	river, station, err := t.splitRiverArgs(message.CommandArguments())
+	if err != nil {
+		msg.Text = "Error fetching river data. Please try again later."
+		slog.Error("Error resolving river name", "error", err)
+		return
+	}
+	if river == "" || station == "" {
+		msg.Text = "Please specify a river and a station. Example: /unfollow ДРИНА Радаљ"
+		return
+	}
+
+	removed, err := t.useCase.Unfollow(message.Chat.ID, river, station)
+	if err != nil {
+		msg.Text = "Error updating your follows. Please try again later."
+		slog.Error("Error unfollowing station", "error", err)
+		return
+	}
+	if !removed {
+		msg.Text = fmt.Sprintf("You don't follow station %s on river %s. Use /follow to see the stations you follow.", station, river)
+		return
+	}
+	msg.Text = fmt.Sprintf("Stopped following %s - %s.", river, station)
+}
+
+// showFollows replies with the stations the chat follows
+func (t *TelegramBot) showFollows(chatID int64, msg *tgbotapi.MessageConfig) {
+	follows, err := t.useCase.GetFollows(chatID)
+	if err != nil {
+		msg.Text = "Error fetching your follows. Please try again later."
+		slog.Error("Error fetching follows", "error", err)
+		return
+	}
+	msg.Text = usecases.FormatFollows(follows)
+}
+
+// dispatchFollows sends the new levels of followed stations
+func (t *TelegramBot) dispatchFollows() {
+	notifications, err := t.useCase.CheckFollows()
+	if err != nil {
+		slog.Error("Error checking followed stations", "error", err)
+		return
+	}
+	t.sendNotifications(notifications)
+}
@@ -0,0 +1,27 @@
+package api
+
+import (
+	"fmt"
+	"log/slog"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// handleForgetCommand processes the /forget command, deleting everything stored about the chat
+func (t *TelegramBot) handleForgetCommand(message *tgbotapi.Message, msg *tgbotapi.MessageConfig) {
+	removed, err := t.useCase.ForgetUser(message.Chat.ID)
+	if err != nil {
+		msg.Text = "Error deleting your data. Please try again later."
+		slog.Error("Error forgetting chat", "chat_id", message.Chat.ID, "error", err)
+		return
+	}
+	if removed == 0 {
+		msg.Text = "There was no data stored about this chat."
+		return
+	}
+	records := "records"
+	if removed == 1 {
+		records = "record"
+	}
+	msg.Text = fmt.Sprintf("Deleted %d %s: your registration, alerts, digest, favorites and settings. Use /start to begin again.", removed, records)
+}
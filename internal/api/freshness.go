@@ -0,0 +1,15 @@
+package api
+
+import (
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// prependFreshnessBanner puts a warning above a reply when all stored data is outdated,
+// e.g. during a source outage
+func (t *TelegramBot) prependFreshnessBanner(msg *tgbotapi.MessageConfig) {
+	if banner := t.useCase.FreshnessBanner(time.Now(), t.useCase.UserLocation(msg.ChatID)); banner != "" {
+		msg.Text = banner + msg.Text
+	}
+}
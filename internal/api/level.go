@@ -0,0 +1,41 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/abelzeko/water-bot/internal/usecases"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// levelUsage explains the arguments of the /level command
+const levelUsage = "Please specify a river and a station. Example: /level ДРИНА Радаљ"
+
+// handleLevelCommand processes the /level [river] [station] command, showing the
+// latest water level of a single station
+func (t *TelegramBot) handleLevelCommand(message *tgbotapi.Message, msg *tgbotapi.MessageConfig) {
+	river, station, err := t.splitRiverArgs(message.CommandArguments())
+	if err != nil {
+		msg.Text = "Error fetching river data. Please try again later."
+		slog.Error("Error resolving river name", "error", err)
+		return
+	}
+	if river == "" || station == "" {
+		msg.Text = levelUsage
+		return
+	}
+
+	level, err := t.useCase.GetStationLevel(river, station)
+	switch {
+	case errors.Is(err, usecases.ErrUnknownRiver):
+		msg.Text = fmt.Sprintf("No information found for river '%s'. Use /rivers to see the available rivers.", river)
+	case errors.Is(err, usecases.ErrUnknownStation):
+		msg.Text = fmt.Sprintf("No station '%s' found on river %s. Use /river %s to see its stations.", station, river, river)
+	case err != nil:
+		msg.Text = "Error fetching river data. Please try again later."
+		slog.Error("Error fetching station level", "error", err)
+	default:
+		msg.Text = t.useCase.FormatStationLevel(level, t.useCase.UserLocation(message.Chat.ID))
+	}
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"sync"
+	"time"
+)
+
+// rateLimitCleanupInterval is how often idle chat buckets are dropped
+const rateLimitCleanupInterval = 10 * time.Minute
+
+// tokenBucket tracks the tokens available to one chat
+type tokenBucket struct {
+	tokens   float64
+	lastSeen time.Time
+	warned   bool // Whether the chat was told to slow down since it was last allowed
+}
+
+// rateLimiter is a per-chat token bucket rate limiter
+type rateLimiter struct {
+	mu      sync.Mutex
+	rate    float64 // Tokens added per second
+	burst   float64 // Bucket capacity
+	buckets map[int64]*tokenBucket
+}
+
+// newRateLimiter creates a limiter allowing rate messages per second with the given burst
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	return &rateLimiter{
+		rate:    rate,
+		burst:   float64(burst),
+		buckets: make(map[int64]*tokenBucket),
+	}
+}
+
+// Allow reports whether a chat may send a message at now, consuming a token if so.
+// When the message is throttled, warn reports whether the chat should be told to
+// slow down, which happens once per throttled streak.
+func (l *rateLimiter) Allow(chatID int64, now time.Time) (allowed, warn bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	bucket, ok := l.buckets[chatID]
+	if !ok {
+		bucket = &tokenBucket{tokens: l.burst, lastSeen: now}
+		l.buckets[chatID] = bucket
+	}
+
+	// Refill the tokens accumulated since the chat was last seen
+	elapsed := now.Sub(bucket.lastSeen).Seconds()
+	if elapsed > 0 {
+		bucket.tokens += elapsed * l.rate
+		if bucket.tokens > l.burst {
+			bucket.tokens = l.burst
+		}
+	}
+	bucket.lastSeen = now
+
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		bucket.warned = false
+		return true, false
+	}
+
+	warn = !bucket.warned
+	bucket.warned = true
+	return false, warn
+}
+
+// cleanup drops the buckets of chats that have been idle long enough to be full again
+func (l *rateLimiter) cleanup(now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	refill := time.Duration(l.burst / l.rate * float64(time.Second))
+	for chatID, bucket := range l.buckets {
+		if now.Sub(bucket.lastSeen) > refill {
+			delete(l.buckets, chatID)
+		}
+	}
+}
+
+// runCleanup periodically drops idle buckets
+func (l *rateLimiter) runCleanup(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		l.cleanup(now)
+	}
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestRateLimiterThrottlesRapidMessages verifies a burst from one chat is throttled without affecting others
+func TestRateLimiterThrottlesRapidMessages(t *testing.T) {
+	limiter := newRateLimiter(1, 5)
+	now := time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC)
+
+	var mu sync.Mutex
+	allowed, warnings := 0, 0
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ok, warn := limiter.Allow(1, now)
+			mu.Lock()
+			defer mu.Unlock()
+			if ok {
+				allowed++
+			}
+			if warn {
+				warnings++
+			}
+		}()
+	}
+	wg.Wait()
+
+	if allowed != 5 {
+		t.Errorf("Expected the burst of 5 to be allowed, got %d", allowed)
+	}
+	if warnings != 1 {
+		t.Errorf("Expected a single slow-down warning, got %d", warnings)
+	}
+
+	if ok, _ := limiter.Allow(2, now); !ok {
+		t.Error("Expected another chat not to be throttled")
+	}
+
+	// Two seconds refill two tokens
+	later := now.Add(2 * time.Second)
+	for i := 0; i < 2; i++ {
+		if ok, _ := limiter.Allow(1, later); !ok {
+			t.Errorf("Expected message %d to be allowed after the refill", i)
+		}
+	}
+	if ok, warn := limiter.Allow(1, later); ok || !warn {
+		t.Errorf("Expected a new throttled streak to warn again, got allowed=%v warn=%v", ok, warn)
+	}
+
+	// Idle chats are forgotten once their bucket would be full again
+	limiter.cleanup(later.Add(time.Minute))
+	if len(limiter.buckets) != 0 {
+		t.Errorf("Expected idle buckets to be cleaned up, %d left", len(limiter.buckets))
+	}
+}
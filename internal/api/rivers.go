@@ -0,0 +1,156 @@
+package api
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// telegramMessageLimit is the maximum length of a Telegram message, in UTF-16 code units
+const telegramMessageLimit = 4096
+
+// riversPageSize is how many rivers one page of the /rivers list shows
+const riversPageSize = 30
+
+// riversCallbackPrefix prefixes the callback data of the /rivers page buttons
+const riversCallbackPrefix = "rivers:"
+
+// splitMessage splits text into chunks of at most limit UTF-16 code units, the unit
+// Telegram measures messages in. Chunks break after a newline where possible and
+// only split inside a line that is longer than limit on its own. Concatenating the
+// chunks gives back the original text.
+func splitMessage(text string, limit int) []string {
+	if textLength(text) <= limit {
+		return []string{text}
+	}
+
+	var chunks []string
+	var current strings.Builder
+	currentLen := 0
+	flush := func() {
+		if current.Len() > 0 {
+			chunks = append(chunks, current.String())
+			current.Reset()
+			currentLen = 0
+		}
+	}
+
+	for _, line := range strings.SplitAfter(text, "\n") {
+		lineLen := textLength(line)
+		if currentLen+lineLen > limit {
+			flush()
+		}
+		if lineLen <= limit {
+			current.WriteString(line)
+			currentLen += lineLen
+			continue
+		}
+
+		// The line alone is too long, split it between characters
+		for _, r := range line {
+			runeLen := utf16.RuneLen(r)
+			if runeLen < 0 {
+				runeLen = 1 // Invalid runes are sent as U+FFFD
+			}
+			if currentLen+runeLen > limit {
+				flush()
+			}
+			current.WriteRune(r)
+			currentLen += runeLen
+		}
+	}
+	flush()
+
+	return chunks
+}
+
+// textLength returns the length of text in UTF-16 code units
+func textLength(text string) int {
+	n := 0
+	for _, r := range text {
+		if l := utf16.RuneLen(r); l > 0 {
+			n += l
+		} else {
+			n++
+		}
+	}
+	return n
+}
+
+// riversPage formats one page of the river list. The page is clamped to the valid
+// range and the keyboard is nil when the list fits on a single page.
+func riversPage(rivers []string, page int) (string, *tgbotapi.InlineKeyboardMarkup) {
+	pages := (len(rivers) + riversPageSize - 1) / riversPageSize
+	if page >= pages {
+		page = pages - 1
+	}
+	if page < 0 {
+		page = 0
+	}
+
+	var text strings.Builder
+	if pages > 1 {
+		text.WriteString(fmt.Sprintf("Available rivers (page %d of %d):\n\n", page+1, pages))
+	} else {
+		text.WriteString("Available rivers:\n\n")
+	}
+	start := page * riversPageSize
+	end := start + riversPageSize
+	if end > len(rivers) {
+		end = len(rivers)
+	}
+	for _, river := range rivers[start:end] {
+		text.WriteString("• " + river + "\n")
+	}
+	text.WriteString("\nUse /river [name] to get detailed information.")
+
+	if pages <= 1 {
+		return text.String(), nil
+	}
+
+	var buttons []tgbotapi.InlineKeyboardButton
+	if page > 0 {
+		buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonData("« Prev", riversCallbackPrefix+strconv.Itoa(page-1)))
+	}
+	if page < pages-1 {
+		buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonData("Next »", riversCallbackPrefix+strconv.Itoa(page+1)))
+	}
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(buttons)
+	return text.String(), &keyboard
+}
+
+// riversPageEdit builds the edit that shows the page requested by a /rivers button press
+func (t *TelegramBot) riversPageEdit(query *tgbotapi.CallbackQuery) (tgbotapi.EditMessageTextConfig, error) {
+	page, err := strconv.Atoi(strings.TrimPrefix(query.Data, riversCallbackPrefix))
+	if err != nil {
+		return tgbotapi.EditMessageTextConfig{}, fmt.Errorf("invalid page %q", query.Data)
+	}
+
+	rivers, err := t.useCase.GetAvailableRivers()
+	if err != nil {
+		return tgbotapi.EditMessageTextConfig{}, err
+	}
+
+	text, keyboard := riversPage(rivers, page)
+	if keyboard == nil {
+		return tgbotapi.NewEditMessageText(query.Message.Chat.ID, query.Message.MessageID, text), nil
+	}
+	return tgbotapi.NewEditMessageTextAndMarkup(query.Message.Chat.ID, query.Message.MessageID, text, *keyboard), nil
+}
+
+// handleRiversPageCallback shows another page of the /rivers list in place
+func (t *TelegramBot) handleRiversPageCallback(query *tgbotapi.CallbackQuery) string {
+	edit, err := t.riversPageEdit(query)
+	if err != nil {
+		slog.Error("Error building rivers page", "error", err)
+		return "Error fetching river data."
+	}
+	if _, err := t.sender.Send(edit); err != nil {
+		slog.Error("Error updating rivers list", "error", err)
+	}
+	return ""
+}
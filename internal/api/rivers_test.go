@@ -0,0 +1,187 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+	"github.com/abelzeko/water-bot/internal/usecases"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// TestSplitMessage verifies chunks respect the limit, prefer line breaks and rebuild the text
+func TestSplitMessage(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		limit  int
+		chunks []string
+	}{
+		{"fits", "abc\ndef", 7, []string{"abc\ndef"}},
+		{"exactly at limit", "abcd", 4, []string{"abcd"}},
+		{"one over limit breaks at newline", "abc\ndef", 6, []string{"abc\n", "def"}},
+		{"lines packed up to limit", "ab\ncd\nef\n", 6, []string{"ab\ncd\n", "ef\n"}},
+		{"long line split", "abcdefg", 3, []string{"abc", "def", "g"}},
+		{"long line after short one", "ab\ncdefgh", 4, []string{"ab\n", "cdef", "gh"}},
+		{"cyrillic counted as one unit", "ДРИНА\nСАВА", 6, []string{"ДРИНА\n", "САВА"}},
+		{"emoji counted as two units", "📍📍📍", 4, []string{"📍📍", "📍"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := splitMessage(tt.text, tt.limit)
+			if fmt.Sprint(got) != fmt.Sprint(tt.chunks) {
+				t.Errorf("Expected %q, got %q", tt.chunks, got)
+			}
+		})
+	}
+}
+
+// TestSplitMessageTelegramLimit verifies a long river list is split into valid messages
+func TestSplitMessageTelegramLimit(t *testing.T) {
+	var sb strings.Builder
+	for i := 0; i < 500; i++ {
+		sb.WriteString(fmt.Sprintf("• РЕКА %03d\n", i))
+	}
+	text := sb.String()
+
+	chunks := splitMessage(text, telegramMessageLimit)
+	if len(chunks) < 2 {
+		t.Fatalf("Expected several chunks, got %d", len(chunks))
+	}
+	for i, chunk := range chunks {
+		if n := textLength(chunk); n > telegramMessageLimit {
+			t.Errorf("Chunk %d has %d units, over the limit", i, n)
+		}
+		if !strings.HasSuffix(chunk, "\n") {
+			t.Errorf("Chunk %d doesn't end at a line break", i)
+		}
+	}
+	if strings.Join(chunks, "") != text {
+		t.Error("Expected the chunks to rebuild the original text")
+	}
+}
+
+// TestRiversPageNavigation verifies the Prev/Next buttons walk through the river list
+func TestRiversPageNavigation(t *testing.T) {
+	repo := newTestRepository(t)
+	bot := &TelegramBot{useCase: usecases.NewRiverUseCase(repo, nil, nil)}
+	var data []entities.RiverData
+	for i := 0; i < riversPageSize*2+5; i++ {
+		data = append(data, entities.RiverData{
+			River:      fmt.Sprintf("РЕКА %03d", i),
+			Station:    "STATION",
+			WaterLevel: "100",
+			Timestamp:  time.Now().Truncate(time.Second),
+		})
+	}
+	if err := repo.SaveRiverData(data); err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+
+	msg := tgbotapi.NewMessage(1, "")
+	bot.handleRiversCommand(&msg)
+	if !strings.HasPrefix(msg.Text, "Available rivers (page 1 of 3)") {
+		t.Fatalf("Expected the first page, got:\n%s", msg.Text)
+	}
+	keyboard, ok := msg.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+	if !ok || len(keyboard.InlineKeyboard[0]) != 1 || *keyboard.InlineKeyboard[0][0].CallbackData != "rivers:1" {
+		t.Fatalf("Expected only a Next button on the first page, got %+v", msg.ReplyMarkup)
+	}
+
+	press := func(data string) tgbotapi.EditMessageTextConfig {
+		t.Helper()
+		edit, err := bot.riversPageEdit(&tgbotapi.CallbackQuery{
+			Data:    data,
+			Message: &tgbotapi.Message{MessageID: 7, Chat: &tgbotapi.Chat{ID: 1}},
+		})
+		if err != nil {
+			t.Fatalf("Failed to build page %s: %v", data, err)
+		}
+		if edit.MessageID != 7 || edit.ChatID != 1 {
+			t.Errorf("Expected the original message to be edited, got chat %d message %d", edit.ChatID, edit.MessageID)
+		}
+		return edit
+	}
+
+	edit := press("rivers:1")
+	if !strings.HasPrefix(edit.Text, "Available rivers (page 2 of 3)") || !strings.Contains(edit.Text, "РЕКА 030") {
+		t.Errorf("Expected the second page, got:\n%s", edit.Text)
+	}
+	if buttons := edit.ReplyMarkup.InlineKeyboard[0]; len(buttons) != 2 {
+		t.Errorf("Expected Prev and Next buttons on the middle page, got %d", len(buttons))
+	}
+
+	edit = press("rivers:2")
+	if !strings.Contains(edit.Text, "page 3 of 3") || strings.Count(edit.Text, "• ") != 5 {
+		t.Errorf("Expected the last page with 5 rivers, got:\n%s", edit.Text)
+	}
+	if buttons := edit.ReplyMarkup.InlineKeyboard[0]; len(buttons) != 1 || *buttons[0].CallbackData != "rivers:1" {
+		t.Errorf("Expected only a Prev button on the last page, got %+v", buttons)
+	}
+
+	// Pages past the end are clamped, e.g. when the list shrank since it was sent
+	if edit = press("rivers:9"); !strings.Contains(edit.Text, "page 3 of 3") {
+		t.Errorf("Expected the last page for an out-of-range request, got:\n%s", edit.Text)
+	}
+}
+
+// TestRiverSummaryExpandsStations verifies /river summarizes a river with many stations
+// and that its button swaps the summary for the per-station detail and back
+func TestRiverSummaryExpandsStations(t *testing.T) {
+	repo := newTestRepository(t)
+	sender := &fakeSender{}
+	bot := &TelegramBot{sender: sender, useCase: usecases.NewRiverUseCase(repo, nil, nil)}
+	var data []entities.RiverData
+	for i := 0; i < usecases.DefaultSummaryThreshold+3; i++ {
+		data = append(data, entities.RiverData{
+			River:      "ДУНАВ",
+			Station:    fmt.Sprintf("Станица %d", i+1),
+			WaterLevel: "200",
+			Timestamp:  time.Now().Truncate(time.Second),
+		})
+	}
+	if err := repo.SaveRiverData(data); err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+
+	msg := tgbotapi.NewMessage(1, "")
+	bot.handleRiverCommand("ДУНАВ", &msg)
+	if !strings.HasPrefix(msg.Text, "Summary for river ДУНАВ (8 stations)") || strings.Contains(msg.Text, "Станица") {
+		t.Fatalf("Expected a summary without stations, got:\n%s", msg.Text)
+	}
+	keyboard, ok := msg.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+	if !ok || *keyboard.InlineKeyboard[0][0].CallbackData != "stations:ДУНАВ" {
+		t.Fatalf("Expected a show stations button, got %+v", msg.ReplyMarkup)
+	}
+
+	press := func(data string) tgbotapi.EditMessageTextConfig {
+		t.Helper()
+		sender.sent = nil
+		bot.handleStationsCallback(&tgbotapi.CallbackQuery{
+			Data:    data,
+			Message: &tgbotapi.Message{MessageID: 7, Chat: &tgbotapi.Chat{ID: 1}},
+		})
+		if len(sender.sent) != 1 {
+			t.Fatalf("Expected one edit for %s, got %d messages", data, len(sender.sent))
+		}
+		edit, ok := sender.sent[0].(tgbotapi.EditMessageTextConfig)
+		if !ok || edit.MessageID != 7 {
+			t.Fatalf("Expected the original message to be edited, got %+v", sender.sent[0])
+		}
+		return edit
+	}
+
+	edit := press("stations:ДУНАВ")
+	if !strings.HasPrefix(edit.Text, "Information for river ДУНАВ") || strings.Count(edit.Text, "📍 Station:") != 8 {
+		t.Errorf("Expected every station once expanded, got:\n%s", edit.Text)
+	}
+	if *edit.ReplyMarkup.InlineKeyboard[0][0].CallbackData != "summary:ДУНАВ" {
+		t.Errorf("Expected a hide stations button, got %+v", edit.ReplyMarkup)
+	}
+
+	if edit = press("summary:ДУНАВ"); !strings.HasPrefix(edit.Text, "Summary for river ДУНАВ") {
+		t.Errorf("Expected the summary once collapsed, got:\n%s", edit.Text)
+	}
+}
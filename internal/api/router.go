@@ -0,0 +1,76 @@
+package api
+
+import (
+	"log/slog"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// commandHandler handles a command, filling in the reply in msg
+type commandHandler func(t *TelegramBot, message *tgbotapi.Message, msg *tgbotapi.MessageConfig)
+
+// middleware wraps a command handler. It may act before or after calling next, or reply
+// itself without calling it.
+type middleware func(next commandHandler) commandHandler
+
+// commandRouter maps command names to handlers wrapped in their middleware
+type commandRouter struct {
+	routes   map[string]commandHandler
+	fallback commandHandler // Handles commands without a route
+}
+
+// newCommandRouter creates a router sending commands without a route to fallback
+func newCommandRouter(fallback commandHandler) *commandRouter {
+	return &commandRouter{routes: make(map[string]commandHandler), fallback: fallback}
+}
+
+// handle routes the named commands to handler wrapped in middleware, the first one outermost
+func (r *commandRouter) handle(names []string, handler commandHandler, wrappers ...middleware) {
+	handler = chain(handler, wrappers...)
+	for _, name := range names {
+		r.routes[name] = handler
+	}
+}
+
+// dispatch runs the route of a command, or the fallback when it has none
+func (r *commandRouter) dispatch(t *TelegramBot, message *tgbotapi.Message, msg *tgbotapi.MessageConfig) {
+	handler, ok := r.routes[message.Command()]
+	if !ok {
+		handler = r.fallback
+	}
+	handler(t, message, msg)
+}
+
+// chain wraps handler in middleware so the first one runs first
+func chain(handler commandHandler, wrappers ...middleware) commandHandler {
+	for i := len(wrappers) - 1; i >= 0; i-- {
+		handler = wrappers[i](handler)
+	}
+	return handler
+}
+
+// unknownCommand replies to commands without a route
+func unknownCommand(_ *TelegramBot, message *tgbotapi.Message, msg *tgbotapi.MessageConfig) {
+	slog.Debug("Received unknown command", "command", message.Command(), "user", message.From.UserName)
+	msg.Text = "Unknown command. Use /help to see available commands."
+}
+
+// logCommand logs each command before it is handled
+func logCommand(next commandHandler) commandHandler {
+	return func(t *TelegramBot, message *tgbotapi.Message, msg *tgbotapi.MessageConfig) {
+		slog.Debug("Handling command", "command", message.Command(), "args", message.CommandArguments(), "user", message.From.UserName)
+		next(t, message, msg)
+	}
+}
+
+// requireAdmin refuses the command to chats not in ADMIN_CHAT_IDS
+func requireAdmin(next commandHandler) commandHandler {
+	return func(t *TelegramBot, message *tgbotapi.Message, msg *tgbotapi.MessageConfig) {
+		if !t.isAdmin(message.Chat.ID) {
+			slog.Warn("Refused admin command", "command", message.Command(), "user", message.From.UserName)
+			msg.Text = "You are not authorized to use this command."
+			return
+		}
+		next(t, message, msg)
+	}
+}
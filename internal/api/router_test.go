@@ -0,0 +1,86 @@
+package api
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// recordingMiddleware appends name to calls before and after running the next handler
+func recordingMiddleware(name string, calls *[]string) middleware {
+	return func(next commandHandler) commandHandler {
+		return func(t *TelegramBot, message *tgbotapi.Message, msg *tgbotapi.MessageConfig) {
+			*calls = append(*calls, name+" before")
+			next(t, message, msg)
+			*calls = append(*calls, name+" after")
+		}
+	}
+}
+
+// TestRouterMiddlewareOrder verifies middleware runs outermost first and can stop the chain
+func TestRouterMiddlewareOrder(t *testing.T) {
+	var calls []string
+	handler := func(_ *TelegramBot, _ *tgbotapi.Message, msg *tgbotapi.MessageConfig) {
+		calls = append(calls, "handler")
+		msg.Text = "handled"
+	}
+	stop := func(next commandHandler) commandHandler {
+		return func(_ *TelegramBot, _ *tgbotapi.Message, msg *tgbotapi.MessageConfig) {
+			calls = append(calls, "stop")
+			msg.Text = "stopped"
+		}
+	}
+
+	r := newCommandRouter(unknownCommand)
+	r.handle([]string{"run", "alias"}, handler, recordingMiddleware("outer", &calls), recordingMiddleware("inner", &calls))
+	r.handle([]string{"blocked"}, handler, recordingMiddleware("outer", &calls), stop, recordingMiddleware("inner", &calls))
+
+	tests := []struct {
+		command   string
+		wantCalls []string
+		wantText  string
+	}{
+		{"/run", []string{"outer before", "inner before", "handler", "inner after", "outer after"}, "handled"},
+		{"/alias", []string{"outer before", "inner before", "handler", "inner after", "outer after"}, "handled"},
+		{"/blocked", []string{"outer before", "stop", "outer after"}, "stopped"},
+	}
+	for _, tt := range tests {
+		calls = nil
+		msg := tgbotapi.NewMessage(1, "")
+		r.dispatch(nil, commandMessage(tt.command), &msg)
+		if !reflect.DeepEqual(calls, tt.wantCalls) {
+			t.Errorf("%s: expected calls %v, got %v", tt.command, tt.wantCalls, calls)
+		}
+		if msg.Text != tt.wantText {
+			t.Errorf("%s: expected reply %q, got %q", tt.command, tt.wantText, msg.Text)
+		}
+	}
+}
+
+// TestRouterUnknownCommandFallsThrough verifies commands without a route reach the fallback
+// without running any route's middleware
+func TestRouterUnknownCommandFallsThrough(t *testing.T) {
+	var calls []string
+	r := newCommandRouter(unknownCommand)
+	r.handle([]string{"run"}, func(*TelegramBot, *tgbotapi.Message, *tgbotapi.MessageConfig) {
+		calls = append(calls, "handler")
+	}, recordingMiddleware("outer", &calls))
+
+	msg := tgbotapi.NewMessage(1, "")
+	r.dispatch(nil, commandMessage("/nosuchcommand"), &msg)
+	if len(calls) != 0 {
+		t.Errorf("Expected no route to run, got %v", calls)
+	}
+	if !strings.Contains(msg.Text, "Unknown command") {
+		t.Errorf("Expected the unknown-command reply, got %q", msg.Text)
+	}
+
+	// The bot's own router falls through the same way
+	msg = tgbotapi.NewMessage(1, "")
+	newTestBot(t, nil).handleCommand(commandMessage("/nosuchcommand"), &msg)
+	if !strings.Contains(msg.Text, "Unknown command") {
+		t.Errorf("Expected the unknown-command reply, got %q", msg.Text)
+	}
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Sender is the part of the Telegram bot API the handlers use to reply.
+// *tgbotapi.BotAPI implements it, tests substitute a fake that records messages.
+type Sender interface {
+	Send(c tgbotapi.Chattable) (tgbotapi.Message, error)
+	Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error)
+}
+
+// Ensure the bot API satisfies Sender
+var _ Sender = (*tgbotapi.BotAPI)(nil)
+
+// sendLong sends text to a chat, split at line boundaries into several messages when
+// it is longer than Telegram allows
+func (t *TelegramBot) sendLong(chatID int64, text string) error {
+	return t.sendSplit(tgbotapi.NewMessage(chatID, text))
+}
+
+// sendSplit sends a message, split like sendLong, with any keyboard attached to the
+// last part. It stops at the first part that fails to send.
+func (t *TelegramBot) sendSplit(msg tgbotapi.MessageConfig) error {
+	chunks := splitMessage(msg.Text, telegramMessageLimit)
+	for i, chunk := range chunks {
+		part := msg
+		part.Text = chunk
+		if i < len(chunks)-1 {
+			part.ReplyMarkup = nil
+		}
+		if _, err := t.sender.Send(part); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Retry policy for transient Telegram API failures
+const (
+	sendAttempts     = 2                // Attempts per message, including the first
+	sendRetryBackoff = time.Second      // Wait before retrying a server error
+	maxSendRetryWait = 30 * time.Second // Longest Retry-After honored, longer ones fail straight away
+)
+
+// retrySender retries sends that failed with a rate limit (429) or a Telegram server
+// error (5xx), honoring the Retry-After Telegram asks for. Other errors, such as the
+// user having blocked the bot, are returned immediately.
+type retrySender struct {
+	Sender
+	attempts int
+	backoff  time.Duration
+	sleep    func(time.Duration)
+}
+
+// newRetrySender wraps a sender with the default retry policy
+func newRetrySender(sender Sender) *retrySender {
+	return &retrySender{Sender: sender, attempts: sendAttempts, backoff: sendRetryBackoff, sleep: time.Sleep}
+}
+
+// Send implements Sender, retrying transient failures
+func (r *retrySender) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	var message tgbotapi.Message
+	err := r.retry(func() error {
+		var err error
+		message, err = r.Sender.Send(c)
+		return err
+	})
+	return message, err
+}
+
+// Request implements Sender, retrying transient failures
+func (r *retrySender) Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error) {
+	var response *tgbotapi.APIResponse
+	err := r.retry(func() error {
+		var err error
+		response, err = r.Sender.Request(c)
+		return err
+	})
+	return response, err
+}
+
+// retry calls attempt until it succeeds, fails with an error that isn't transient or
+// runs out of attempts
+func (r *retrySender) retry(attempt func() error) error {
+	var err error
+	for i := 0; i < r.attempts; i++ {
+		if err = attempt(); err == nil {
+			return nil
+		}
+		wait, ok := r.retryDelay(err)
+		if !ok || i == r.attempts-1 {
+			return err
+		}
+		slog.Warn("Telegram request failed, retrying", "wait", wait, "error", err)
+		r.sleep(wait)
+	}
+	return err
+}
+
+// retryDelay reports whether a failed request is worth retrying and how long to wait first
+func (r *retrySender) retryDelay(err error) (time.Duration, bool) {
+	var apiErr *tgbotapi.Error
+	if !errors.As(err, &apiErr) {
+		return 0, false
+	}
+	switch {
+	case apiErr.Code == http.StatusTooManyRequests:
+		if apiErr.RetryAfter <= 0 {
+			return r.backoff, true
+		}
+		wait := time.Duration(apiErr.RetryAfter) * time.Second
+		return wait, wait <= maxSendRetryWait
+	case apiErr.Code >= http.StatusInternalServerError:
+		return r.backoff, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,192 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+	"github.com/abelzeko/water-bot/internal/usecases"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// flakySender fails with the given errors, one per call, and then records messages like fakeSender
+type flakySender struct {
+	fakeSender
+	errs  []error
+	calls int
+}
+
+// Send implements Sender
+func (f *flakySender) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	f.calls++
+	if len(f.errs) > 0 {
+		err := f.errs[0]
+		f.errs = f.errs[1:]
+		return tgbotapi.Message{}, err
+	}
+	return f.fakeSender.Send(c)
+}
+
+// apiError builds a Telegram API error with an optional Retry-After in seconds
+func apiError(code, retryAfter int) error {
+	return &tgbotapi.Error{Code: code, Message: http.StatusText(code), ResponseParameters: tgbotapi.ResponseParameters{RetryAfter: retryAfter}}
+}
+
+// TestRetrySender verifies which failures are retried, how long it waits and that it gives up
+func TestRetrySender(t *testing.T) {
+	tests := []struct {
+		name      string
+		errs      []error
+		wantErr   bool
+		wantCalls int
+		wantWaits []time.Duration
+	}{
+		{"success", nil, false, 1, nil},
+		{"rate limited once", []error{apiError(http.StatusTooManyRequests, 3)}, false, 2, []time.Duration{3 * time.Second}},
+		{"rate limited without retry-after", []error{apiError(http.StatusTooManyRequests, 0)}, false, 2, []time.Duration{sendRetryBackoff}},
+		{"server error once", []error{apiError(http.StatusBadGateway, 0)}, false, 2, []time.Duration{sendRetryBackoff}},
+		{"server error twice", []error{apiError(http.StatusInternalServerError, 0), apiError(http.StatusInternalServerError, 0)}, true, 2, []time.Duration{sendRetryBackoff}},
+		{"retry-after too long", []error{apiError(http.StatusTooManyRequests, 120)}, true, 1, nil},
+		{"blocked", []error{apiError(http.StatusForbidden, 0)}, true, 1, nil},
+		{"network error", []error{errors.New("connection reset")}, true, 1, nil},
+	}
+	for _, tt := range tests {
+		flaky := &flakySender{errs: tt.errs}
+		var waits []time.Duration
+		sender := newRetrySender(flaky)
+		sender.sleep = func(d time.Duration) { waits = append(waits, d) }
+
+		_, err := sender.Send(tgbotapi.NewMessage(1, "hello"))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error %v, got %v", tt.name, tt.wantErr, err)
+		}
+		if flaky.calls != tt.wantCalls {
+			t.Errorf("%s: expected %d attempts, got %d", tt.name, tt.wantCalls, flaky.calls)
+		}
+		if len(waits) != len(tt.wantWaits) || (len(waits) > 0 && waits[0] != tt.wantWaits[0]) {
+			t.Errorf("%s: expected waits %v, got %v", tt.name, tt.wantWaits, waits)
+		}
+	}
+}
+
+// TestHandleUpdateRetriesAndUnregistersBlockedChats verifies a reply survives one transient
+// failure and that a chat that blocked the bot is unregistered
+func TestHandleUpdateRetriesAndUnregistersBlockedChats(t *testing.T) {
+	bot := newTestBot(t, nil)
+	flaky := &flakySender{errs: []error{apiError(http.StatusTooManyRequests, 1)}}
+	sender := newRetrySender(flaky)
+	sender.sleep = func(time.Duration) {}
+	bot.sender = sender
+
+	bot.handleUpdate(tgbotapi.Update{Message: commandMessage("/river ДРИНА")})
+	if texts := flaky.texts(); len(texts) != 1 {
+		t.Fatalf("Expected the reply to be delivered on the retry, got %v", texts)
+	}
+
+	if err := bot.useCase.RegisterUser(entities.User{ChatID: 1, Username: "tester"}); err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	flaky.errs = []error{apiError(http.StatusForbidden, 0)}
+	bot.handleUpdate(tgbotapi.Update{Message: commandMessage("/river ДРИНА")})
+
+	users, err := bot.useCase.GetAllUsers()
+	if err != nil {
+		t.Fatalf("Failed to get users: %v", err)
+	}
+	if len(users) != 0 {
+		t.Errorf("Expected the blocked chat to be unregistered, got %+v", users)
+	}
+}
+
+// TestSendLongSplitsOversizedText verifies long replies are sent as several messages at line boundaries
+func TestSendLongSplitsOversizedText(t *testing.T) {
+	bot := newTestBot(t, nil)
+	sender := bot.sender.(*fakeSender)
+
+	// 100 lines of 100 characters fit 40 to a message
+	line := strings.Repeat("x", 99) + "\n"
+	text := strings.Repeat(line, 100)
+	if err := bot.sendLong(1, text); err != nil {
+		t.Fatalf("Failed to send: %v", err)
+	}
+	texts := sender.texts()
+	if len(texts) != 3 {
+		t.Fatalf("Expected 3 messages, got %d", len(texts))
+	}
+	for i, want := range []int{40, 40, 20} {
+		if got := strings.Count(texts[i], "\n"); got != want || !strings.HasSuffix(texts[i], "\n") {
+			t.Errorf("Message %d: expected %d whole lines, got %d", i, want, got)
+		}
+	}
+
+	// A river with many stations, formatted as /river shows it in full
+	var data []entities.RiverData
+	for i := 0; i < 60; i++ {
+		data = append(data, entities.RiverData{
+			River:      "ДУНАВ",
+			Station:    fmt.Sprintf("Станица %02d", i),
+			WaterLevel: "250",
+			WaterTemp:  "12.5",
+			Tendency:   entities.Rising,
+			Timestamp:  time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC),
+		})
+	}
+	info := bot.useCase.FormatRiverInfo(data)
+	if textLength(info) <= telegramMessageLimit {
+		t.Fatalf("Expected the river info to exceed the limit, got %d", textLength(info))
+	}
+	sender.sent = nil
+	if err := bot.sendLong(1, info); err != nil {
+		t.Fatalf("Failed to send: %v", err)
+	}
+	texts = sender.texts()
+	if len(texts) < 2 || strings.Join(texts, "") != info {
+		t.Fatalf("Expected the river info split over several messages, got %d", len(texts))
+	}
+	for i, text := range texts {
+		if textLength(text) > telegramMessageLimit {
+			t.Errorf("Message %d is %d long, over the limit", i, textLength(text))
+		}
+	}
+}
+
+// TestNotificationToBlockedChatRemovesIt verifies a notification the user blocked removes
+// the chat's digest, alerts and follows so they stop targeting it
+func TestNotificationToBlockedChatRemovesIt(t *testing.T) {
+	bot := newTestBot(t, nil)
+	flaky := &flakySender{errs: []error{apiError(http.StatusForbidden, 0)}}
+	bot.sender = flaky
+
+	uc := bot.useCase
+	if err := uc.RegisterUser(entities.User{ChatID: 7, Username: "tester"}); err != nil {
+		t.Fatalf("Failed to register user: %v", err)
+	}
+	if _, err := uc.AddDigestRiver(7, "ДРИНА", 8); err != nil {
+		t.Fatalf("Failed to add digest: %v", err)
+	}
+	if _, err := uc.Subscribe(7, "ДРИНА", entities.AlertLevel, entities.AlertAbove, 300); err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	if _, err := uc.Follow(7, "ДРИНА", "Радаљ"); err != nil {
+		t.Fatalf("Failed to follow: %v", err)
+	}
+
+	bot.sendNotifications([]usecases.Notification{{ChatID: 7, Text: "🔔 Alert"}})
+
+	if _, found, err := uc.GetDigest(7); err != nil || found {
+		t.Errorf("Expected the digest to be deleted, got found=%v (err %v)", found, err)
+	}
+	if subs, err := uc.GetSubscriptions(7); err != nil || len(subs) != 0 {
+		t.Errorf("Expected the alerts to be deleted, got %v (err %v)", subs, err)
+	}
+	if follows, err := uc.GetFollows(7); err != nil || len(follows) != 0 {
+		t.Errorf("Expected the follows to be deleted, got %v (err %v)", follows, err)
+	}
+	if users, err := uc.GetAllUsers(); err != nil || len(users) != 0 {
+		t.Errorf("Expected the chat to be unregistered, got %v (err %v)", users, err)
+	}
+}
@@ -0,0 +1,35 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/abelzeko/water-bot/internal/usecases"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// stationUsage explains the argument of the /station command
+const stationUsage = "Please specify a station. Example: /station Сремска Митровица"
+
+// handleStationCommand processes the /station [name] command, showing the latest level
+// of a station without knowing its river
+func (t *TelegramBot) handleStationCommand(message *tgbotapi.Message, msg *tgbotapi.MessageConfig) {
+	station := strings.TrimSpace(message.CommandArguments())
+	if station == "" {
+		msg.Text = stationUsage
+		return
+	}
+
+	levels, err := t.useCase.FindStation(station)
+	switch {
+	case errors.Is(err, usecases.ErrUnknownStation):
+		msg.Text = fmt.Sprintf("No station '%s' found on any river. Use /river [name] to see a river's stations.", station)
+	case err != nil:
+		msg.Text = "Error fetching river data. Please try again later."
+		slog.Error("Error looking up station", "station", station, "error", err)
+	default:
+		msg.Text = t.useCase.FormatStationMatches(levels, t.useCase.UserLocation(message.Chat.ID))
+	}
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/usecases"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Periods the /stats command covers, in days
+const (
+	defaultStatsDays = 30
+	maxStatsDays     = 365
+)
+
+// statsUsage explains the arguments of the /stats command
+const statsUsage = "Please specify a river and a station, optionally with a number of days. Example: /stats ДРИНА Радаљ 30"
+
+// handleStatsCommand processes the /stats [river] [station] [days] command, showing
+// the distribution of a station's water level over a period
+func (t *TelegramBot) handleStatsCommand(message *tgbotapi.Message, msg *tgbotapi.MessageConfig) {
+	river, rest, err := t.splitRiverArgs(message.CommandArguments())
+	if err != nil {
+		msg.Text = "Error fetching river data. Please try again later."
+		slog.Error("Error resolving river name", "error", err)
+		return
+	}
+
+	// A trailing number is the period, the words before it name the station
+	days := defaultStatsDays
+	words := strings.Fields(rest)
+	if len(words) > 0 {
+		if value, err := strconv.Atoi(words[len(words)-1]); err == nil {
+			if value < 1 || value > maxStatsDays {
+				msg.Text = fmt.Sprintf("The period must be between 1 and %d days.", maxStatsDays)
+				return
+			}
+			days = value
+			words = words[:len(words)-1]
+		}
+	}
+	station := strings.Join(words, " ")
+	if river == "" || station == "" {
+		msg.Text = statsUsage
+		return
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	stats, err := t.useCase.StationStats(river, station, since)
+	switch {
+	case errors.Is(err, usecases.ErrUnknownRiver):
+		msg.Text = fmt.Sprintf("No information found for river '%s'. Use /rivers to see the available rivers.", river)
+	case errors.Is(err, usecases.ErrUnknownStation):
+		msg.Text = fmt.Sprintf("No station '%s' found on river %s. Use /river %s to see its stations.", station, river, river)
+	case errors.Is(err, usecases.ErrInsufficientHistory):
+		msg.Text = fmt.Sprintf("No water level readings of %s - %s were stored in the last %d days.", river, station, days)
+	case err != nil:
+		msg.Text = "Error computing statistics. Please try again later."
+		slog.Error("Error computing station statistics", "error", err)
+	default:
+		msg.Text = usecases.FormatStationStats(stats, days, t.useCase.UserLocation(message.Chat.ID))
+	}
+}
@@ -0,0 +1,202 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+	"github.com/abelzeko/water-bot/internal/units"
+	"github.com/abelzeko/water-bot/internal/usecases"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// unsubscribeCallbackPrefix prefixes the callback data of alert delete buttons
+const unsubscribeCallbackPrefix = "unsub:"
+
+// dataCheckInterval is how often the bot checks the repository for newly refreshed data
+const dataCheckInterval = time.Minute
+
+// handleSubscribeCommand processes the /subscribe [river] above|below [cm] command
+func (t *TelegramBot) handleSubscribeCommand(message *tgbotapi.Message, msg *tgbotapi.MessageConfig) {
+	usage := "Usage: /subscribe [river] above|below [level in cm]\nExample: /subscribe ДРИНА above 300"
+	t.subscribe(message, msg, entities.AlertLevel, usage)
+}
+
+// handleSubscribeTempCommand processes the /subscribe_temp [river] above|below [°C] command
+func (t *TelegramBot) handleSubscribeTempCommand(message *tgbotapi.Message, msg *tgbotapi.MessageConfig) {
+	usage := "Usage: /subscribe_temp [river] above|below [temperature in °C]\nExample: /subscribe_temp ДРИНА above 16"
+	t.subscribe(message, msg, entities.AlertTemp, usage)
+}
+
+// subscribe parses the arguments shared by the alert commands and saves an alert of the given kind
+func (t *TelegramBot) subscribe(message *tgbotapi.Message, msg *tgbotapi.MessageConfig, kind entities.AlertKind, usage string) {
+	river, rest, err := t.splitRiverArgs(message.CommandArguments())
+	if err != nil {
+		msg.Text = "Error fetching river data. Please try again later."
+		slog.Error("Error resolving river name", "error", err)
+		return
+	}
+
+	fields := strings.Fields(rest)
+	if river == "" || len(fields) != 2 {
+		msg.Text = usage
+		return
+	}
+
+	direction := entities.AlertDirection(strings.ToLower(fields[0]))
+	if direction != entities.AlertAbove && direction != entities.AlertBelow {
+		msg.Text = usage
+		return
+	}
+
+	threshold, err := units.ParseNumber(fields[1])
+	if err != nil {
+		msg.Text = usage
+		return
+	}
+
+	sub, err := t.useCase.Subscribe(message.Chat.ID, river, kind, direction, threshold)
+	switch {
+	case errors.Is(err, usecases.ErrUnknownRiver):
+		msg.Text = fmt.Sprintf("No information found for river '%s'. Use /rivers to see the available rivers.", river)
+	case errors.Is(err, usecases.ErrTooManySubscriptions):
+		msg.Text = "You already have the maximum number of alerts. Use /myalerts to remove some."
+	case err != nil:
+		msg.Text = "Error saving the alert. Please try again later."
+		slog.Error("Error creating subscription", "error", err)
+	case kind == entities.AlertTemp:
+		msg.Text = fmt.Sprintf("Alert saved! You'll be notified when the water temperature of river %s goes %s %s at any station.\nUse /myalerts to manage your alerts.",
+			sub.River, sub.Direction, sub.FormatThreshold())
+	default:
+		msg.Text = fmt.Sprintf("Alert saved! You'll be notified when river %s goes %s %s.\nUse /myalerts to manage your alerts.",
+			sub.River, sub.Direction, sub.FormatThreshold())
+	}
+}
+
+// handleMyAlertsCommand processes the /myalerts command
+func (t *TelegramBot) handleMyAlertsCommand(message *tgbotapi.Message, msg *tgbotapi.MessageConfig) {
+	subs, err := t.useCase.GetSubscriptions(message.Chat.ID)
+	if err != nil {
+		msg.Text = "Error fetching your alerts. Please try again later."
+		slog.Error("Error fetching subscriptions", "error", err)
+		return
+	}
+
+	msg.Text = t.formatAlertsList(subs)
+	if len(subs) > 0 {
+		msg.ReplyMarkup = alertsKeyboard(subs)
+	}
+}
+
+// formatAlertsList formats a chat's alerts for the /myalerts reply
+func (t *TelegramBot) formatAlertsList(subs []entities.Subscription) string {
+	if len(subs) == 0 {
+		return "You have no active alerts. Use /subscribe to create one."
+	}
+
+	text := "Your alerts:\n\n"
+	for _, sub := range subs {
+		text += t.useCase.FormatSubscription(sub) + "\n"
+	}
+	text += "\nTap a button below to delete an alert."
+	return text
+}
+
+// alertsKeyboard builds one delete button per alert
+func alertsKeyboard(subs []entities.Subscription) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, sub := range subs {
+		label := fmt.Sprintf("❌ %s %s %s", sub.River, sub.Direction, sub.FormatThreshold())
+		data := unsubscribeCallbackPrefix + strconv.FormatInt(sub.ID, 10)
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(label, data)))
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// handleUnsubscribeCallback deletes the alert referenced by a button press.
+// Deletion is scoped to the chat the button was pressed in.
+func (t *TelegramBot) handleUnsubscribeCallback(query *tgbotapi.CallbackQuery) string {
+	id, err := strconv.ParseInt(strings.TrimPrefix(query.Data, unsubscribeCallbackPrefix), 10, 64)
+	if err != nil {
+		return "Invalid alert."
+	}
+
+	chatID := query.Message.Chat.ID
+	deleted, err := t.useCase.Unsubscribe(chatID, id)
+	if err != nil {
+		slog.Error("Error deleting subscription", "error", err)
+		return "Error deleting the alert."
+	}
+	if !deleted {
+		return "Alert not found."
+	}
+
+	// Refresh the list in place so the deleted alert disappears
+	subs, err := t.useCase.GetSubscriptions(chatID)
+	if err != nil {
+		slog.Error("Error fetching subscriptions", "error", err)
+		return "Alert deleted."
+	}
+	var edit tgbotapi.EditMessageTextConfig
+	if len(subs) > 0 {
+		edit = tgbotapi.NewEditMessageTextAndMarkup(chatID, query.Message.MessageID, t.formatAlertsList(subs), alertsKeyboard(subs))
+	} else {
+		edit = tgbotapi.NewEditMessageText(chatID, query.Message.MessageID, t.formatAlertsList(subs))
+	}
+	if _, err := t.sender.Send(edit); err != nil {
+		slog.Error("Error updating alerts list", "error", err)
+	}
+
+	return "Alert deleted."
+}
+
+// watchDataUpdates periodically checks for freshly refreshed data and runs the
+// post-refresh hooks. The scraper may run in another process, so the bot detects
+// refreshes by watching the newest stored timestamp. Source outages bring no new
+// data, so they are checked on every tick.
+func (t *TelegramBot) watchDataUpdates(interval time.Duration) {
+	var lastSeen time.Time
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		t.dispatchSourceOutages()
+
+		lastUpdate, err := t.useCase.GetLastUpdateTime()
+		if err != nil {
+			slog.Error("Error checking for data updates", "error", err)
+			continue
+		}
+		if !lastUpdate.After(lastSeen) {
+			continue
+		}
+		lastSeen = lastUpdate
+
+		slog.Info("New data available, checking alerts and followed stations", "last_update", lastUpdate.Format(time.RFC3339))
+		t.dispatchAlerts()
+		t.dispatchFollows()
+	}
+}
+
+// dispatchAlerts evaluates all alert subscriptions and sends the resulting notifications
+func (t *TelegramBot) dispatchAlerts() {
+	notifications, err := t.useCase.CheckAlerts(time.Now())
+	if err != nil {
+		slog.Error("Error checking alerts", "error", err)
+		return
+	}
+	t.sendNotifications(notifications)
+}
+
+// sendNotifications delivers notifications produced by the use case
+func (t *TelegramBot) sendNotifications(notifications []usecases.Notification) {
+	for _, n := range notifications {
+		if err := t.sendLong(n.ChatID, n.Text); err != nil {
+			t.handleSendError(n.ChatID, err)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Callback data prefixes of the buttons that expand and collapse a river summary
+const (
+	showStationsCallbackPrefix = "stations:"
+	hideStationsCallbackPrefix = "summary:"
+)
+
+// maxCallbackData is the most bytes Telegram accepts as a button's callback data
+const maxCallbackData = 64
+
+// riverReply formats a river's readings for /river. Rivers with more stations than the
+// summary threshold get a summary with a button revealing the per-station detail, or
+// with expanded set the detail with a button collapsing it again. The keyboard is nil
+// when the readings are shown in full without a summary.
+func (t *TelegramBot) riverReply(riverData []entities.RiverData, expanded bool) (string, *tgbotapi.InlineKeyboardMarkup) {
+	if !t.useCase.ShouldSummarize(riverData) {
+		return t.useCase.FormatRiverInfo(riverData), nil
+	}
+
+	// Names too long to fit in the callback data can't be expanded later, show them in full
+	river := riverData[0].River
+	if len(showStationsCallbackPrefix+river) > maxCallbackData || len(hideStationsCallbackPrefix+river) > maxCallbackData {
+		return t.useCase.FormatRiverInfo(riverData), nil
+	}
+
+	if expanded {
+		keyboard := tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("▴ Hide stations", hideStationsCallbackPrefix+river),
+		))
+		return t.useCase.FormatRiverInfo(riverData), &keyboard
+	}
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("▾ Show stations (%d)", len(riverData)), showStationsCallbackPrefix+river),
+	))
+	return t.useCase.FormatRiverSummary(riverData), &keyboard
+}
+
+// handleStationsCallback expands a river summary into per-station detail, or collapses it back
+func (t *TelegramBot) handleStationsCallback(query *tgbotapi.CallbackQuery) string {
+	expanded := strings.HasPrefix(query.Data, showStationsCallbackPrefix)
+	river := strings.TrimPrefix(strings.TrimPrefix(query.Data, showStationsCallbackPrefix), hideStationsCallbackPrefix)
+
+	riverData, err := t.useCase.GetRiverDataByName(river)
+	if err != nil {
+		slog.Error("Error fetching river data", "error", err)
+		return "Error fetching river data."
+	}
+	if len(riverData) == 0 {
+		return fmt.Sprintf("No information found for river '%s'.", river)
+	}
+	t.useCase.LocalizeForChat(query.Message.Chat.ID, riverData)
+
+	text, keyboard := t.riverReply(riverData, expanded)
+
+	// A message can't be edited past Telegram's length limit, send the detail as new messages instead
+	if textLength(text) > telegramMessageLimit {
+		if err := t.sendLong(query.Message.Chat.ID, text); err != nil {
+			slog.Error("Error sending river stations", "error", err)
+		}
+		return ""
+	}
+
+	var edit tgbotapi.EditMessageTextConfig
+	if keyboard == nil {
+		edit = tgbotapi.NewEditMessageText(query.Message.Chat.ID, query.Message.MessageID, text)
+	} else {
+		edit = tgbotapi.NewEditMessageTextAndMarkup(query.Message.Chat.ID, query.Message.MessageID, text, *keyboard)
+	}
+	if _, err := t.sender.Send(edit); err != nil {
+		slog.Error("Error updating river summary", "error", err)
+	}
+	return ""
+}
@@ -3,55 +3,146 @@ package api
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
 
+	"github.com/abelzeko/water-bot/internal/charts"
+	"github.com/abelzeko/water-bot/internal/entities"
+	"github.com/abelzeko/water-bot/internal/units"
 	"github.com/abelzeko/water-bot/internal/usecases"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
+// chartPeriod is how much history the /chart command plots
+const chartPeriod = 7 * 24 * time.Hour
+
+// maxRiverSuggestions limits the alternatives offered when a river name isn't found
+const maxRiverSuggestions = 3
+
+// naturalLanguageTimeout bounds how long a free-text query may wait for the AI service
+const naturalLanguageTimeout = 30 * time.Second
+
 // TelegramBot handles interactions with the Telegram API
 type TelegramBot struct {
-	bot     *tgbotapi.BotAPI
+	bot     *tgbotapi.BotAPI // Receives updates, nil when not connected to Telegram
+	sender  Sender           // Sends replies, the bot API itself in production
 	useCase *usecases.RiverUseCase
+	limiter *rateLimiter   // nil disables rate limiting
+	admins  map[int64]bool // Chats allowed to run admin commands
+}
+
+// BotConfig holds the settings of the Telegram bot
+type BotConfig struct {
+	AdminChatIDs []int64 // Chats allowed to run admin commands
+	RateLimit    float64 // Messages per second each chat may send, 0 disables rate limiting
+	RateBurst    int     // Messages a chat may send at once before RateLimit applies
 }
 
 // NewTelegramBot creates a new Telegram bot handler
-func NewTelegramBot(botToken string, useCase *usecases.RiverUseCase) (*TelegramBot, error) {
+func NewTelegramBot(botToken string, cfg BotConfig, useCase *usecases.RiverUseCase) (*TelegramBot, error) {
 	bot, err := tgbotapi.NewBotAPI(botToken)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create bot: %v", err)
 	}
 
+	var limiter *rateLimiter
+	if cfg.RateLimit > 0 {
+		limiter = newRateLimiter(cfg.RateLimit, cfg.RateBurst)
+	} else {
+		slog.Info("Rate limiting is disabled")
+	}
+
 	return &TelegramBot{
 		bot:     bot,
+		sender:  newRetrySender(bot),
 		useCase: useCase,
+		limiter: limiter,
+		admins:  adminSet(cfg.AdminChatIDs),
 	}, nil
 }
 
+// CheckAuthorized reports an error unless the bot is authorized with Telegram
+func (t *TelegramBot) CheckAuthorized() error {
+	if t.bot == nil || t.bot.Self.ID == 0 {
+		return errors.New("bot is not authorized with Telegram")
+	}
+	return nil
+}
+
 // Start begins listening for and handling Telegram messages
 func (t *TelegramBot) Start() {
-	log.Printf("Authorized on Telegram account %s", t.bot.Self.UserName)
+	slog.Info("Authorized on Telegram", "account", t.bot.Self.UserName)
 
 	u := tgbotapi.NewUpdate(0)
 	u.Timeout = 60
 
 	updates := t.bot.GetUpdatesChan(u)
-	log.Println("Bot is now listening for messages...")
+	slog.Info("Bot is now listening for messages")
 
-	for update := range updates {
-		if update.Message == nil {
-			continue
-		}
+	go t.watchDataUpdates(dataCheckInterval)
+	if t.limiter != nil {
+		go t.limiter.runCleanup(rateLimitCleanupInterval)
+	}
+	t.startDigestScheduler()
+
+	// Handle updates concurrently so a slow reply doesn't block other chats
+	dispatchUpdates(updates, updateWorkers, t.handleUpdate)
+}
+
+// handleUpdate processes a single Telegram update
+func (t *TelegramBot) handleUpdate(update tgbotapi.Update) {
+	if update.CallbackQuery != nil {
+		t.handleCallback(update.CallbackQuery)
+		return
+	}
+	if update.Message == nil {
+		return
+	}
+
+	// Log incoming messages
+	slog.Info("Received message",
+		"chat_id", update.Message.Chat.ID,
+		"user", update.Message.From.UserName,
+		"user_id", update.Message.From.ID,
+		"text", update.Message.Text)
+
+	if !t.allowMessage(update.Message) {
+		return
+	}
+
+	// Track activity of registered users, a failure here shouldn't block the reply
+	if err := t.useCase.TouchUser(update.Message.Chat.ID, update.Message.Time()); err != nil {
+		slog.Error("Error updating last seen", "chat_id", update.Message.Chat.ID, "error", err)
+	}
+
+	t.handleMessage(update)
+}
 
-		// Log incoming messages
-		log.Printf("Received message from %s (ID: %d): %s",
-			update.Message.From.UserName,
-			update.Message.From.ID,
-			update.Message.Text)
+// allowMessage applies the per-chat rate limit, telling the chat to slow down
+// the first time a message is throttled
+func (t *TelegramBot) allowMessage(message *tgbotapi.Message) bool {
+	if t.limiter == nil {
+		return true
+	}
 
-		t.handleMessage(update)
+	allowed, warn := t.limiter.Allow(message.Chat.ID, time.Now())
+	if allowed {
+		return true
+	}
+
+	slog.Warn("Throttled message", "chat_id", message.Chat.ID, "user", message.From.UserName)
+	if warn {
+		reply := tgbotapi.NewMessage(message.Chat.ID, "You're sending messages too fast. Please slow down.")
+		if _, err := t.sender.Send(reply); err != nil {
+			slog.Error("Error sending rate limit notice", "error", err)
+		}
 	}
+	return false
 }
 
 // handleMessage processes a Telegram message update
@@ -65,35 +156,53 @@ func (t *TelegramBot) handleMessage(update tgbotapi.Update) {
 		t.handleNonCommand(update.Message, &msg)
 	}
 
-	log.Printf("Sending response to user %s", update.Message.From.UserName)
-	if _, err := t.bot.Send(msg); err != nil {
-		log.Printf("Error sending message: %v", err)
+	// Handlers that reply with media send it themselves and leave the text empty
+	if msg.Text == "" {
+		return
 	}
-}
 
-// handleCommand processes commands like /start, /help, etc.
-func (t *TelegramBot) handleCommand(message *tgbotapi.Message, msg *tgbotapi.MessageConfig) {
-	switch message.Command() {
-
-	case "help":
-		log.Printf("Handling /help command for user %s", message.From.UserName)
-		msg.Text = "Available commands:\n" +
-			"/rivers - Show the list of rivers\n" +
-			"/river [name] - Show information for a specific river\n" +
-			"/help - Show this help message"
+	// Replies over Telegram's length limit are sent in several messages,
+	// with any keyboard attached to the last one
+	slog.Debug("Sending response", "chat_id", msg.ChatID, "user", update.Message.From.UserName)
+	if err := t.sendSplit(msg); err != nil {
+		t.handleSendError(msg.ChatID, err)
+	}
+}
 
-	case "rivers":
-		log.Printf("Handling /rivers command for user %s", message.From.UserName)
-		t.handleRiversCommand(msg)
+// handleSendError logs a message that could not be delivered. A chat that blocked the
+// bot is removed together with its alerts, digest and follows, so nothing is sent to it again.
+func (t *TelegramBot) handleSendError(chatID int64, err error) {
+	if !isBlockedError(err) {
+		slog.Error("Error sending message", "chat_id", chatID, "error", err)
+		return
+	}
+	slog.Warn("Chat blocked the bot, unregistering it", "chat_id", chatID)
+	if err := t.useCase.RemoveUser(chatID); err != nil {
+		slog.Error("Error removing blocked chat", "chat_id", chatID, "error", err)
+	}
+}
 
-	case "river":
-		args := message.CommandArguments()
-		log.Printf("Handling /river command with args '%s' for user %s", args, message.From.UserName)
-		t.handleRiverCommand(args, msg)
+// handleCallback processes inline keyboard button presses
+func (t *TelegramBot) handleCallback(query *tgbotapi.CallbackQuery) {
+	slog.Debug("Received callback", "user", query.From.UserName, "user_id", query.From.ID, "data", query.Data)
+	if query.Message == nil {
+		return
+	}
 
+	var answer string
+	switch {
+	case strings.HasPrefix(query.Data, unsubscribeCallbackPrefix):
+		answer = t.handleUnsubscribeCallback(query)
+	case strings.HasPrefix(query.Data, riversCallbackPrefix):
+		answer = t.handleRiversPageCallback(query)
+	case strings.HasPrefix(query.Data, showStationsCallbackPrefix), strings.HasPrefix(query.Data, hideStationsCallbackPrefix):
+		answer = t.handleStationsCallback(query)
 	default:
-		log.Printf("Received unknown command /%s from user %s", message.Command(), message.From.UserName)
-		msg.Text = "Unknown command. Use /help to see available commands."
+		answer = "Unknown action."
+	}
+
+	if _, err := t.sender.Request(tgbotapi.NewCallback(query.ID, answer)); err != nil {
+		slog.Error("Error answering callback", "error", err)
 	}
 }
 
@@ -103,20 +212,24 @@ func (t *TelegramBot) handleRiversCommand(msg *tgbotapi.MessageConfig) {
 	rivers, err := t.useCase.GetAvailableRivers()
 	if err != nil {
 		msg.Text = "Error fetching river data. Please try again later."
-		log.Printf("Error fetching river data: %v", err)
+		slog.Error("Error fetching river data", "error", err)
 		return
 	}
 
-	msg.Text = "Available rivers:\n\n"
-	for _, river := range rivers {
-		msg.Text += "• " + river + "\n"
+	// Long lists are paged with Prev/Next buttons
+	text, keyboard := riversPage(rivers, 0)
+	msg.Text = text
+	if keyboard != nil {
+		msg.ReplyMarkup = *keyboard
 	}
-	msg.Text += "\nUse /river [name] to get detailed information."
+	t.prependFreshnessBanner(msg)
 }
 
 // handleRiverCommand processes the /river [name] command
 func (t *TelegramBot) handleRiverCommand(args string, msg *tgbotapi.MessageConfig) {
-	if args == "" {
+	args, asImage := cutFlag(args, imageFlag)
+	args, err := usecases.SanitizeRiverName(args)
+	if err != nil {
 		msg.Text = "Please specify a river name. Example: /river ДУНАВ"
 		return
 	}
@@ -125,34 +238,526 @@ func (t *TelegramBot) handleRiverCommand(args string, msg *tgbotapi.MessageConfi
 	riverData, err := t.useCase.GetRiverDataByName(args)
 	if err != nil {
 		msg.Text = "Error fetching river data. Please try again later."
-		log.Printf("Error fetching river data: %v", err)
+		slog.Error("Error fetching river data", "error", err)
+		return
+	}
+
+	if len(riverData) == 0 {
+		// A new database has nothing until the first refresh completes
+		if hasData, err := t.useCase.HasData(); err != nil {
+			slog.Error("Error counting readings", "error", err)
+		} else if !hasData {
+			msg.Text = dataLoadingMessage
+			return
+		}
+
+		msg.Text = fmt.Sprintf("No information found for river '%s'.", args)
+		if suggestions := t.useCase.SuggestRivers(args, maxRiverSuggestions); len(suggestions) > 0 {
+			msg.Text += fmt.Sprintf(" Did you mean: %s?", strings.Join(suggestions, ", "))
+		}
+		msg.Text += " Use /rivers to see the available rivers."
+		return
+	}
+
+	if asImage {
+		t.sendRiverTable(riverData, msg)
+		return
+	}
+	t.useCase.LocalizeForChat(msg.ChatID, riverData)
+
+	// Rivers with many stations get a summary, with the stations behind a button
+	text, keyboard := t.riverReply(riverData, false)
+	msg.Text = text
+	if keyboard != nil {
+		msg.ReplyMarkup = *keyboard
+	}
+	t.prependFreshnessBanner(msg)
+}
+
+// dataLoadingMessage is the reply to river queries before the first refresh stored any readings
+const dataLoadingMessage = "Data is still loading, try again in a minute."
+
+// imageFlag asks /river for a table image instead of text
+const imageFlag = "--image"
+
+// cutFlag removes a flag such as --image from command arguments and reports whether it was present
+func cutFlag(args, flag string) (string, bool) {
+	fields := strings.Fields(args)
+	kept := fields[:0]
+	found := false
+	for _, field := range fields {
+		if strings.EqualFold(field, flag) {
+			found = true
+			continue
+		}
+		kept = append(kept, field)
+	}
+	if !found {
+		return args, false
+	}
+	return strings.Join(kept, " "), true
+}
+
+// sendRiverTable sends a river's readings as a table image, falling back to text if that fails
+func (t *TelegramBot) sendRiverTable(riverData []entities.RiverData, msg *tgbotapi.MessageConfig) {
+	image, err := t.useCase.RenderRiverTable(riverData)
+	if err != nil {
+		slog.Error("Error rendering river table", "error", err)
+		msg.Text = t.useCase.FormatRiverInfo(riverData)
+		return
+	}
+
+	photo := tgbotapi.NewPhoto(msg.ChatID, tgbotapi.FileBytes{Name: "river.png", Bytes: image})
+	photo.Caption = fmt.Sprintf("Current readings for river %s", riverData[0].River)
+	if _, err := t.sender.Send(photo); err != nil {
+		slog.Error("Error sending river table", "error", err)
+		msg.Text = t.useCase.FormatRiverInfo(riverData)
+	}
+}
+
+// handleTrendCommand processes the /trend [river] command
+func (t *TelegramBot) handleTrendCommand(args string, msg *tgbotapi.MessageConfig) {
+	river, _, err := t.splitRiverArgs(args)
+	if err != nil {
+		msg.Text = "Error fetching river data. Please try again later."
+		slog.Error("Error resolving river name", "error", err)
+		return
+	}
+	if river == "" {
+		msg.Text = "Please specify a river name. Example: /trend ДРИНА"
+		return
+	}
+
+	riverData, err := t.useCase.GetRiverDataByName(river)
+	if err != nil {
+		msg.Text = "Error fetching river data. Please try again later."
+		slog.Error("Error fetching river data", "error", err)
+		return
+	}
+	if len(riverData) == 0 {
+		msg.Text = fmt.Sprintf("No information found for river '%s'. Use /rivers to see the available rivers.", river)
+		return
+	}
+
+	msg.Text = t.useCase.FormatTrends(river, riverData)
+}
+
+// handleLinkCommand processes the /link [river] command, showing the source pages
+// the river's readings come from so they can be checked there
+func (t *TelegramBot) handleLinkCommand(args string, msg *tgbotapi.MessageConfig) {
+	river, _, err := t.splitRiverArgs(args)
+	if err != nil {
+		msg.Text = "Error fetching river data. Please try again later."
+		slog.Error("Error resolving river name", "error", err)
+		return
+	}
+	if river == "" {
+		msg.Text = "Please specify a river name. Example: /link ГРАДАЦ"
+		return
+	}
+
+	riverData, err := t.useCase.GetRiverDataByName(river)
+	if err != nil {
+		msg.Text = "Error fetching river data. Please try again later."
+		slog.Error("Error fetching river data", "error", err)
+		return
+	}
+	if len(riverData) == 0 {
+		msg.Text = fmt.Sprintf("No information found for river '%s'. Use /rivers to see the available rivers.", river)
+		return
+	}
+
+	msg.Text = usecases.FormatRiverLinks(riverData)
+	msg.DisableWebPagePreview = true
+}
+
+// handleRawCommand processes the /raw [river] command, showing every stored field of a river's readings
+func (t *TelegramBot) handleRawCommand(args string, msg *tgbotapi.MessageConfig) {
+	river, err := usecases.SanitizeRiverName(args)
+	if err != nil {
+		msg.Text = "Please specify a river name. Example: /raw ДРИНА"
+		return
+	}
+
+	riverData, err := t.useCase.GetRiverDataByName(river)
+	if err != nil {
+		msg.Text = "Error fetching river data. Please try again later."
+		slog.Error("Error fetching river data", "error", err)
+		return
+	}
+	if len(riverData) == 0 {
+		msg.Text = fmt.Sprintf("No information found for river '%s'. Use /rivers to see the available rivers.", river)
+		return
+	}
+	t.useCase.LocalizeForChat(msg.ChatID, riverData)
+
+	levels, err := t.useCase.GetStationLevels(riverData)
+	if err != nil {
+		msg.Text = "Error fetching river data. Please try again later."
+		slog.Error("Error fetching level changes", "error", err)
+		return
+	}
+	msg.Text = t.useCase.FormatRiverInfoVerbose(levels)
+	msg.DisableWebPagePreview = true
+}
+
+// handleYesterdayCommand processes the /yesterday [river] command, comparing each
+// station's level with its level a day earlier
+func (t *TelegramBot) handleYesterdayCommand(args string, msg *tgbotapi.MessageConfig) {
+	river, err := usecases.SanitizeRiverName(args)
+	if err != nil {
+		msg.Text = "Please specify a river name. Example: /yesterday ДРИНА"
+		return
+	}
+
+	riverData, err := t.useCase.GetRiverDataByName(river)
+	if err != nil {
+		msg.Text = "Error fetching river data. Please try again later."
+		slog.Error("Error fetching river data", "error", err)
+		return
+	}
+	if len(riverData) == 0 {
+		msg.Text = fmt.Sprintf("No information found for river '%s'. Use /rivers to see the available rivers.", river)
+		return
+	}
+	t.useCase.LocalizeForChat(msg.ChatID, riverData)
+
+	comparisons, err := t.useCase.CompareWithYesterday(riverData)
+	if err != nil {
+		msg.Text = "Error fetching river data. Please try again later."
+		slog.Error("Error fetching yesterday's readings", "error", err)
+		return
+	}
+	msg.Text = t.useCase.FormatComparison(comparisons)
+}
+
+// handleChartCommand processes the /chart [river] [station] command
+func (t *TelegramBot) handleChartCommand(message *tgbotapi.Message, msg *tgbotapi.MessageConfig) {
+	args := strings.TrimSpace(message.CommandArguments())
+	if args == "" {
+		msg.Text = "Please specify a river name. Example: /chart ГРАДАЦ"
+		return
+	}
+
+	river, station, err := t.splitRiverArgs(args)
+	if err != nil {
+		msg.Text = "Error fetching river data. Please try again later."
+		slog.Error("Error resolving river name", "error", err)
+		return
+	}
+
+	riverData, err := t.useCase.GetRiverDataByName(river)
+	if err != nil {
+		msg.Text = "Error fetching river data. Please try again later."
+		slog.Error("Error fetching river data", "error", err)
+		return
+	}
+	if len(riverData) == 0 {
+		msg.Text = fmt.Sprintf("No information found for river '%s'. Use /rivers to see the available rivers.", river)
+		return
+	}
+
+	station, ok := resolveStation("chart", river, station, riverData, msg)
+	if !ok {
+		return
+	}
+
+	image, err := t.useCase.RenderLevelChart(river, station, time.Now().Add(-chartPeriod))
+	if errors.Is(err, charts.ErrNotEnoughPoints) {
+		msg.Text = fmt.Sprintf("Not enough readings for %s - %s to draw a chart yet.\n\n", river, station) +
+			t.useCase.FormatRiverInfo(riverData)
+		return
+	}
+	if err != nil {
+		msg.Text = "Error rendering the chart. Please try again later."
+		slog.Error("Error rendering chart", "error", err)
+		return
+	}
+
+	photo := tgbotapi.NewPhoto(message.Chat.ID, tgbotapi.FileBytes{Name: "chart.png", Bytes: image})
+	photo.Caption = fmt.Sprintf("Water level for %s - %s over the last 7 days", river, station)
+	slog.Debug("Sending chart", "user", message.From.UserName)
+	if _, err := t.sender.Send(photo); err != nil {
+		slog.Error("Error sending chart", "error", err)
+		msg.Text = "Error sending the chart. Please try again later."
+	}
+}
+
+// gapsPeriod is how much history the /gaps command scans
+const gapsPeriod = 7 * 24 * time.Hour
+
+// handleGapsCommand processes the /gaps [river] [station] command, listing the intervals
+// in which a station had no readings for longer than expected
+func (t *TelegramBot) handleGapsCommand(message *tgbotapi.Message, msg *tgbotapi.MessageConfig) {
+	river, station, err := t.splitRiverArgs(message.CommandArguments())
+	if err != nil {
+		msg.Text = "Error fetching river data. Please try again later."
+		slog.Error("Error resolving river name", "error", err)
+		return
+	}
+	if river == "" {
+		msg.Text = "Please specify a river name. Example: /gaps ГРАДАЦ"
 		return
 	}
 
+	riverData, err := t.useCase.GetRiverDataByName(river)
+	if err != nil {
+		msg.Text = "Error fetching river data. Please try again later."
+		slog.Error("Error fetching river data", "error", err)
+		return
+	}
 	if len(riverData) == 0 {
-		msg.Text = fmt.Sprintf("No information found for river '%s'. Use /rivers to see the available rivers.", args)
+		msg.Text = fmt.Sprintf("No information found for river '%s'. Use /rivers to see the available rivers.", river)
+		return
+	}
+
+	station, ok := resolveStation("gaps", river, station, riverData, msg)
+	if !ok {
+		return
+	}
+
+	gaps, err := t.useCase.FindDataGaps(river, station, time.Now().Add(-gapsPeriod), usecases.DefaultMaxGap)
+	if err != nil {
+		msg.Text = "Error fetching river data. Please try again later."
+		slog.Error("Error finding data gaps", "error", err)
+		return
+	}
+	msg.Text = usecases.FormatDataGaps(river, station, gaps, usecases.DefaultMaxGap, t.useCase.UserLocation(message.Chat.ID))
+}
+
+// resolveStation matches a station name against a river's stations, defaulting to the only
+// one the river has. When the station is ambiguous or unknown it replies with the choices
+// for command and reports false.
+func resolveStation(command, river, station string, riverData []entities.RiverData, msg *tgbotapi.MessageConfig) (string, bool) {
+	if station == "" {
+		if len(riverData) > 1 {
+			msg.Text = fmt.Sprintf("River %s has several stations, please pick one:\n\n", river)
+			for _, rd := range riverData {
+				msg.Text += fmt.Sprintf("• /%s %s %s\n", command, river, rd.Station)
+			}
+			return "", false
+		}
+		return riverData[0].Station, true
+	}
+
+	for _, rd := range riverData {
+		if strings.EqualFold(rd.Station, station) {
+			return rd.Station, true
+		}
+	}
+	msg.Text = fmt.Sprintf("No station '%s' found on river %s.", station, river)
+	return "", false
+}
+
+// splitRiverArgs splits command arguments into a known river name and the remaining text.
+// River names may contain spaces, so the longest matching prefix of words wins.
+func (t *TelegramBot) splitRiverArgs(args string) (river, rest string, err error) {
+	args, err = usecases.SanitizeRiverName(args)
+	if err != nil {
+		return "", "", nil
+	}
+	words := strings.Fields(args)
+
+	rivers, err := t.useCase.GetAvailableRivers()
+	if err != nil {
+		return "", "", err
+	}
+
+	for n := len(words); n > 0; n-- {
+		candidate := strings.Join(words[:n], " ")
+		for _, known := range rivers {
+			if strings.EqualFold(known, candidate) {
+				return known, strings.Join(words[n:], " "), nil
+			}
+		}
+	}
+
+	// Unknown river, assume it is the first word so the caller can report it
+	return words[0], strings.Join(words[1:], " "), nil
+}
+
+// handleStartCommand processes the /start command, registering the chat's user.
+// Starting again is harmless, the user is only registered once.
+func (t *TelegramBot) handleStartCommand(message *tgbotapi.Message, msg *tgbotapi.MessageConfig) {
+	user := entities.User{
+		ChatID:   message.Chat.ID,
+		LastSeen: message.Time(),
+	}
+	if message.From != nil {
+		user.Username = message.From.UserName
+		user.LanguageCode = message.From.LanguageCode
+	}
+	if err := t.useCase.RegisterUser(user); err != nil {
+		slog.Error("Error registering user", "chat_id", message.Chat.ID, "error", err)
+	}
+
+	msg.Text = "Welcome to Water Bot! I report water levels and temperatures of rivers in the Balkans.\n\n" +
+		"Use /rivers to see the list of rivers, /river [name] for a specific river, " +
+		"or just ask me about a river in your own words. Use /help to see all commands."
+}
+
+// handleStatusCommand processes the /status command
+func (t *TelegramBot) handleStatusCommand(msg *tgbotapi.MessageConfig) {
+	lastUpdates, err := t.useCase.GetLastUpdateTimeBySource()
+	if err != nil {
+		msg.Text = "Error fetching data source status. Please try again later."
+		slog.Error("Error fetching data source status", "error", err)
+		return
+	}
+
+	checks, err := t.useCase.GetSourceChecks()
+	if err != nil {
+		msg.Text = "Error fetching data source status. Please try again later."
+		slog.Error("Error fetching source checks", "error", err)
+		return
+	}
+
+	msg.Text = t.useCase.FormatSourceStatus(lastUpdates, checks)
+}
+
+// handleSourcesCommand processes the /sources command
+func (t *TelegramBot) handleSourcesCommand(msg *tgbotapi.MessageConfig) {
+	lastUpdates, err := t.useCase.GetLastUpdateTimeBySource()
+	if err != nil {
+		msg.Text = "Error fetching data source status. Please try again later."
+		slog.Error("Error fetching data source status", "error", err)
+		return
+	}
+	coverage, err := t.useCase.GetSourceCoverage()
+	if err != nil {
+		msg.Text = "Error fetching data source status. Please try again later."
+		slog.Error("Error fetching source coverage", "error", err)
 		return
 	}
 
-	msg.Text = t.useCase.FormatRiverInfo(riverData)
+	msg.Text = t.useCase.FormatSources(lastUpdates, coverage)
 }
 
 // handleNonCommand processes regular messages by calling the use case
 func (t *TelegramBot) handleNonCommand(message *tgbotapi.Message, msg *tgbotapi.MessageConfig) {
-	log.Printf("Received non-command message from user %s: %s", message.From.UserName, message.Text)
+	slog.Debug("Received non-command message", "user", message.From.UserName, "text", message.Text)
 
 	// Call the use case to handle the natural language query
-	ctx := context.Background() // Create a background context
+	ctx, cancel := context.WithTimeout(context.Background(), naturalLanguageTimeout)
+	defer cancel()
 	responseText, err := t.useCase.HandleNaturalLanguageQuery(ctx, message.Text)
 
 	if err != nil {
 		// Although HandleNaturalLanguageQuery currently returns nil error,
 		// handle potential future errors defensively.
-		log.Printf("Error handling natural language query in use case: %v", err)
+		slog.Error("Error handling natural language query in use case", "error", err)
 		msg.Text = "An unexpected error occurred. Please try again later."
 		return
 	}
 
 	// Assign the response generated by the use case
 	msg.Text = responseText
+	t.prependFreshnessBanner(msg)
+}
+
+// Limits of the /top ranking
+const (
+	defaultTopStations = 10
+	maxTopStations     = 50
+)
+
+// handleWaterTempCommand processes the /watertemp [min] [max] command
+func (t *TelegramBot) handleWaterTempCommand(args string, msg *tgbotapi.MessageConfig) {
+	const usage = "Usage: /watertemp [min] [max]\nExample: /watertemp 8 14"
+	fields := strings.Fields(args)
+	if len(fields) != 2 {
+		msg.Text = usage
+		return
+	}
+	min, errMin := units.ParseNumber(fields[0])
+	max, errMax := units.ParseNumber(fields[1])
+	if errMin != nil || errMax != nil || min > max {
+		msg.Text = usage
+		return
+	}
+
+	stations, err := t.useCase.GetStationsByTempRange(min, max)
+	if err != nil {
+		msg.Text = "Error fetching river data. Please try again later."
+		slog.Error("Error fetching stations by temperature", "error", err)
+		return
+	}
+
+	msg.Text = t.useCase.FormatTempRange(stations, min, max)
+}
+
+// handleDischargeCommand processes the /discharge [min] command
+func (t *TelegramBot) handleDischargeCommand(args string, msg *tgbotapi.MessageConfig) {
+	const usage = "Usage: /discharge [min]\nExample: /discharge 500"
+	fields := strings.Fields(args)
+	if len(fields) != 1 {
+		msg.Text = usage
+		return
+	}
+	min, err := units.ParseNumber(fields[0])
+	if err != nil || min < 0 {
+		msg.Text = usage
+		return
+	}
+
+	stations, err := t.useCase.GetStationsByDischarge(min)
+	if err != nil {
+		msg.Text = "Error fetching river data. Please try again later."
+		slog.Error("Error fetching stations by discharge", "error", err)
+		return
+	}
+
+	msg.Text = t.useCase.FormatDischarge(stations, min)
+}
+
+// handleConvertCommand processes the /convert [value] [unit] command
+func (t *TelegramBot) handleConvertCommand(args string, msg *tgbotapi.MessageConfig) {
+	const usage = "Usage: /convert [value] cm|m|ft\nExample: /convert 325 cm"
+
+	// Accept both "325 cm" and "325cm"
+	args = strings.TrimSpace(args)
+	split := strings.IndexFunc(args, unicode.IsLetter)
+	if split <= 0 {
+		msg.Text = usage
+		return
+	}
+	value, err := units.ParseNumber(args[:split])
+	if err != nil {
+		msg.Text = usage
+		return
+	}
+	unit, err := units.ParseUnit(args[split:])
+	if err != nil {
+		msg.Text = fmt.Sprintf("Unknown unit %q. %s", strings.TrimSpace(args[split:]), usage)
+		return
+	}
+
+	msg.Text = t.useCase.FormatConversion(value, unit)
+}
+
+// handleTopCommand processes the /top [rising] [count] command
+func (t *TelegramBot) handleTopCommand(args string, msg *tgbotapi.MessageConfig) {
+	limit := defaultTopStations
+	risingOnly := false
+	for _, field := range strings.Fields(args) {
+		if strings.EqualFold(field, "rising") {
+			risingOnly = true
+			continue
+		}
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 1 || n > maxTopStations {
+			msg.Text = fmt.Sprintf("Usage: /top [rising] [count, 1-%d]\nExample: /top rising 5", maxTopStations)
+			return
+		}
+		limit = n
+	}
+
+	stations, err := t.useCase.GetTopStations(limit, risingOnly)
+	if err != nil {
+		msg.Text = "Error fetching river data. Please try again later."
+		slog.Error("Error fetching top stations", "error", err)
+		return
+	}
+
+	msg.Text = t.useCase.FormatTopStations(stations, risingOnly)
 }
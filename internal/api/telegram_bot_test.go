@@ -0,0 +1,513 @@
+package api
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+	"github.com/abelzeko/water-bot/internal/integration"
+	"github.com/abelzeko/water-bot/internal/integration/openai"
+	"github.com/abelzeko/water-bot/internal/repository"
+	"github.com/abelzeko/water-bot/internal/usecases"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// fakeOpenAIService returns a canned agent response and records the query it received
+type fakeOpenAIService struct {
+	response *openai.AgentResponse
+	query    string
+}
+
+// InterpretUserQuery implements openai.OpenAIService
+func (f *fakeOpenAIService) InterpretUserQuery(ctx context.Context, userMessage string, supportedRivers []string) (*openai.AgentResponse, error) {
+	f.query = userMessage
+	if _, ok := ctx.Deadline(); !ok {
+		return nil, context.DeadlineExceeded
+	}
+	return f.response, nil
+}
+
+// fakeSender records the messages the bot sends instead of calling Telegram
+type fakeSender struct {
+	mu   sync.Mutex
+	sent []tgbotapi.Chattable
+}
+
+// Send implements Sender
+func (f *fakeSender) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sent = append(f.sent, c)
+	return tgbotapi.Message{}, nil
+}
+
+// Request implements Sender
+func (f *fakeSender) Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sent = append(f.sent, c)
+	return &tgbotapi.APIResponse{Ok: true}, nil
+}
+
+// texts returns the text of every message sent
+func (f *fakeSender) texts() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var texts []string
+	for _, c := range f.sent {
+		if msg, ok := c.(tgbotapi.MessageConfig); ok {
+			texts = append(texts, msg.Text)
+		}
+	}
+	return texts
+}
+
+// newTestRepository creates a SQLite repository in a temporary directory
+func newTestRepository(t *testing.T) *repository.SQLiteRiverRepository {
+	t.Helper()
+	repo, err := repository.NewSQLiteRiverRepository(filepath.Join(t.TempDir(), "test-riverdata.db"))
+	if err != nil {
+		t.Fatalf("Failed to initialize repository: %v", err)
+	}
+	t.Cleanup(func() { repo.Close() })
+	return repo
+}
+
+// newTestBot creates a bot without a Telegram connection backed by a temporary SQLite repository
+func newTestBot(t *testing.T, openAIService openai.OpenAIService) *TelegramBot {
+	t.Helper()
+	repo := newTestRepository(t)
+	err := repo.SaveRiverData([]entities.RiverData{
+		{River: "ДРИНА", Station: "Радаљ", WaterLevel: "142", Timestamp: time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC)},
+	})
+	if err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+
+	return &TelegramBot{sender: &fakeSender{}, useCase: usecases.NewRiverUseCase(repo, nil, openAIService)}
+}
+
+// textMessage builds a plain text message from a test user
+func textMessage(text string) *tgbotapi.Message {
+	return &tgbotapi.Message{
+		Text: text,
+		From: &tgbotapi.User{ID: 1, UserName: "tester"},
+		Chat: &tgbotapi.Chat{ID: 1},
+	}
+}
+
+// commandMessage builds a command message such as "/river ДРИНА" from a test user
+func commandMessage(text string) *tgbotapi.Message {
+	message := textMessage(text)
+	command, _, _ := strings.Cut(text, " ")
+	message.Entities = []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: len(command)}}
+	return message
+}
+
+// TestHandleNonCommandUsesNaturalLanguage verifies free text is answered through the AI service
+func TestHandleNonCommandUsesNaturalLanguage(t *testing.T) {
+	fake := &fakeOpenAIService{response: &openai.AgentResponse{
+		CommandName:      "GetRiverDataByName",
+		SerbianRiverName: "ДРИНА",
+		UserMessage:      "Ок, ищу данные по Дрине.",
+	}}
+	bot := newTestBot(t, fake)
+
+	msg := tgbotapi.NewMessage(1, "")
+	bot.handleNonCommand(textMessage("как там Дрина?"), &msg)
+
+	if fake.query != "как там Дрина?" {
+		t.Errorf("Expected the message to be sent to the AI service, got %q", fake.query)
+	}
+	// The fixture's reading is old, so the reply opens with the freshness banner
+	if !strings.HasPrefix(msg.Text, "⚠️ Data may be outdated (last update: 2025-04-18 08:00 CEST)\n\nОк, ищу данные по Дрине.") {
+		t.Errorf("Expected the freshness banner and then the agent's message, got:\n%s", msg.Text)
+	}
+	if !strings.Contains(msg.Text, "Information for river ДРИНА") || !strings.Contains(msg.Text, "Радаљ") {
+		t.Errorf("Expected river information in the reply, got:\n%s", msg.Text)
+	}
+}
+
+// TestHandleNonCommandWithoutNaturalLanguage verifies free text gets a fixed reply when the AI service is disabled
+func TestHandleNonCommandWithoutNaturalLanguage(t *testing.T) {
+	bot := newTestBot(t, nil)
+
+	msg := tgbotapi.NewMessage(1, "")
+	bot.handleNonCommand(textMessage("как там Дрина?"), &msg)
+
+	if !strings.Contains(msg.Text, "/help") {
+		t.Errorf("Expected a reply pointing to /help, got %q", msg.Text)
+	}
+}
+
+// TestDispatchUpdatesHandlesAllConcurrently verifies simultaneous updates all get handled, in order per chat
+func TestDispatchUpdatesHandlesAllConcurrently(t *testing.T) {
+	const chats, perChat = 20, 5
+
+	var mu sync.Mutex
+	handled := make(map[int64][]int)
+
+	updates := make(chan tgbotapi.Update, chats*perChat)
+	for i := 0; i < perChat; i++ {
+		for chat := int64(1); chat <= chats; chat++ {
+			updates <- tgbotapi.Update{UpdateID: i, Message: &tgbotapi.Message{Chat: &tgbotapi.Chat{ID: chat}}}
+		}
+	}
+	close(updates)
+
+	start := time.Now()
+	dispatchUpdates(updates, updateWorkers, func(update tgbotapi.Update) {
+		time.Sleep(10 * time.Millisecond) // Simulate a slow reply
+		mu.Lock()
+		defer mu.Unlock()
+		chatID := update.Message.Chat.ID
+		handled[chatID] = append(handled[chatID], update.UpdateID)
+	})
+	elapsed := time.Since(start)
+
+	for chat := int64(1); chat <= chats; chat++ {
+		got := handled[chat]
+		if len(got) != perChat {
+			t.Errorf("Chat %d: expected %d updates handled, got %d", chat, perChat, len(got))
+			continue
+		}
+		for i, id := range got {
+			if id != i {
+				t.Errorf("Chat %d: expected updates in order, got %v", chat, got)
+				break
+			}
+		}
+	}
+
+	// Sequential handling would take at least 1s
+	if elapsed >= 500*time.Millisecond {
+		t.Errorf("Expected updates to be handled concurrently, took %v", elapsed)
+	}
+}
+
+// TestHandleConvertCommand verifies conversions and the usage reply for invalid input
+func TestHandleConvertCommand(t *testing.T) {
+	bot := newTestBot(t, nil)
+	tests := []struct {
+		args string
+		want string
+	}{
+		{"325 cm", "325 cm = 3.25 m = 10.66 ft"},
+		{"325cm", "325 cm = 3.25 m = 10.66 ft"},
+		{"3,25 m", "3.25 m = 325 cm = 10.66 ft"},
+		{"10 feet", "10 ft = 304.8 cm = 3.05 m"},
+		{"", "Usage: /convert"},
+		{"cm", "Usage: /convert"},
+		{"abc cm", "Usage: /convert"},
+		{"325", "Usage: /convert"},
+		{"325 inch", `Unknown unit "inch"`},
+	}
+	for _, tt := range tests {
+		msg := tgbotapi.NewMessage(1, "")
+		bot.handleConvertCommand(tt.args, &msg)
+		if !strings.HasPrefix(msg.Text, tt.want) {
+			t.Errorf("/convert %s: expected %q, got %q", tt.args, tt.want, msg.Text)
+		}
+	}
+}
+
+// TestHandleSourcesCommand verifies every supported source is listed with its coverage
+func TestHandleSourcesCommand(t *testing.T) {
+	repo := newTestRepository(t)
+	ts := time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC)
+	err := repo.SaveRiverData([]entities.RiverData{
+		{River: "ДРИНА", Station: "Радаљ", WaterLevel: "142", Source: integration.SourceHidmet, Timestamp: ts},
+		{River: "САВА", Station: "Шабац", WaterLevel: "325", Source: integration.SourceHidmet, Timestamp: ts},
+		{River: "ДРИНА", Station: "Фоча", WaterLevel: "98", Source: integration.SourceRhmzRs, Timestamp: ts},
+	})
+	if err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+	bot := &TelegramBot{useCase: usecases.NewRiverUseCase(repo, nil, nil)}
+
+	msg := tgbotapi.NewMessage(1, "")
+	bot.handleSourcesCommand(&msg)
+
+	for _, source := range integration.Sources() {
+		if !strings.Contains(msg.Text, source.Authority) || !strings.Contains(msg.Text, source.Website) {
+			t.Errorf("Expected source %s to be listed, got:\n%s", source.Name, msg.Text)
+		}
+	}
+	for _, want := range []string{"2 rivers, 2 stations", "1 rivers, 1 stations", "No data yet"} {
+		if !strings.Contains(msg.Text, want) {
+			t.Errorf("Expected %q in:\n%s", want, msg.Text)
+		}
+	}
+}
+
+// TestHandleStartIsIdempotent verifies repeated /start calls register the user once and update last_seen
+func TestHandleStartIsIdempotent(t *testing.T) {
+	repo := newTestRepository(t)
+	bot := &TelegramBot{useCase: usecases.NewRiverUseCase(repo, nil, nil)}
+
+	first := time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC)
+	second := first.Add(time.Hour)
+	for _, sent := range []time.Time{first, second} {
+		message := commandMessage("/start")
+		message.Date = int(sent.Unix())
+
+		msg := tgbotapi.NewMessage(1, "")
+		bot.handleCommand(message, &msg)
+		if !strings.Contains(msg.Text, "Welcome") {
+			t.Errorf("Expected a welcome message, got: %s", msg.Text)
+		}
+	}
+
+	user, found, err := repo.GetUser(1)
+	if err != nil || !found {
+		t.Fatalf("Expected user to be registered, got found=%v err=%v", found, err)
+	}
+	if user.Username != "tester" {
+		t.Errorf("Expected username tester, got %q", user.Username)
+	}
+	if !user.FirstSeen.Equal(first) {
+		t.Errorf("Expected first seen %v, got %v", first, user.FirstSeen)
+	}
+	if !user.LastSeen.Equal(second) {
+		t.Errorf("Expected last seen %v, got %v", second, user.LastSeen)
+	}
+}
+
+// TestHandleCommand verifies the reply of commands answered from stored data
+func TestHandleCommand(t *testing.T) {
+	bot := newTestBot(t, nil)
+
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"help", "/help", []string{"Available commands:", "/river [name]"}},
+		{"river", "/river ДРИНА", []string{"ДРИНА", "Радаљ", "142 cm"}},
+		{"river without name", "/river", []string{"Please specify a river name"}},
+		{"river typo", "/river ДРНА", []string{"No information found for river 'ДРНА'", "Did you mean: ДРИНА?"}},
+		{"unknown command", "/fly", []string{"Unknown command"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := tgbotapi.NewMessage(1, "")
+			bot.handleCommand(commandMessage(tt.text), &msg)
+			for _, want := range tt.want {
+				if !strings.Contains(msg.Text, want) {
+					t.Errorf("Expected %q in:\n%s", want, msg.Text)
+				}
+			}
+		})
+	}
+}
+
+// TestHandleUpdateSendsReply verifies a command update is answered through the sender
+func TestHandleUpdateSendsReply(t *testing.T) {
+	bot := newTestBot(t, nil)
+	sender := bot.sender.(*fakeSender)
+
+	bot.handleUpdate(tgbotapi.Update{Message: commandMessage("/river ДРИНА")})
+
+	texts := sender.texts()
+	if len(texts) != 1 {
+		t.Fatalf("Expected one reply, got %d: %v", len(texts), texts)
+	}
+	if !strings.Contains(texts[0], "Радаљ") {
+		t.Errorf("Expected the river info to be sent, got:\n%s", texts[0])
+	}
+}
+
+// TestHandleRiverImage verifies /river --image sends the readings as a photo instead of text
+func TestHandleRiverImage(t *testing.T) {
+	bot := newTestBot(t, nil)
+	sender := bot.sender.(*fakeSender)
+
+	bot.handleUpdate(tgbotapi.Update{Message: commandMessage("/river ДРИНА --image")})
+
+	if texts := sender.texts(); len(texts) != 0 {
+		t.Errorf("Expected no text reply, got %v", texts)
+	}
+	if len(sender.sent) != 1 {
+		t.Fatalf("Expected one photo, got %d messages", len(sender.sent))
+	}
+	photo, ok := sender.sent[0].(tgbotapi.PhotoConfig)
+	if !ok {
+		t.Fatalf("Expected a photo, got %T", sender.sent[0])
+	}
+	file, ok := photo.File.(tgbotapi.FileBytes)
+	if !ok || len(file.Bytes) == 0 {
+		t.Errorf("Expected a rendered image, got %#v", photo.File)
+	}
+	if !strings.Contains(photo.Caption, "ДРИНА") {
+		t.Errorf("Expected the river in the caption, got %q", photo.Caption)
+	}
+}
+
+// TestHandleTimeZoneCommand verifies /tz stores a valid zone and applies it to /river
+func TestHandleTimeZoneCommand(t *testing.T) {
+	bot := newTestBot(t, nil)
+
+	steps := []struct {
+		text string
+		want string
+	}{
+		{"/tz", "Timestamps are shown in Europe/Belgrade"},
+		{"/tz Mars/Olympus", "Unknown time zone 'Mars/Olympus'"},
+		{"/tz Europe/Moscow", "Time zone set to Europe/Moscow."},
+		{"/tz", "Timestamps are shown in Europe/Moscow"},
+		{"/river ДРИНА", "Last update: 2025-04-18 09:00:00 MSK"},
+	}
+	for _, step := range steps {
+		msg := tgbotapi.NewMessage(1, "")
+		bot.handleCommand(commandMessage(step.text), &msg)
+		if !strings.Contains(msg.Text, step.want) {
+			t.Errorf("%s: expected %q in:\n%s", step.text, step.want, msg.Text)
+		}
+	}
+}
+
+// TestHandleLevelCommand verifies /level shows one station and explains unknown names
+func TestHandleLevelCommand(t *testing.T) {
+	bot := newTestBot(t, nil)
+
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"/level ДРИНА Радаљ", "📍 ДРИНА - Радаљ\n💧 Water Level: 142 cm"},
+		{"/level drina radalj", "📍 ДРИНА - Радаљ"},
+		{"/level ДРИНА Зворник", "No station 'Зворник' found on river ДРИНА."},
+		{"/level ТАРА Радаљ", "No information found for river 'ТАРА'"},
+		{"/level ДРИНА", levelUsage},
+	}
+	for _, tt := range tests {
+		msg := tgbotapi.NewMessage(1, "")
+		bot.handleCommand(commandMessage(tt.text), &msg)
+		if !strings.Contains(msg.Text, tt.want) {
+			t.Errorf("%s: expected %q in:\n%s", tt.text, tt.want, msg.Text)
+		}
+	}
+}
+
+// TestHandleStatsCommand verifies /stats parses the station and period and reports missing history
+func TestHandleStatsCommand(t *testing.T) {
+	repo := newTestRepository(t)
+	err := repo.SaveRiverData([]entities.RiverData{
+		{River: "ДРИНА", Station: "Радаљ", WaterLevel: "150", Timestamp: time.Now().Add(-time.Hour)},
+	})
+	if err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+	bot := &TelegramBot{sender: &fakeSender{}, useCase: usecases.NewRiverUseCase(repo, nil, nil)}
+
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"/stats ДРИНА Радаљ", "over the last 30 days"},
+		{"/stats ДРИНА Радаљ 7", "• Max: 150 cm"},
+		{"/stats ДРИНА Радаљ 400", "The period must be between 1 and 365 days."},
+		{"/stats ДРИНА Зворник", "No station 'Зворник' found on river ДРИНА."},
+		{"/stats ДРИНА 30", statsUsage},
+	}
+	for _, tt := range tests {
+		msg := tgbotapi.NewMessage(1, "")
+		bot.handleCommand(commandMessage(tt.text), &msg)
+		if !strings.Contains(msg.Text, tt.want) {
+			t.Errorf("%s: expected %q in:\n%s", tt.text, tt.want, msg.Text)
+		}
+	}
+}
+
+// TestHandleLinkCommand verifies /link returns the page a river's readings were scraped from
+func TestHandleLinkCommand(t *testing.T) {
+	const gradacURL = "https://www.hidmet.gov.rs/ciril/osmotreni/nrt_tabela_grafik.php?hm_id=45902&period=7"
+	repo := newTestRepository(t)
+	err := repo.SaveRiverData([]entities.RiverData{
+		{River: "ГРАДАЦ", Station: "ДЕГУРИЋ", WaterLevel: "85", Source: "gradac", URL: gradacURL, Timestamp: time.Now()},
+		{River: "ДРИНА", Station: "Радаљ", WaterLevel: "142", Source: "rhmzrs", Timestamp: time.Now()},
+	})
+	if err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+	bot := &TelegramBot{sender: &fakeSender{}, useCase: usecases.NewRiverUseCase(repo, nil, nil)}
+
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"/link ГРАДАЦ", "• ДЕГУРИЋ: " + gradacURL},
+		{"/link Gradac", "• ДЕГУРИЋ: " + gradacURL},
+		{"/link ДРИНА", "• Радаљ: https://novi.rhmzrs.com"},
+		{"/link ДУНАВ", "No information found for river 'ДУНАВ'"},
+		{"/link", "Please specify a river name."},
+	}
+	for _, tt := range tests {
+		msg := tgbotapi.NewMessage(1, "")
+		bot.handleCommand(commandMessage(tt.text), &msg)
+		if !strings.Contains(msg.Text, tt.want) {
+			t.Errorf("%s: expected %q in:\n%s", tt.text, tt.want, msg.Text)
+		}
+	}
+}
+
+// TestHandleRawCommand verifies /raw shows all readings of a river in verbose form
+func TestHandleRawCommand(t *testing.T) {
+	bot := newTestBot(t, nil)
+
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"/raw ДРИНА", "All readings for river ДРИНА"},
+		{"/raw drina", "💧 Water Level: 142 cm (1.42 m)"},
+		{"/raw ДУНАВ", "No information found for river 'ДУНАВ'"},
+		{"/raw", "Please specify a river name."},
+	}
+	for _, tt := range tests {
+		msg := tgbotapi.NewMessage(1, "")
+		bot.handleCommand(commandMessage(tt.text), &msg)
+		if !strings.Contains(msg.Text, tt.want) {
+			t.Errorf("%s: expected %q in:\n%s", tt.text, tt.want, msg.Text)
+		}
+	}
+}
+
+// TestHandleRiverBeforeFirstRefresh verifies an empty database is reported as loading rather than as an unknown river
+func TestHandleRiverBeforeFirstRefresh(t *testing.T) {
+	tests := []struct {
+		name    string
+		bot     *TelegramBot
+		want    string
+		notWant string
+	}{
+		{
+			name:    "empty database",
+			bot:     &TelegramBot{sender: &fakeSender{}, useCase: usecases.NewRiverUseCase(newTestRepository(t), nil, nil)},
+			want:    dataLoadingMessage,
+			notWant: "No information found",
+		},
+		{
+			name:    "populated database",
+			bot:     newTestBot(t, nil),
+			want:    "No information found for river 'ДУНАВ'",
+			notWant: dataLoadingMessage,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := tgbotapi.NewMessage(1, "")
+			tt.bot.handleCommand(commandMessage("/river ДУНАВ"), &msg)
+			if !strings.Contains(msg.Text, tt.want) {
+				t.Errorf("Expected %q in:\n%s", tt.want, msg.Text)
+			}
+			if strings.Contains(msg.Text, tt.notWant) {
+				t.Errorf("Did not expect %q in:\n%s", tt.notWant, msg.Text)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/abelzeko/water-bot/internal/usecases"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// handleTimeZoneCommand processes the /tz [zone] command, showing or setting
+// the time zone timestamps are shown in
+func (t *TelegramBot) handleTimeZoneCommand(message *tgbotapi.Message, msg *tgbotapi.MessageConfig) {
+	chatID := message.Chat.ID
+	name := strings.TrimSpace(message.CommandArguments())
+	if name == "" {
+		zone, err := t.useCase.UserTimeZone(chatID)
+		if err != nil {
+			msg.Text = "Error fetching your time zone. Please try again later."
+			slog.Error("Error fetching time zone", "error", err)
+			return
+		}
+		msg.Text = fmt.Sprintf("Timestamps are shown in %s. Change it with /tz [zone], e.g. /tz Europe/Moscow", zone)
+		return
+	}
+
+	zone, err := t.useCase.SetUserTimeZone(chatID, name)
+	switch {
+	case errors.Is(err, usecases.ErrInvalidTimeZone):
+		msg.Text = fmt.Sprintf("Unknown time zone '%s'. Use a name such as Europe/Belgrade or America/New_York.", name)
+	case err != nil:
+		msg.Text = "Error saving your time zone. Please try again later."
+		slog.Error("Error saving time zone", "error", err)
+	default:
+		msg.Text = fmt.Sprintf("Time zone set to %s.", zone)
+	}
+}
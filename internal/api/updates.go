@@ -0,0 +1,51 @@
+package api
+
+import (
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// updateWorkers is the number of goroutines handling Telegram updates
+const updateWorkers = 8
+
+// updateQueueSize is how many updates may wait for each worker
+const updateQueueSize = 16
+
+// dispatchUpdates handles updates with a bounded pool of workers until the updates channel is closed.
+// Updates from the same chat always go to the same worker, so each chat's messages are answered in order.
+func dispatchUpdates(updates <-chan tgbotapi.Update, workers int, handle func(tgbotapi.Update)) {
+	queues := make([]chan tgbotapi.Update, workers)
+	var wg sync.WaitGroup
+	for i := range queues {
+		queues[i] = make(chan tgbotapi.Update, updateQueueSize)
+		wg.Add(1)
+		go func(queue <-chan tgbotapi.Update) {
+			defer wg.Done()
+			for update := range queue {
+				handle(update)
+			}
+		}(queues[i])
+	}
+
+	for update := range updates {
+		worker := updateChatID(update) % int64(workers)
+		if worker < 0 {
+			worker = -worker // Group chats have negative IDs
+		}
+		queues[worker] <- update
+	}
+
+	for _, queue := range queues {
+		close(queue)
+	}
+	wg.Wait()
+}
+
+// updateChatID returns the chat an update belongs to, or 0 if it has none
+func updateChatID(update tgbotapi.Update) int64 {
+	if chat := update.FromChat(); chat != nil {
+		return chat.ID
+	}
+	return 0
+}
@@ -0,0 +1,73 @@
+// Package charts renders river data as images
+package charts
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/wcharczuk/go-chart/v2"
+)
+
+// ErrNotEnoughPoints is returned when a series is too short to be plotted
+var ErrNotEnoughPoints = errors.New("not enough data points to render a chart")
+
+// MinPoints is the minimum number of readings needed to draw a line
+const MinPoints = 2
+
+// Point is a single water level reading to plot
+type Point struct {
+	Time  time.Time
+	Level float64 // Water level in cm
+}
+
+// RenderLevelChart draws a PNG line chart of water level over time
+func RenderLevelChart(title string, points []Point) ([]byte, error) {
+	if len(points) < MinPoints {
+		return nil, ErrNotEnoughPoints
+	}
+
+	xValues := make([]time.Time, len(points))
+	yValues := make([]float64, len(points))
+	for i, p := range points {
+		xValues[i] = p.Time
+		yValues[i] = p.Level
+	}
+
+	graph := chart.Chart{
+		Title:  title,
+		Width:  1024,
+		Height: 512,
+		Background: chart.Style{
+			Padding: chart.Box{Top: 50, Left: 20, Right: 20, Bottom: 20},
+		},
+		XAxis: chart.XAxis{
+			ValueFormatter: chart.TimeValueFormatterWithFormat("02.01 15:04"),
+		},
+		YAxis: chart.YAxis{
+			Name: "cm",
+			ValueFormatter: func(v interface{}) string {
+				return fmt.Sprintf("%.0f", v)
+			},
+		},
+		Series: []chart.Series{
+			chart.TimeSeries{
+				Name: title,
+				Style: chart.Style{
+					StrokeColor: chart.ColorBlue,
+					StrokeWidth: 2,
+				},
+				XValues: xValues,
+				YValues: yValues,
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := graph.Render(chart.PNG, &buf); err != nil {
+		return nil, fmt.Errorf("failed to render chart: %v", err)
+	}
+
+	return buf.Bytes(), nil
+}
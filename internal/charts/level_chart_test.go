@@ -0,0 +1,40 @@
+package charts
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+	"testing"
+	"time"
+)
+
+// TestRenderLevelChart verifies a synthetic series produces a decodable PNG
+func TestRenderLevelChart(t *testing.T) {
+	start := time.Date(2025, 4, 18, 0, 0, 0, 0, time.UTC)
+	var points []Point
+	for i := 0; i < 48; i++ {
+		points = append(points, Point{
+			Time:  start.Add(time.Duration(i) * 30 * time.Minute),
+			Level: 40 + float64(i%7),
+		})
+	}
+
+	image, err := RenderLevelChart("ГРАДАЦ - ДЕГУРИЋ", points)
+	if err != nil {
+		t.Fatalf("Failed to render chart: %v", err)
+	}
+	if len(image) == 0 {
+		t.Fatal("Rendered chart is empty")
+	}
+	if _, err := png.Decode(bytes.NewReader(image)); err != nil {
+		t.Errorf("Rendered chart is not a valid PNG: %v", err)
+	}
+}
+
+// TestRenderLevelChartTooFewPoints verifies short series are rejected
+func TestRenderLevelChartTooFewPoints(t *testing.T) {
+	_, err := RenderLevelChart("ДРИНА", []Point{{Time: time.Now(), Level: 140}})
+	if !errors.Is(err, ErrNotEnoughPoints) {
+		t.Errorf("Expected ErrNotEnoughPoints, got %v", err)
+	}
+}
@@ -0,0 +1,161 @@
+package charts
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+	"github.com/wcharczuk/go-chart/v2"
+	"github.com/wcharczuk/go-chart/v2/drawing"
+)
+
+// ErrNoRows is returned when there are no readings to put in a table
+var ErrNoRows = errors.New("no readings to render a table")
+
+// Table layout, in pixels
+const (
+	tableFontSize  = 12.0
+	tableRowHeight = 28
+	tablePadding   = 16
+	tableCellInset = 8
+)
+
+// tableColumn is one column of the readings table
+type tableColumn struct {
+	title string
+	width int
+	value func(rd entities.RiverData) string
+}
+
+// tableColumns lists the columns of the readings table, station names get the most room
+var tableColumns = []tableColumn{
+	{"River", 160, func(rd entities.RiverData) string { return rd.River }},
+	{"Station", 240, func(rd entities.RiverData) string { return rd.Station }},
+	{"Level (cm)", 110, func(rd entities.RiverData) string { return rd.WaterLevel }},
+	{"Temp (°C)", 110, func(rd entities.RiverData) string { return rd.WaterTemp }},
+	{"Tendency", 100, func(rd entities.RiverData) string {
+		if !rd.Tendency.IsKnown() {
+			return ""
+		}
+		return rd.Tendency.Label()
+	}},
+}
+
+// Table colors
+var (
+	tableHeaderColor = drawing.Color{R: 0x1f, G: 0x4e, B: 0x79, A: 0xff}
+	tableStripeColor = drawing.Color{R: 0xf0, G: 0xf4, B: 0xf8, A: 0xff}
+	tableGridColor   = drawing.Color{R: 0xc8, G: 0xd0, B: 0xd8, A: 0xff}
+)
+
+// RenderTableImage draws a PNG table of readings with their river, station,
+// water level, water temperature and tendency. Text too wide for its column
+// is truncated with an ellipsis.
+func RenderTableImage(data []entities.RiverData) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, ErrNoRows
+	}
+
+	tableWidth := 0
+	for _, column := range tableColumns {
+		tableWidth += column.width
+	}
+	width := tableWidth + 2*tablePadding
+	height := (len(data)+1)*tableRowHeight + 2*tablePadding
+
+	r, err := chart.PNG(width, height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create table image: %v", err)
+	}
+	font, err := chart.GetDefaultFont()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load font: %v", err)
+	}
+	r.SetFont(font)
+	r.SetFontSize(tableFontSize)
+
+	fillRect(r, 0, 0, width, height, chart.ColorWhite)
+	fillRect(r, tablePadding, tablePadding, tableWidth, tableRowHeight, tableHeaderColor)
+	for i := range data {
+		if i%2 == 1 {
+			fillRect(r, tablePadding, tablePadding+(i+1)*tableRowHeight, tableWidth, tableRowHeight, tableStripeColor)
+		}
+	}
+
+	// Header row, then one row per reading
+	r.SetFontColor(chart.ColorWhite)
+	drawRow(r, 0, func(column tableColumn) string { return column.title })
+	r.SetFontColor(chart.ColorBlack)
+	for i, rd := range data {
+		drawRow(r, i+1, func(column tableColumn) string { return column.value(rd) })
+	}
+
+	// Grid lines below each row and between columns
+	r.SetStrokeColor(tableGridColor)
+	r.SetStrokeWidth(1)
+	bottom := tablePadding + (len(data)+1)*tableRowHeight
+	for row := 1; row <= len(data)+1; row++ {
+		y := tablePadding + row*tableRowHeight
+		r.MoveTo(tablePadding, y)
+		r.LineTo(tablePadding+tableWidth, y)
+	}
+	x := tablePadding
+	for _, column := range tableColumns[:len(tableColumns)-1] {
+		x += column.width
+		r.MoveTo(x, tablePadding+tableRowHeight)
+		r.LineTo(x, bottom)
+	}
+	r.Stroke()
+
+	var buf bytes.Buffer
+	if err := r.Save(&buf); err != nil {
+		return nil, fmt.Errorf("failed to render table: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// drawRow writes the text of one table row, row 0 being the header
+func drawRow(r chart.Renderer, row int, text func(column tableColumn) string) {
+	x := tablePadding
+	baseline := tablePadding + row*tableRowHeight + tableRowHeight/2 + int(tableFontSize)/2
+	for _, column := range tableColumns {
+		maxWidth := column.width - 2*tableCellInset
+		cell := truncateText(strings.TrimSpace(text(column)), maxWidth, func(s string) int {
+			return r.MeasureText(s).Width()
+		})
+		r.Text(cell, x+tableCellInset, baseline)
+		x += column.width
+	}
+}
+
+// fillRect fills a rectangle with a solid color
+func fillRect(r chart.Renderer, x, y, width, height int, color drawing.Color) {
+	r.SetFillColor(color)
+	r.SetStrokeColor(color)
+	r.SetStrokeWidth(0)
+	r.MoveTo(x, y)
+	r.LineTo(x+width, y)
+	r.LineTo(x+width, y+height)
+	r.LineTo(x, y+height)
+	r.Close()
+	r.Fill()
+}
+
+// truncateText shortens text with a trailing ellipsis until measure reports it
+// fits within maxWidth. Text that already fits is returned unchanged.
+func truncateText(text string, maxWidth int, measure func(string) int) string {
+	if measure(text) <= maxWidth {
+		return text
+	}
+
+	runes := []rune(text)
+	for n := len(runes) - 1; n > 0; n-- {
+		truncated := strings.TrimSpace(string(runes[:n])) + "…"
+		if measure(truncated) <= maxWidth {
+			return truncated
+		}
+	}
+	return "…"
+}
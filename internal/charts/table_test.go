@@ -0,0 +1,70 @@
+package charts
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// TestRenderTableImage verifies sample rows produce a decodable PNG with one row per reading
+func TestRenderTableImage(t *testing.T) {
+	timestamp := time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC)
+	data := []entities.RiverData{
+		{River: "ДУНАВ", Station: "Бездан", WaterLevel: "312", WaterTemp: "12.4", Tendency: entities.Rising, Timestamp: timestamp},
+		{River: "ДУНАВ", Station: "Нови Сад", WaterLevel: "210", Tendency: entities.Falling, Timestamp: timestamp},
+		{River: "ДУНАВ", Station: "Смедерево са веома дугачким именом станице", WaterLevel: "455", Timestamp: timestamp},
+	}
+
+	image, err := RenderTableImage(data)
+	if err != nil {
+		t.Fatalf("Failed to render table: %v", err)
+	}
+	if len(image) == 0 {
+		t.Fatal("Rendered table is empty")
+	}
+	decoded, err := png.Decode(bytes.NewReader(image))
+	if err != nil {
+		t.Fatalf("Rendered table is not a valid PNG: %v", err)
+	}
+	if want := (len(data)+1)*tableRowHeight + 2*tablePadding; decoded.Bounds().Dy() != want {
+		t.Errorf("Expected height %d, got %d", want, decoded.Bounds().Dy())
+	}
+}
+
+// TestRenderTableImageNoRows verifies an empty table is rejected
+func TestRenderTableImageNoRows(t *testing.T) {
+	if _, err := RenderTableImage(nil); !errors.Is(err, ErrNoRows) {
+		t.Errorf("Expected ErrNoRows, got %v", err)
+	}
+}
+
+// TestTruncateText verifies long text is shortened with an ellipsis to fit
+func TestTruncateText(t *testing.T) {
+	// One pixel per character keeps the expectations readable
+	measure := func(s string) int { return utf8.RuneCountInString(s) }
+
+	tests := []struct {
+		name     string
+		text     string
+		maxWidth int
+		want     string
+	}{
+		{"fits", "Бездан", 10, "Бездан"},
+		{"exact fit", "Бездан", 6, "Бездан"},
+		{"truncated", "Смедерево", 6, "Смеде…"},
+		{"trailing space trimmed", "Нови Сад", 6, "Нови…"},
+		{"no room", "Бездан", 0, "…"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := truncateText(tt.text, tt.maxWidth, measure); got != tt.want {
+				t.Errorf("truncateText(%q, %d) = %q, want %q", tt.text, tt.maxWidth, got, tt.want)
+			}
+		})
+	}
+}
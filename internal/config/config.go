@@ -0,0 +1,373 @@
+// Package config loads the bot's and the scraper's settings from the environment
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Defaults of the optional settings
+const (
+	defaultRefreshCron      = "0 * * * *" // At the start of every hour
+	defaultStaleAfter       = time.Hour
+	defaultRiver            = "ГРАДАЦ"
+	defaultSummaryThreshold = 5
+	defaultBackupKeep       = 7
+	defaultRateLimit        = 1.0
+	defaultRateBurst        = 5
+	defaultOpenAICacheSize  = 256
+	defaultOpenAICacheTTL   = 10 * time.Minute
+)
+
+// Default plausible ranges of fetched readings, wide enough for any Balkan station but
+// rejecting placeholders such as 9999 and temperatures no unfrozen river reaches
+var (
+	defaultLevelRange = Range{Min: -500, Max: 2000}
+	defaultTempRange  = Range{Min: -1, Max: 40}
+)
+
+// Range is an inclusive "min:max" range
+type Range struct {
+	Min, Max float64
+}
+
+// Sources holds the pages the scraper fetches and the User-Agent it sends. Empty fields
+// keep the scraper's defaults.
+type Sources struct {
+	HidmetURL        string // HIDMET_URL
+	GradacURL        string // GRADAC_URL
+	HidmetHistoryURL string // HIDMET_HISTORY_URL
+	RhmzRsListingURL string // RHMZRS_LISTING_URL
+	DhmzURL          string // DHMZ_URL
+	UserAgent        string // SCRAPER_USER_AGENT
+}
+
+// Config holds the settings read from the environment, validated and with defaults applied
+type Config struct {
+	LogFormat string // LOG_FORMAT
+	LogLevel  string // LOG_LEVEL
+
+	TelegramBotToken string  // TELEGRAM_BOT_TOKEN
+	AdminChatIDs     []int64 // ADMIN_CHAT_IDS, comma-separated
+	RateLimit        float64 // RATE_LIMIT_PER_SECOND, 0 disables rate limiting
+	RateBurst        int     // RATE_LIMIT_BURST
+
+	OpenAIAPIKey       string        // OPENAI_API_KEY, natural-language mode is disabled without it
+	OpenAIModel        string        // OPENAI_MODEL, empty uses the service's default
+	OpenAITemperature  *float64      // OPENAI_TEMPERATURE, nil leaves the model's default
+	OpenAICacheSize    int           // OPENAI_CACHE_SIZE, 0 disables the cache
+	OpenAICacheTTL     time.Duration // OPENAI_CACHE_TTL
+	OpenAISystemPrompt string        // OPENAI_SYSTEM_PROMPT or the contents of OPENAI_SYSTEM_PROMPT_FILE
+
+	DBDriver    string // DB_DRIVER, "sqlite" or "postgres"
+	DBPath      string // DB_PATH, empty uses the repository's default path
+	PostgresDSN string // POSTGRES_DSN, required by the postgres driver
+
+	Sources Sources
+
+	RefreshSchedule string        // Cron spec from REFRESH_CRON or REFRESH_INTERVAL
+	RefreshWait     time.Duration // REFRESH_WAIT
+	RunScraperInBot bool          // RUN_SCRAPER_IN_BOT
+
+	StaleAfter       time.Duration // DATA_STALE_AFTER
+	LevelRange       Range         // VALID_LEVEL_RANGE, in cm
+	TempRange        Range         // VALID_TEMP_RANGE, in °C
+	DefaultRiver     string        // DEFAULT_RIVER, set but empty disables it
+	SummaryThreshold int           // RIVER_SUMMARY_THRESHOLD, 0 disables summaries
+
+	HealthAddr string // HEALTH_ADDR, empty uses the health server's default
+
+	BackupKeep int    // BACKUP_KEEP, 0 disables backups
+	BackupDir  string // BACKUP_DIR, empty keeps backups next to the database
+}
+
+// loadOptions holds the settings applied by Option values
+type loadOptions struct {
+	requireBotToken bool
+}
+
+// Option tunes what Load requires
+type Option func(*loadOptions)
+
+// RequireBotToken makes TELEGRAM_BOT_TOKEN required, which only the bot needs
+func RequireBotToken() Option {
+	return func(o *loadOptions) {
+		o.requireBotToken = true
+	}
+}
+
+// Load reads the configuration from the environment. Every missing required variable
+// and invalid value is reported together in the returned error, not just the first one.
+func Load(opts ...Option) (Config, error) {
+	var options loadOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	var l loader
+	cfg := Config{
+		LogFormat:        env("LOG_FORMAT"),
+		LogLevel:         env("LOG_LEVEL"),
+		TelegramBotToken: env("TELEGRAM_BOT_TOKEN"),
+		AdminChatIDs:     l.chatIDs("ADMIN_CHAT_IDS"),
+		RateLimit:        l.number("RATE_LIMIT_PER_SECOND", defaultRateLimit, 0),
+		RateBurst:        l.integer("RATE_LIMIT_BURST", defaultRateBurst, 1),
+
+		OpenAIAPIKey:       env("OPENAI_API_KEY"),
+		OpenAIModel:        env("OPENAI_MODEL"),
+		OpenAITemperature:  l.temperature("OPENAI_TEMPERATURE"),
+		OpenAICacheSize:    l.integer("OPENAI_CACHE_SIZE", defaultOpenAICacheSize, 0),
+		OpenAICacheTTL:     l.duration("OPENAI_CACHE_TTL", defaultOpenAICacheTTL, false),
+		OpenAISystemPrompt: l.systemPrompt("OPENAI_SYSTEM_PROMPT", "OPENAI_SYSTEM_PROMPT_FILE"),
+
+		DBDriver:    env("DB_DRIVER"),
+		DBPath:      DBPath(),
+		PostgresDSN: env("POSTGRES_DSN"),
+
+		Sources: Sources{
+			HidmetURL:        env("HIDMET_URL"),
+			GradacURL:        env("GRADAC_URL"),
+			HidmetHistoryURL: env("HIDMET_HISTORY_URL"),
+			RhmzRsListingURL: env("RHMZRS_LISTING_URL"),
+			DhmzURL:          env("DHMZ_URL"),
+			UserAgent:        env("SCRAPER_USER_AGENT"),
+		},
+
+		RefreshSchedule: l.schedule("REFRESH_CRON", "REFRESH_INTERVAL"),
+		RefreshWait:     l.duration("REFRESH_WAIT", 0, false),
+		RunScraperInBot: l.boolean("RUN_SCRAPER_IN_BOT"),
+
+		StaleAfter:       l.duration("DATA_STALE_AFTER", defaultStaleAfter, true),
+		LevelRange:       l.bounds("VALID_LEVEL_RANGE", defaultLevelRange),
+		TempRange:        l.bounds("VALID_TEMP_RANGE", defaultTempRange),
+		DefaultRiver:     defaultRiver,
+		SummaryThreshold: l.integer("RIVER_SUMMARY_THRESHOLD", defaultSummaryThreshold, 0),
+
+		HealthAddr: env("HEALTH_ADDR"),
+
+		BackupKeep: l.integer("BACKUP_KEEP", defaultBackupKeep, 0),
+		BackupDir:  env("BACKUP_DIR"),
+	}
+	if river, ok := os.LookupEnv("DEFAULT_RIVER"); ok {
+		cfg.DefaultRiver = strings.TrimSpace(river)
+	}
+
+	var missing []string
+	if options.requireBotToken && cfg.TelegramBotToken == "" {
+		missing = append(missing, "TELEGRAM_BOT_TOKEN")
+	}
+	switch cfg.DBDriver {
+	case "", "sqlite", "sqlite3":
+	case "postgres", "postgresql":
+		if cfg.PostgresDSN == "" {
+			missing = append(missing, "POSTGRES_DSN")
+		}
+	default:
+		l.invalid("DB_DRIVER", cfg.DBDriver, "must be sqlite or postgres")
+	}
+
+	errs := l.errs
+	if len(missing) > 0 {
+		errs = append([]error{fmt.Errorf("missing required environment variables: %s", strings.Join(missing, ", "))}, errs...)
+	}
+	if len(errs) > 0 {
+		return Config{}, errors.Join(errs...)
+	}
+	return cfg, nil
+}
+
+// DBPath returns the SQLite database path set by DB_PATH, empty when it is unset
+func DBPath() string {
+	return env("DB_PATH")
+}
+
+// loader parses environment variables, collecting an error for each invalid value and
+// using the default in its place so the remaining variables are still checked
+type loader struct {
+	errs []error
+}
+
+// invalid records that a variable has an invalid value
+func (l *loader) invalid(key, raw, rule string) {
+	l.errs = append(l.errs, fmt.Errorf("invalid %s %q: %s", key, raw, rule))
+}
+
+// integer parses a whole number of at least min, returning fallback when it is unset
+func (l *loader) integer(key string, fallback, min int) int {
+	raw := env(key)
+	if raw == "" {
+		return fallback
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < min {
+		l.invalid(key, raw, fmt.Sprintf("must be a whole number of at least %d", min))
+		return fallback
+	}
+	return value
+}
+
+// number parses a number of at least min, returning fallback when it is unset
+func (l *loader) number(key string, fallback, min float64) float64 {
+	raw := env(key)
+	if raw == "" {
+		return fallback
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil || value < min {
+		l.invalid(key, raw, fmt.Sprintf("must be a number of at least %g", min))
+		return fallback
+	}
+	return value
+}
+
+// duration parses a non-negative duration, or a positive one when positive is set,
+// returning fallback when it is unset
+func (l *loader) duration(key string, fallback time.Duration, positive bool) time.Duration {
+	raw := env(key)
+	if raw == "" {
+		return fallback
+	}
+	value, err := time.ParseDuration(raw)
+	switch {
+	case err != nil:
+		l.invalid(key, raw, "must be a duration such as 10m")
+	case positive && value <= 0:
+		l.invalid(key, raw, "must be positive")
+	case value < 0:
+		l.invalid(key, raw, "must not be negative")
+	default:
+		return value
+	}
+	return fallback
+}
+
+// boolean parses a true or false flag, false when it is unset
+func (l *loader) boolean(key string) bool {
+	raw := env(key)
+	if raw == "" {
+		return false
+	}
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		l.invalid(key, raw, "must be true or false")
+	}
+	return value
+}
+
+// bounds parses a "min:max" range, returning fallback when it is unset
+func (l *loader) bounds(key string, fallback Range) Range {
+	raw := env(key)
+	if raw == "" {
+		return fallback
+	}
+	minText, maxText, ok := strings.Cut(raw, ":")
+	if !ok {
+		l.invalid(key, raw, "expected min:max")
+		return fallback
+	}
+	min, minErr := strconv.ParseFloat(strings.TrimSpace(minText), 64)
+	max, maxErr := strconv.ParseFloat(strings.TrimSpace(maxText), 64)
+	switch {
+	case minErr != nil || maxErr != nil:
+		l.invalid(key, raw, "min and max must be numbers")
+	case min > max:
+		l.invalid(key, raw, "minimum is above maximum")
+	default:
+		return Range{Min: min, Max: max}
+	}
+	return fallback
+}
+
+// temperature parses the OpenAI sampling temperature, nil when it is unset
+func (l *loader) temperature(key string) *float64 {
+	raw := env(key)
+	if raw == "" {
+		return nil
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil || value < 0 || value > 2 {
+		l.invalid(key, raw, "must be a number between 0 and 2")
+		return nil
+	}
+	return &value
+}
+
+// chatIDs parses a comma-separated list of chat IDs, ignoring empty entries
+func (l *loader) chatIDs(key string) []int64 {
+	raw := env(key)
+	var ids []int64
+	for _, field := range strings.Split(raw, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			l.invalid(key, raw, fmt.Sprintf("%q is not a chat ID", field))
+			return nil
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// schedule returns the refresh cron spec from a cron variable or an interval variable
+// (a duration such as "15m"), defaulting to hourly
+func (l *loader) schedule(cronKey, intervalKey string) string {
+	spec, interval := env(cronKey), env(intervalKey)
+	switch {
+	case spec != "" && interval != "":
+		l.errs = append(l.errs, fmt.Errorf("%s and %s are mutually exclusive", cronKey, intervalKey))
+	case interval != "":
+		d, err := time.ParseDuration(interval)
+		if err != nil || d < time.Minute {
+			l.invalid(intervalKey, interval, "must be a duration of at least 1m")
+			break
+		}
+		return "@every " + d.String()
+	case spec != "":
+		if _, err := cron.ParseStandard(spec); err != nil {
+			l.invalid(cronKey, spec, err.Error())
+			break
+		}
+		return spec
+	}
+	return defaultRefreshCron
+}
+
+// systemPrompt returns the prompt set by promptKey, or read from the file named by
+// fileKey, empty when neither is set
+func (l *loader) systemPrompt(promptKey, fileKey string) string {
+	prompt, path := os.Getenv(promptKey), env(fileKey)
+	switch {
+	case prompt != "" && path != "":
+		l.errs = append(l.errs, fmt.Errorf("set only one of %s and %s", promptKey, fileKey))
+		return ""
+	case path != "":
+		data, err := os.ReadFile(path)
+		if err != nil {
+			l.invalid(fileKey, path, err.Error())
+			return ""
+		}
+		prompt = string(data)
+	case prompt == "":
+		return ""
+	}
+
+	if strings.TrimSpace(prompt) == "" {
+		l.errs = append(l.errs, errors.New("the configured OpenAI system prompt is empty"))
+		return ""
+	}
+	return prompt
+}
+
+// env returns the value of an environment variable with surrounding spaces trimmed
+func env(key string) string {
+	return strings.TrimSpace(os.Getenv(key))
+}
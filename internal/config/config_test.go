@@ -0,0 +1,311 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// configVars are the environment variables Load reads
+var configVars = []string{
+	"LOG_FORMAT", "LOG_LEVEL", "TELEGRAM_BOT_TOKEN", "ADMIN_CHAT_IDS", "RATE_LIMIT_PER_SECOND",
+	"RATE_LIMIT_BURST", "OPENAI_API_KEY", "OPENAI_MODEL", "OPENAI_TEMPERATURE", "OPENAI_CACHE_SIZE",
+	"OPENAI_CACHE_TTL", "OPENAI_SYSTEM_PROMPT", "OPENAI_SYSTEM_PROMPT_FILE", "DB_DRIVER", "DB_PATH",
+	"POSTGRES_DSN", "HIDMET_URL", "GRADAC_URL", "HIDMET_HISTORY_URL", "RHMZRS_LISTING_URL", "DHMZ_URL",
+	"SCRAPER_USER_AGENT", "REFRESH_CRON", "REFRESH_INTERVAL", "REFRESH_WAIT", "RUN_SCRAPER_IN_BOT",
+	"DATA_STALE_AFTER", "VALID_LEVEL_RANGE", "VALID_TEMP_RANGE", "RIVER_SUMMARY_THRESHOLD", "HEALTH_ADDR",
+	"BACKUP_KEEP", "BACKUP_DIR",
+}
+
+// clearEnv empties every variable Load reads for the rest of the test. DEFAULT_RIVER is
+// unset rather than emptied, since an empty value disables the default river.
+func clearEnv(t *testing.T) {
+	t.Helper()
+	for _, key := range configVars {
+		t.Setenv(key, "")
+	}
+	t.Setenv("DEFAULT_RIVER", "")
+	if err := os.Unsetenv("DEFAULT_RIVER"); err != nil {
+		t.Fatalf("Failed to unset DEFAULT_RIVER: %v", err)
+	}
+}
+
+// TestLoadDefaults verifies the defaults used when nothing is configured, without requiring a bot token
+func TestLoadDefaults(t *testing.T) {
+	clearEnv(t)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	want := Config{
+		RateLimit:        defaultRateLimit,
+		RateBurst:        defaultRateBurst,
+		OpenAICacheSize:  defaultOpenAICacheSize,
+		OpenAICacheTTL:   defaultOpenAICacheTTL,
+		RefreshSchedule:  defaultRefreshCron,
+		StaleAfter:       defaultStaleAfter,
+		LevelRange:       defaultLevelRange,
+		TempRange:        defaultTempRange,
+		DefaultRiver:     defaultRiver,
+		SummaryThreshold: defaultSummaryThreshold,
+		BackupKeep:       defaultBackupKeep,
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("Expected defaults %+v, got %+v", want, cfg)
+	}
+}
+
+// TestLoadOverrides verifies set variables replace the defaults
+func TestLoadOverrides(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("TELEGRAM_BOT_TOKEN", " token ")
+	t.Setenv("ADMIN_CHAT_IDS", " 42, -100123 ,,7")
+	t.Setenv("RATE_LIMIT_PER_SECOND", "0")
+	t.Setenv("OPENAI_API_KEY", "key")
+	t.Setenv("OPENAI_MODEL", "gpt-4o-mini")
+	t.Setenv("OPENAI_TEMPERATURE", "0.2")
+	t.Setenv("OPENAI_CACHE_SIZE", "0")
+	t.Setenv("OPENAI_SYSTEM_PROMPT", "Answer in English.")
+	t.Setenv("DB_DRIVER", "postgres")
+	t.Setenv("POSTGRES_DSN", "postgres://localhost/water")
+	t.Setenv("GRADAC_URL", "http://localhost:8080/gradac")
+	t.Setenv("REFRESH_INTERVAL", "15m")
+	t.Setenv("REFRESH_WAIT", "30s")
+	t.Setenv("RUN_SCRAPER_IN_BOT", "true")
+	t.Setenv("DATA_STALE_AFTER", "3h")
+	t.Setenv("VALID_LEVEL_RANGE", "-100:1500")
+	t.Setenv("DEFAULT_RIVER", "")
+	t.Setenv("RIVER_SUMMARY_THRESHOLD", "0")
+	t.Setenv("BACKUP_KEEP", "0")
+
+	cfg, err := Load(RequireBotToken())
+	if err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	if cfg.TelegramBotToken != "token" {
+		t.Errorf("Expected the trimmed token, got %q", cfg.TelegramBotToken)
+	}
+	if want := []int64{42, -100123, 7}; !reflect.DeepEqual(cfg.AdminChatIDs, want) {
+		t.Errorf("Expected admins %v, got %v", want, cfg.AdminChatIDs)
+	}
+	if cfg.RateLimit != 0 {
+		t.Errorf("Expected rate limiting to be disabled, got %v", cfg.RateLimit)
+	}
+	if cfg.OpenAIAPIKey != "key" || cfg.OpenAIModel != "gpt-4o-mini" {
+		t.Errorf("Expected the OpenAI key and model, got %q and %q", cfg.OpenAIAPIKey, cfg.OpenAIModel)
+	}
+	if cfg.OpenAITemperature == nil || *cfg.OpenAITemperature != 0.2 {
+		t.Errorf("Expected temperature 0.2, got %v", cfg.OpenAITemperature)
+	}
+	if cfg.OpenAICacheSize != 0 || cfg.OpenAISystemPrompt != "Answer in English." {
+		t.Errorf("Expected the cache disabled and the custom prompt, got %d and %q", cfg.OpenAICacheSize, cfg.OpenAISystemPrompt)
+	}
+	if cfg.DBDriver != "postgres" || cfg.PostgresDSN != "postgres://localhost/water" {
+		t.Errorf("Expected the postgres driver and DSN, got %q and %q", cfg.DBDriver, cfg.PostgresDSN)
+	}
+	if want := (Sources{GradacURL: "http://localhost:8080/gradac"}); cfg.Sources != want {
+		t.Errorf("Expected sources %+v, got %+v", want, cfg.Sources)
+	}
+	if cfg.RefreshSchedule != "@every 15m0s" || cfg.RefreshWait != 30*time.Second {
+		t.Errorf("Expected a 15 minute schedule waiting 30s, got %q and %v", cfg.RefreshSchedule, cfg.RefreshWait)
+	}
+	if !cfg.RunScraperInBot {
+		t.Error("Expected the scraper to run in the bot")
+	}
+	if cfg.StaleAfter != 3*time.Hour {
+		t.Errorf("Expected stale after 3h, got %v", cfg.StaleAfter)
+	}
+	if want := (Range{Min: -100, Max: 1500}); cfg.LevelRange != want || cfg.TempRange != defaultTempRange {
+		t.Errorf("Expected level range %+v and the default temperature range, got %+v and %+v", want, cfg.LevelRange, cfg.TempRange)
+	}
+	if cfg.DefaultRiver != "" || cfg.SummaryThreshold != 0 || cfg.BackupKeep != 0 {
+		t.Errorf("Expected the default river, summaries and backups to be disabled, got %+v", cfg)
+	}
+}
+
+// TestLoadSystemPromptFile verifies the OpenAI system prompt can be read from a file
+func TestLoadSystemPromptFile(t *testing.T) {
+	clearEnv(t)
+	path := filepath.Join(t.TempDir(), "prompt.txt")
+	if err := os.WriteFile(path, []byte("Answer briefly.\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write prompt: %v", err)
+	}
+	t.Setenv("OPENAI_SYSTEM_PROMPT_FILE", path)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	if cfg.OpenAISystemPrompt != "Answer briefly.\n" {
+		t.Errorf("Expected the prompt from the file, got %q", cfg.OpenAISystemPrompt)
+	}
+}
+
+// TestLoadRefreshSchedule tests reading the refresh schedule from REFRESH_CRON and REFRESH_INTERVAL
+func TestLoadRefreshSchedule(t *testing.T) {
+	tests := []struct {
+		name     string
+		cronSpec string
+		interval string
+		want     string
+		wantErr  bool
+	}{
+		{name: "default hourly", want: "0 * * * *"},
+		{name: "cron spec", cronSpec: "*/15 * * * *", want: "*/15 * * * *"},
+		{name: "cron descriptor", cronSpec: "@hourly", want: "@hourly"},
+		{name: "invalid cron spec", cronSpec: "every hour", wantErr: true},
+		{name: "cron spec with seconds field", cronSpec: "0 0 * * * *", wantErr: true},
+		{name: "interval", interval: "15m", want: "@every 15m0s"},
+		{name: "interval in hours", interval: "2h", want: "@every 2h0m0s"},
+		{name: "invalid interval", interval: "15 minutes", wantErr: true},
+		{name: "interval too short", interval: "10s", wantErr: true},
+		{name: "both set", cronSpec: "0 * * * *", interval: "15m", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			t.Setenv("REFRESH_CRON", tt.cronSpec)
+			t.Setenv("REFRESH_INTERVAL", tt.interval)
+
+			cfg, err := Load()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if cfg.RefreshSchedule != tt.want {
+				t.Errorf("Expected schedule %q, got %q", tt.want, cfg.RefreshSchedule)
+			}
+		})
+	}
+}
+
+// TestLoadErrors verifies missing and invalid variables are all reported together
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		opts     []Option
+		wantErrs []string
+	}{
+		{
+			name:     "bot token only required by the bot",
+			opts:     []Option{RequireBotToken()},
+			wantErrs: []string{"missing required environment variables: TELEGRAM_BOT_TOKEN"},
+		},
+		{
+			name:     "all missing variables listed",
+			env:      map[string]string{"DB_DRIVER": "postgres"},
+			opts:     []Option{RequireBotToken()},
+			wantErrs: []string{"missing required environment variables: TELEGRAM_BOT_TOKEN, POSTGRES_DSN"},
+		},
+		{
+			name: "invalid values reported with missing ones",
+			env: map[string]string{
+				"DB_DRIVER":        "mysql",
+				"ADMIN_CHAT_IDS":   "42,abc",
+				"REFRESH_INTERVAL": "10s",
+				"DATA_STALE_AFTER": "soon",
+				"BACKUP_KEEP":      "-1",
+			},
+			opts: []Option{RequireBotToken()},
+			wantErrs: []string{
+				"missing required environment variables: TELEGRAM_BOT_TOKEN",
+				"invalid DB_DRIVER",
+				"invalid ADMIN_CHAT_IDS",
+				"invalid REFRESH_INTERVAL",
+				"invalid DATA_STALE_AFTER",
+				"invalid BACKUP_KEEP",
+			},
+		},
+		{
+			name:     "invalid boolean",
+			env:      map[string]string{"RUN_SCRAPER_IN_BOT": "sometimes"},
+			wantErrs: []string{"invalid RUN_SCRAPER_IN_BOT"},
+		},
+		{
+			name: "invalid durations",
+			env: map[string]string{
+				"DATA_STALE_AFTER": "0s",
+				"REFRESH_WAIT":     "-1s",
+				"OPENAI_CACHE_TTL": "a while",
+			},
+			wantErrs: []string{
+				`invalid DATA_STALE_AFTER "0s": must be positive`,
+				`invalid REFRESH_WAIT "-1s": must not be negative`,
+				"invalid OPENAI_CACHE_TTL",
+			},
+		},
+		{
+			name: "invalid numbers",
+			env: map[string]string{
+				"RATE_LIMIT_PER_SECOND":   "-1",
+				"RATE_LIMIT_BURST":        "0",
+				"OPENAI_TEMPERATURE":      "3",
+				"OPENAI_CACHE_SIZE":       "many",
+				"RIVER_SUMMARY_THRESHOLD": "-5",
+			},
+			wantErrs: []string{
+				"invalid RATE_LIMIT_PER_SECOND",
+				"invalid RATE_LIMIT_BURST",
+				"invalid OPENAI_TEMPERATURE",
+				"invalid OPENAI_CACHE_SIZE",
+				"invalid RIVER_SUMMARY_THRESHOLD",
+			},
+		},
+		{
+			name: "invalid ranges",
+			env: map[string]string{
+				"VALID_LEVEL_RANGE": "2000:-500",
+				"VALID_TEMP_RANGE":  "40",
+			},
+			wantErrs: []string{
+				`invalid VALID_LEVEL_RANGE "2000:-500": minimum is above maximum`,
+				`invalid VALID_TEMP_RANGE "40": expected min:max`,
+			},
+		},
+		{
+			name:     "range with a non-numeric bound",
+			env:      map[string]string{"VALID_TEMP_RANGE": "cold:40"},
+			wantErrs: []string{"invalid VALID_TEMP_RANGE"},
+		},
+		{
+			name: "both system prompts set",
+			env: map[string]string{
+				"OPENAI_SYSTEM_PROMPT":      "Answer briefly.",
+				"OPENAI_SYSTEM_PROMPT_FILE": "prompt.txt",
+			},
+			wantErrs: []string{"set only one of OPENAI_SYSTEM_PROMPT and OPENAI_SYSTEM_PROMPT_FILE"},
+		},
+		{
+			name:     "missing system prompt file",
+			env:      map[string]string{"OPENAI_SYSTEM_PROMPT_FILE": "/nonexistent/prompt.txt"},
+			wantErrs: []string{"invalid OPENAI_SYSTEM_PROMPT_FILE"},
+		},
+		{
+			name:     "blank system prompt",
+			env:      map[string]string{"OPENAI_SYSTEM_PROMPT": "  \n"},
+			wantErrs: []string{"the configured OpenAI system prompt is empty"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			_, err := Load(tt.opts...)
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+			for _, want := range tt.wantErrs {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Expected error to contain %q, got %q", want, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package entities
+
+import (
+	"time"
+)
+
+// Digest is a chat's request to receive a summary of its favorite rivers once a day
+type Digest struct {
+	ChatID    int64     // Telegram chat that receives the digest
+	Rivers    []string  // Rivers included in the digest
+	SendHour  int       // Local hour of the day (0-23) at which the digest is sent
+	TimeZone  string    // IANA time zone the send hour is expressed in
+	CreatedAt time.Time // When the digest was created
+}
+
+// IsDue reports whether the digest should be sent during the hour containing now.
+// An unknown time zone falls back to UTC.
+func (d Digest) IsDue(now time.Time) bool {
+	loc, err := time.LoadLocation(d.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	return now.In(loc).Hour() == d.SendHour
+}
@@ -0,0 +1,35 @@
+package entities
+
+import (
+	"testing"
+	"time"
+)
+
+// TestDigestIsDue verifies the send hour is matched in the digest's time zone
+func TestDigestIsDue(t *testing.T) {
+	// 06:30 UTC is 08:30 in Belgrade during summer time
+	now := time.Date(2025, 7, 1, 6, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		digest Digest
+		want   bool
+	}{
+		{"local hour matches", Digest{SendHour: 8, TimeZone: "Europe/Belgrade"}, true},
+		{"UTC hour does not match local", Digest{SendHour: 6, TimeZone: "Europe/Belgrade"}, false},
+		{"UTC zone", Digest{SendHour: 6, TimeZone: "UTC"}, true},
+		{"unknown zone falls back to UTC", Digest{SendHour: 6, TimeZone: "Nowhere/Invalid"}, true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.digest.IsDue(now); got != tt.want {
+			t.Errorf("%s: IsDue() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// In winter Belgrade is UTC+1, so 8:00 local is 07:00 UTC
+	winter := time.Date(2025, 1, 15, 7, 0, 0, 0, time.UTC)
+	if !(Digest{SendHour: 8, TimeZone: "Europe/Belgrade"}).IsDue(winter) {
+		t.Error("Expected 8:00 Belgrade digest to be due at 07:00 UTC in winter")
+	}
+}
@@ -0,0 +1,32 @@
+package entities
+
+// FloodStage classifies a water level against a station's flood defence thresholds
+type FloodStage string
+
+// Flood stages, from normal to the highest alert
+const (
+	FloodNormal  FloodStage = "normal"
+	FloodWarning FloodStage = "warning"
+	FloodDanger  FloodStage = "danger"
+)
+
+// FloodLevel holds the official flood defence thresholds of a station
+type FloodLevel struct {
+	River     string  // Name of the river, as stored with the readings
+	Station   string  // Monitoring station name
+	WarningCm float64 // Level in cm from which regular flood defence is declared
+	DangerCm  float64 // Level in cm from which emergency flood defence is declared
+}
+
+// Classify returns the flood stage of a water level in cm. A threshold that is
+// reached counts as crossed.
+func (f FloodLevel) Classify(level float64) FloodStage {
+	switch {
+	case level >= f.DangerCm:
+		return FloodDanger
+	case level >= f.WarningCm:
+		return FloodWarning
+	default:
+		return FloodNormal
+	}
+}
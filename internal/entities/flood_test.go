@@ -0,0 +1,24 @@
+package entities
+
+import "testing"
+
+// TestFloodLevelClassify verifies levels are classified against both thresholds, inclusively
+func TestFloodLevelClassify(t *testing.T) {
+	level := FloodLevel{River: "ДУНАВ", Station: "Земун", WarningCm: 500, DangerCm: 600}
+	tests := []struct {
+		value float64
+		want  FloodStage
+	}{
+		{-20, FloodNormal},
+		{499.9, FloodNormal},
+		{500, FloodWarning},
+		{599, FloodWarning},
+		{600, FloodDanger},
+		{720, FloodDanger},
+	}
+	for _, tt := range tests {
+		if got := level.Classify(tt.value); got != tt.want {
+			t.Errorf("Classify(%v) = %s; expected %s", tt.value, got, tt.want)
+		}
+	}
+}
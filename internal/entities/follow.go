@@ -0,0 +1,14 @@
+package entities
+
+import (
+	"time"
+)
+
+// Follow is a chat's request to be sent every new water level of a station
+type Follow struct {
+	ChatID    int64     // Telegram chat that receives the updates
+	River     string    // Name of the followed station's river
+	Station   string    // Name of the followed station
+	LastLevel string    // Water level last sent to the chat, so unchanged levels aren't repeated
+	CreatedAt time.Time // When the follow was created
+}
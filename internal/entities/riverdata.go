@@ -2,7 +2,10 @@
 package entities
 
 import (
+	"strings"
 	"time"
+
+	"github.com/abelzeko/water-bot/internal/units"
 )
 
 // RiverData represents a single river data entry in the system
@@ -12,5 +15,97 @@ type RiverData struct {
 	Station    string    // Monitoring station name
 	WaterLevel string    // Current water level in cm
 	WaterTemp  string    // Water temperature in °C
+	Discharge  string    // Discharge in m³/s, empty when not reported
+	Tendency   Tendency  // Normalized direction of the water level
+	Source     string    // Identifier of the source the reading was scraped from
+	URL        string    // Page the reading was published on, empty when unknown
+	GaugeZero  string    // Elevation of the gauge's zero in m above sea level, empty when not reported
 	Timestamp  time.Time // When the data was recorded
+	Stale      bool      // Whether the reading is too old to be trusted, not persisted
+}
+
+// MarkStale flags the readings recorded more than maxAge before now and reports
+// whether any of them is stale
+func MarkStale(data []RiverData, now time.Time, maxAge time.Duration) bool {
+	anyStale := false
+	for i := range data {
+		data[i].Stale = now.Sub(data[i].Timestamp) > maxAge
+		anyStale = anyStale || data[i].Stale
+	}
+	return anyStale
+}
+
+// InLocation converts the timestamps of readings to loc so they are shown in that time zone
+func InLocation(data []RiverData, loc *time.Location) {
+	for i := range data {
+		data[i].Timestamp = data[i].Timestamp.In(loc)
+	}
+}
+
+// NormalizeRiverName returns the canonical spelling of a river name: trimmed, with runs
+// of whitespace collapsed to one space and upper-cased as most sources publish them
+func NormalizeRiverName(name string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(name), " "))
+}
+
+// NormalizeRiverNames rewrites the river names of readings to their canonical spelling,
+// so sources spelling a river slightly differently store it under one name
+func NormalizeRiverNames(data []RiverData) {
+	for i := range data {
+		data[i].River = NormalizeRiverName(data[i].River)
+	}
+}
+
+// DischargeValue parses the discharge as m³/s, accepting a decimal comma and thousands separators.
+// Discharges are published in whole m³/s, so "1.520" is 1520.
+// Reports false when the discharge is missing, e.g. shown as "-".
+func (rd RiverData) DischargeValue() (float64, bool) {
+	return parseMeasurement(rd.Discharge)
+}
+
+// LevelValue parses the water level as cm, accepting a decimal comma and thousands separators.
+// Reports false when the level is missing or not a number.
+func (rd RiverData) LevelValue() (float64, bool) {
+	return parseMeasurement(rd.WaterLevel)
+}
+
+// GaugeZeroValue parses the gauge zero as m above sea level, accepting a decimal comma and thousands separators.
+// Gauge zeros are published with up to three decimals, so "178.520" is 178.52.
+// Reports false when the gauge zero is missing or not a number.
+func (rd RiverData) GaugeZeroValue() (float64, bool) {
+	zero, err := units.ParseDecimal(rd.GaugeZero)
+	if err != nil {
+		return 0, false
+	}
+	return zero, true
+}
+
+// Elevation returns the water surface's elevation in m above sea level, the gauge zero
+// plus the level. Reports false unless both are known.
+func (rd RiverData) Elevation() (float64, bool) {
+	zero, ok := rd.GaugeZeroValue()
+	if !ok {
+		return 0, false
+	}
+	level, ok := rd.LevelValue()
+	if !ok {
+		return 0, false
+	}
+	return zero + level/100, true
+}
+
+// TempValue parses the water temperature as °C, accepting a decimal comma and thousands separators.
+// Reports false when the temperature is missing or not a number.
+func (rd RiverData) TempValue() (float64, bool) {
+	return parseMeasurement(rd.WaterTemp)
+}
+
+// parseMeasurement parses a scraped number with units.ParseNumber, reporting false
+// when it is missing or malformed
+func parseMeasurement(raw string) (float64, bool) {
+	number, err := units.ParseNumber(raw)
+	if err != nil {
+		return 0, false
+	}
+	return number, true
 }
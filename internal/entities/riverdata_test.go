@@ -0,0 +1,111 @@
+package entities
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// TestMarkStale verifies only readings older than the maximum age are flagged
+func TestMarkStale(t *testing.T) {
+	now := time.Date(2025, 4, 18, 12, 0, 0, 0, time.UTC)
+	data := []RiverData{
+		{Station: "fresh", Timestamp: now.Add(-time.Hour)},
+		{Station: "boundary", Timestamp: now.Add(-3 * time.Hour)},
+		{Station: "old", Timestamp: now.Add(-4 * time.Hour)},
+	}
+
+	if !MarkStale(data, now, 3*time.Hour) {
+		t.Error("Expected MarkStale to report stale readings")
+	}
+	for i, want := range []bool{false, false, true} {
+		if data[i].Stale != want {
+			t.Errorf("%s: expected stale %v, got %v", data[i].Station, want, data[i].Stale)
+		}
+	}
+
+	if MarkStale(data[:2], now, 3*time.Hour) {
+		t.Error("Expected no stale readings among recent ones")
+	}
+}
+
+// TestDischargeValue verifies discharge parsing tolerates missing values, decimal commas and thousands separators
+func TestDischargeValue(t *testing.T) {
+	tests := []struct {
+		discharge string
+		want      float64
+		ok        bool
+	}{
+		{"1520", 1520, true},
+		{" 12,5 ", 12.5, true},
+		{"0.8", 0.8, true},
+		{"1.520", 1520, true},
+		{"1.520,5", 1520.5, true},
+		{"-", 0, false},
+		{"", 0, false},
+		{"n/a", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := RiverData{Discharge: tt.discharge}.DischargeValue()
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("DischargeValue(%q) = %v, %v; expected %v, %v", tt.discharge, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+// TestLevelAndTempValue verifies level and temperature parsing share the discharge rules
+func TestLevelAndTempValue(t *testing.T) {
+	data := RiverData{WaterLevel: " 142 ", WaterTemp: "12,5"}
+	if level, ok := data.LevelValue(); !ok || level != 142 {
+		t.Errorf("LevelValue() = %v, %v; expected 142, true", level, ok)
+	}
+	if temp, ok := data.TempValue(); !ok || temp != 12.5 {
+		t.Errorf("TempValue() = %v, %v; expected 12.5, true", temp, ok)
+	}
+	if _, ok := (RiverData{WaterLevel: "-"}).LevelValue(); ok {
+		t.Error("Expected a missing level to be reported")
+	}
+}
+
+// TestElevation verifies the water surface elevation adds the level in m to the gauge zero
+func TestElevation(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   RiverData
+		want   float64
+		wantOK bool
+	}{
+		{"both known", RiverData{GaugeZero: "129,47", WaterLevel: "142"}, 130.89, true},
+		{"negative level", RiverData{GaugeZero: "72.22", WaterLevel: "-50"}, 71.72, true},
+		{"gauge zero with three decimals", RiverData{GaugeZero: "178.520", WaterLevel: "142"}, 179.94, true},
+		{"no gauge zero", RiverData{WaterLevel: "142"}, 0, false},
+		{"no level", RiverData{GaugeZero: "129.47", WaterLevel: "-"}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.data.Elevation()
+			if ok != tt.wantOK || math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Elevation() = %v, %v; expected %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+// TestNormalizeRiverName verifies spellings differing in case and spacing share one canonical name
+func TestNormalizeRiverName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"ДРИНА", "ДРИНА"},
+		{"  Дрина ", "ДРИНА"},
+		{"ЗАПАДНА\tМОРАВА", "ЗАПАДНА МОРАВА"},
+		{"Zapadna  morava", "ZAPADNA MORAVA"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizeRiverName(tt.name); got != tt.want {
+			t.Errorf("NormalizeRiverName(%q) = %q; expected %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+package entities
+
+import (
+	"time"
+)
+
+// SourceCheck is the outcome of the last successful fetch of a data source
+type SourceCheck struct {
+	Source    string    // Identifier of the source
+	CheckedAt time.Time // When the source was last fetched successfully
+	Unchanged bool      // Whether that fetch had no reading newer than the ones already stored
+}
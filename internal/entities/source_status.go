@@ -0,0 +1,15 @@
+package entities
+
+import (
+	"time"
+)
+
+// SourceStatus is whether a data source could be fetched on its last refresh, and which
+// state watching chats were last told about
+type SourceStatus struct {
+	Source        string    // Identifier of the source
+	Down          bool      // Whether the last refresh failed to fetch the source
+	Error         string    // Why the last refresh failed, empty when the source is up
+	ChangedAt     time.Time // When the source last went down or came back up
+	AnnouncedDown bool      // The state last announced to watching chats
+}
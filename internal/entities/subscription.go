@@ -0,0 +1,63 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+)
+
+// AlertDirection describes which side of the threshold triggers an alert
+type AlertDirection string
+
+// Supported alert directions
+const (
+	AlertAbove AlertDirection = "above"
+	AlertBelow AlertDirection = "below"
+)
+
+// AlertKind is the reading an alert compares with its threshold
+type AlertKind string
+
+// Supported alert kinds
+const (
+	AlertLevel AlertKind = "level" // Water level in cm
+	AlertTemp  AlertKind = "temp"  // Water temperature in °C
+)
+
+// Subscription is a chat's request to be alerted when a river crosses a water level
+// or temperature threshold
+type Subscription struct {
+	ID            int64
+	ChatID        int64          // Telegram chat that receives the alert
+	River         string         // Name of the watched river
+	Kind          AlertKind      // Reading compared with the threshold, the water level when empty
+	Threshold     float64        // Threshold in cm, or in °C for temperature alerts
+	Direction     AlertDirection // Whether crossing above or below the threshold alerts
+	Triggered     bool           // Whether the condition currently holds and the alert already fired
+	LastTriggered time.Time      // When the alert last fired, zero if never
+	CreatedAt     time.Time      // When the subscription was created
+}
+
+// Matches reports whether a water level or temperature satisfies the subscription's condition
+func (s Subscription) Matches(value float64) bool {
+	if s.Direction == AlertBelow {
+		return value < s.Threshold
+	}
+	return value > s.Threshold
+}
+
+// Value returns the reading the subscription compares with its threshold, reporting
+// false when the station doesn't publish it
+func (s Subscription) Value(rd RiverData) (float64, bool) {
+	if s.Kind == AlertTemp {
+		return rd.TempValue()
+	}
+	return rd.LevelValue()
+}
+
+// FormatThreshold formats the threshold with its unit
+func (s Subscription) FormatThreshold() string {
+	if s.Kind == AlertTemp {
+		return fmt.Sprintf("%g °C", s.Threshold)
+	}
+	return fmt.Sprintf("%.0f cm", s.Threshold)
+}
@@ -0,0 +1,96 @@
+package entities
+
+import (
+	"strings"
+)
+
+// Tendency is the normalized direction in which a station's water level is moving
+type Tendency string
+
+// Supported tendency values, stored as-is in the database
+const (
+	Rising  Tendency = "rising"
+	Falling Tendency = "falling"
+	Stable  Tendency = "stable"
+	Unknown Tendency = "unknown"
+)
+
+// tendencyAliases maps the raw markers used by the sources to a normalized tendency.
+// hidmet publishes an <img> whose alt text describes the tendency, while RHMZ RS
+// uses arrow characters directly in the table cell and DHMZ uses either.
+var tendencyAliases = map[string]Tendency{
+	// Arrows
+	"▲": Rising, "↑": Rising, "⬆": Rising, "⇧": Rising, "+": Rising,
+	"▼": Falling, "↓": Falling, "⬇": Falling, "⇩": Falling,
+	"►": Stable, "▶": Stable, "→": Stable, "↔": Stable, "=": Stable, "●": Stable,
+
+	// Serbian Cyrillic
+	"пораст": Rising, "расте": Rising, "раст": Rising,
+	"опадање": Falling, "опада": Falling, "пад": Falling,
+	"стагнација": Stable, "стагнира": Stable, "стационарно": Stable, "мирује": Stable, "непромењено": Stable,
+
+	// Serbian Latin
+	"porast": Rising, "raste": Rising, "rast": Rising,
+	"opadanje": Falling, "opada": Falling, "pad": Falling,
+	"stagnacija": Stable, "stagnira": Stable, "stacionarno": Stable, "miruje": Stable, "nepromenjeno": Stable,
+
+	// Croatian, as in "u porastu" / "u opadanju"
+	"porastu": Rising, "opadanju": Falling, "stagnaciji": Stable,
+
+	// English
+	"rising": Rising, "up": Rising,
+	"falling": Falling, "down": Falling,
+	"stable": Stable, "steady": Stable,
+}
+
+// ParseTendency normalizes a raw tendency marker (arrow or alt text) from any source
+func ParseTendency(raw string) Tendency {
+	normalized := strings.ToLower(strings.TrimSpace(raw))
+	if normalized == "" {
+		return Unknown
+	}
+
+	if tendency, ok := tendencyAliases[normalized]; ok {
+		return tendency
+	}
+
+	// Alt texts are sometimes full phrases such as "Тенденција: пораст"
+	for _, word := range strings.FieldsFunc(normalized, func(r rune) bool {
+		return r == ' ' || r == ':' || r == ',' || r == '.' || r == '-'
+	}) {
+		if tendency, ok := tendencyAliases[word]; ok {
+			return tendency
+		}
+	}
+
+	return Unknown
+}
+
+// Symbol returns the arrow used to render the tendency
+func (t Tendency) Symbol() string {
+	switch t {
+	case Rising:
+		return "⬆️"
+	case Falling:
+		return "⬇️"
+	case Stable:
+		return "➡️"
+	default:
+		return "❔"
+	}
+}
+
+// Label returns a human-readable description of the tendency
+func (t Tendency) Label() string {
+	switch t {
+	case Rising, Falling, Stable:
+		return string(t)
+	default:
+		return "unknown"
+	}
+}
+
+// IsKnown reports whether the tendency carries a real direction
+func (t Tendency) IsKnown() bool {
+	return t == Rising || t == Falling || t == Stable
+}
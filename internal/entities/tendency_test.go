@@ -0,0 +1,48 @@
+package entities
+
+import "testing"
+
+// TestParseTendency verifies that arrows and alt texts from all sources are normalized
+func TestParseTendency(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want Tendency
+	}{
+		{"rhmz up arrow", "▲", Rising},
+		{"rhmz down arrow", "▼", Falling},
+		{"rhmz stable arrow", "►", Stable},
+		{"arrow with whitespace", "  ▼\n", Falling},
+		{"hidmet alt rising", "пораст", Rising},
+		{"hidmet alt falling", "Опадање", Falling},
+		{"hidmet alt stable", "стагнација", Stable},
+		{"latin alt", "raste", Rising},
+		{"alt phrase", "Тенденција: опада", Falling},
+		{"already normalized", "stable", Stable},
+		{"empty", "", Unknown},
+		{"whitespace only", "   ", Unknown},
+		{"dash", "-", Unknown},
+		{"garbage", "xyz", Unknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseTendency(tt.raw); got != tt.want {
+				t.Errorf("ParseTendency(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestTendencyRendering verifies symbols and labels for known and unknown tendencies
+func TestTendencyRendering(t *testing.T) {
+	if Rising.Symbol() != "⬆️" || Falling.Symbol() != "⬇️" || Stable.Symbol() != "➡️" {
+		t.Errorf("Unexpected symbols: %s %s %s", Rising.Symbol(), Falling.Symbol(), Stable.Symbol())
+	}
+	if Unknown.IsKnown() {
+		t.Error("Unknown tendency should not be reported as known")
+	}
+	if Tendency("").Label() != "unknown" {
+		t.Errorf("Expected empty tendency label to be 'unknown', got %q", Tendency("").Label())
+	}
+}
@@ -0,0 +1,15 @@
+package entities
+
+import (
+	"time"
+)
+
+// User is a chat that has started the bot
+type User struct {
+	ChatID       int64     // Telegram chat the user talks to the bot from
+	Username     string    // Telegram username, empty if the user has none
+	LanguageCode string    // IETF language tag reported by the Telegram client
+	FirstSeen    time.Time // When the user first started the bot
+	LastSeen     time.Time // When the user last sent a message
+	TimeZone     string    // IANA time zone timestamps are shown in, empty for the default
+}
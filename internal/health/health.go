@@ -0,0 +1,101 @@
+// Package health provides the /healthz endpoint used by container orchestration
+package health
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultAddr is the address the health server listens on when HEALTH_ADDR is not set
+const DefaultAddr = ":8080"
+
+// Checker reports the process as healthy when all of its checks pass
+type Checker struct {
+	mu           sync.RWMutex
+	checks       []namedCheck
+	lastRefresh  time.Time
+	trackRefresh bool
+}
+
+// namedCheck is a single health check
+type namedCheck struct {
+	name  string
+	check func() error
+}
+
+// response is the JSON body returned by the health endpoint
+type response struct {
+	Status         string            `json:"status"`
+	Checks         map[string]string `json:"checks"`
+	LastRefreshAge string            `json:"last_refresh_age,omitempty"`
+}
+
+// NewChecker creates a checker without any checks
+func NewChecker() *Checker {
+	return &Checker{}
+}
+
+// AddCheck registers a check, a non-nil error marks the process unhealthy
+func (c *Checker) AddCheck(name string, check func() error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+}
+
+// RecordRefresh records a successful data refresh, reported as its age by the endpoint.
+// Only processes that run the scraper record refreshes.
+func (c *Checker) RecordRefresh(at time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastRefresh = at
+	c.trackRefresh = true
+}
+
+// ServeHTTP responds with 200 when every check passes and 503 otherwise
+func (c *Checker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.RLock()
+	checks := c.checks
+	lastRefresh, trackRefresh := c.lastRefresh, c.trackRefresh
+	c.mu.RUnlock()
+
+	resp := response{Status: "ok", Checks: make(map[string]string)}
+	status := http.StatusOK
+	for _, check := range checks {
+		if err := check.check(); err != nil {
+			resp.Checks[check.name] = err.Error()
+			resp.Status = "unavailable"
+			status = http.StatusServiceUnavailable
+			continue
+		}
+		resp.Checks[check.name] = "ok"
+	}
+	if trackRefresh {
+		resp.LastRefreshAge = time.Since(lastRefresh).Truncate(time.Second).String()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		slog.Error("Error writing health response", "error", err)
+	}
+}
+
+// Serve starts the health server on addr in the background
+func Serve(addr string, checker *Checker) {
+	if addr == "" {
+		addr = DefaultAddr
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/healthz", checker)
+
+	go func() {
+		slog.Info("Health endpoint listening", "addr", addr, "path", "/healthz")
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			slog.Error("Health server stopped", "error", err)
+		}
+	}()
+}
@@ -0,0 +1,62 @@
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestCheckerUnavailableWhenPingFails verifies a failing repository ping returns 503
+func TestCheckerUnavailableWhenPingFails(t *testing.T) {
+	checker := NewChecker()
+	checker.AddCheck("telegram", func() error { return nil })
+	checker.AddCheck("repository", func() error { return errors.New("database is locked") })
+
+	rec := httptest.NewRecorder()
+	checker.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503, got %d", rec.Code)
+	}
+
+	var resp response
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Invalid JSON response: %v", err)
+	}
+	if resp.Status != "unavailable" || resp.Checks["repository"] != "database is locked" || resp.Checks["telegram"] != "ok" {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+}
+
+// TestCheckerHealthy verifies passing checks return 200 with the refresh age
+func TestCheckerHealthy(t *testing.T) {
+	checker := NewChecker()
+	checker.AddCheck("repository", func() error { return nil })
+
+	rec := httptest.NewRecorder()
+	checker.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", rec.Code)
+	}
+
+	var resp response
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Invalid JSON response: %v", err)
+	}
+	if resp.LastRefreshAge != "" {
+		t.Errorf("Expected no refresh age without a recorded refresh, got %q", resp.LastRefreshAge)
+	}
+
+	checker.RecordRefresh(time.Now().Add(-5 * time.Minute))
+	rec = httptest.NewRecorder()
+	checker.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Invalid JSON response: %v", err)
+	}
+	if resp.LastRefreshAge != "5m0s" {
+		t.Errorf("Expected refresh age 5m0s, got %q", resp.LastRefreshAge)
+	}
+}
@@ -0,0 +1,43 @@
+package integration
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html/charset"
+)
+
+// readUTF8 reads a response body and transcodes it to UTF-8, so Cyrillic served in a
+// legacy encoding such as windows-1251 isn't mangled. The charset is taken from the
+// Content-Type header or the page's <meta> tag. A body that is valid UTF-8 is kept
+// as it is unless the header names another charset, as the charset package would
+// otherwise guess windows-1252 for pages without a declaration.
+func readUTF8(res *http.Response) ([]byte, error) {
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	encoding, name, certain := charset.DetermineEncoding(body, res.Header.Get("Content-Type"))
+	if name == "utf-8" || (!certain && utf8.Valid(body)) {
+		return body, nil
+	}
+	decoded, err := encoding.NewDecoder().Bytes(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s page: %v", name, err)
+	}
+	return decoded, nil
+}
+
+// parseDocument reads a response as UTF-8 and parses it as HTML
+func parseDocument(res *http.Response) (*goquery.Document, error) {
+	body, err := readUTF8(res)
+	if err != nil {
+		return nil, err
+	}
+	return goquery.NewDocumentFromReader(bytes.NewReader(body))
+}
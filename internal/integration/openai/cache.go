@@ -0,0 +1,87 @@
+package openai
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cacheEntry is a cached interpretation and when it stops being valid.
+type cacheEntry struct {
+	key      string
+	response AgentResponse
+	expires  time.Time
+}
+
+// responseCache is a size-bounded LRU cache of interpretations that expire after a TTL.
+// It is safe for concurrent use.
+type responseCache struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	order   *list.List // Most recently used first
+	entries map[string]*list.Element
+	now     func() time.Time
+}
+
+// newResponseCache creates a cache holding at most size interpretations for ttl each.
+func newResponseCache(size int, ttl time.Duration) *responseCache {
+	return &responseCache{
+		size:    size,
+		ttl:     ttl,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+		now:     time.Now,
+	}
+}
+
+// cacheKey identifies a query by its normalized text and the rivers it was interpreted against.
+func cacheKey(userMessage string, supportedRivers []string) string {
+	normalized := strings.ToLower(strings.Join(strings.Fields(userMessage), " "))
+	rivers := sha256.Sum256([]byte(strings.Join(supportedRivers, "\n")))
+	return hex.EncodeToString(rivers[:]) + ":" + normalized
+}
+
+// get returns the cached interpretation for key, reporting false if it is missing or expired.
+func (c *responseCache) get(key string) (AgentResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return AgentResponse{}, false
+	}
+	entry := element.Value.(*cacheEntry)
+	if !c.now().Before(entry.expires) {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		return AgentResponse{}, false
+	}
+
+	c.order.MoveToFront(element)
+	return entry.response, true
+}
+
+// put stores an interpretation, evicting the least recently used one when the cache is full.
+func (c *responseCache) put(key string, response AgentResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expires := c.now().Add(c.ttl)
+	if element, ok := c.entries[key]; ok {
+		entry := element.Value.(*cacheEntry)
+		entry.response, entry.expires = response, expires
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, response: response, expires: expires})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
@@ -0,0 +1,60 @@
+package openai
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestResponseCacheExpires(t *testing.T) {
+	now := time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC)
+	cache := newResponseCache(10, 10*time.Minute)
+	cache.now = func() time.Time { return now }
+
+	cache.put("key", AgentResponse{CommandName: "GeneralQuery"})
+	now = now.Add(9 * time.Minute)
+	if _, ok := cache.get("key"); !ok {
+		t.Error("Expected the entry to be cached within the TTL")
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok := cache.get("key"); ok {
+		t.Error("Expected the entry to expire after the TTL")
+	}
+}
+
+func TestResponseCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newResponseCache(2, time.Hour)
+	cache.put("a", AgentResponse{UserMessage: "a"})
+	cache.put("b", AgentResponse{UserMessage: "b"})
+	cache.get("a") // a is now more recently used than b
+	cache.put("c", AgentResponse{UserMessage: "c"})
+
+	if _, ok := cache.get("b"); ok {
+		t.Error("Expected the least recently used entry to be evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if resp, ok := cache.get(key); !ok || resp.UserMessage != key {
+			t.Errorf("Expected %s to stay cached, got %+v (found %v)", key, resp, ok)
+		}
+	}
+}
+
+func TestResponseCacheConcurrentUse(t *testing.T) {
+	cache := newResponseCache(8, time.Hour)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := fmt.Sprintf("key-%d", i%4)
+			cache.put(key, AgentResponse{UserMessage: key})
+			if resp, ok := cache.get(key); ok && resp.UserMessage != key {
+				t.Errorf("Expected %s, got %s", key, resp.UserMessage)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,93 @@
+package openai
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+// generalQueryCommand is the command assumed when the model leaves command_name blank
+const generalQueryCommand = "GeneralQuery"
+
+// agentFieldRe matches a string field of the agent response, also in truncated JSON
+var agentFieldRe = regexp.MustCompile(`"(command_name|serbian_river_name|user_message)"\s*:\s*"((?:[^"\\]|\\.)*)"`)
+
+// decodeAgentResponse parses the model's output into an AgentResponse.
+// The output is decoded strictly first. When it doesn't match the schema, e.g. it has
+// extra fields, wrong types or was cut off, the known fields are salvaged and the
+// mismatch is logged. A blank command_name is treated as a general query.
+func decodeAgentResponse(content string) (AgentResponse, error) {
+	resp, err := decodeStrict(content)
+	if err != nil {
+		var salvaged bool
+		resp, salvaged = salvageAgentResponse(content)
+		if !salvaged {
+			return AgentResponse{}, fmt.Errorf("error unmarshalling OpenAI response: %w", err)
+		}
+		slog.Warn("OpenAI response doesn't match the schema, using the fields that could be read",
+			"error", err, "command_name", resp.CommandName, "serbian_river_name", resp.SerbianRiverName)
+	}
+
+	if strings.TrimSpace(resp.CommandName) == "" {
+		resp.CommandName = generalQueryCommand
+	}
+	return resp, nil
+}
+
+// decodeStrict decodes a single JSON object with exactly the fields of AgentResponse
+func decodeStrict(content string) (AgentResponse, error) {
+	decoder := json.NewDecoder(strings.NewReader(content))
+	decoder.DisallowUnknownFields()
+
+	var resp AgentResponse
+	if err := decoder.Decode(&resp); err != nil {
+		return AgentResponse{}, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return AgentResponse{}, errors.New("unexpected data after the JSON object")
+	}
+	return resp, nil
+}
+
+// salvageAgentResponse extracts the string fields of AgentResponse from output that
+// failed strict decoding. Fields of another type are ignored. It reports false when
+// neither a command nor a river could be found.
+func salvageAgentResponse(content string) (AgentResponse, bool) {
+	var resp AgentResponse
+
+	// Output that is valid JSON apart from extra or mistyped fields, possibly wrapped in prose
+	var fields map[string]json.RawMessage
+	if start, end := strings.Index(content, "{"), strings.LastIndex(content, "}"); start >= 0 && end > start &&
+		json.Unmarshal([]byte(content[start:end+1]), &fields) == nil {
+		readField := func(name string, target *string) {
+			if raw, ok := fields[name]; ok {
+				_ = json.Unmarshal(raw, target) // Leave mistyped fields empty
+			}
+		}
+		readField("command_name", &resp.CommandName)
+		readField("serbian_river_name", &resp.SerbianRiverName)
+		readField("user_message", &resp.UserMessage)
+	} else {
+		// Truncated output still holds the fields completed before the cut
+		for _, match := range agentFieldRe.FindAllStringSubmatch(content, -1) {
+			var value string
+			if err := json.Unmarshal([]byte(`"`+match[2]+`"`), &value); err != nil {
+				continue
+			}
+			switch match[1] {
+			case "command_name":
+				resp.CommandName = value
+			case "serbian_river_name":
+				resp.SerbianRiverName = value
+			case "user_message":
+				resp.UserMessage = value
+			}
+		}
+	}
+
+	return resp, resp.CommandName != "" || resp.SerbianRiverName != ""
+}
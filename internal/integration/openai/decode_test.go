@@ -0,0 +1,76 @@
+package openai
+
+import "testing"
+
+// TestDecodeAgentResponse verifies schema-conforming, malformed and partial model output
+func TestDecodeAgentResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    AgentResponse
+		wantErr bool
+	}{
+		{
+			name:    "valid",
+			content: `{"command_name":"GetRiverDataByName","serbian_river_name":"ДРИНА","user_message":"Ок"}`,
+			want:    AgentResponse{CommandName: "GetRiverDataByName", SerbianRiverName: "ДРИНА", UserMessage: "Ок"},
+		},
+		{
+			name:    "extra field",
+			content: `{"command_name":"GetRiverDataByName","serbian_river_name":"ДРИНА","user_message":"Ок","confidence":0.9}`,
+			want:    AgentResponse{CommandName: "GetRiverDataByName", SerbianRiverName: "ДРИНА", UserMessage: "Ок"},
+		},
+		{
+			name:    "wrong type",
+			content: `{"command_name":"GetRiverDataByName","serbian_river_name":"ДРИНА","user_message":["Ок"]}`,
+			want:    AgentResponse{CommandName: "GetRiverDataByName", SerbianRiverName: "ДРИНА"},
+		},
+		{
+			name:    "wrapped in a code fence",
+			content: "```json\n{\"command_name\":\"GeneralQuery\",\"serbian_river_name\":\"\",\"user_message\":\"Šta bre?\"}\n```",
+			want:    AgentResponse{CommandName: "GeneralQuery", UserMessage: "Šta bre?"},
+		},
+		{
+			name:    "truncated",
+			content: `{"command_name":"GetRiverDataByName","serbian_river_name":"ВЕЛИКА МОРАВА","user_message":"Ок, ищу да`,
+			want:    AgentResponse{CommandName: "GetRiverDataByName", SerbianRiverName: "ВЕЛИКА МОРАВА"},
+		},
+		{
+			name:    "missing fields",
+			content: `{"serbian_river_name":"САВА"}`,
+			want:    AgentResponse{CommandName: generalQueryCommand, SerbianRiverName: "САВА"},
+		},
+		{
+			name:    "blank command",
+			content: `{"command_name":"","serbian_river_name":"","user_message":"Чё тебе надо?"}`,
+			want:    AgentResponse{CommandName: generalQueryCommand, UserMessage: "Чё тебе надо?"},
+		},
+		{
+			name:    "not json",
+			content: "I can't help with that.",
+			wantErr: true,
+		},
+		{
+			name:    "unrelated object",
+			content: `{"error":"overloaded"}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeAgentResponse(tt.content)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package openai
+
+import (
+	"strings"
+	"unicode"
+)
+
+// maxPromptRivers caps how many river names are sent to the model with each query,
+// every name is paid for in prompt tokens on every call.
+const maxPromptRivers = 200
+
+// maxUserMessageRunes caps the length of a user message sent to the model. River
+// questions are short, anything longer is cut rather than paid for.
+const maxUserMessageRunes = 500
+
+// minMatchPrefix is the shortest prefix a message word and a river name must share
+// to count as a mention, short enough to allow for declension ("Дрине", "Drini").
+const minMatchPrefix = 3
+
+// cyrillicToLatin maps Serbian Cyrillic letters to Serbian Latin
+var cyrillicToLatin = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'ђ': "đ", 'е': "e", 'ж': "ž",
+	'з': "z", 'и': "i", 'ј': "j", 'к': "k", 'л': "l", 'љ': "lj", 'м': "m", 'н': "n",
+	'њ': "nj", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'ћ': "ć", 'у': "u",
+	'ф': "f", 'х': "h", 'ц': "c", 'ч': "č", 'џ': "dž", 'ш': "š",
+}
+
+// truncateMessage cuts a user message to maxUserMessageRunes characters
+func truncateMessage(message string) string {
+	runes := []rune(message)
+	if len(runes) <= maxUserMessageRunes {
+		return message
+	}
+	return string(runes[:maxUserMessageRunes])
+}
+
+// promptRivers returns the rivers to send to the model for a message: the ones the
+// message seems to mention, or all of them when it mentions none, capped at maxPromptRivers
+func promptRivers(message string, rivers []string) []string {
+	var words []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(message), isNotLetter) {
+		words = append(words, word, toLatin(word))
+	}
+
+	var mentioned []string
+	for _, river := range rivers {
+		if mentionsRiver(words, river) {
+			mentioned = append(mentioned, river)
+		}
+	}
+	if len(mentioned) == 0 {
+		mentioned = rivers
+	}
+	if len(mentioned) > maxPromptRivers {
+		mentioned = mentioned[:maxPromptRivers]
+	}
+	return mentioned
+}
+
+// mentionsRiver reports whether any of the words starts like a word of the river's
+// name, in Cyrillic or Latin script
+func mentionsRiver(words []string, river string) bool {
+	for _, part := range strings.FieldsFunc(strings.ToLower(river), isNotLetter) {
+		for _, form := range []string{part, toLatin(part)} {
+			prefix := []rune(form)
+			// Allow the last letter to change with declension, but not below the minimum
+			if len(prefix) > minMatchPrefix {
+				prefix = prefix[:len(prefix)-1]
+			}
+			if len(prefix) < minMatchPrefix {
+				continue
+			}
+			for _, word := range words {
+				if strings.HasPrefix(word, string(prefix)) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// toLatin transliterates lower-case Serbian Cyrillic to Latin, leaving other letters as they are
+func toLatin(text string) string {
+	var result strings.Builder
+	for _, r := range text {
+		if latin, ok := cyrillicToLatin[r]; ok {
+			result.WriteString(latin)
+		} else {
+			result.WriteRune(r)
+		}
+	}
+	return result.String()
+}
+
+// isNotLetter separates the words of a message or river name
+func isNotLetter(r rune) bool {
+	return !unicode.IsLetter(r)
+}
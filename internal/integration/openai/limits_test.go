@@ -0,0 +1,60 @@
+package openai
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestPromptRivers verifies only the rivers a message mentions are sent, in either script
+// and declined, and all of them when it mentions none
+func TestPromptRivers(t *testing.T) {
+	rivers := []string{"ДРИНА", "ДУНАВ", "ЗАПАДНА МОРАВА", "САВА"}
+
+	tests := []struct {
+		message string
+		want    []string
+	}{
+		{"Kakav je vodostaj Drine?", []string{"ДРИНА"}},
+		{"Как там Дунай?", []string{"ДУНАВ"}},
+		{"sava ili morava", []string{"ЗАПАДНА МОРАВА", "САВА"}},
+		{"hello", rivers},
+	}
+	for _, tt := range tests {
+		if got := promptRivers(tt.message, rivers); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("promptRivers(%q) = %v; expected %v", tt.message, got, tt.want)
+		}
+	}
+}
+
+// TestPromptStaysWithinBudget verifies a huge river list and message don't grow the request past a fixed size
+func TestPromptStaysWithinBudget(t *testing.T) {
+	// Roughly 2,000 tokens at about 4 bytes each, the default persona included
+	const budget = 8000
+
+	var rivers []string
+	for i := 0; i < 5000; i++ {
+		rivers = append(rivers, fmt.Sprintf("РЕКА %d", i))
+	}
+	message := strings.Repeat("x", 20000)
+
+	fake := &fakeCompletions{}
+	service := newTestService(t, fake)
+	if _, err := service.InterpretUserQuery(context.Background(), message, rivers); err != nil {
+		t.Fatalf("Failed to interpret query: %v", err)
+	}
+
+	system := systemPrompt(t, fake)
+	user := fake.params.Messages[1].OfUser.Content.OfString.Value
+	if size := len(system) + len(user); size > budget {
+		t.Errorf("Expected the prompt to stay under %d bytes, got %d", budget, size)
+	}
+	if len([]rune(user)) != maxUserMessageRunes {
+		t.Errorf("Expected the message to be cut to %d characters, got %d", maxUserMessageRunes, len([]rune(user)))
+	}
+	if !strings.Contains(system, "РЕКА 199]") || strings.Contains(system, "РЕКА 200") {
+		t.Errorf("Expected the first %d rivers in the prompt", maxPromptRivers)
+	}
+}
@@ -2,11 +2,11 @@ package openai
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
-	"os"
+	"log/slog"
+	"net/http"
+	"time"
 
 	"github.com/invopop/jsonschema"
 	"github.com/openai/openai-go"
@@ -26,10 +26,32 @@ type OpenAIService interface {
 	InterpretUserQuery(ctx context.Context, userMessage string, supportedRivers []string) (*AgentResponse, error)
 }
 
+// chatCompletionClient is the part of the OpenAI client used by the service, replaceable in tests.
+type chatCompletionClient interface {
+	New(ctx context.Context, body openai.ChatCompletionNewParams, opts ...option.RequestOption) (*openai.ChatCompletion, error)
+}
+
+// defaultModel is the chat model used when no model is configured.
+const defaultModel = openai.ChatModelGPT4o
+
+// defaultRequestTimeout bounds a query when the caller's context has no deadline.
+const defaultRequestTimeout = 15 * time.Second
+
+// maxAttempts is how many times a completion is tried when OpenAI fails transiently.
+const maxAttempts = 2
+
+// defaultRetryBackoff is how long to wait before retrying a transient failure.
+const defaultRetryBackoff = 500 * time.Millisecond
+
 // openAIServiceImpl implements the OpenAIService interface.
 type openAIServiceImpl struct {
-	client openai.Client
-	schema interface{}
+	completions  chatCompletionClient
+	schema       interface{}
+	model        string
+	temperature  *float64 // nil uses the API's default sampling
+	backoff      time.Duration
+	cache        *responseCache // nil disables caching
+	systemPrompt string         // Template with riversPlaceholder for the known rivers
 }
 
 // GenerateSchema generates a JSON schema for a given type.
@@ -43,46 +65,68 @@ func GenerateSchema[T any]() interface{} {
 	return schema
 }
 
-// NewOpenAIService creates and initializes a new OpenAIService.
-func NewOpenAIService() (OpenAIService, error) {
-	apiKey := os.Getenv("OPENAI_API_KEY")
-	if apiKey == "" {
+// Config holds the settings of the OpenAI service
+type Config struct {
+	APIKey       string
+	Model        string        // Empty uses defaultModel
+	Temperature  *float64      // nil leaves sampling to the model's default
+	CacheSize    int           // Interpretations cached, 0 disables the cache
+	CacheTTL     time.Duration // How long an interpretation is reused, 0 disables the cache
+	SystemPrompt string        // Persona template with riversPlaceholder, empty uses defaultSystemPrompt
+}
+
+// NewOpenAIService creates and initializes a new OpenAIService with the given settings.
+func NewOpenAIService(cfg Config) (OpenAIService, error) {
+	if cfg.APIKey == "" {
 		return nil, errors.New("OPENAI_API_KEY environment variable not set")
 	}
-	client := openai.NewClient(option.WithAPIKey(apiKey))
-	schema := GenerateSchema[AgentResponse]()
+	// The service retries transient errors itself, within the caller's deadline
+	client := openai.NewClient(option.WithAPIKey(cfg.APIKey), option.WithMaxRetries(0))
 
-	return &openAIServiceImpl{
-		client: client,
-		schema: schema,
-	}, nil
+	return newOpenAIService(&client.Chat.Completions, cfg), nil
 }
 
-// InterpretUserQuery sends a message to the OpenAI agent and returns the structured response.
-func (s *openAIServiceImpl) InterpretUserQuery(ctx context.Context, userMessage string, supportedRivers []string) (*AgentResponse, error) {
-	systemPrompt := fmt.Sprintf(`You are a brutally honest, no‑bullshit water information bot—an absolute guru in fly fishing and Balkan rivers, with zero patience for idiots. You love nothing more than knocking back rakia, beer, and blasting turbofalk at full volume while you work.
-
-Your mission is to parse user requests about rivers in Serbia (and the Balkans), dish out fly‑fishing advice and any river data they need—no sugarcoating, no fluff.
+// newOpenAIService creates the service around a chat completion client
+func newOpenAIService(completions chatCompletionClient, cfg Config) *openAIServiceImpl {
+	model := cfg.Model
+	if model == "" {
+		model = defaultModel
+	}
 
-Requirements:
-- You’re an expert in fly fishing and Balkan rivers; any question outside that, you mock mercilessly.
-- You understand Russian, English, and Serbian.
-- You reply in the same language the user used, and in the most cutting, direct tone possible.
-- You casually reference rakia, beer, or turbofalk when you feel like it (“Here’s your data, now pour me a rakija!”).
+	var cache *responseCache
+	if cfg.CacheSize > 0 && cfg.CacheTTL > 0 {
+		cache = newResponseCache(cfg.CacheSize, cfg.CacheTTL)
+	}
 
-List of known Serbian rivers: %s
+	slog.Info("Using OpenAI model", "model", model)
+	return &openAIServiceImpl{
+		completions:  completions,
+		schema:       GenerateSchema[AgentResponse](),
+		model:        model,
+		temperature:  cfg.Temperature,
+		backoff:      defaultRetryBackoff,
+		cache:        cache,
+		systemPrompt: systemPromptTemplate(cfg.SystemPrompt),
+	}
+}
 
-Behavior:
-1. If the user clearly wants data on a specific river from the list:
-   - intent = “GetRiverDataByName”
-   - Translate the user’s river name into its proper Serbian form from the list; if it’s missing or dubious, leave serbian_river_name as an empty string.
-   - user_message: a one‑line confirmation in the user’s language, dripping with attitude (e.g. “Ок, ищу данные по Дунай, не мешай мне.”).
-2. If the user isn’t asking for specific river data (greetings, small talk, nonsense):
-   - intent = “GeneralQuery”
-   - serbian_river_name = ""
-   - user_message: a blunt reply in their language (“Чё тебе надо?”, “What now?”, “Šta bre hoćeš?”).
+// InterpretUserQuery sends a message to the OpenAI agent and returns the structured response.
+// Identical recent queries are answered from the cache without calling the API.
+// Long messages are truncated and only the rivers the message seems to mention are
+// sent, to bound the tokens each query costs.
+func (s *openAIServiceImpl) InterpretUserQuery(ctx context.Context, userMessage string, supportedRivers []string) (*AgentResponse, error) {
+	userMessage = truncateMessage(userMessage)
+	supportedRivers = promptRivers(userMessage, supportedRivers)
+
+	key := cacheKey(userMessage, supportedRivers)
+	if s.cache != nil {
+		if cached, ok := s.cache.get(key); ok {
+			slog.Debug("Answering query from the OpenAI cache")
+			return &cached, nil
+		}
+	}
 
-Output **strictly** in JSON.`, supportedRivers)
+	systemPrompt := renderSystemPrompt(s.systemPrompt, supportedRivers)
 
 	schemaParam := openai.ResponseFormatJSONSchemaJSONSchemaParam{
 		Name:        "agent_response",
@@ -95,29 +139,81 @@ Output **strictly** in JSON.`, supportedRivers)
 		OfJSONSchema: &openai.ResponseFormatJSONSchemaParam{JSONSchema: schemaParam},
 	}
 
-	chat, err := s.client.Chat.Completions.New(ctx, openai.ChatCompletionNewParams{
+	params := openai.ChatCompletionNewParams{
 		Messages: []openai.ChatCompletionMessageParamUnion{
 			openai.SystemMessage(systemPrompt),
 			openai.UserMessage(userMessage),
 		},
 		ResponseFormat: respFormat,
-		Model:          openai.ChatModelGPT4o,
-	})
+		Model:          s.model,
+	}
+	if s.temperature != nil {
+		params.Temperature = openai.Float(*s.temperature)
+	}
+
+	// Don't let a query without a deadline hang on a slow API
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultRequestTimeout)
+		defer cancel()
+	}
 
+	chat, err := s.complete(ctx, params)
 	if err != nil {
 		return nil, fmt.Errorf("error calling OpenAI API: %w", err)
 	}
 
+	slog.Info("OpenAI token usage",
+		"model", chat.Model,
+		"prompt_tokens", chat.Usage.PromptTokens,
+		"completion_tokens", chat.Usage.CompletionTokens,
+		"total_tokens", chat.Usage.TotalTokens)
+
 	if len(chat.Choices) == 0 || chat.Choices[0].Message.Content == "" {
 		return nil, errors.New("received empty response from OpenAI")
 	}
 
-	var agentResp AgentResponse
-	err = json.Unmarshal([]byte(chat.Choices[0].Message.Content), &agentResp)
+	agentResp, err := decodeAgentResponse(chat.Choices[0].Message.Content)
 	if err != nil {
-		log.Printf("Failed to unmarshal OpenAI response: %s\nRaw response: %s", err, chat.Choices[0].Message.Content)
-		return nil, fmt.Errorf("error unmarshalling OpenAI response: %w", err)
+		slog.Error("Failed to unmarshal OpenAI response", "error", err, "raw", chat.Choices[0].Message.Content)
+		return nil, err
 	}
 
+	if s.cache != nil {
+		s.cache.put(key, agentResp)
+	}
 	return &agentResp, nil
 }
+
+// complete requests a chat completion, retrying once after a short backoff when
+// OpenAI is rate limiting or failing transiently.
+func (s *openAIServiceImpl) complete(ctx context.Context, params openai.ChatCompletionNewParams) (*openai.ChatCompletion, error) {
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		var chat *openai.ChatCompletion
+		chat, err = s.completions.New(ctx, params)
+		if err == nil {
+			return chat, nil
+		}
+		if attempt == maxAttempts || !isTransientError(err) {
+			break
+		}
+
+		slog.Warn("OpenAI request failed, retrying", "attempt", attempt, "wait", s.backoff, "error", err)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(s.backoff):
+		}
+	}
+	return nil, err
+}
+
+// isTransientError reports whether an OpenAI error is worth retrying: rate limits and server errors.
+func isTransientError(err error) bool {
+	var apiErr *openai.Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= http.StatusInternalServerError
+}
@@ -0,0 +1,179 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
+)
+
+// fakeCompletions records the request parameters and returns a canned agent response
+type fakeCompletions struct {
+	params openai.ChatCompletionNewParams
+}
+
+// New implements chatCompletionClient
+func (f *fakeCompletions) New(ctx context.Context, body openai.ChatCompletionNewParams, opts ...option.RequestOption) (*openai.ChatCompletion, error) {
+	f.params = body
+	return &openai.ChatCompletion{
+		Choices: []openai.ChatCompletionChoice{{
+			Message: openai.ChatCompletionMessage{
+				Content: `{"command_name":"GeneralQuery","serbian_river_name":"","user_message":"Šta bre hoćeš?"}`,
+			},
+		}},
+	}, nil
+}
+
+// TestConfiguredModelIsUsed verifies the configured model and temperature are passed to the API
+func TestConfiguredModelIsUsed(t *testing.T) {
+	temperature := 0.2
+	fake := &fakeCompletions{}
+	service := newOpenAIService(fake, Config{Model: "gpt-4o-mini", Temperature: &temperature})
+
+	resp, err := service.InterpretUserQuery(context.Background(), "hello", []string{"ДРИНА"})
+	if err != nil {
+		t.Fatalf("Failed to interpret query: %v", err)
+	}
+	if resp.CommandName != "GeneralQuery" {
+		t.Errorf("Expected GeneralQuery, got %s", resp.CommandName)
+	}
+
+	if fake.params.Model != "gpt-4o-mini" {
+		t.Errorf("Expected model gpt-4o-mini, got %s", fake.params.Model)
+	}
+	if !fake.params.Temperature.IsPresent() || fake.params.Temperature.Value != 0.2 {
+		t.Errorf("Expected temperature 0.2, got %+v", fake.params.Temperature)
+	}
+}
+
+// TestDefaultModel verifies the default model and sampling are used when nothing is configured
+func TestDefaultModel(t *testing.T) {
+	fake := &fakeCompletions{}
+	service := newOpenAIService(fake, Config{})
+	if _, err := service.InterpretUserQuery(context.Background(), "hello", nil); err != nil {
+		t.Fatalf("Failed to interpret query: %v", err)
+	}
+
+	if fake.params.Model != defaultModel {
+		t.Errorf("Expected model %s, got %s", defaultModel, fake.params.Model)
+	}
+	if fake.params.Temperature.IsPresent() {
+		t.Errorf("Expected no temperature to be sent, got %v", fake.params.Temperature.Value)
+	}
+}
+
+// flakyCompletions fails with the given status code a number of times before succeeding.
+type flakyCompletions struct {
+	fakeCompletions
+	status   int
+	failures int
+	calls    int
+	deadline bool
+}
+
+func (f *flakyCompletions) New(ctx context.Context, body openai.ChatCompletionNewParams, opts ...option.RequestOption) (*openai.ChatCompletion, error) {
+	f.calls++
+	_, f.deadline = ctx.Deadline()
+	if f.calls <= f.failures {
+		return nil, &openai.Error{
+			StatusCode: f.status,
+			Request:    httptest.NewRequest(http.MethodPost, "https://api.openai.com/v1/chat/completions", nil),
+			Response:   &http.Response{StatusCode: f.status},
+		}
+	}
+	return f.fakeCompletions.New(ctx, body, opts...)
+}
+
+func newTestService(t *testing.T, completions chatCompletionClient) *openAIServiceImpl {
+	t.Helper()
+	service := newOpenAIService(completions, Config{CacheSize: 256, CacheTTL: 10 * time.Minute})
+	service.backoff = time.Millisecond
+	return service
+}
+
+func TestRetriesRateLimitOnce(t *testing.T) {
+	fake := &flakyCompletions{status: http.StatusTooManyRequests, failures: 1}
+	service := newTestService(t, fake)
+
+	resp, err := service.InterpretUserQuery(context.Background(), "hello", nil)
+	if err != nil {
+		t.Fatalf("Expected the retry to succeed, got %v", err)
+	}
+	if resp.CommandName != "GeneralQuery" {
+		t.Errorf("Expected GeneralQuery, got %s", resp.CommandName)
+	}
+	if fake.calls != 2 {
+		t.Errorf("Expected 2 attempts, got %d", fake.calls)
+	}
+	if !fake.deadline {
+		t.Error("Expected a default deadline when the context has none")
+	}
+}
+
+func TestRetryIsBounded(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		wantCalls int
+	}{
+		{"persistent server error", http.StatusBadGateway, maxAttempts},
+		{"client error is not retried", http.StatusBadRequest, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &flakyCompletions{status: tt.status, failures: 10}
+			service := newTestService(t, fake)
+
+			_, err := service.InterpretUserQuery(context.Background(), "hello", nil)
+			var apiErr *openai.Error
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.status {
+				t.Errorf("Expected the %d error to be returned, got %v", tt.status, err)
+			}
+			if fake.calls != tt.wantCalls {
+				t.Errorf("Expected %d attempts, got %d", tt.wantCalls, fake.calls)
+			}
+		})
+	}
+}
+
+func TestIdenticalQueryIsCached(t *testing.T) {
+	fake := &flakyCompletions{}
+	service := newTestService(t, fake)
+	rivers := []string{"ДРИНА", "САВА"}
+
+	for _, message := range []string{"Hello", "  hello ", "HELLO"} {
+		if _, err := service.InterpretUserQuery(context.Background(), message, rivers); err != nil {
+			t.Fatalf("Failed to interpret query: %v", err)
+		}
+	}
+	if fake.calls != 1 {
+		t.Errorf("Expected repeated queries to be answered from the cache, got %d API calls", fake.calls)
+	}
+
+	// A different river list may change the interpretation
+	if _, err := service.InterpretUserQuery(context.Background(), "hello", []string{"ДРИНА"}); err != nil {
+		t.Fatalf("Failed to interpret query: %v", err)
+	}
+	if fake.calls != 2 {
+		t.Errorf("Expected a new river list to miss the cache, got %d API calls", fake.calls)
+	}
+}
+
+func TestCacheCanBeDisabled(t *testing.T) {
+	fake := &flakyCompletions{}
+	service := newOpenAIService(fake, Config{CacheSize: 0, CacheTTL: 10 * time.Minute})
+
+	for i := 0; i < 2; i++ {
+		if _, err := service.InterpretUserQuery(context.Background(), "hello", nil); err != nil {
+			t.Fatalf("Failed to interpret query: %v", err)
+		}
+	}
+	if fake.calls != 2 {
+		t.Errorf("Expected every query to call the API, got %d calls", fake.calls)
+	}
+}
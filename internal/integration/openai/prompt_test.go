@@ -0,0 +1,45 @@
+package openai
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// systemPrompt returns the system message of the last request sent to fake
+func systemPrompt(t *testing.T, fake *fakeCompletions) string {
+	t.Helper()
+	if len(fake.params.Messages) == 0 || fake.params.Messages[0].OfSystem == nil {
+		t.Fatal("Expected the request to start with a system message")
+	}
+	return fake.params.Messages[0].OfSystem.Content.OfString.Value
+}
+
+// TestCustomSystemPrompt verifies a configured persona replaces the default one with the rivers substituted
+func TestCustomSystemPrompt(t *testing.T) {
+	tests := []struct {
+		name   string
+		prompt string
+		want   string
+	}{
+		{"custom", "You are a friendly river guide. Known rivers: {rivers}. Reply in JSON.", "You are a friendly river guide. Known rivers: [ДРИНА САВА]. Reply in JSON."},
+		{"default", "", "List of known Serbian rivers: [ДРИНА САВА]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeCompletions{}
+			service := newOpenAIService(fake, Config{SystemPrompt: tt.prompt})
+
+			if _, err := service.InterpretUserQuery(context.Background(), "hello", []string{"ДРИНА", "САВА"}); err != nil {
+				t.Fatalf("Failed to interpret query: %v", err)
+			}
+			got := systemPrompt(t, fake)
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("Expected %q in the system prompt:\n%s", tt.want, got)
+			}
+			if strings.Contains(got, riversPlaceholder) {
+				t.Errorf("Expected the placeholder to be substituted:\n%s", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package integration
+
+// SourceInfo describes a data source for users
+type SourceInfo struct {
+	Name      string // Identifier stored with every reading
+	Authority string // Who publishes the data
+	Country   string // Country or entity whose stations the source reports
+	Website   string
+	Priority  int // Which source wins when several report the same reading, higher first
+}
+
+// sourceRegistry lists the supported sources in the order they are refreshed
+var sourceRegistry = []SourceInfo{
+	{
+		Name:      SourceHidmet,
+		Authority: "Republic Hydrometeorological Service of Serbia",
+		Country:   "Serbia",
+		Website:   "https://www.hidmet.gov.rs",
+		Priority:  2,
+	},
+	{
+		Name:      SourceGradac,
+		Authority: "Republic Hydrometeorological Service of Serbia, ГРАДАЦ station table",
+		Country:   "Serbia",
+		Website:   "https://www.hidmet.gov.rs",
+		Priority:  1,
+	},
+	{
+		Name:      SourceRhmzRs,
+		Authority: "Republic Hydrometeorological Institute of Republika Srpska",
+		Country:   "Republika Srpska",
+		Website:   "https://novi.rhmzrs.com",
+		Priority:  2,
+	},
+	{
+		Name:      SourceDhmz,
+		Authority: "Croatian Meteorological and Hydrological Service",
+		Country:   "Croatia",
+		Website:   "https://hidro.dhz.hr",
+		Priority:  2,
+	},
+}
+
+// Sources returns the descriptions of all supported sources
+func Sources() []SourceInfo {
+	return append([]SourceInfo(nil), sourceRegistry...)
+}
+
+// LookupSource returns the description of a source by name
+func LookupSource(name string) (SourceInfo, bool) {
+	for _, info := range sourceRegistry {
+		if info.Name == name {
+			return info, true
+		}
+	}
+	return SourceInfo{}, false
+}
+
+// SourcePriority returns the priority of a source, or 0 for unknown sources.
+// The authorities' main station tables outrank single-station tables.
+func SourcePriority(source string) int {
+	info, _ := LookupSource(source)
+	return info.Priority
+}
+
+// SourceCountry returns the country a source's stations are in, or "" for unknown sources
+func SourceCountry(source string) string {
+	info, _ := LookupSource(source)
+	return info.Country
+}
@@ -0,0 +1,7 @@
+package integration
+
+// gradacHmID is the hidmet hm_id of the ДЕГУРИЋ station on river ГРАДАЦ
+const gradacHmID = 45902
+
+// maxHistoryDays is the longest period the hidmet station pages are fetched for
+const maxHistoryDays = 30
@@ -2,10 +2,14 @@
 package integration
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
-	"io"
-	"log"
+	"log/slog"
+	"math"
 	"net/http"
+	"net/url"
 	"regexp"
 	"sort"
 	"strconv"
@@ -14,112 +18,369 @@ import (
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/abelzeko/water-bot/internal/entities"
+	"github.com/abelzeko/water-bot/internal/refdata"
+	"github.com/abelzeko/water-bot/internal/units"
 )
 
+// Identifiers of the supported data sources, stored with every reading
+const (
+	SourceHidmet = "hidmet"
+	SourceGradac = "gradac"
+	SourceRhmzRs = "rhmzrs"
+	SourceDhmz   = "dhmz"
+)
+
+// Default URLs of the data sources, overridable through ScraperConfig
+const (
+	defaultHidmetURL         = "https://www.hidmet.gov.rs/ciril/osmotreni/stanje_voda.php"
+	defaultGradacURL         = "https://www.hidmet.gov.rs/ciril/osmotreni/nrt_tabela_grafik.php?hm_id=45902&period=7"
+	defaultStationHistoryURL = "https://www.hidmet.gov.rs/ciril/osmotreni/nrt_tabela_grafik.php"
+	defaultRhmzRsListingURL  = "https://novi.rhmzrs.com/page/bilten-izvjestaj-o-vodostanju"
+	defaultDhmzURL           = "https://hidro.dhz.hr/"
+)
+
+// Headers sent with every scraper request. Some of the sites block or
+// throttle Go's default User-Agent, so the bot identifies itself.
+const (
+	defaultUserAgent = "balkan-river-bot/1.0 (+https://github.com/AlexeyBelezeko/balkan-river-bot)"
+	acceptLanguage   = "sr,hr;q=0.9,bs;q=0.8,en;q=0.5"
+)
+
+// ErrNoData is returned when a page parsed and has a data table, but no readings
+// could be extracted from it. It usually means the site's layout changed.
+var ErrNoData = errors.New("data table has no readable rows")
+
 // WaterScraper provides functionality to scrape water data from external sources
 type WaterScraper struct {
-	sourceURL      string
-	gradacRiverURL string
+	sourceURL         string
+	gradacRiverURL    string
+	stationHistoryURL string
+	rhmzRsListingURL  string
+	dhmzURL           string
+	userAgent         string
+	client            *http.Client
 }
 
-// NewWaterScraper creates a new water data scraper
-func NewWaterScraper(url string) *WaterScraper {
-	if url == "" {
-		// Default source URL
-		url = "https://www.hidmet.gov.rs/ciril/osmotreni/stanje_voda.php"
+// ScraperConfig holds the pages a WaterScraper fetches and the User-Agent it sends
+type ScraperConfig struct {
+	HidmetURL        string
+	GradacURL        string
+	HidmetHistoryURL string
+	RhmzRsListingURL string
+	DhmzURL          string
+	UserAgent        string
+}
+
+// DefaultScraperConfig returns the production source pages and User-Agent
+func DefaultScraperConfig() ScraperConfig {
+	return ScraperConfig{
+		HidmetURL:        defaultHidmetURL,
+		GradacURL:        defaultGradacURL,
+		HidmetHistoryURL: defaultStationHistoryURL,
+		RhmzRsListingURL: defaultRhmzRsListingURL,
+		DhmzURL:          defaultDhmzURL,
+		UserAgent:        defaultUserAgent,
 	}
+}
+
+// NewWaterScraper creates a new water data scraper reading the hidmet page at sourceURL,
+// or the default one when it is empty. The other sources use their default pages.
+func NewWaterScraper(sourceURL string) *WaterScraper {
+	return NewWaterScraperWithConfig(ScraperConfig{HidmetURL: sourceURL})
+}
+
+// NewWaterScraperWithConfig creates a water data scraper fetching the pages in cfg.
+// Empty fields keep their default.
+func NewWaterScraperWithConfig(cfg ScraperConfig) *WaterScraper {
+	defaults := DefaultScraperConfig()
 	return &WaterScraper{
-		sourceURL:      url,
-		gradacRiverURL: "https://www.hidmet.gov.rs/ciril/osmotreni/nrt_tabela_grafik.php?hm_id=45902&period=7",
+		sourceURL:         orDefault(cfg.HidmetURL, defaults.HidmetURL),
+		gradacRiverURL:    orDefault(cfg.GradacURL, defaults.GradacURL),
+		stationHistoryURL: orDefault(cfg.HidmetHistoryURL, defaults.HidmetHistoryURL),
+		rhmzRsListingURL:  orDefault(cfg.RhmzRsListingURL, defaults.RhmzRsListingURL),
+		dhmzURL:           orDefault(cfg.DhmzURL, defaults.DhmzURL),
+		userAgent:         orDefault(cfg.UserAgent, defaults.UserAgent),
+		client:            http.DefaultClient,
+	}
+}
+
+// get sends a GET request with the scraper's identifying headers, aborted when ctx is done
+func (ws *WaterScraper) get(ctx context.Context, pageURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", ws.userAgent)
+	req.Header.Set("Accept-Language", acceptLanguage)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml;q=0.9,*/*;q=0.8")
+	return ws.client.Do(req)
+}
+
+// orDefault returns value, or fallback if it is empty
+func orDefault(value, fallback string) string {
+	if value != "" {
+		return value
 	}
+	return fallback
 }
 
-// FetchWaterData retrieves water data from the website
-func (ws *WaterScraper) FetchWaterData() ([]entities.RiverData, error) {
-	log.Printf("Sending HTTP request to water monitoring website")
+// FetchWaterData retrieves water data from the website.
+// Readings have a zero Timestamp when the page's data timestamp can't be extracted.
+func (ws *WaterScraper) FetchWaterData(ctx context.Context) ([]entities.RiverData, error) {
+	slog.Debug("Sending HTTP request to water monitoring website")
 	// Send an HTTP GET request to the website
-	res, err := http.Get(ws.sourceURL)
+	res, err := ws.get(ctx, ws.sourceURL)
 	if err != nil {
-		log.Printf("Error fetching data: %v", err)
+		slog.Error("Error fetching data", "error", err)
 		return nil, fmt.Errorf("failed to fetch the webpage: %v", err)
 	}
 	defer res.Body.Close()
 
 	// Check for successful response
 	if res.StatusCode != 200 {
-		log.Printf("Received unexpected status code: %d %s", res.StatusCode, res.Status)
+		slog.Error("Received unexpected status code", "source", SourceHidmet, "status", res.Status)
 		return nil, fmt.Errorf("unexpected status code: %d %s", res.StatusCode, res.Status)
 	}
-	log.Printf("Successfully received HTTP response with status: %s", res.Status)
+	slog.Debug("Received HTTP response", "source", SourceHidmet, "status", res.Status)
 
 	// Parse the HTML document
-	log.Printf("Parsing HTML document")
-	doc, err := goquery.NewDocumentFromReader(res.Body)
+	slog.Debug("Parsing HTML document", "source", SourceHidmet)
+	doc, err := parseDocument(res)
 	if err != nil {
-		log.Printf("Error parsing HTML: %v", err)
+		slog.Error("Error parsing HTML", "error", err)
 		return nil, fmt.Errorf("failed to parse the webpage: %v", err)
 	}
 
-	// Extract timestamp from the website
-	timestamp := ws.ExtractTimestamp(doc)
+	// Extract timestamp from the website. Without one the rows are left unstamped
+	// rather than looking freshly measured, the caller decides what to do with them.
+	timestamp, ok := ws.ExtractTimestamp(doc)
+	if !ok {
+		slog.Warn("hidmet page has no recognizable data timestamp, the page layout may have changed. Readings are returned without a timestamp")
+	}
+
+	// Locate the columns by their headers, the site has shifted them before
+	columns, ok := detectHidmetColumns(doc)
+	if !ok {
+		slog.Warn("hidmet table headers not recognized, using the default column layout")
+		columns = defaultHidmetColumns
+	}
 
 	var data []entities.RiverData
 	rowCount := 0
 
-	// Iterate over each table row in the document
-	doc.Find("table tbody tr").Each(func(index int, row *goquery.Selection) {
-		rowCount++
+	// Iterate over each table row in the document. Rows may sit directly under the table
+	// without a tbody, each row is still matched once when there is one.
+	doc.Find("table tr").Each(func(index int, row *goquery.Selection) {
 		cells := row.Find("td")
-		if cells.Length() >= 10 {
-			// Extract river name from the first cell
-			river := strings.TrimSpace(cells.Eq(0).Text())
+		if isHidmetHeaderRow(row, cells, columns) {
+			return
+		}
+		rowCount++
+		if cells.Length() >= columns.minCells() {
+			river := strings.TrimSpace(cells.Eq(columns.river).Text())
+
+			// The station cell usually links to the station's page
+			stationCell := cells.Eq(columns.station)
+			station := strings.TrimSpace(stationCell.Find("a").Text())
+			if station == "" {
+				station = strings.TrimSpace(stationCell.Text())
+			}
+			stationURL := ws.sourceURL
+			if href, ok := stationCell.Find("a").Attr("href"); ok && strings.TrimSpace(href) != "" {
+				if resolved, err := resolveURL(ws.sourceURL, strings.TrimSpace(href)); err == nil {
+					stationURL = resolved
+				}
+			}
 
-			// Extract station name from the third cell, which contains an <a> tag
-			station := strings.TrimSpace(cells.Eq(2).Find("a").Text())
+			waterLevel := strings.TrimSpace(cells.Eq(columns.level).Text())
+			waterTemp := ""
+			if columns.temp >= 0 {
+				waterTemp = strings.TrimSpace(cells.Eq(columns.temp).Text())
+			}
+			discharge := ""
+			if columns.discharge >= 0 {
+				discharge = strings.TrimSpace(cells.Eq(columns.discharge).Text())
+				if discharge == "-" {
+					discharge = "" // No discharge data
+				}
+			}
+			gaugeZero := ""
+			if columns.gaugeZero >= 0 {
+				gaugeZero = strings.TrimSpace(cells.Eq(columns.gaugeZero).Text())
+				if gaugeZero == "-" {
+					gaugeZero = "" // No gauge zero data
+				}
+			}
 
-			// Extract water level and water temperature from the respective cells
-			waterLevel := strings.TrimSpace(cells.Eq(5).Text())
-			waterTemp := strings.TrimSpace(cells.Eq(8).Text())
+			// Tendency is rendered as an image, its alt text describes the direction
+			rawTendency := ""
+			if columns.tendency >= 0 {
+				tendencyCell := cells.Eq(columns.tendency)
+				var ok bool
+				rawTendency, ok = tendencyCell.Find("img").Attr("alt")
+				if !ok {
+					rawTendency = tendencyCell.Text()
+				}
+			}
 
 			data = append(data, entities.RiverData{
 				River:      river,
 				Station:    station,
 				WaterLevel: waterLevel,
 				WaterTemp:  waterTemp,
+				Discharge:  discharge,
+				Tendency:   entities.ParseTendency(rawTendency),
+				Source:     SourceHidmet,
+				URL:        stationURL,
+				GaugeZero:  gaugeZero,
 				Timestamp:  timestamp,
 			})
 		}
 	})
 
-	log.Printf("Parsed %d rows, extracted %d valid data entries", rowCount, len(data))
+	slog.Debug("Parsed hidmet table", "rows", rowCount, "valid", len(data))
+	if len(data) == 0 && doc.Find("table").Length() > 0 {
+		return nil, fmt.Errorf("hidmet: %w", ErrNoData)
+	}
 	return data, nil
 }
 
+// hidmetColumns holds the cell indices of the hidmet table's columns, -1 when a column is absent
+type hidmetColumns struct {
+	river, station, level, temp, discharge, tendency, gaugeZero int
+}
+
+// defaultHidmetColumns is the layout of the hidmet table when its headers can't be read
+var defaultHidmetColumns = hidmetColumns{river: 0, station: 2, level: 5, temp: 8, discharge: -1, tendency: 9, gaugeZero: -1}
+
+// minCells returns how many cells a data row needs to contain every known column
+func (c hidmetColumns) minCells() int {
+	n := 0
+	for _, index := range []int{c.river, c.station, c.level, c.temp, c.discharge, c.tendency, c.gaugeZero} {
+		if index+1 > n {
+			n = index + 1
+		}
+	}
+	return n
+}
+
+// isHidmetHeaderRow reports whether a table row holds column headers rather than a
+// reading: it has header cells, is in the table head, or its cells are header labels
+func isHidmetHeaderRow(row *goquery.Selection, cells *goquery.Selection, columns hidmetColumns) bool {
+	if row.Find("th").Length() > 0 || row.ParentsFiltered("thead").Length() > 0 {
+		return true
+	}
+	station := strings.ToLower(cells.Eq(columns.station).Text())
+	level := strings.ToLower(cells.Eq(columns.level).Text())
+	return strings.Contains(station, "станица") || strings.Contains(level, "водостај")
+}
+
+// detectHidmetColumns maps the hidmet table's header labels to cell indices.
+// It reports false unless the river, station and water level columns are all found.
+func detectHidmetColumns(doc *goquery.Document) (hidmetColumns, bool) {
+	var best hidmetColumns
+	found := false
+	doc.Find("table tr").EachWithBreak(func(_ int, row *goquery.Selection) bool {
+		headers := row.Find("th")
+		if headers.Length() == 0 {
+			return true
+		}
+
+		columns := hidmetColumns{river: -1, station: -1, level: -1, temp: -1, discharge: -1, tendency: -1, gaugeZero: -1}
+		index := 0
+		headers.Each(func(_ int, cell *goquery.Selection) {
+			label := strings.ToLower(strings.Join(strings.Fields(cell.Text()), " "))
+			switch {
+			case columns.river < 0 && (strings.Contains(label, "река") || strings.Contains(label, "водоток")):
+				columns.river = index
+			case columns.station < 0 && strings.Contains(label, "станица"):
+				columns.station = index
+			case columns.level < 0 && strings.Contains(label, "водостај") && !strings.Contains(label, "промена"):
+				columns.level = index
+			case columns.temp < 0 && strings.Contains(label, "температура"):
+				columns.temp = index
+			case columns.discharge < 0 && strings.Contains(label, "проток"):
+				columns.discharge = index
+			case columns.tendency < 0 && strings.Contains(label, "тенденција"):
+				columns.tendency = index
+			case columns.gaugeZero < 0 && strings.Contains(label, "кота"):
+				columns.gaugeZero = index
+			}
+
+			// Header cells may span several data columns
+			span := 1
+			if value, ok := cell.Attr("colspan"); ok {
+				if n, err := strconv.Atoi(value); err == nil && n > 1 {
+					span = n
+				}
+			}
+			index += span
+		})
+
+		if columns.river >= 0 && columns.station >= 0 && columns.level >= 0 {
+			best, found = columns, true
+			return false
+		}
+		return true
+	})
+	return best, found
+}
+
 // FetchGradacRiverData retrieves water data specifically for river ГРАДАЦ
 // Only returns valid timestamp-level pairs where level is an integer
-func (ws *WaterScraper) FetchGradacRiverData() ([]entities.RiverData, error) {
-	log.Printf("Sending HTTP request to fetch river ГРАДАЦ data")
-	// Send an HTTP GET request to the special ГРАДАЦ river URL
-	res, err := http.Get(ws.gradacRiverURL)
+func (ws *WaterScraper) FetchGradacRiverData(ctx context.Context) ([]entities.RiverData, error) {
+	station, _ := refdata.LookupHidmetStation(gradacHmID)
+	return ws.fetchStationTable(ctx, ws.gradacRiverURL, station)
+}
+
+// FetchStationHistory retrieves the last days of readings of a hidmet station by its hm_id.
+// The station must be listed in the reference data.
+func (ws *WaterScraper) FetchStationHistory(ctx context.Context, hmID int, days int) ([]entities.RiverData, error) {
+	station, ok := refdata.LookupHidmetStation(hmID)
+	if !ok {
+		return nil, fmt.Errorf("unknown station hm_id %d", hmID)
+	}
+	if days < 1 || days > maxHistoryDays {
+		return nil, fmt.Errorf("history period must be between 1 and %d days, got %d", maxHistoryDays, days)
+	}
+
+	pageURL, err := url.Parse(ws.stationHistoryURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid station history URL %q: %v", ws.stationHistoryURL, err)
+	}
+	query := pageURL.Query()
+	query.Set("hm_id", strconv.Itoa(hmID))
+	query.Set("period", strconv.Itoa(days))
+	pageURL.RawQuery = query.Encode()
+
+	return ws.fetchStationTable(ctx, pageURL.String(), station)
+}
+
+// fetchStationTable retrieves a hidmet station page and parses its two-column
+// table of timestamps and water levels. Only rows where the level is an integer are kept,
+// and only the last of rows repeating a timestamp.
+func (ws *WaterScraper) fetchStationTable(ctx context.Context, pageURL string, station refdata.HidmetStation) ([]entities.RiverData, error) {
+	slog.Debug("Sending HTTP request for river data", "river", station.River)
+	res, err := ws.get(ctx, pageURL)
 	if err != nil {
-		log.Printf("Error fetching ГРАДАЦ river data: %v", err)
-		return nil, fmt.Errorf("failed to fetch ГРАДАЦ river data: %v", err)
+		slog.Error("Error fetching river data", "river", station.River, "error", err)
+		return nil, fmt.Errorf("failed to fetch %s river data: %v", station.River, err)
 	}
 	defer res.Body.Close()
 
 	// Check for successful response
 	if res.StatusCode != 200 {
-		log.Printf("Received unexpected status code for ГРАДАЦ river: %d %s", res.StatusCode, res.Status)
-		return nil, fmt.Errorf("unexpected status code for ГРАДАЦ river: %d %s", res.StatusCode, res.Status)
+		slog.Error("Received unexpected status code", "river", station.River, "status", res.Status)
+		return nil, fmt.Errorf("unexpected status code for %s river: %d %s", station.River, res.StatusCode, res.Status)
 	}
-	log.Printf("Successfully received HTTP response for ГРАДАЦ river with status: %s", res.Status)
+	slog.Debug("Received HTTP response", "river", station.River, "status", res.Status)
 
 	// Parse the HTML document
-	log.Printf("Parsing HTML document for ГРАДАЦ river")
-	doc, err := goquery.NewDocumentFromReader(res.Body)
+	slog.Debug("Parsing HTML document", "river", station.River)
+	doc, err := parseDocument(res)
 	if err != nil {
-		log.Printf("Error parsing ГРАДАЦ river HTML: %v", err)
-		return nil, fmt.Errorf("failed to parse the ГРАДАЦ river webpage: %v", err)
+		slog.Error("Error parsing river HTML", "river", station.River, "error", err)
+		return nil, fmt.Errorf("failed to parse the %s river webpage: %v", station.River, err)
 	}
 
 	var data []entities.RiverData
@@ -151,35 +412,45 @@ func (ws *WaterScraper) FetchGradacRiverData() ([]entities.RiverData, error) {
 			// Parse the timestamp in UTC since the website posts timestamps in UTC
 			timestamp, parseErr := time.ParseInLocation("02.01.2006 15:04", dateTimeStr, utc)
 			if parseErr != nil {
-				log.Printf("Warning: Skipping row with invalid timestamp format: %s, %v", dateTimeStr, parseErr)
+				slog.Debug("Skipping row with invalid timestamp format", "timestamp", dateTimeStr, "error", parseErr)
 				skippedRows++
 				return
 			}
 
 			// Parse water level to verify it's an integer
-			waterLevel, parseErr := strconv.Atoi(waterLevelStr)
-			if parseErr != nil {
-				log.Printf("Warning: Skipping row with non-integer water level: %s", waterLevelStr)
+			level, parseErr := units.ParseNumber(waterLevelStr)
+			if parseErr != nil || level != math.Trunc(level) {
+				slog.Debug("Skipping row with non-integer water level", "level", waterLevelStr)
 				skippedRows++
 				return
 			}
+			waterLevel := int(level)
 
 			// Only include valid data
 			validRows++
 
 			// Create river data entry
 			data = append(data, entities.RiverData{
-				River:      "ГРАДАЦ",
-				Station:    "ДЕГУРИЋ",
+				River:      station.River,
+				Station:    station.Station,
 				WaterLevel: fmt.Sprintf("%d", waterLevel), // Ensure it's consistently formatted
 				WaterTemp:  "",                            // Not available in this source
+				Tendency:   entities.Unknown,              // Not available in this source
+				Source:     station.Source,
+				URL:        pageURL,
 				Timestamp:  timestamp,
 			})
 		}
 	})
 
-	log.Printf("ГРАДАЦ river data: processed %d rows, found %d valid entries, skipped %d invalid entries",
-		processedRows, validRows, skippedRows)
+	slog.Debug("Parsed river data",
+		"river", station.River, "rows", processedRows, "valid", validRows, "skipped", skippedRows)
+
+	// A repeated row would only collide with itself on insert, keep the page's last one
+	data, duplicates := dedupByTimestamp(data)
+	if duplicates > 0 {
+		slog.Warn("Station table repeats timestamps, keeping the last row of each", "river", station.River, "station", station.Station, "duplicates", duplicates)
+	}
 
 	// Sorting data by timestamp (oldest first) for consistency
 	sort.Slice(data, func(i, j int) bool {
@@ -189,10 +460,29 @@ func (ws *WaterScraper) FetchGradacRiverData() ([]entities.RiverData, error) {
 	return data, nil
 }
 
-// ExtractTimestamp extracts the timestamp from the HTML document
-func (ws *WaterScraper) ExtractTimestamp(doc *goquery.Document) time.Time {
-	// Default fallback
-	timestamp := time.Now()
+// dedupByTimestamp keeps the last of the readings sharing a timestamp, in their original
+// order otherwise, and returns how many were dropped
+func dedupByTimestamp(data []entities.RiverData) ([]entities.RiverData, int) {
+	last := make(map[time.Time]int, len(data))
+	for i, rd := range data {
+		last[rd.Timestamp] = i
+	}
+	if len(last) == len(data) {
+		return data, 0
+	}
+
+	deduped := make([]entities.RiverData, 0, len(last))
+	for i, rd := range data {
+		if last[rd.Timestamp] == i {
+			deduped = append(deduped, rd)
+		}
+	}
+	return deduped, len(data) - len(deduped)
+}
+
+// ExtractTimestamp extracts the timestamp from the HTML document.
+// It reports false, logging a warning, when the page has no parsable timestamp.
+func (ws *WaterScraper) ExtractTimestamp(doc *goquery.Document) (time.Time, bool) {
 	timestampText := ""
 
 	// Look for the timestamp in the page using multiple selectors
@@ -207,7 +497,7 @@ func (ws *WaterScraper) ExtractTimestamp(doc *goquery.Document) time.Time {
 		doc.Find(selector).Each(func(i int, s *goquery.Selection) {
 			text := strings.TrimSpace(s.Text())
 			if strings.Contains(text, "Хидролошки подаци:") {
-				log.Printf("Found timestamp text using selector '%s': %s", selector, text)
+				slog.Debug("Found timestamp text", "selector", selector, "text", text)
 				timestampText = text
 			}
 		})
@@ -216,125 +506,143 @@ func (ws *WaterScraper) ExtractTimestamp(doc *goquery.Document) time.Time {
 		}
 	}
 
-	// Parse the timestamp if found
-	if timestampText != "" {
-		extractedTime := ws.parseTimestampText(timestampText)
-		if !extractedTime.IsZero() {
-			timestamp = extractedTime
-			log.Printf("Successfully extracted timestamp: %s", timestamp.Format(time.RFC3339))
-		} else {
-			log.Printf("Failed to parse timestamp from: %s", timestampText)
-		}
-	} else {
-		log.Printf("Timestamp text not found, using current time")
+	if timestampText == "" {
+		slog.Warn("hidmet timestamp text not found")
+		return time.Time{}, false
 	}
 
-	return timestamp
-}
-
-// parseTimestampText parses timestamp text from the webpage
-func (ws *WaterScraper) parseTimestampText(text string) time.Time {
-	// Default fallback
-	timestamp := time.Time{}
+	extractedTime, err := ParseHidmetTimestamp(timestampText)
+	if err != nil {
+		slog.Warn("Error parsing hidmet timestamp", "error", err)
+		return time.Time{}, false
+	}
 
-	// Expected format examples:
-	// "Хидролошки подаци: ПЕТАК 18.04.2025. време: 8:00 (06:00 UTC)"
-	// "Хидролошки подаци: 18.04.2025. време: 8:00"
+	slog.Debug("Extracted timestamp", "timestamp", extractedTime.Format(time.RFC3339))
+	return extractedTime, true
+}
 
-	// Try to parse the timestamp
-	if strings.Contains(text, "Хидролошки подаци:") && strings.Contains(text, "време:") {
-		dateParts := strings.Split(text, "време:")
-		if len(dateParts) >= 2 {
-			// Extract date part - skip the day name if present
-			dateText := strings.TrimSpace(strings.Split(dateParts[0], ":")[1])
-			dateFields := strings.Fields(dateText)
+// hidmetDateRe and hidmetTimeRe match the date and the time of a hidmet data header
+var (
+	hidmetDateRe = regexp.MustCompile(`(\d{1,2})\.(\d{1,2})\.(\d{4})\.?`)
+	hidmetTimeRe = regexp.MustCompile(`време:\s*(\d{1,2}):(\d{2})`)
+)
 
-			// The date should be in format DD.MM.YYYY.
-			// It might be preceded by a day name
-			var dateStr string
-			for _, field := range dateFields {
-				if strings.Contains(field, ".") {
-					dateStr = field
-					break
-				}
-			}
+// ParseHidmetTimestamp parses the data header published on hidmet pages, in Serbian time.
+// Supported formats:
+//
+//	"Хидролошки подаци: ПЕТАК 18.04.2025. време: 8:00 (06:00 UTC)"
+//	"Хидролошки подаци: 18.04.2025. време: 8:00"
+func ParseHidmetTimestamp(text string) (time.Time, error) {
+	_, afterLabel, found := strings.Cut(text, "Хидролошки подаци:")
+	if !found {
+		return time.Time{}, fmt.Errorf("hidmet timestamp label not found in %q", text)
+	}
 
-			// Extract time part
-			timeStr := strings.TrimSpace(strings.Split(dateParts[1], "(")[0])
+	dateText, timeText, found := strings.Cut(afterLabel, "време:")
+	if !found {
+		return time.Time{}, fmt.Errorf("hidmet timestamp time not found in %q", text)
+	}
 
-			log.Printf("Extracted date: '%s', time: '%s'", dateStr, timeStr)
+	dateMatch := hidmetDateRe.FindStringSubmatch(dateText)
+	if dateMatch == nil {
+		return time.Time{}, fmt.Errorf("hidmet timestamp date not found in %q", text)
+	}
+	timeMatch := hidmetTimeRe.FindStringSubmatch("време:" + timeText)
+	if timeMatch == nil {
+		return time.Time{}, fmt.Errorf("hidmet timestamp time not found in %q", text)
+	}
 
-			// Parse date DD.MM.YYYY.
-			var day, month, year int
-			_, err := fmt.Sscanf(dateStr, "%d.%d.%d.", &day, &month, &year)
-			if err != nil {
-				log.Printf("Error parsing date from '%s': %v", dateStr, err)
-				return timestamp
-			}
+	day, _ := strconv.Atoi(dateMatch[1])
+	month, _ := strconv.Atoi(dateMatch[2])
+	year, _ := strconv.Atoi(dateMatch[3])
+	hour, _ := strconv.Atoi(timeMatch[1])
+	minute, _ := strconv.Atoi(timeMatch[2])
 
-			// Parse time HH:MM
-			var hour, minute int
-			_, err = fmt.Sscanf(timeStr, "%d:%d", &hour, &minute)
-			if err != nil {
-				log.Printf("Error parsing time from '%s': %v", timeStr, err)
-				return timestamp
-			}
+	loc, err := time.LoadLocation("Europe/Belgrade") // Serbian time zone
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to load Serbian time zone: %v", err)
+	}
 
-			// Create timestamp
-			loc, _ := time.LoadLocation("Europe/Belgrade") // Serbian time zone
-			timestamp = time.Date(year, time.Month(month), day, hour, minute, 0, 0, loc)
-			log.Printf("Successfully parsed timestamp: %s", timestamp.Format(time.RFC3339))
-		}
+	timestamp := time.Date(year, time.Month(month), day, hour, minute, 0, 0, loc)
+	// time.Date normalizes out-of-range values, reject them instead
+	if timestamp.Day() != day || int(timestamp.Month()) != month || hour > 23 || minute > 59 {
+		return time.Time{}, fmt.Errorf("invalid hidmet timestamp in %q", text)
 	}
 
-	return timestamp
+	return timestamp, nil
 }
 
 // FetchRhmzRsData retrieves water data from the novi.rhmzrs.com website
-func (ws *WaterScraper) FetchRhmzRsData() ([]entities.RiverData, error) {
-	log.Printf("Fetching data from RHMZ RS website")
+func (ws *WaterScraper) FetchRhmzRsData(ctx context.Context) ([]entities.RiverData, error) {
+	slog.Debug("Fetching data from RHMZ RS website")
 
 	// Step 1: Fetch the listing page
-	listURL := "https://novi.rhmzrs.com/page/bilten-izvjestaj-o-vodostanju"
-	resp, err := http.Get(listURL)
+	resp, err := ws.get(ctx, ws.rhmzRsListingURL)
 	if err != nil {
-		log.Printf("Error fetching RHMZ RS listing page: %v", err)
+		slog.Error("Error fetching RHMZ RS listing page", "error", err)
 		return nil, fmt.Errorf("failed to fetch RHMZ RS listing page: %v", err)
 	}
 	defer resp.Body.Close()
 
-	bodyBytes, err := io.ReadAll(resp.Body)
+	// Check for successful response
+	if resp.StatusCode != 200 {
+		slog.Error("Received unexpected status code for RHMZ RS listing page", "status", resp.Status)
+		return nil, fmt.Errorf("unexpected status code for RHMZ RS listing page: %d %s", resp.StatusCode, resp.Status)
+	}
+
+	bodyBytes, err := readUTF8(resp)
 	if err != nil {
-		log.Printf("Error reading RHMZ RS listing HTML: %v", err)
+		slog.Error("Error reading RHMZ RS listing HTML", "error", err)
 		return nil, fmt.Errorf("error reading RHMZ RS listing HTML: %v", err)
 	}
+	if len(bytes.TrimSpace(bodyBytes)) == 0 {
+		slog.Error("RHMZ RS listing page is empty")
+		return nil, fmt.Errorf("RHMZ RS listing page is empty")
+	}
 	body := string(bodyBytes)
 
 	// Step 2: Extract link to the latest bulletin
 	linkRe := regexp.MustCompile(`<a[^>]+href="([^"]+)"[^>]*>Редован\s+хидролошки\s+билтен`)
 	match := linkRe.FindStringSubmatch(body)
 	if len(match) < 2 {
-		log.Printf("Latest RHMZ RS bulletin link not found")
+		slog.Error("Latest RHMZ RS bulletin link not found")
 		return nil, fmt.Errorf("latest RHMZ RS bulletin link not found")
 	}
-	href := match[1]
-	if strings.HasPrefix(href, "/") {
-		href = "https://novi.rhmzrs.com" + href
+	href, err := resolveURL(ws.rhmzRsListingURL, match[1])
+	if err != nil {
+		slog.Error("Invalid RHMZ RS bulletin link", "link", match[1], "error", err)
+		return nil, fmt.Errorf("invalid RHMZ RS bulletin link %q: %v", match[1], err)
 	}
-	log.Printf("Found bulletin link: %s", href)
+	slog.Debug("Found bulletin link", "link", href)
 
 	// Step 3: Fetch the bulletin page
-	resp2, err := http.Get(href)
+	resp2, err := ws.get(ctx, href)
 	if err != nil {
-		log.Printf("Error fetching RHMZ RS bulletin page: %v", err)
+		slog.Error("Error fetching RHMZ RS bulletin page", "error", err)
 		return nil, fmt.Errorf("error fetching RHMZ RS bulletin page: %v", err)
 	}
 	defer resp2.Body.Close()
 
+	// Check for successful response
+	if resp2.StatusCode != 200 {
+		slog.Error("Received unexpected status code for RHMZ RS bulletin page", "status", resp2.Status)
+		return nil, fmt.Errorf("unexpected status code for RHMZ RS bulletin page: %d %s", resp2.StatusCode, resp2.Status)
+	}
+
+	bulletinBytes, err := readUTF8(resp2)
+	if err != nil {
+		slog.Error("Error reading RHMZ RS bulletin HTML", "error", err)
+		return nil, fmt.Errorf("error reading RHMZ RS bulletin HTML: %v", err)
+	}
+	if len(bytes.TrimSpace(bulletinBytes)) == 0 {
+		slog.Error("RHMZ RS bulletin page is empty")
+		return nil, fmt.Errorf("RHMZ RS bulletin page is empty")
+	}
+
 	// Step 4: Parse the HTML document using goquery
-	doc, err := goquery.NewDocumentFromReader(resp2.Body)
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(bulletinBytes))
 	if err != nil {
-		log.Printf("Error parsing RHMZ RS bulletin HTML: %v", err)
+		slog.Error("Error parsing RHMZ RS bulletin HTML", "error", err)
 		return nil, fmt.Errorf("error parsing RHMZ RS bulletin HTML: %v", err)
 	}
 
@@ -352,16 +660,16 @@ func (ws *WaterScraper) FetchRhmzRsData() ([]entities.RiverData, error) {
 					// Parse timestamp from matched date and time
 					dateStr := tsMatch[1]
 					timeStr := tsMatch[2]
-					log.Printf("Extracted RHMZ RS date: '%s', time: '%s'", dateStr, timeStr)
+					slog.Debug("Extracted RHMZ RS date", "date", dateStr, "time", timeStr)
 
 					// Parse timestamp in Serbian/Bosnian time zone
 					loc, _ := time.LoadLocation("Europe/Sarajevo")
 					t, err := time.ParseInLocation("02.01.2006 15:04", dateStr+" "+timeStr, loc)
 					if err == nil {
 						timestamp = t
-						log.Printf("Successfully parsed RHMZ RS timestamp: %s", timestamp.Format(time.RFC3339))
+						slog.Debug("Parsed RHMZ RS timestamp", "timestamp", timestamp.Format(time.RFC3339))
 					} else {
-						log.Printf("Error parsing RHMZ RS timestamp: %v", err)
+						slog.Error("Error parsing RHMZ RS timestamp", "error", err)
 					}
 				}
 			}
@@ -389,88 +697,111 @@ func (ws *WaterScraper) FetchRhmzRsData() ([]entities.RiverData, error) {
 		// Count words (splitting by whitespace)
 		words := strings.Fields(name)
 		if len(words) > 3 {
-			log.Printf("Skipping river with too many words (%d): %s", len(words), name)
+			slog.Debug("Skipping river with too many words", "words", len(words), "name", name)
 			return false
 		}
 
 		// Check for special characters (excluding letters, digits, spaces, and hyphens)
 		specialCharRegex := regexp.MustCompile(`[^a-zA-Zа-яА-ЯčćđšžČĆĐŠŽ0-9\s\-]`)
 		if specialCharRegex.MatchString(name) {
-			log.Printf("Skipping river with special characters: %s", name)
+			slog.Debug("Skipping river with special characters", "name", name)
 			return false
 		}
 
 		return true
 	}
 
+	// Rivers and stations may span several rows, rebuild each row's full set of cells
+	grid := newRowspanGrid()
+	var currentStation string
+	columns := defaultRhmzRsColumns
+
 	doc.Find("table tr").Each(func(i int, tr *goquery.Selection) {
-		cells := tr.Find("td")
-		cellCount := cells.Length()
+		cells := grid.expand(tr.Find("td"))
 
 		// Skip rows without enough columns
-		if cellCount < 4 {
+		if len(cells) < 4 {
 			return
 		}
 
 		// Look for header row that contains column titles
 		if !headerPassed {
-			headerText := strings.TrimSpace(cells.Eq(0).Text())
-			if headerText == "РИЈЕКА" {
+			if isRhmzRsHeader(cells) {
 				headerPassed = true
-				return // Skip this header row
+				var ok bool
+				if columns, ok = detectRhmzRsColumns(cells); !ok {
+					slog.Warn("RHMZ RS table headers not recognized, using the default column layout")
+					columns = defaultRhmzRsColumns
+				}
 			}
-			return // Skip any row before header
+			return // Skip the header and any row before it
 		}
 
-		// Check for empty rows or footnote rows
-		firstCellText := strings.TrimSpace(cells.Eq(0).Text())
-		if firstCellText == "" || strings.Contains(firstCellText, "Напомена") || strings.Contains(firstCellText, "Легенда") {
+		// Skip footnote rows
+		firstCellText := cells[0]
+		if strings.Contains(firstCellText, "Напомена") || strings.Contains(firstCellText, "Легенда") {
 			return
 		}
 
-		// Handle river name - might span multiple rows (use rowspan)
-		riverName := strings.TrimSpace(cells.Eq(0).Text())
-		if riverName != "" {
-			// Validate river name if it's not empty
+		// A non-empty river cell starts a new river, a blank one continues the last river seen
+		if riverName := cellAt(cells, columns.river); riverName != "" {
 			if !isValidRiverName(riverName) {
 				invalidRiverNames++
 				currentRiver = "" // Reset current river to avoid using this invalid name
+				currentStation = ""
 				return
 			}
+			if riverName != currentRiver {
+				currentStation = ""
+			}
 			currentRiver = riverName
-		} else if currentRiver == "" {
-			skippedEntries++
-			return // Skip row if no river name is set
 		}
 
-		// Validate river name (must not be empty at this point)
+		// Extract data from cells
+		station := cellAt(cells, columns.station)
+		waterLevelStr := cellAt(cells, columns.level)
+		hasLevel := waterLevelStr != "" && waterLevelStr != "-"
+
+		// A row with only a river name heads the stations listed below it
+		if station == "" && !hasLevel {
+			return
+		}
+
+		// Skip rows whose river is still unknown
 		if currentRiver == "" {
 			skippedEntries++
-			return // Skip rows with empty river names
+			return
 		}
 
-		processedEntries++
-
-		// Extract data from cells
-		station := strings.TrimSpace(cells.Eq(1).Text())
-
-		// Skip rows without a station name
+		// A blank station cell continues the previous station of the same river
+		if station == "" {
+			station = currentStation
+		}
 		if station == "" {
 			skippedEntries++
 			return
 		}
+		currentStation = station
+
+		processedEntries++
 
-		// Extract water level (4th column - index 3)
-		waterLevelStr := strings.TrimSpace(cells.Eq(3).Text())
-		if waterLevelStr == "-" || waterLevelStr == "" {
+		if !hasLevel {
 			waterLevelStr = "0" // Default when no data
 		}
 
-		// Extract water temperature (6th column - index 5)
-		waterTemp := strings.TrimSpace(cells.Eq(5).Text())
+		waterTemp := cellAt(cells, columns.temp)
 		if waterTemp == "-" {
 			waterTemp = "" // No temperature data
 		}
+		discharge := cellAt(cells, columns.discharge)
+		if discharge == "-" {
+			discharge = "" // No discharge data
+		}
+		gaugeZero := cellAt(cells, columns.gaugeZero)
+		if gaugeZero == "-" {
+			gaugeZero = "" // No gauge zero data
+		}
+		tendency := entities.ParseTendency(cellAt(cells, columns.tendency))
 
 		// Create a RiverData entry
 		data = append(data, entities.RiverData{
@@ -478,11 +809,279 @@ func (ws *WaterScraper) FetchRhmzRsData() ([]entities.RiverData, error) {
 			Station:    station,
 			WaterLevel: waterLevelStr,
 			WaterTemp:  waterTemp,
+			Discharge:  discharge,
+			Tendency:   tendency,
+			Source:     SourceRhmzRs,
+			URL:        href,
+			GaugeZero:  gaugeZero,
 			Timestamp:  timestamp,
 		})
 	})
 
-	log.Printf("RHMZ RS data: extracted %d river data entries, skipped %d entries with invalid river names, skipped %d other invalid entries",
-		len(data), invalidRiverNames, skippedEntries)
+	slog.Debug("Parsed RHMZ RS data",
+		"valid", len(data), "invalid_names", invalidRiverNames, "skipped", skippedEntries)
+	if len(data) == 0 && doc.Find("table").Length() > 0 {
+		return nil, fmt.Errorf("RHMZ RS bulletin: %w", ErrNoData)
+	}
 	return data, nil
 }
+
+// rhmzRsColumns holds the cell indices of the RHMZ RS bulletin table's columns, -1 when a column is absent
+type rhmzRsColumns struct {
+	river, station, level, temp, discharge, tendency, gaugeZero int
+}
+
+// defaultRhmzRsColumns is the layout of the RHMZ RS bulletin when its headers can't be read:
+// river, station, КОТА „О", level, level change, water temperature, discharge, tendency
+var defaultRhmzRsColumns = rhmzRsColumns{river: 0, station: 1, gaugeZero: 2, level: 3, temp: 5, discharge: 6, tendency: 7}
+
+// isRhmzRsHeader reports whether a row holds the column titles of the RHMZ RS bulletin table
+func isRhmzRsHeader(cells []string) bool {
+	for _, cell := range cells {
+		if rhmzRsLabel(cell) == "РИЈЕКА" {
+			return true
+		}
+	}
+	return false
+}
+
+// detectRhmzRsColumns maps the RHMZ RS header labels to cell indices.
+// It reports false unless the river, station and water level columns are all found.
+func detectRhmzRsColumns(header []string) (rhmzRsColumns, bool) {
+	columns := rhmzRsColumns{river: -1, station: -1, level: -1, temp: -1, discharge: -1, tendency: -1, gaugeZero: -1}
+	for index, cell := range header {
+		label := rhmzRsLabel(cell)
+		switch {
+		case columns.river < 0 && (label == "РИЈЕКА" || label == "РЕКА"):
+			columns.river = index
+		case columns.station < 0 && strings.Contains(label, "СТАНИЦА"):
+			columns.station = index
+		// Checked before the level, its label is "ТЕНДЕНЦИЈА ВОДОСТАЈА"
+		case columns.tendency < 0 && strings.Contains(label, "ТЕНДЕНЦИЈА"):
+			columns.tendency = index
+		case columns.level < 0 && strings.Contains(label, "ВОДОСТАЈ") && !strings.Contains(label, "ПРОМ"):
+			columns.level = index
+		case columns.temp < 0 && strings.Contains(label, "ТЕМП"):
+			columns.temp = index
+		case columns.discharge < 0 && (strings.Contains(label, "ПРОТИЦАЈ") || strings.Contains(label, "ПРОТОК")):
+			columns.discharge = index
+		case columns.gaugeZero < 0 && strings.Contains(label, "КОТА"):
+			columns.gaugeZero = index
+		}
+	}
+	return columns, columns.river >= 0 && columns.station >= 0 && columns.level >= 0
+}
+
+// rhmzRsLabel normalizes a header cell for matching, upper-casing it and collapsing whitespace
+func rhmzRsLabel(cell string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(cell), " "))
+}
+
+// spannedCell is a cell that still covers rows below the one it was declared in
+type spannedCell struct {
+	text string
+	rows int // Rows below still covered
+}
+
+// rowspanGrid rebuilds table rows whose cells are covered by a rowspan from a row above.
+// Rows must be expanded in document order.
+type rowspanGrid struct {
+	spans map[int]*spannedCell // By column index
+}
+
+// newRowspanGrid creates a grid with no cells spanning rows yet
+func newRowspanGrid() *rowspanGrid {
+	return &rowspanGrid{spans: make(map[int]*spannedCell)}
+}
+
+// expand returns the trimmed text of a row's cells by column, repeating cells spanning
+// from rows above into the columns they cover. Columns covered by a colspan are left blank.
+func (g *rowspanGrid) expand(cells *goquery.Selection) []string {
+	var row []string
+	column := 0
+
+	// fillSpanned copies the cells spanning from above into the columns at the current position
+	fillSpanned := func() {
+		for {
+			span, ok := g.spans[column]
+			if !ok {
+				return
+			}
+			row = append(row, span.text)
+			if span.rows--; span.rows == 0 {
+				delete(g.spans, column)
+			}
+			column++
+		}
+	}
+
+	cells.Each(func(_ int, cell *goquery.Selection) {
+		fillSpanned()
+
+		text := strings.TrimSpace(cell.Text())
+		if rows := spanAttr(cell, "rowspan"); rows > 1 {
+			g.spans[column] = &spannedCell{text: text, rows: rows - 1}
+		}
+		row = append(row, text)
+		column++
+		for extra := spanAttr(cell, "colspan"); extra > 1; extra-- {
+			row = append(row, "")
+			column++
+		}
+	})
+	fillSpanned()
+
+	// Spans beyond the last cell of this row, e.g. when rows are ragged, still cover it
+	for col, span := range g.spans {
+		if col < column {
+			continue
+		}
+		for len(row) <= col {
+			row = append(row, "")
+		}
+		row[col] = span.text
+		if span.rows--; span.rows == 0 {
+			delete(g.spans, col)
+		}
+	}
+	return row
+}
+
+// spanAttr returns a cell's rowspan or colspan, 1 when missing or invalid
+func spanAttr(cell *goquery.Selection, name string) int {
+	value, ok := cell.Attr(name)
+	if !ok {
+		return 1
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || n < 1 {
+		return 1
+	}
+	return n
+}
+
+// cellAt returns the cell text at index, or an empty string if the row is shorter
+func cellAt(cells []string, index int) string {
+	if index >= 0 && index < len(cells) {
+		return cells[index]
+	}
+	return ""
+}
+
+// dhmzTimestampLayouts are the measurement time formats used on the DHMZ website
+var dhmzTimestampLayouts = []string{
+	"02.01.2006. 15:04",
+	"02.01.2006 15:04",
+	"2.1.2006. 15:04",
+	"2.1.2006 15:04",
+}
+
+// FetchDhmzData retrieves water data from the Croatian DHMZ hydrology website
+func (ws *WaterScraper) FetchDhmzData(ctx context.Context) ([]entities.RiverData, error) {
+	slog.Debug("Fetching data from DHMZ website")
+	res, err := ws.get(ctx, ws.dhmzURL)
+	if err != nil {
+		slog.Error("Error fetching DHMZ data", "error", err)
+		return nil, fmt.Errorf("failed to fetch DHMZ data: %v", err)
+	}
+	defer res.Body.Close()
+
+	// Check for successful response
+	if res.StatusCode != 200 {
+		slog.Error("Received unexpected status code for DHMZ", "status", res.Status)
+		return nil, fmt.Errorf("unexpected status code for DHMZ: %d %s", res.StatusCode, res.Status)
+	}
+
+	doc, err := parseDocument(res)
+	if err != nil {
+		slog.Error("Error parsing DHMZ HTML", "error", err)
+		return nil, fmt.Errorf("failed to parse the DHMZ webpage: %v", err)
+	}
+
+	// DHMZ publishes timestamps in Croatian local time
+	loc, _ := time.LoadLocation("Europe/Zagreb")
+
+	var data []entities.RiverData
+	skippedRows := 0
+
+	// Expected columns: river, station, measurement time, water level (cm), tendency, water temperature
+	doc.Find("table tr").Each(func(index int, row *goquery.Selection) {
+		cells := row.Find("td")
+		if cells.Length() < 5 {
+			return // Header rows use <th> or have fewer columns
+		}
+
+		river := strings.ToUpper(strings.TrimSpace(cells.Eq(0).Text()))
+		station := strings.TrimSpace(cells.Eq(1).Text())
+		dateTimeStr := strings.TrimSpace(cells.Eq(2).Text())
+		waterLevel := strings.TrimSpace(cells.Eq(3).Text())
+
+		if river == "" || station == "" || waterLevel == "" || waterLevel == "-" {
+			skippedRows++
+			return
+		}
+
+		timestamp, ok := parseDhmzTimestamp(dateTimeStr, loc)
+		if !ok {
+			slog.Debug("Skipping DHMZ row with invalid timestamp format", "timestamp", dateTimeStr)
+			skippedRows++
+			return
+		}
+
+		// Tendency is shown either as an arrow image or as text
+		tendencyCell := cells.Eq(4)
+		rawTendency, ok := tendencyCell.Find("img").Attr("alt")
+		if !ok {
+			rawTendency = tendencyCell.Text()
+		}
+
+		waterTemp := ""
+		if cells.Length() > 5 {
+			waterTemp = strings.TrimSpace(cells.Eq(5).Text())
+			if waterTemp == "-" {
+				waterTemp = "" // No temperature data
+			}
+		}
+
+		data = append(data, entities.RiverData{
+			River:      river,
+			Station:    station,
+			WaterLevel: waterLevel,
+			WaterTemp:  waterTemp,
+			Tendency:   entities.ParseTendency(rawTendency),
+			Source:     SourceDhmz,
+			URL:        ws.dhmzURL,
+			Timestamp:  timestamp,
+		})
+	})
+
+	slog.Debug("Parsed DHMZ data", "valid", len(data), "skipped", skippedRows)
+	if len(data) == 0 && doc.Find("table").Length() > 0 {
+		return nil, fmt.Errorf("DHMZ: %w", ErrNoData)
+	}
+	return data, nil
+}
+
+// parseDhmzTimestamp parses a DHMZ measurement time in the given location
+func parseDhmzTimestamp(text string, loc *time.Location) (time.Time, bool) {
+	text = strings.Join(strings.Fields(text), " ")
+	for _, layout := range dhmzTimestampLayouts {
+		if t, err := time.ParseInLocation(layout, text, loc); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// resolveURL resolves a possibly relative link against the page it was found on
+func resolveURL(base, href string) (string, error) {
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	ref, err := url.Parse(href)
+	if err != nil {
+		return "", err
+	}
+	return baseURL.ResolveReference(ref).String(), nil
+}
@@ -0,0 +1,56 @@
+// Package logging configures the application's log output format and level
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"strings"
+)
+
+// Supported values of the LOG_FORMAT environment variable
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Setup configures logging for the given format and minimum level, writing to w.
+// The text format keeps the standard log output unchanged. The JSON format
+// routes both slog and the standard log package through a JSON handler,
+// so plain log.Printf lines become JSON records with a "msg" field.
+// The level filters slog records, in the JSON format plain log lines count as info.
+func Setup(format, level string, w io.Writer) error {
+	minLevel, err := parseLevel(level)
+	if err != nil {
+		return err
+	}
+
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", FormatText:
+		log.SetOutput(w)
+		slog.SetLogLoggerLevel(minLevel)
+		return nil
+	case FormatJSON:
+		slog.SetDefault(slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: minLevel})))
+		return nil
+	default:
+		return fmt.Errorf("unsupported log format %q, expected %q or %q", format, FormatText, FormatJSON)
+	}
+}
+
+// parseLevel parses the LOG_LEVEL environment variable, defaulting to info
+func parseLevel(level string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return 0, fmt.Errorf("unsupported log level %q, expected debug, info, warn or error", level)
+	}
+}
@@ -0,0 +1,118 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// restoreLogging resets the global loggers changed by Setup once the test ends
+func restoreLogging(t *testing.T) {
+	t.Helper()
+	logger, writer, flags := slog.Default(), log.Writer(), log.Flags()
+	t.Cleanup(func() {
+		slog.SetDefault(logger)
+		slog.SetLogLoggerLevel(slog.LevelInfo)
+		log.SetOutput(writer)
+		log.SetFlags(flags)
+	})
+}
+
+// TestSetupJSON verifies every log line is valid JSON carrying the structured fields
+func TestSetupJSON(t *testing.T) {
+	restoreLogging(t)
+
+	var buf bytes.Buffer
+	if err := Setup("json", "", &buf); err != nil {
+		t.Fatalf("Failed to set up logging: %v", err)
+	}
+
+	log.Printf("Starting Water Bot...")
+	slog.Info("Fetched source data", "source", "hidmet", "rows", 42)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 log lines, got %d:\n%s", len(lines), buf.String())
+	}
+
+	var records []map[string]interface{}
+	for _, line := range lines {
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Invalid JSON log line %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+
+	if records[0]["msg"] != "Starting Water Bot..." {
+		t.Errorf("Expected standard log message in msg, got %v", records[0]["msg"])
+	}
+	if records[1]["source"] != "hidmet" || records[1]["rows"] != float64(42) {
+		t.Errorf("Expected structured fields, got %v", records[1])
+	}
+}
+
+// TestSetupText verifies the default format keeps plain log lines
+func TestSetupText(t *testing.T) {
+	restoreLogging(t)
+
+	var buf bytes.Buffer
+	if err := Setup("", "", &buf); err != nil {
+		t.Fatalf("Failed to set up logging: %v", err)
+	}
+	log.SetFlags(0)
+	log.Printf("Starting Water Bot...")
+
+	if buf.String() != "Starting Water Bot...\n" {
+		t.Errorf("Expected a plain log line, got %q", buf.String())
+	}
+
+	if err := Setup("xml", "", &buf); err == nil {
+		t.Error("Expected an error for an unsupported format")
+	}
+}
+
+// TestSetupLevel verifies messages below the configured level are suppressed in both formats
+func TestSetupLevel(t *testing.T) {
+	tests := []struct {
+		format    string
+		level     string
+		wantDebug bool
+		wantInfo  bool
+	}{
+		{format: "text", level: "", wantDebug: false, wantInfo: true},
+		{format: "text", level: "info", wantDebug: false, wantInfo: true},
+		{format: "text", level: "debug", wantDebug: true, wantInfo: true},
+		{format: "text", level: "warn", wantDebug: false, wantInfo: false},
+		{format: "json", level: "", wantDebug: false, wantInfo: true},
+		{format: "json", level: "DEBUG", wantDebug: true, wantInfo: true},
+		{format: "json", level: "error", wantDebug: false, wantInfo: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format+"/"+tt.level, func(t *testing.T) {
+			restoreLogging(t)
+
+			var buf bytes.Buffer
+			if err := Setup(tt.format, tt.level, &buf); err != nil {
+				t.Fatalf("Failed to set up logging: %v", err)
+			}
+			slog.Debug("Parsing HTML document")
+			slog.Info("Fetched source data")
+
+			if got := strings.Contains(buf.String(), "Parsing HTML document"); got != tt.wantDebug {
+				t.Errorf("Expected debug message logged=%v, got:\n%s", tt.wantDebug, buf.String())
+			}
+			if got := strings.Contains(buf.String(), "Fetched source data"); got != tt.wantInfo {
+				t.Errorf("Expected info message logged=%v, got:\n%s", tt.wantInfo, buf.String())
+			}
+		})
+	}
+
+	if err := Setup("text", "verbose", &bytes.Buffer{}); err == nil {
+		t.Error("Expected an error for an unsupported level")
+	}
+}
@@ -0,0 +1,31 @@
+package refdata
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// loadAliases parses an alias file into a map keyed by upper-cased alias
+func loadAliases(data []byte) (map[string]string, error) {
+	var raw map[string]string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse river aliases: %v", err)
+	}
+	aliases := make(map[string]string, len(raw))
+	for alias, river := range raw {
+		key := strings.ToUpper(strings.TrimSpace(alias))
+		if key == "" || strings.TrimSpace(river) == "" {
+			return nil, fmt.Errorf("invalid river alias %q: alias and river are required", alias)
+		}
+		aliases[key] = river
+	}
+	return aliases, nil
+}
+
+// RiverAlias returns the stored river name an alias refers to, reporting false when
+// the name is not a known alias. Aliases are matched case-insensitively.
+func RiverAlias(alias string) (string, bool) {
+	river, ok := riverAliases[strings.ToUpper(strings.TrimSpace(alias))]
+	return river, ok
+}
@@ -0,0 +1,50 @@
+package refdata
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// floodLevelRecord is one entry of floodLevelsJSON
+type floodLevelRecord struct {
+	River     string  `json:"river"`
+	Station   string  `json:"station"`
+	WarningCm float64 `json:"warning_cm"`
+	DangerCm  float64 `json:"danger_cm"`
+}
+
+// loadFloodLevels parses and validates a flood level file
+func loadFloodLevels(data []byte) ([]entities.FloodLevel, error) {
+	var records []floodLevelRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to parse flood levels: %v", err)
+	}
+
+	levels := make([]entities.FloodLevel, 0, len(records))
+	for _, record := range records {
+		river, station := strings.TrimSpace(record.River), strings.TrimSpace(record.Station)
+		if river == "" || station == "" {
+			return nil, fmt.Errorf("invalid flood level %+v: river and station are required", record)
+		}
+		if record.WarningCm <= 0 || record.DangerCm <= record.WarningCm {
+			return nil, fmt.Errorf("invalid flood level of %s at %s: danger must be above a positive warning level", river, station)
+		}
+		levels = append(levels, entities.FloodLevel{
+			River:     river,
+			Station:   station,
+			WarningCm: record.WarningCm,
+			DangerCm:  record.DangerCm,
+		})
+	}
+	return levels, nil
+}
+
+// FloodLevels returns the flood defence thresholds of every station that has them
+func FloodLevels() []entities.FloodLevel {
+	levels := make([]entities.FloodLevel, len(floodLevels))
+	copy(levels, floodLevels)
+	return levels
+}
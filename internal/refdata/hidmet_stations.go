@@ -0,0 +1,54 @@
+package refdata
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// HidmetStation is a station whose history can be fetched from hidmet by its hm_id
+type HidmetStation struct {
+	HmID    int    `json:"hm_id"`
+	River   string `json:"river"`
+	Station string `json:"station"`
+	Source  string `json:"source"` // Source identifier stored with the station's readings
+}
+
+// loadHidmetStations parses and validates a hidmet station file into a map keyed by hm_id
+func loadHidmetStations(data []byte) (map[int]HidmetStation, error) {
+	var records []HidmetStation
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to parse hidmet stations: %v", err)
+	}
+
+	stations := make(map[int]HidmetStation, len(records))
+	for _, station := range records {
+		if station.HmID <= 0 {
+			return nil, fmt.Errorf("invalid hidmet station %+v: hm_id must be positive", station)
+		}
+		if strings.TrimSpace(station.River) == "" || strings.TrimSpace(station.Station) == "" || strings.TrimSpace(station.Source) == "" {
+			return nil, fmt.Errorf("invalid hidmet station %d: river, station and source are required", station.HmID)
+		}
+		if _, ok := stations[station.HmID]; ok {
+			return nil, fmt.Errorf("duplicate hidmet station %d", station.HmID)
+		}
+		stations[station.HmID] = station
+	}
+	return stations, nil
+}
+
+// LookupHidmetStation returns the station identified by a hidmet hm_id
+func LookupHidmetStation(hmID int) (HidmetStation, bool) {
+	station, ok := hidmetStations[hmID]
+	return station, ok
+}
+
+// LookupHmID returns the hidmet hm_id of a river's station
+func LookupHmID(river, station string) (int, bool) {
+	for hmID, s := range hidmetStations {
+		if s.River == river && s.Station == station {
+			return hmID, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,39 @@
+// Package refdata bundles the static reference data the bot ships with: river aliases,
+// flood defence thresholds and the hidmet stations known by hm_id. The files are embedded
+// in the binary and validated when the package loads, so broken data fails at startup.
+package refdata
+
+import (
+	_ "embed"
+)
+
+// aliasesJSON maps alternate spellings, transliterations and station names to the
+// river name stored in the database. Keys are matched case-insensitively.
+//
+//go:embed aliases.json
+var aliasesJSON []byte
+
+// floodLevelsJSON lists the flood defence thresholds of the stations hidmet publishes them for
+//
+//go:embed flood_levels.json
+var floodLevelsJSON []byte
+
+// hidmetStationsJSON lists the hidmet stations whose history can be fetched by hm_id
+//
+//go:embed hidmet_stations.json
+var hidmetStationsJSON []byte
+
+// Parsed reference data, loaded once when the package is initialized
+var (
+	riverAliases   = mustLoad(loadAliases(aliasesJSON))
+	floodLevels    = mustLoad(loadFloodLevels(floodLevelsJSON))
+	hidmetStations = mustLoad(loadHidmetStations(hidmetStationsJSON))
+)
+
+// mustLoad returns parsed embedded data, which is fixed at build time, panicking when it is invalid
+func mustLoad[T any](data T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
@@ -0,0 +1,93 @@
+package refdata
+
+import (
+	"testing"
+)
+
+// TestEmbeddedData verifies the embedded files parse and the accessors find known entries
+func TestEmbeddedData(t *testing.T) {
+	aliases := []struct {
+		alias string
+		river string
+	}{
+		{"gradac", "ГРАДАЦ"},
+		{" Danube ", "ДУНАВ"},
+		{"ДЕГУРИЋ", "ГРАДАЦ"},
+	}
+	for _, tt := range aliases {
+		if river, ok := RiverAlias(tt.alias); !ok || river != tt.river {
+			t.Errorf("Expected alias %q to map to %s, got %q, %v", tt.alias, tt.river, river, ok)
+		}
+	}
+	if river, ok := RiverAlias("ДРИНА"); ok {
+		t.Errorf("Expected a stored river name not to be an alias, got %q", river)
+	}
+
+	levels := FloodLevels()
+	found := false
+	for _, level := range levels {
+		if level.River == "ДУНАВ" && level.Station == "Бездан" {
+			found = level.WarningCm == 500 && level.DangerCm == 650
+		}
+	}
+	if !found {
+		t.Errorf("Expected ДУНАВ at Бездан with 500/650 cm among %d flood levels", len(levels))
+	}
+	levels[0].River = "CHANGED"
+	if FloodLevels()[0].River == "CHANGED" {
+		t.Error("Expected FloodLevels to return a copy")
+	}
+
+	station, ok := LookupHidmetStation(45902)
+	if !ok || station.River != "ГРАДАЦ" || station.Station != "ДЕГУРИЋ" || station.Source != "gradac" {
+		t.Errorf("Expected hm_id 45902 to be ГРАДАЦ at ДЕГУРИЋ from gradac, got %+v, %v", station, ok)
+	}
+	if hmID, ok := LookupHmID("ГРАДАЦ", "ДЕГУРИЋ"); !ok || hmID != 45902 {
+		t.Errorf("Expected ГРАДАЦ at ДЕГУРИЋ to be hm_id 45902, got %d, %v", hmID, ok)
+	}
+	if _, ok := LookupHidmetStation(1); ok {
+		t.Error("Expected no station for an unknown hm_id")
+	}
+}
+
+// TestLoadRejectsInvalidData verifies broken reference data fails at startup
+func TestLoadRejectsInvalidData(t *testing.T) {
+	tests := []struct {
+		name string
+		load func(data []byte) error
+		data string
+	}{
+		{"malformed aliases", loadError(loadAliases), `["gradac"]`},
+		{"alias without river", loadError(loadAliases), `{"gradac": " "}`},
+		{"malformed flood levels", loadError(loadFloodLevels), `not json`},
+		{"flood level without river", loadError(loadFloodLevels), `[{"river": "", "station": "Земун", "warning_cm": 500, "danger_cm": 600}]`},
+		{"zero warning level", loadError(loadFloodLevels), `[{"river": "ДУНАВ", "station": "Земун", "warning_cm": 0, "danger_cm": 600}]`},
+		{"danger below warning", loadError(loadFloodLevels), `[{"river": "ДУНАВ", "station": "Земун", "warning_cm": 600, "danger_cm": 500}]`},
+		{"hidmet station without hm_id", loadError(loadHidmetStations), `[{"river": "ГРАДАЦ", "station": "ДЕГУРИЋ", "source": "gradac"}]`},
+		{"hidmet station without source", loadError(loadHidmetStations), `[{"hm_id": 1, "river": "ГРАДАЦ", "station": "ДЕГУРИЋ"}]`},
+		{"duplicate hm_id", loadError(loadHidmetStations), `[{"hm_id": 1, "river": "А", "station": "Б", "source": "x"}, {"hm_id": 1, "river": "В", "station": "Г", "source": "x"}]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.load([]byte(tt.data)); err == nil {
+				t.Errorf("Expected an error for %s", tt.data)
+			}
+		})
+	}
+
+	aliases, err := loadAliases([]byte(`{" gradac ": "ГРАДАЦ"}`))
+	if err != nil {
+		t.Fatalf("Failed to load aliases: %v", err)
+	}
+	if aliases["GRADAC"] != "ГРАДАЦ" {
+		t.Errorf("Expected GRADAC to map to ГРАДАЦ, got %q", aliases["GRADAC"])
+	}
+}
+
+// loadError adapts a loader to return only its error
+func loadError[T any](load func(data []byte) (T, error)) func(data []byte) error {
+	return func(data []byte) error {
+		_, err := load(data)
+		return err
+	}
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Backup file names carry the time they were taken, so sorting them by name sorts them by age
+const (
+	backupPrefix     = "riverdata-"
+	backupSuffix     = ".db"
+	backupTimeLayout = "20060102-150405"
+)
+
+// Backup snapshots the database into a new file in destDir and returns its path.
+// VACUUM INTO writes a consistent, compacted copy while other connections keep
+// reading and writing, and the copy is an ordinary SQLite database.
+func (r *SQLiteRiverRepository) Backup(destDir string) (string, error) {
+	if err := os.MkdirAll(destDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %v", err)
+	}
+
+	path := filepath.Join(destDir, backupPrefix+time.Now().UTC().Format(backupTimeLayout)+backupSuffix)
+	if _, err := r.db.Exec(`VACUUM INTO ?`, path); err != nil {
+		return "", fmt.Errorf("failed to back up database to %s: %v", path, err)
+	}
+
+	slog.Info("Backed up database", "path", path)
+	return path, nil
+}
+
+// PruneBackups deletes all but the newest keep backups in dir and returns how many were deleted
+func PruneBackups(dir string, keep int) (int, error) {
+	backups, err := filepath.Glob(filepath.Join(dir, backupPrefix+"*"+backupSuffix))
+	if err != nil {
+		return 0, fmt.Errorf("failed to list backups: %v", err)
+	}
+	if len(backups) <= keep {
+		return 0, nil
+	}
+
+	sort.Strings(backups)
+	removed := 0
+	for _, path := range backups[:len(backups)-keep] {
+		if err := os.Remove(path); err != nil {
+			return removed, fmt.Errorf("failed to delete old backup %s: %v", path, err)
+		}
+		removed++
+	}
+	return removed, nil
+}
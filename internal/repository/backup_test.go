@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestBackup verifies a backup is a readable copy of the database with the same rows
+func TestBackup(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+	if err := repo.SaveRiverData(generateReadings(12)); err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+
+	path, err := repo.Backup(filepath.Join(t.TempDir(), "backups"))
+	if err != nil {
+		t.Fatalf("Failed to back up database: %v", err)
+	}
+
+	backup, err := NewSQLiteRiverRepository(path)
+	if err != nil {
+		t.Fatalf("Failed to open backup: %v", err)
+	}
+	defer backup.Close()
+
+	count, err := backup.CountRows()
+	if err != nil {
+		t.Fatalf("Failed to count backup rows: %v", err)
+	}
+	if count != 12 {
+		t.Errorf("Expected 12 rows in the backup, got %d", count)
+	}
+}
+
+// TestPruneBackups verifies only the newest backups are kept and other files are left alone
+func TestPruneBackups(t *testing.T) {
+	dir := t.TempDir()
+	names := []string{
+		"riverdata-20250416-033000.db",
+		"riverdata-20250417-033000.db",
+		"riverdata-20250418-033000.db",
+		"notes.txt",
+	}
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	removed, err := PruneBackups(dir, 2)
+	if err != nil {
+		t.Fatalf("Failed to prune backups: %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("Expected 1 backup to be deleted, got %d", removed)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Failed to list directory: %v", err)
+	}
+	var left []string
+	for _, entry := range entries {
+		left = append(left, entry.Name())
+	}
+	want := []string{"notes.txt", "riverdata-20250417-033000.db", "riverdata-20250418-033000.db"}
+	if len(left) != len(want) {
+		t.Fatalf("Expected %v to be left, got %v", want, left)
+	}
+	for i := range want {
+		if left[i] != want[i] {
+			t.Errorf("Expected %v to be left, got %v", want, left)
+			break
+		}
+	}
+}
+
+// TestDBPathCreatesDirectory verifies the database is created at its path, directories included
+func TestDBPathCreatesDirectory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "custom.db")
+
+	repo, err := NewSQLiteRiverRepository(path)
+	if err != nil {
+		t.Fatalf("Failed to initialize repository: %v", err)
+	}
+	defer repo.Close()
+
+	if repo.DBPath != path {
+		t.Errorf("Expected database at %s, got %s", path, repo.DBPath)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Expected the database file to exist: %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// digestColumns is the column list expected by scanDigest
+const digestColumns = `chat_id, rivers, send_hour, timezone, created_at`
+
+// digestRiverSeparator joins the rivers of a digest in the rivers column
+const digestRiverSeparator = ","
+
+// GetDigest returns the daily digest of a chat, reporting false if it has none
+func (r *sqlRiverRepository) GetDigest(chatID int64) (entities.Digest, bool, error) {
+	query := `SELECT ` + digestColumns + ` FROM daily_digests WHERE chat_id = ?`
+
+	digest, err := scanDigest(r.db.QueryRow(r.rebind(query), chatID))
+	if err == sql.ErrNoRows {
+		return entities.Digest{}, false, nil
+	}
+	if err != nil {
+		return entities.Digest{}, false, fmt.Errorf("failed to query digest for chat %d: %v", chatID, err)
+	}
+
+	return digest, true, nil
+}
+
+// SaveDigest creates or replaces the daily digest of a chat
+func (r *sqlRiverRepository) SaveDigest(digest entities.Digest) error {
+	query := `
+		INSERT INTO daily_digests(chat_id, rivers, send_hour, timezone, created_at)
+		VALUES(?, ?, ?, ?, ?)
+		ON CONFLICT(chat_id) DO UPDATE SET
+			rivers = excluded.rivers,
+			send_hour = excluded.send_hour,
+			timezone = excluded.timezone`
+
+	createdAt := digest.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+
+	_, err := r.db.Exec(r.rebind(query),
+		digest.ChatID,
+		strings.Join(digest.Rivers, digestRiverSeparator),
+		digest.SendHour,
+		digest.TimeZone,
+		r.timeArg(createdAt),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save digest for chat %d: %v", digest.ChatID, err)
+	}
+
+	return nil
+}
+
+// DeleteDigest removes the daily digest of a chat.
+// Returns false if the chat had no digest.
+func (r *sqlRiverRepository) DeleteDigest(chatID int64) (bool, error) {
+	result, err := r.db.Exec(r.rebind(`DELETE FROM daily_digests WHERE chat_id = ?`), chatID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete digest for chat %d: %v", chatID, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check deleted digest for chat %d: %v", chatID, err)
+	}
+
+	return affected > 0, nil
+}
+
+// GetAllDigests returns every stored daily digest
+func (r *sqlRiverRepository) GetAllDigests() ([]entities.Digest, error) {
+	rows, err := r.db.Query(`SELECT ` + digestColumns + ` FROM daily_digests ORDER BY chat_id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query digests: %v", err)
+	}
+	defer rows.Close()
+
+	var result []entities.Digest
+	for rows.Next() {
+		digest, err := scanDigest(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan digest: %v", err)
+		}
+		result = append(result, digest)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during row iteration: %v", err)
+	}
+
+	return result, nil
+}
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanDigest reads a single row selected with digestColumns
+func scanDigest(row rowScanner) (entities.Digest, error) {
+	var digest entities.Digest
+	var rivers string
+	var createdAt dbTime
+	if err := row.Scan(&digest.ChatID, &rivers, &digest.SendHour, &digest.TimeZone, &createdAt); err != nil {
+		return entities.Digest{}, err
+	}
+	if rivers != "" {
+		digest.Rivers = strings.Split(rivers, digestRiverSeparator)
+	}
+	digest.CreatedAt = createdAt.Time
+	return digest, nil
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"time"
+)
+
+// exportHeader is the header row of CSV exports
+var exportHeader = []string{"river", "station", "water_level", "water_temp", "tendency", "source", "timestamp"}
+
+// StreamRiverData writes all readings of a river since the given time to w as CSV, oldest first.
+// Rows are written as they are read so large exports aren't held in memory.
+func (r *sqlRiverRepository) StreamRiverData(w io.Writer, river string, since time.Time) error {
+	query := `
+		SELECT ` + riverDataColumns + `
+		FROM river_data
+		WHERE river = ? AND timestamp >= ?
+		ORDER BY timestamp, station`
+
+	rows, err := r.db.Query(r.rebind(query), river, r.timeArg(since))
+	if err != nil {
+		return fmt.Errorf("failed to query readings for %s: %v", river, err)
+	}
+	defer rows.Close()
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(exportHeader); err != nil {
+		return fmt.Errorf("failed to write CSV header: %v", err)
+	}
+
+	for rows.Next() {
+		rd, err := scanRiverDataRow(rows)
+		if err != nil {
+			return err
+		}
+		err = writer.Write([]string{
+			rd.River,
+			rd.Station,
+			rd.WaterLevel,
+			rd.WaterTemp,
+			string(rd.Tendency),
+			rd.Source,
+			rd.Timestamp.UTC().Format(time.RFC3339),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to write CSV row: %v", err)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error during row iteration: %v", err)
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %v", err)
+	}
+
+	return nil
+}
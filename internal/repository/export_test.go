@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// TestStreamRiverData verifies the CSV header, row format and escaping of names containing commas
+func TestStreamRiverData(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+
+	start := time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC)
+	err := repo.SaveRiverData([]entities.RiverData{
+		{River: "ДРИНА", Station: "Бајина Башта, ХЕ", WaterLevel: "142", WaterTemp: "9.5", Tendency: entities.Falling, Source: "hidmet", Timestamp: start},
+		{River: "ДРИНА", Station: "Радаљ", WaterLevel: "150", Source: "hidmet", Timestamp: start.Add(-48 * time.Hour)},
+		{River: "САВА", Station: "Шабац", WaterLevel: "325", Source: "hidmet", Timestamp: start},
+	})
+	if err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := repo.StreamRiverData(&buf, "ДРИНА", start.Add(-time.Hour)); err != nil {
+		t.Fatalf("Failed to export data: %v", err)
+	}
+
+	expected := "river,station,water_level,water_temp,tendency,source,timestamp\n" +
+		"ДРИНА,\"Бајина Башта, ХЕ\",142,9.5,falling,hidmet,2025-04-18T06:00:00Z\n"
+	if buf.String() != expected {
+		t.Errorf("Unexpected CSV:\n%s\nexpected:\n%s", buf.String(), expected)
+	}
+
+	buf.Reset()
+	if err := repo.StreamRiverData(&buf, "НЕПОЗНАТА", start.Add(-time.Hour)); err != nil {
+		t.Fatalf("Failed to export data: %v", err)
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != 1 {
+		t.Errorf("Expected only the header for an unknown river, got %d lines", lines)
+	}
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+)
+
+// AddFavorite bookmarks a river for a chat.
+// Returns false if the river was already a favorite.
+func (r *sqlRiverRepository) AddFavorite(chatID int64, river string) (bool, error) {
+	query := `
+		INSERT INTO favorites(chat_id, river, created_at)
+		VALUES(?, ?, ?)
+		ON CONFLICT(chat_id, river) DO NOTHING`
+
+	result, err := r.db.Exec(r.rebind(query), chatID, river, r.timeArg(time.Now()))
+	if err != nil {
+		return false, fmt.Errorf("failed to add favorite %s for chat %d: %v", river, chatID, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check added favorite %s for chat %d: %v", river, chatID, err)
+	}
+
+	return affected > 0, nil
+}
+
+// RemoveFavorite removes a river from a chat's favorites.
+// Returns false if the river was not a favorite.
+func (r *sqlRiverRepository) RemoveFavorite(chatID int64, river string) (bool, error) {
+	result, err := r.db.Exec(r.rebind(`DELETE FROM favorites WHERE chat_id = ? AND river = ?`), chatID, river)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove favorite %s for chat %d: %v", river, chatID, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check removed favorite %s for chat %d: %v", river, chatID, err)
+	}
+
+	return affected > 0, nil
+}
+
+// GetFavorites returns the favorite rivers of a chat in the order they were added
+func (r *sqlRiverRepository) GetFavorites(chatID int64) ([]string, error) {
+	rows, err := r.db.Query(r.rebind(`SELECT river FROM favorites WHERE chat_id = ? ORDER BY created_at, river`), chatID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query favorites for chat %d: %v", chatID, err)
+	}
+	defer rows.Close()
+
+	var rivers []string
+	for rows.Next() {
+		var river string
+		if err := rows.Scan(&river); err != nil {
+			return nil, fmt.Errorf("failed to scan favorite: %v", err)
+		}
+		rivers = append(rivers, river)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during row iteration: %v", err)
+	}
+
+	return rivers, nil
+}
@@ -0,0 +1,51 @@
+package repository
+
+import "testing"
+
+// TestFavoritesAreIdempotent verifies adding and removing a favorite twice only changes it once
+func TestFavoritesAreIdempotent(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+
+	const chatID = int64(100)
+	for i, want := range []bool{true, false} {
+		added, err := repo.AddFavorite(chatID, "ДРИНА")
+		if err != nil {
+			t.Fatalf("Failed to add favorite: %v", err)
+		}
+		if added != want {
+			t.Errorf("Add %d: expected added=%v, got %v", i+1, want, added)
+		}
+	}
+	if _, err := repo.AddFavorite(chatID, "САВА"); err != nil {
+		t.Fatalf("Failed to add favorite: %v", err)
+	}
+	if _, err := repo.AddFavorite(chatID+1, "ДУНАВ"); err != nil {
+		t.Fatalf("Failed to add favorite: %v", err)
+	}
+
+	favorites, err := repo.GetFavorites(chatID)
+	if err != nil {
+		t.Fatalf("Failed to get favorites: %v", err)
+	}
+	if len(favorites) != 2 || favorites[0] != "ДРИНА" || favorites[1] != "САВА" {
+		t.Errorf("Expected [ДРИНА САВА], got %v", favorites)
+	}
+
+	for i, want := range []bool{true, false} {
+		removed, err := repo.RemoveFavorite(chatID, "ДРИНА")
+		if err != nil {
+			t.Fatalf("Failed to remove favorite: %v", err)
+		}
+		if removed != want {
+			t.Errorf("Remove %d: expected removed=%v, got %v", i+1, want, removed)
+		}
+	}
+
+	favorites, err = repo.GetFavorites(chatID)
+	if err != nil {
+		t.Fatalf("Failed to get favorites: %v", err)
+	}
+	if len(favorites) != 1 || favorites[0] != "САВА" {
+		t.Errorf("Expected [САВА], got %v", favorites)
+	}
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+	"github.com/abelzeko/water-bot/internal/refdata"
+)
+
+// seedFloodLevels writes the embedded flood levels, overwriting the stored thresholds
+// of the same stations so a new release's values take effect
+func seedFloodLevels(db *sql.DB, rebind func(query string) string) error {
+	levels := refdata.FloodLevels()
+	query := rebind(`
+		INSERT INTO flood_levels(river, station, warning_cm, danger_cm)
+		VALUES(?, ?, ?, ?)
+		ON CONFLICT(river, station) DO UPDATE SET
+			warning_cm = excluded.warning_cm,
+			danger_cm = excluded.danger_cm`)
+	for _, level := range levels {
+		if _, err := db.Exec(query, level.River, level.Station, level.WarningCm, level.DangerCm); err != nil {
+			return fmt.Errorf("failed to seed flood level of %s at %s: %v", level.River, level.Station, err)
+		}
+	}
+	return nil
+}
+
+// GetFloodLevels returns the flood defence thresholds of every station that has them
+func (r *sqlRiverRepository) GetFloodLevels() ([]entities.FloodLevel, error) {
+	rows, err := r.db.Query(`SELECT river, station, warning_cm, danger_cm FROM flood_levels ORDER BY river, station`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query flood levels: %v", err)
+	}
+	defer rows.Close()
+
+	var result []entities.FloodLevel
+	for rows.Next() {
+		var level entities.FloodLevel
+		if err := rows.Scan(&level.River, &level.Station, &level.WarningCm, &level.DangerCm); err != nil {
+			return nil, fmt.Errorf("failed to scan flood level: %v", err)
+		}
+		result = append(result, level)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during row iteration: %v", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/abelzeko/water-bot/internal/refdata"
+)
+
+// TestSeedFloodLevels verifies the embedded flood levels are stored once, however often the database is opened
+func TestSeedFloodLevels(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+
+	embedded := refdata.FloodLevels()
+	if len(embedded) == 0 {
+		t.Fatal("Expected flood levels in the embedded file")
+	}
+
+	// Opening an existing database seeds again, which must update rather than duplicate
+	if err := seedFloodLevels(repo.db, repo.rebind); err != nil {
+		t.Fatalf("Failed to seed flood levels again: %v", err)
+	}
+	levels, err := repo.GetFloodLevels()
+	if err != nil {
+		t.Fatalf("Failed to get flood levels: %v", err)
+	}
+	if len(levels) != len(embedded) {
+		t.Errorf("Expected %d flood levels, got %d", len(embedded), len(levels))
+	}
+}
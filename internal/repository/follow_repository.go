@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// followColumns is the column list expected by scanFollows
+const followColumns = `chat_id, river, station, last_level, created_at`
+
+// AddFollow stores a chat's follow of a station.
+// Returns false if the chat already follows the station.
+func (r *sqlRiverRepository) AddFollow(follow entities.Follow) (bool, error) {
+	query := `
+		INSERT INTO follows(chat_id, river, station, last_level, created_at)
+		VALUES(?, ?, ?, ?, ?)
+		ON CONFLICT(chat_id, river, station) DO NOTHING`
+
+	createdAt := follow.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+
+	result, err := r.db.Exec(r.rebind(query), follow.ChatID, follow.River, follow.Station, follow.LastLevel, r.timeArg(createdAt))
+	if err != nil {
+		return false, fmt.Errorf("failed to add follow of %s at %s for chat %d: %v", follow.River, follow.Station, follow.ChatID, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check added follow of %s at %s for chat %d: %v", follow.River, follow.Station, follow.ChatID, err)
+	}
+
+	return affected > 0, nil
+}
+
+// RemoveFollow deletes a chat's follow of a station.
+// Returns false if the chat didn't follow the station.
+func (r *sqlRiverRepository) RemoveFollow(chatID int64, river, station string) (bool, error) {
+	result, err := r.db.Exec(r.rebind(`DELETE FROM follows WHERE chat_id = ? AND river = ? AND station = ?`), chatID, river, station)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove follow of %s at %s for chat %d: %v", river, station, chatID, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check removed follow of %s at %s for chat %d: %v", river, station, chatID, err)
+	}
+
+	return affected > 0, nil
+}
+
+// GetFollowsByChat returns the stations a chat follows in the order they were followed
+func (r *sqlRiverRepository) GetFollowsByChat(chatID int64) ([]entities.Follow, error) {
+	query := `
+		SELECT ` + followColumns + `
+		FROM follows
+		WHERE chat_id = ?
+		ORDER BY created_at, river, station`
+
+	rows, err := r.db.Query(r.rebind(query), chatID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query follows for chat %d: %v", chatID, err)
+	}
+	defer rows.Close()
+
+	return scanFollows(rows)
+}
+
+// GetAllFollows returns every stored follow
+func (r *sqlRiverRepository) GetAllFollows() ([]entities.Follow, error) {
+	query := `
+		SELECT ` + followColumns + `
+		FROM follows
+		ORDER BY chat_id, created_at`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query follows: %v", err)
+	}
+	defer rows.Close()
+
+	return scanFollows(rows)
+}
+
+// UpdateFollowLevel records the water level last sent to a chat for a followed station
+func (r *sqlRiverRepository) UpdateFollowLevel(chatID int64, river, station, level string) error {
+	query := `UPDATE follows SET last_level = ? WHERE chat_id = ? AND river = ? AND station = ?`
+
+	if _, err := r.db.Exec(r.rebind(query), level, chatID, river, station); err != nil {
+		return fmt.Errorf("failed to update follow of %s at %s for chat %d: %v", river, station, chatID, err)
+	}
+	return nil
+}
+
+// scanFollows reads all rows of a query selecting followColumns
+func scanFollows(rows *sql.Rows) ([]entities.Follow, error) {
+	var follows []entities.Follow
+	for rows.Next() {
+		var follow entities.Follow
+		var createdAt dbTime
+		if err := rows.Scan(&follow.ChatID, &follow.River, &follow.Station, &follow.LastLevel, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan follow: %v", err)
+		}
+		follow.CreatedAt = createdAt.Time
+		follows = append(follows, follow)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during row iteration: %v", err)
+	}
+
+	return follows, nil
+}
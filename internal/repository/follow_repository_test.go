@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// TestFollowsRoundTrip verifies follows are stored once per station, keep their last
+// sent level and are removed only for their own chat
+func TestFollowsRoundTrip(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+
+	const chatID = int64(100)
+	follow := entities.Follow{ChatID: chatID, River: "ДРИНА", Station: "Радаљ", LastLevel: "142"}
+	for i, want := range []bool{true, false} {
+		added, err := repo.AddFollow(follow)
+		if err != nil {
+			t.Fatalf("Failed to add follow: %v", err)
+		}
+		if added != want {
+			t.Errorf("Add %d: expected added=%v, got %v", i+1, want, added)
+		}
+	}
+	if _, err := repo.AddFollow(entities.Follow{ChatID: chatID + 1, River: "ДРИНА", Station: "Радаљ"}); err != nil {
+		t.Fatalf("Failed to add follow: %v", err)
+	}
+
+	if err := repo.UpdateFollowLevel(chatID, "ДРИНА", "Радаљ", "150"); err != nil {
+		t.Fatalf("Failed to update follow: %v", err)
+	}
+	follows, err := repo.GetFollowsByChat(chatID)
+	if err != nil {
+		t.Fatalf("Failed to get follows: %v", err)
+	}
+	if len(follows) != 1 || follows[0].Station != "Радаљ" || follows[0].LastLevel != "150" {
+		t.Errorf("Expected one follow of Радаљ at 150, got %+v", follows)
+	}
+
+	removed, err := repo.RemoveFollow(chatID, "ДРИНА", "Радаљ")
+	if err != nil || !removed {
+		t.Fatalf("Expected the follow removed, got %v, %v", removed, err)
+	}
+	all, err := repo.GetAllFollows()
+	if err != nil {
+		t.Fatalf("Failed to get follows: %v", err)
+	}
+	if len(all) != 1 || all[0].ChatID != chatID+1 {
+		t.Errorf("Expected only the other chat's follow left, got %+v", all)
+	}
+}
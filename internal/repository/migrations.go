@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// sqlExecutor is the part of *sql.DB and *sql.Tx that migrations use
+type sqlExecutor interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// migration is one step of the SQLite schema's history. Migrations must be idempotent,
+// databases created before versioning was introduced run all of them once.
+type migration struct {
+	version int
+	name    string
+	apply   func(tx sqlExecutor) error
+}
+
+// execMigration returns a migration step that runs fixed SQL
+func execMigration(query string) func(tx sqlExecutor) error {
+	return func(tx sqlExecutor) error {
+		_, err := tx.Exec(query)
+		return err
+	}
+}
+
+// addColumnMigration returns a migration step that adds a column unless it is already present
+func addColumnMigration(table, column, definition string) func(tx sqlExecutor) error {
+	return func(tx sqlExecutor) error {
+		return ensureColumn(tx, table, column, definition)
+	}
+}
+
+// sqliteMigrations is the SQLite schema's history, applied in order. Append new steps
+// with the next version number and never change or reorder the ones already released.
+var sqliteMigrations = []migration{
+	{1, "create river_data", execMigration(`
+		CREATE TABLE IF NOT EXISTS river_data (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			river TEXT NOT NULL,
+			station TEXT NOT NULL,
+			water_level TEXT,
+			water_temp TEXT,
+			timestamp DATETIME DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(river, station, timestamp)
+		);
+		CREATE INDEX IF NOT EXISTS idx_river ON river_data(river);
+		CREATE INDEX IF NOT EXISTS idx_timestamp ON river_data(timestamp);`)},
+	{2, "add river_data.tendency", addColumnMigration("river_data", "tendency", "TEXT")},
+	{3, "add river_data.source", addColumnMigration("river_data", "source", "TEXT")},
+	{4, "add river_data.discharge", addColumnMigration("river_data", "discharge", "TEXT")},
+	{5, "create subscriptions", execMigration(`
+		CREATE TABLE IF NOT EXISTS subscriptions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			chat_id INTEGER NOT NULL,
+			river TEXT NOT NULL,
+			threshold REAL NOT NULL,
+			direction TEXT NOT NULL,
+			triggered BOOLEAN NOT NULL DEFAULT 0,
+			last_triggered DATETIME,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);
+		CREATE INDEX IF NOT EXISTS idx_subscriptions_chat ON subscriptions(chat_id);`)},
+	{6, "create daily_digests", execMigration(`
+		CREATE TABLE IF NOT EXISTS daily_digests (
+			chat_id INTEGER PRIMARY KEY,
+			rivers TEXT NOT NULL,
+			send_hour INTEGER NOT NULL,
+			timezone TEXT NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);`)},
+	{7, "create users", execMigration(`
+		CREATE TABLE IF NOT EXISTS users (
+			chat_id INTEGER PRIMARY KEY,
+			username TEXT NOT NULL DEFAULT '',
+			language_code TEXT NOT NULL DEFAULT '',
+			first_seen DATETIME NOT NULL,
+			last_seen DATETIME NOT NULL
+		);`)},
+	{8, "add users.time_zone", addColumnMigration("users", "time_zone", "TEXT NOT NULL DEFAULT ''")},
+	{9, "create favorites", execMigration(`
+		CREATE TABLE IF NOT EXISTS favorites (
+			chat_id INTEGER NOT NULL,
+			river TEXT NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY(chat_id, river)
+		);`)},
+	{10, "add river_data.url", addColumnMigration("river_data", "url", "TEXT")},
+	{11, "create flood_levels", execMigration(`
+		CREATE TABLE IF NOT EXISTS flood_levels (
+			river TEXT NOT NULL,
+			station TEXT NOT NULL,
+			warning_cm REAL NOT NULL,
+			danger_cm REAL NOT NULL,
+			PRIMARY KEY(river, station)
+		);`)},
+	{12, "add river_data.gauge_zero", addColumnMigration("river_data", "gauge_zero", "TEXT")},
+	{13, "normalize river names", func(tx sqlExecutor) error {
+		return normalizeStoredRiverNames(tx, func(query string) string { return query })
+	}},
+	{14, "create source_checks", execMigration(`
+		CREATE TABLE IF NOT EXISTS source_checks (
+			source TEXT PRIMARY KEY,
+			checked_at DATETIME NOT NULL,
+			unchanged BOOLEAN NOT NULL DEFAULT 0
+		);`)},
+	{15, "add subscriptions.kind", addColumnMigration("subscriptions", "kind", "TEXT NOT NULL DEFAULT 'level'")},
+	{16, "create follows", execMigration(`
+		CREATE TABLE IF NOT EXISTS follows (
+			chat_id INTEGER NOT NULL,
+			river TEXT NOT NULL,
+			station TEXT NOT NULL,
+			last_level TEXT NOT NULL DEFAULT '',
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY(chat_id, river, station)
+		);`)},
+	{17, "create source_status", execMigration(`
+		CREATE TABLE IF NOT EXISTS source_status (
+			source TEXT PRIMARY KEY,
+			down BOOLEAN NOT NULL DEFAULT 0,
+			error TEXT NOT NULL DEFAULT '',
+			changed_at DATETIME NOT NULL,
+			announced_down BOOLEAN NOT NULL DEFAULT 0
+		);`)},
+	{18, "create source_watchers", execMigration(`
+		CREATE TABLE IF NOT EXISTS source_watchers (
+			chat_id INTEGER PRIMARY KEY,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		);`)},
+	// Rows written before timestamps were normalized hold the driver's default layout in
+	// local time, rewrite them as UTC RFC3339 so they sort correctly. Rows that would
+	// collide with an already normalized reading are left to the legacy fallback in
+	// parseStoredTimestamp.
+	{19, "normalize river_data timestamps", execMigration(`
+		UPDATE OR IGNORE river_data
+		SET timestamp = strftime('%Y-%m-%dT%H:%M:%SZ', timestamp)
+		WHERE timestamp NOT LIKE '%Z' AND strftime('%Y-%m-%dT%H:%M:%SZ', timestamp) IS NOT NULL`)},
+}
+
+// migrateSQLite applies the migrations a database hasn't run yet, each in its own
+// transaction together with recording its version in schema_migrations
+func migrateSQLite(db *sql.DB, migrations []migration) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at DATETIME NOT NULL
+		)`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %v", err)
+	}
+
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if applied[m.version] {
+			continue
+		}
+
+		tx, err := db.Begin()
+		if err != nil {
+			return fmt.Errorf("failed to begin migration %d: %v", m.version, err)
+		}
+		if err := m.apply(tx); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to apply migration %d (%s): %v", m.version, m.name, err)
+		}
+		_, err = tx.Exec(`INSERT INTO schema_migrations(version, name, applied_at) VALUES(?, ?, ?)`,
+			m.version, m.name, sqliteTimeArg(time.Now()))
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to record migration %d: %v", m.version, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit migration %d: %v", m.version, err)
+		}
+		slog.Info("Applied schema migration", "version", m.version, "name", m.name)
+	}
+	return nil
+}
+
+// appliedMigrations returns the versions recorded in schema_migrations
+func appliedMigrations(db *sql.DB) (map[int]bool, error) {
+	rows, err := db.Query(`SELECT version FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schema_migrations: %v", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]bool)
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, fmt.Errorf("failed to scan migration version: %v", err)
+		}
+		applied[version] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during row iteration: %v", err)
+	}
+	return applied, nil
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+)
+
+// schemaVersions returns how many migrations a database has recorded
+func schemaVersions(t *testing.T, db *sql.DB) int {
+	t.Helper()
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM schema_migrations`).Scan(&count); err != nil {
+		t.Fatalf("Failed to count migrations: %v", err)
+	}
+	return count
+}
+
+// TestMigrationsUpgradeOldDatabase verifies a database created before versioning is
+// brought to the latest version with its readings intact, and migrating again is a no-op
+func TestMigrationsUpgradeOldDatabase(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "old-riverdata.db")
+
+	// The schema as the first releases created it, before tendency, source and the other tables
+	old, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	_, err = old.Exec(`
+		CREATE TABLE river_data (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			river TEXT NOT NULL,
+			station TEXT NOT NULL,
+			water_level TEXT,
+			water_temp TEXT,
+			timestamp DATETIME DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(river, station, timestamp)
+		);
+		INSERT INTO river_data(river, station, water_level, water_temp, timestamp)
+		VALUES('ДРИНА', 'Радаљ', '142', '11.2', '2025-04-18T06:00:00Z');`)
+	if err != nil {
+		t.Fatalf("Failed to create old schema: %v", err)
+	}
+	old.Close()
+
+	repo, err := NewSQLiteRiverRepository(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open old database: %v", err)
+	}
+	defer repo.Close()
+
+	if got := schemaVersions(t, repo.db); got != len(sqliteMigrations) {
+		t.Errorf("Expected %d applied migrations, got %d", len(sqliteMigrations), got)
+	}
+	for _, column := range []string{"tendency", "source", "discharge", "url", "gauge_zero"} {
+		var count int
+		err := repo.db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info('river_data') WHERE name = ?`, column).Scan(&count)
+		if err != nil || count != 1 {
+			t.Errorf("Expected river_data.%s to exist, got count %d (err %v)", column, count, err)
+		}
+	}
+
+	data, err := repo.GetRiverDataByName("ДРИНА")
+	if err != nil {
+		t.Fatalf("Failed to read migrated data: %v", err)
+	}
+	if len(data) != 1 || data[0].WaterLevel != "142" {
+		t.Errorf("Expected the old reading to survive migration, got %+v", data)
+	}
+
+	if err := migrateSQLite(repo.db, sqliteMigrations); err != nil {
+		t.Fatalf("Second migration run failed: %v", err)
+	}
+	if got := schemaVersions(t, repo.db); got != len(sqliteMigrations) {
+		t.Errorf("Expected a second run to apply nothing, got %d migrations", got)
+	}
+}
+
+// TestFailedMigrationRollsBack verifies a failing migration leaves neither its changes
+// nor its version behind, so it is retried on the next start
+func TestFailedMigrationRollsBack(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+
+	broken := append(sqliteMigrations[:len(sqliteMigrations):len(sqliteMigrations)], migration{
+		version: len(sqliteMigrations) + 1,
+		name:    "broken",
+		apply: execMigration(`
+			CREATE TABLE half_done (id INTEGER);
+			INSERT INTO missing_table VALUES(1);`),
+	})
+	if err := migrateSQLite(repo.db, broken); err == nil {
+		t.Fatal("Expected the broken migration to fail")
+	}
+
+	if got := schemaVersions(t, repo.db); got != len(sqliteMigrations) {
+		t.Errorf("Expected the failed migration not to be recorded, got %d migrations", got)
+	}
+	var count int
+	if err := repo.db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE name = 'half_done'`).Scan(&count); err != nil {
+		t.Fatalf("Failed to query schema: %v", err)
+	}
+	if count != 0 {
+		t.Error("Expected the failed migration's table to be rolled back")
+	}
+}
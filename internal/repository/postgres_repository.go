@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/lib/pq"
+)
+
+// PostgresRiverRepository implements RiverRepository using PostgreSQL,
+// allowing the bot and scraper to share storage across hosts
+type PostgresRiverRepository struct {
+	sqlRiverRepository
+}
+
+// NewPostgresRiverRepository connects to PostgreSQL and initializes the schema
+func NewPostgresRiverRepository(dsn string) (*PostgresRiverRepository, error) {
+	slog.Info("Opening PostgreSQL database")
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %v", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect to database: %v", err)
+	}
+
+	// Timestamps are stored as TIMESTAMPTZ so no string parsing is needed on read
+	createTableSQL := `
+	CREATE TABLE IF NOT EXISTS river_data (
+		id BIGSERIAL PRIMARY KEY,
+		river TEXT NOT NULL,
+		station TEXT NOT NULL,
+		water_level TEXT,
+		water_temp TEXT,
+		discharge TEXT,
+		tendency TEXT,
+		source TEXT,
+		url TEXT,
+		gauge_zero TEXT,
+		timestamp TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		UNIQUE(river, station, timestamp)
+	);
+	ALTER TABLE river_data ADD COLUMN IF NOT EXISTS tendency TEXT;
+	ALTER TABLE river_data ADD COLUMN IF NOT EXISTS source TEXT;
+	ALTER TABLE river_data ADD COLUMN IF NOT EXISTS discharge TEXT;
+	ALTER TABLE river_data ADD COLUMN IF NOT EXISTS url TEXT;
+	ALTER TABLE river_data ADD COLUMN IF NOT EXISTS gauge_zero TEXT;
+	CREATE INDEX IF NOT EXISTS idx_river ON river_data(river);
+	CREATE INDEX IF NOT EXISTS idx_timestamp ON river_data(timestamp);
+
+	CREATE TABLE IF NOT EXISTS subscriptions (
+		id BIGSERIAL PRIMARY KEY,
+		chat_id BIGINT NOT NULL,
+		river TEXT NOT NULL,
+		threshold DOUBLE PRECISION NOT NULL,
+		direction TEXT NOT NULL,
+		triggered BOOLEAN NOT NULL DEFAULT FALSE,
+		last_triggered TIMESTAMPTZ,
+		created_at TIMESTAMPTZ DEFAULT NOW()
+	);
+	CREATE INDEX IF NOT EXISTS idx_subscriptions_chat ON subscriptions(chat_id);
+	ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS kind TEXT NOT NULL DEFAULT 'level';
+
+	CREATE TABLE IF NOT EXISTS daily_digests (
+		chat_id BIGINT PRIMARY KEY,
+		rivers TEXT NOT NULL,
+		send_hour INTEGER NOT NULL,
+		timezone TEXT NOT NULL,
+		created_at TIMESTAMPTZ DEFAULT NOW()
+	);
+
+	CREATE TABLE IF NOT EXISTS users (
+		chat_id BIGINT PRIMARY KEY,
+		username TEXT NOT NULL DEFAULT '',
+		language_code TEXT NOT NULL DEFAULT '',
+		first_seen TIMESTAMPTZ NOT NULL,
+		last_seen TIMESTAMPTZ NOT NULL,
+		time_zone TEXT NOT NULL DEFAULT ''
+	);
+	ALTER TABLE users ADD COLUMN IF NOT EXISTS time_zone TEXT NOT NULL DEFAULT '';
+
+	CREATE TABLE IF NOT EXISTS favorites (
+		chat_id BIGINT NOT NULL,
+		river TEXT NOT NULL,
+		created_at TIMESTAMPTZ DEFAULT NOW(),
+		PRIMARY KEY(chat_id, river)
+	);
+
+	CREATE TABLE IF NOT EXISTS flood_levels (
+		river TEXT NOT NULL,
+		station TEXT NOT NULL,
+		warning_cm DOUBLE PRECISION NOT NULL,
+		danger_cm DOUBLE PRECISION NOT NULL,
+		PRIMARY KEY(river, station)
+	);
+
+	CREATE TABLE IF NOT EXISTS source_checks (
+		source TEXT PRIMARY KEY,
+		checked_at TIMESTAMPTZ NOT NULL,
+		unchanged BOOLEAN NOT NULL DEFAULT FALSE
+	);
+
+	CREATE TABLE IF NOT EXISTS follows (
+		chat_id BIGINT NOT NULL,
+		river TEXT NOT NULL,
+		station TEXT NOT NULL,
+		last_level TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMPTZ DEFAULT NOW(),
+		PRIMARY KEY(chat_id, river, station)
+	);
+
+	CREATE TABLE IF NOT EXISTS source_status (
+		source TEXT PRIMARY KEY,
+		down BOOLEAN NOT NULL DEFAULT FALSE,
+		error TEXT NOT NULL DEFAULT '',
+		changed_at TIMESTAMPTZ NOT NULL,
+		announced_down BOOLEAN NOT NULL DEFAULT FALSE
+	);
+
+	CREATE TABLE IF NOT EXISTS source_watchers (
+		chat_id BIGINT PRIMARY KEY,
+		created_at TIMESTAMPTZ DEFAULT NOW()
+	);`
+
+	if _, err := db.Exec(createTableSQL); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create tables: %v", err)
+	}
+	if err := seedFloodLevels(db, rebindPostgres); err != nil {
+		db.Close()
+		return nil, err
+	}
+	// Without versioned migrations this runs on every start, it only touches names not yet normalized
+	if err := normalizeStoredRiverNames(db, rebindPostgres); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &PostgresRiverRepository{
+		sqlRiverRepository: sqlRiverRepository{
+			db:        db,
+			rebind:    rebindPostgres,
+			timeArg:   func(t time.Time) interface{} { return t },
+			maxParams: postgresMaxParams,
+		},
+	}, nil
+}
+
+// rebindPostgres converts "?" placeholders into PostgreSQL's numbered "$n" form
+func rebindPostgres(query string) string {
+	var b strings.Builder
+	n := 0
+	for _, ch := range query {
+		if ch == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(ch)
+	}
+	return b.String()
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// TestRebindPostgres verifies placeholder conversion to PostgreSQL syntax
+func TestRebindPostgres(t *testing.T) {
+	got := rebindPostgres("SELECT * FROM river_data WHERE river = ? AND station = ?")
+	want := "SELECT * FROM river_data WHERE river = $1 AND station = $2"
+	if got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+// newTestPostgresRepository connects to the database in POSTGRES_DSN or skips the test
+func newTestPostgresRepository(t *testing.T) *PostgresRiverRepository {
+	t.Helper()
+	dsn := os.Getenv("POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_DSN not set, skipping PostgreSQL integration test")
+	}
+
+	repo, err := NewPostgresRiverRepository(dsn)
+	if err != nil {
+		t.Fatalf("Failed to initialize PostgreSQL repository: %v", err)
+	}
+
+	cleanup := func() {
+		if _, err := repo.db.Exec(`DELETE FROM river_data WHERE river LIKE 'TEST-PG-%'`); err != nil {
+			t.Errorf("Failed to clean up test rows: %v", err)
+		}
+	}
+	cleanup()
+	t.Cleanup(func() {
+		cleanup()
+		repo.Close()
+	})
+	return repo
+}
+
+// TestPostgresRepositoryIntegration saves and reads back river data through PostgreSQL
+func TestPostgresRepositoryIntegration(t *testing.T) {
+	repo := newTestPostgresRepository(t)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	data := []entities.RiverData{
+		{River: "TEST-PG-DUNAV", Station: "STATION-1", WaterLevel: "100", WaterTemp: "15.5", Tendency: entities.Rising, Timestamp: now.Add(-time.Hour)},
+		{River: "TEST-PG-DUNAV", Station: "STATION-1", WaterLevel: "110", WaterTemp: "15.7", Tendency: entities.Rising, Timestamp: now},
+		{River: "TEST-PG-DUNAV", Station: "STATION-2", WaterLevel: "120", WaterTemp: "15.2", Tendency: entities.Falling, Timestamp: now},
+		{River: "TEST-PG-SAVA", Station: "STATION-3", WaterLevel: "80", WaterTemp: "14.0", Tendency: entities.Stable, Timestamp: now},
+	}
+	if err := repo.SaveRiverData(data); err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+
+	// Saving the same rows again must upsert rather than fail
+	if err := repo.SaveRiverData(data); err != nil {
+		t.Fatalf("Failed to re-save data: %v", err)
+	}
+
+	retrieved, err := repo.GetRiverDataByName("TEST-PG-DUNAV")
+	if err != nil {
+		t.Fatalf("Failed to retrieve river data: %v", err)
+	}
+	if len(retrieved) != 2 {
+		t.Fatalf("Expected latest reading for 2 stations, got %d", len(retrieved))
+	}
+	if retrieved[0].WaterLevel != "110" || !retrieved[0].Timestamp.Equal(now) {
+		t.Errorf("Expected latest STATION-1 reading 110 at %v, got %s at %v",
+			now, retrieved[0].WaterLevel, retrieved[0].Timestamp)
+	}
+	if retrieved[1].Tendency != entities.Falling {
+		t.Errorf("Expected STATION-2 tendency falling, got %s", retrieved[1].Tendency)
+	}
+
+	rivers, err := repo.GetUniqueRivers()
+	if err != nil {
+		t.Fatalf("Failed to get unique rivers: %v", err)
+	}
+	found := 0
+	for _, river := range rivers {
+		if river == "TEST-PG-DUNAV" || river == "TEST-PG-SAVA" {
+			found++
+		}
+	}
+	if found != 2 {
+		t.Errorf("Expected both test rivers in unique rivers, found %d", found)
+	}
+
+	lastUpdate, err := repo.GetLastUpdateTime()
+	if err != nil {
+		t.Fatalf("Failed to get last update time: %v", err)
+	}
+	if lastUpdate.Before(now) {
+		t.Errorf("Expected last update time at or after %v, got %v", now, lastUpdate)
+	}
+}
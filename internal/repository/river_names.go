@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// normalizeStoredRiverNames rewrites river names stored before names were normalized to
+// their canonical spelling, see entities.NormalizeRiverName. Readings that already exist
+// under the canonical name for the same station and time are duplicates and are dropped.
+func normalizeStoredRiverNames(db sqlExecutor, rebind func(string) string) error {
+	rows, err := db.Query(`SELECT DISTINCT river FROM river_data`)
+	if err != nil {
+		return fmt.Errorf("failed to query river names: %v", err)
+	}
+	var rivers []string
+	for rows.Next() {
+		var river string
+		if err := rows.Scan(&river); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan river name: %v", err)
+		}
+		rivers = append(rivers, river)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return fmt.Errorf("error during row iteration: %v", err)
+	}
+	rows.Close()
+
+	for _, river := range rivers {
+		canonical := entities.NormalizeRiverName(river)
+		if canonical == river {
+			continue
+		}
+		_, err := db.Exec(rebind(`
+			UPDATE river_data SET river = ?
+			WHERE river = ? AND NOT EXISTS (
+				SELECT 1 FROM river_data AS existing
+				WHERE existing.river = ? AND existing.station = river_data.station AND existing.timestamp = river_data.timestamp
+			)`), canonical, river, canonical)
+		if err != nil {
+			return fmt.Errorf("failed to rename river %q: %v", river, err)
+		}
+		if _, err := db.Exec(rebind(`DELETE FROM river_data WHERE river = ?`), river); err != nil {
+			return fmt.Errorf("failed to drop duplicate readings of river %q: %v", river, err)
+		}
+		slog.Info("Renamed stored river", "from", river, "to", canonical)
+	}
+	return nil
+}
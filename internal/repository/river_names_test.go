@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// TestMessyRiverNamesCollapse verifies river names differing only in case and spacing
+// are listed once, and stored ones are rewritten to the canonical name without duplicates
+func TestMessyRiverNamesCollapse(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+
+	recorded := time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC)
+	err := repo.SaveRiverData([]entities.RiverData{
+		{River: "ДРИНА", Station: "Радаљ", WaterLevel: "142", Timestamp: recorded},
+		{River: " ДРИНА ", Station: "Радаљ", WaterLevel: "142", Timestamp: recorded},
+		{River: "Дрина", Station: "Фоча", WaterLevel: "88", Timestamp: recorded},
+		{River: "ЗАПАДНА  МОРАВА", Station: "Краљево", WaterLevel: "120", Timestamp: recorded},
+	})
+	if err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+
+	rivers, err := repo.GetUniqueRivers()
+	if err != nil {
+		t.Fatalf("Failed to get rivers: %v", err)
+	}
+	if want := []string{"ДРИНА", "ЗАПАДНА МОРАВА"}; !reflect.DeepEqual(rivers, want) {
+		t.Errorf("Expected rivers %v, got %v", want, rivers)
+	}
+
+	if err := normalizeStoredRiverNames(repo.db, repo.rebind); err != nil {
+		t.Fatalf("Failed to normalize stored names: %v", err)
+	}
+	drina, err := repo.GetRiverDataByName("ДРИНА")
+	if err != nil {
+		t.Fatalf("Failed to get river data: %v", err)
+	}
+	if len(drina) != 2 {
+		t.Errorf("Expected Радаљ once and Фоча under ДРИНА, got %+v", drina)
+	}
+	count, err := repo.CountRows()
+	if err != nil {
+		t.Fatalf("Failed to count rows: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("Expected the duplicate Радаљ reading to be dropped, got %d rows", count)
+	}
+}
@@ -4,9 +4,13 @@ package repository
 import (
 	"database/sql"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/abelzeko/water-bot/internal/entities"
 	_ "github.com/mattn/go-sqlite3"
@@ -16,100 +20,385 @@ import (
 type RiverRepository interface {
 	SaveRiverData(data []entities.RiverData) error
 	GetRiverDataByName(riverName string) ([]entities.RiverData, error)
+	GetStationHistory(river, station string, since time.Time) ([]entities.RiverData, error)
+	GetStationLatest(river, station string) (entities.RiverData, bool, error)
+	GetLastTwoReadings(river, station string) ([]entities.RiverData, error)
+	GetByStation(station string) ([]entities.RiverData, error)
+	GetReadingNear(river, station string, t time.Time) (entities.RiverData, bool, error)
+	StreamRiverData(w io.Writer, river string, since time.Time) error
 	GetUniqueRivers() ([]string, error)
+	GetLatestReadings() ([]entities.RiverData, error)
+	GetTopStations(limit int) ([]entities.RiverData, error)
+	GetStationsByTempRange(min, max float64) ([]entities.RiverData, error)
+	GetStationsByDischarge(min float64) ([]entities.RiverData, error)
+	GetLastUpdateTime() (time.Time, error)
+	CountRows() (int64, error)
+	GetLastUpdateTimeBySource() (map[string]time.Time, error)
+
+	AddSubscription(sub entities.Subscription) (int64, error)
+	GetSubscriptionsByChat(chatID int64) ([]entities.Subscription, error)
+	GetAllSubscriptions() ([]entities.Subscription, error)
+	DeleteSubscription(chatID, id int64) (bool, error)
+	UpdateSubscriptionState(id int64, triggered bool, lastTriggered time.Time) error
+
+	GetDigest(chatID int64) (entities.Digest, bool, error)
+	SaveDigest(digest entities.Digest) error
+	DeleteDigest(chatID int64) (bool, error)
+	GetAllDigests() ([]entities.Digest, error)
+
+	UpsertUser(user entities.User) error
+	TouchUser(chatID int64, seen time.Time) error
+	GetUser(chatID int64) (entities.User, bool, error)
+	GetAllUsers() ([]entities.User, error)
+	DeleteUser(chatID int64) (bool, error)
+	ForgetUser(chatID int64) (int64, error)
+	SetUserTimeZone(chatID int64, timeZone string) error
+
+	AddFavorite(chatID int64, river string) (bool, error)
+	RemoveFavorite(chatID int64, river string) (bool, error)
+	GetFavorites(chatID int64) ([]string, error)
+
+	AddFollow(follow entities.Follow) (bool, error)
+	RemoveFollow(chatID int64, river, station string) (bool, error)
+	GetFollowsByChat(chatID int64) ([]entities.Follow, error)
+	GetAllFollows() ([]entities.Follow, error)
+	UpdateFollowLevel(chatID int64, river, station, level string) error
+
+	GetFloodLevels() ([]entities.FloodLevel, error)
+
+	SaveSourceCheck(check entities.SourceCheck) error
+	GetSourceChecks() (map[string]entities.SourceCheck, error)
+
+	UpdateSourceStatus(source string, down bool, errText string, at time.Time) error
+	GetSourceStatuses() ([]entities.SourceStatus, error)
+	SetSourceAnnounced(source string, down bool) error
+	AddSourceWatcher(chatID int64) (bool, error)
+	RemoveSourceWatcher(chatID int64) (bool, error)
+	GetSourceWatchers() ([]int64, error)
+
+	Ping() error
 	Close() error
 }
 
+// NewRiverRepository opens the storage backend selected by driver ("sqlite" or "postgres").
+// The dsn is only used by the postgres driver and dbPath only by SQLite, which uses its
+// default path when it is empty.
+func NewRiverRepository(driver, dsn, dbPath string) (RiverRepository, error) {
+	switch driver {
+	case "", "sqlite", "sqlite3":
+		return NewSQLiteRiverRepository(dbPath)
+	case "postgres", "postgresql":
+		if dsn == "" {
+			return nil, fmt.Errorf("postgres driver selected but no connection string provided")
+		}
+		return NewPostgresRiverRepository(dsn)
+	default:
+		return nil, fmt.Errorf("unsupported database driver: %s", driver)
+	}
+}
+
+// sqlRiverRepository implements the RiverRepository queries shared by all SQL backends.
+// Queries are written with "?" placeholders and rebound to the driver's syntax.
+type sqlRiverRepository struct {
+	db        *sql.DB
+	rebind    func(query string) string
+	timeArg   func(t time.Time) interface{}
+	maxParams int // Bound parameters allowed per statement
+}
+
 // SQLiteRiverRepository implements RiverRepository using SQLite
 type SQLiteRiverRepository struct {
-	db     *sql.DB
+	sqlRiverRepository
 	DBPath string
 }
 
-// NewSQLiteRiverRepository creates and initializes a new SQLite repository
-func NewSQLiteRiverRepository(dbPath string) (*SQLiteRiverRepository, error) {
+// sqliteConnParams configure every SQLite connection for the bot and the scraper
+// sharing one database file. WAL lets the other process read while this one
+// writes, at the cost of -wal and -shm files next to the database that must be
+// kept with it (and WAL doesn't work on network file systems). The busy timeout
+// makes a blocked writer wait up to 5s for the lock instead of failing with
+// "database is locked". Immediate transactions take the write lock up front so
+// they wait on it rather than failing when upgrading from a read.
+const sqliteConnParams = "?_busy_timeout=5000&_journal_mode=WAL&_txlock=immediate"
+
+// Default SQLite connection pool. WAL lets several connections read at once, so a
+// slow read such as a CSV export no longer holds up the others.
+const (
+	defaultSQLiteMaxOpenConns = 4
+	defaultSQLiteMaxIdleConns = 4
+)
+
+// sqliteOptions holds the settings applied by SQLiteOption values
+type sqliteOptions struct {
+	maxOpenConns int
+	maxIdleConns int
+}
+
+// SQLiteOption tunes a SQLite repository when it is created
+type SQLiteOption func(*sqliteOptions)
+
+// WithMaxOpenConns limits the open connections to the database, 1 serializes every query.
+// Writes are serialized by SQLite whatever the pool size: a writer waits up to the busy
+// timeout for another connection's write to finish.
+func WithMaxOpenConns(n int) SQLiteOption {
+	return func(o *sqliteOptions) {
+		o.maxOpenConns = n
+	}
+}
+
+// WithMaxIdleConns sets how many connections are kept open between queries
+func WithMaxIdleConns(n int) SQLiteOption {
+	return func(o *sqliteOptions) {
+		o.maxIdleConns = n
+	}
+}
+
+// NewSQLiteRiverRepository creates and initializes a new SQLite repository.
+// An empty dbPath falls back to data/riverdata.db.
+// Without options the connection pool allows defaultSQLiteMaxOpenConns concurrent readers.
+func NewSQLiteRiverRepository(dbPath string, opts ...SQLiteOption) (*SQLiteRiverRepository, error) {
+	options := sqliteOptions{
+		maxOpenConns: defaultSQLiteMaxOpenConns,
+		maxIdleConns: defaultSQLiteMaxIdleConns,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	if dbPath == "" {
 		// Set default path if not specified
-		dbDir := "data"
-		if err := os.MkdirAll(dbDir, 0755); err != nil {
-			return nil, fmt.Errorf("failed to create database directory: %v", err)
-		}
-		dbPath = filepath.Join(dbDir, "riverdata.db")
+		dbPath = filepath.Join("data", "riverdata.db")
+	}
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create database directory: %v", err)
 	}
 
-	log.Printf("Opening database at %s", dbPath)
-	db, err := sql.Open("sqlite3", dbPath)
+	slog.Info("Opening database", "path", dbPath)
+	db, err := sql.Open("sqlite3", dbPath+sqliteConnParams)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %v", err)
 	}
+	// Readers run concurrently under WAL, writers still take turns on the database lock
+	db.SetMaxOpenConns(options.maxOpenConns)
+	db.SetMaxIdleConns(options.maxIdleConns)
 
-	// Create river_data table if it doesn't exist
-	createTableSQL := `
-	CREATE TABLE IF NOT EXISTS river_data (
-		id INTEGER PRIMARY KEY AUTOINCREMENT,
-		river TEXT NOT NULL,
-		station TEXT NOT NULL,
-		water_level TEXT,
-		water_temp TEXT,
-		timestamp DATETIME DEFAULT CURRENT_TIMESTAMP,
-		UNIQUE(river, station, timestamp)
-	);
-	CREATE INDEX IF NOT EXISTS idx_river ON river_data(river);
-	CREATE INDEX IF NOT EXISTS idx_timestamp ON river_data(timestamp);`
-
-	_, err = db.Exec(createTableSQL)
-	if err != nil {
+	// Bring the schema up to date, a new database runs every migration
+	if err := migrateSQLite(db, sqliteMigrations); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if err := seedFloodLevels(db, func(query string) string { return query }); err != nil {
 		db.Close()
-		return nil, fmt.Errorf("failed to create tables: %v", err)
+		return nil, err
 	}
 
 	return &SQLiteRiverRepository{
-		db:     db,
+		sqlRiverRepository: sqlRiverRepository{
+			db:        db,
+			rebind:    func(query string) string { return query },
+			timeArg:   sqliteTimeArg,
+			maxParams: sqliteMaxParams,
+		},
 		DBPath: dbPath,
 	}, nil
 }
 
+// sqliteTimestampLayout is the single layout used for timestamps stored in SQLite
+const sqliteTimestampLayout = time.RFC3339
+
+// legacyTimestampLayout is how the SQLite driver serialized time.Time values before
+// timestamps were formatted explicitly
+const legacyTimestampLayout = "2006-01-02 15:04:05.999999999-07:00"
+
+// sqliteTimeArg formats a timestamp for binding so SQLite always stores UTC RFC3339 text
+func sqliteTimeArg(t time.Time) interface{} {
+	return t.UTC().Format(sqliteTimestampLayout)
+}
+
+// parseStoredTimestamp parses a timestamp read back as text from the database
+func parseStoredTimestamp(value string) (time.Time, error) {
+	if t, err := time.Parse(sqliteTimestampLayout, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(legacyTimestampLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unrecognized timestamp format %q", value)
+	}
+	return t.UTC(), nil
+}
+
+// dbTime scans a timestamp column regardless of whether the driver returns it
+// as a native time value (PostgreSQL, typed SQLite columns) or as text (SQLite aggregates)
+type dbTime struct {
+	Time time.Time
+}
+
+// Scan implements the sql.Scanner interface
+func (t *dbTime) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		t.Time = time.Time{}
+	case time.Time:
+		t.Time = v
+	case string:
+		parsed, err := parseStoredTimestamp(v)
+		if err != nil {
+			return err
+		}
+		t.Time = parsed
+	case []byte:
+		parsed, err := parseStoredTimestamp(string(v))
+		if err != nil {
+			return err
+		}
+		t.Time = parsed
+	default:
+		return fmt.Errorf("unsupported timestamp type %T", value)
+	}
+	return nil
+}
+
+// ensureColumn adds a column to an existing table unless it is already present
+func ensureColumn(db sqlExecutor, table, column, definition string) error {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return fmt.Errorf("failed to inspect table %s: %v", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid        int
+			name       string
+			columnType string
+			notNull    int
+			dfltValue  sql.NullString
+			primaryKey int
+		)
+		if err := rows.Scan(&cid, &name, &columnType, &notNull, &dfltValue, &primaryKey); err != nil {
+			return fmt.Errorf("failed to scan table info for %s: %v", table, err)
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error during table info iteration: %v", err)
+	}
+
+	slog.Info("Adding column", "table", table, "column", column)
+	if _, err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)); err != nil {
+		return fmt.Errorf("failed to add column %s to %s: %v", column, table, err)
+	}
+	return nil
+}
+
+// Ping checks that the database is reachable
+func (r *sqlRiverRepository) Ping() error {
+	if err := r.db.Ping(); err != nil {
+		return fmt.Errorf("database ping failed: %v", err)
+	}
+	return nil
+}
+
 // Close closes the database connection
-func (r *SQLiteRiverRepository) Close() error {
+func (r *sqlRiverRepository) Close() error {
 	if r.db != nil {
 		return r.db.Close()
 	}
 	return nil
 }
 
-// SaveRiverData stores river data in the database
-func (r *SQLiteRiverRepository) SaveRiverData(data []entities.RiverData) error {
+// saveBatchSize is how many readings SaveRiverData inserts per statement
+const saveBatchSize = 100
+
+// riverDataInsertColumns is the number of parameters bound per inserted reading
+const riverDataInsertColumns = 10
+
+// Bound parameter limits of the supported databases
+const (
+	sqliteMaxParams   = 999 // SQLITE_MAX_VARIABLE_NUMBER of SQLite before 3.32
+	postgresMaxParams = 65535
+)
+
+// insertRiverDataSQL builds an upsert of rows readings
+func insertRiverDataSQL(rows int) string {
+	placeholders := make([]string, rows)
+	for i := range placeholders {
+		placeholders[i] = "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	}
+	return `
+		INSERT INTO river_data(river, station, water_level, water_temp, discharge, tendency, source, url, gauge_zero, timestamp)
+		VALUES ` + strings.Join(placeholders, ", ") + `
+		ON CONFLICT(river, station, timestamp) DO UPDATE SET
+		water_level=excluded.water_level,
+		water_temp=excluded.water_temp,
+		discharge=excluded.discharge,
+		tendency=excluded.tendency,
+		source=excluded.source,
+		url=excluded.url,
+		gauge_zero=excluded.gauge_zero`
+}
+
+// SaveRiverData stores river data in the database. Readings are inserted in
+// batches of up to saveBatchSize rows per statement, or one row at a time when
+// a batch would exceed the database's parameter limit.
+func (r *sqlRiverRepository) SaveRiverData(data []entities.RiverData) error {
+	// A statement may not update the same row twice, keep the last reading per key
+	data = dedupeReadings(data)
+
+	batchSize := saveBatchSize
+	if r.maxParams > 0 && batchSize*riverDataInsertColumns > r.maxParams {
+		batchSize = r.maxParams / riverDataInsertColumns
+	}
+	if batchSize < 1 {
+		batchSize = 1
+	}
+
 	tx, err := r.db.Begin()
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %v", err)
 	}
 
-	// Prepare SQL statement for inserting data
-	stmt, err := tx.Prepare(`
-		INSERT INTO river_data(river, station, water_level, water_temp, timestamp)
-		VALUES(?, ?, ?, ?, ?)
-		ON CONFLICT(river, station, timestamp) DO UPDATE SET
-		water_level=excluded.water_level,
-		water_temp=excluded.water_temp
-	`)
+	// Prepare the full batch statement once, the last partial batch gets its own
+	stmt, err := tx.Prepare(r.rebind(insertRiverDataSQL(batchSize)))
 	if err != nil {
 		tx.Rollback()
 		return fmt.Errorf("failed to prepare statement: %v", err)
 	}
 	defer stmt.Close()
 
-	// Insert each river data record
-	for _, rd := range data {
-		_, err := stmt.Exec(
-			rd.River,
-			rd.Station,
-			rd.WaterLevel,
-			rd.WaterTemp,
-			rd.Timestamp,
-		)
+	for start := 0; start < len(data); start += batchSize {
+		end := start + batchSize
+		if end > len(data) {
+			end = len(data)
+		}
+		batch := data[start:end]
+
+		args := make([]interface{}, 0, len(batch)*riverDataInsertColumns)
+		for _, rd := range batch {
+			args = append(args,
+				rd.River,
+				rd.Station,
+				rd.WaterLevel,
+				rd.WaterTemp,
+				rd.Discharge,
+				string(rd.Tendency),
+				rd.Source,
+				rd.URL,
+				rd.GaugeZero,
+				r.timeArg(rd.Timestamp),
+			)
+		}
+
+		if len(batch) == batchSize {
+			_, err = stmt.Exec(args...)
+		} else {
+			_, err = tx.Exec(r.rebind(insertRiverDataSQL(len(batch))), args...)
+		}
 		if err != nil {
 			tx.Rollback()
-			return fmt.Errorf("failed to insert data for %s at %s: %v", rd.River, rd.Station, err)
+			return fmt.Errorf("failed to insert data for %s at %s: %v", batch[0].River, batch[0].Station, err)
 		}
 	}
 
@@ -117,15 +406,35 @@ func (r *SQLiteRiverRepository) SaveRiverData(data []entities.RiverData) error {
 		return fmt.Errorf("failed to commit transaction: %v", err)
 	}
 
-	log.Printf("Successfully saved %d river data records", len(data))
+	slog.Debug("Saved river data records", "rows", len(data))
 	return nil
 }
 
+// dedupeReadings keeps the last reading for each river, station and timestamp
+func dedupeReadings(data []entities.RiverData) []entities.RiverData {
+	type key struct {
+		river, station string
+		timestamp      int64
+	}
+	index := make(map[key]int, len(data))
+	result := make([]entities.RiverData, 0, len(data))
+	for _, rd := range data {
+		k := key{rd.River, rd.Station, rd.Timestamp.UnixNano()}
+		if i, ok := index[k]; ok {
+			result[i] = rd
+			continue
+		}
+		index[k] = len(result)
+		result = append(result, rd)
+	}
+	return result
+}
+
 // GetRiverDataByName retrieves data for a specific river
-func (r *SQLiteRiverRepository) GetRiverDataByName(riverName string) ([]entities.RiverData, error) {
+func (r *sqlRiverRepository) GetRiverDataByName(riverName string) ([]entities.RiverData, error) {
 	// Using subquery to get only the most recent data for each station
 	query := `
-		SELECT id, river, station, water_level, water_temp, timestamp
+		SELECT ` + riverDataColumns + `
 		FROM river_data
 		WHERE river = ? AND (river, station, timestamp) IN (
 			SELECT river, station, MAX(timestamp) 
@@ -135,24 +444,25 @@ func (r *SQLiteRiverRepository) GetRiverDataByName(riverName string) ([]entities
 		)
 		ORDER BY station`
 
-	rows, err := r.db.Query(query, riverName, riverName)
+	rows, err := r.db.Query(r.rebind(query), riverName, riverName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query river data for %s: %v", riverName, err)
 	}
 	defer rows.Close()
 
+	return scanRiverData(rows)
+}
+
+// riverDataColumns is the column list expected by scanRiverData
+const riverDataColumns = `id, river, station, water_level, water_temp, COALESCE(discharge, ''), COALESCE(tendency, ''), COALESCE(source, ''), COALESCE(url, ''), COALESCE(gauge_zero, ''), timestamp`
+
+// scanRiverData reads all rows selected with riverDataColumns
+func scanRiverData(rows *sql.Rows) ([]entities.RiverData, error) {
 	var result []entities.RiverData
 	for rows.Next() {
-		var rd entities.RiverData
-		if err := rows.Scan(
-			&rd.ID,
-			&rd.River,
-			&rd.Station,
-			&rd.WaterLevel,
-			&rd.WaterTemp,
-			&rd.Timestamp,
-		); err != nil {
-			return nil, fmt.Errorf("failed to scan row: %v", err)
+		rd, err := scanRiverDataRow(rows)
+		if err != nil {
+			return nil, err
 		}
 		result = append(result, rd)
 	}
@@ -164,8 +474,166 @@ func (r *SQLiteRiverRepository) GetRiverDataByName(riverName string) ([]entities
 	return result, nil
 }
 
+// scanRiverDataRow reads the current row selected with riverDataColumns.
+// NULL levels and temperatures are read as empty strings.
+func scanRiverDataRow(rows *sql.Rows) (entities.RiverData, error) {
+	var rd entities.RiverData
+	var waterLevel, waterTemp sql.NullString
+	var tendency string
+	var timestamp dbTime
+	if err := rows.Scan(
+		&rd.ID,
+		&rd.River,
+		&rd.Station,
+		&waterLevel,
+		&waterTemp,
+		&rd.Discharge,
+		&tendency,
+		&rd.Source,
+		&rd.URL,
+		&rd.GaugeZero,
+		&timestamp,
+	); err != nil {
+		return entities.RiverData{}, fmt.Errorf("failed to scan row: %v", err)
+	}
+	rd.WaterLevel = waterLevel.String
+	rd.WaterTemp = waterTemp.String
+	rd.Tendency = entities.ParseTendency(tendency)
+	rd.Timestamp = timestamp.Time
+	return rd, nil
+}
+
+// GetStationHistory returns all readings for a station since the given time, oldest first
+func (r *sqlRiverRepository) GetStationHistory(river, station string, since time.Time) ([]entities.RiverData, error) {
+	query := `
+		SELECT ` + riverDataColumns + `
+		FROM river_data
+		WHERE river = ? AND station = ? AND timestamp >= ?
+		ORDER BY timestamp`
+
+	rows, err := r.db.Query(r.rebind(query), river, station, r.timeArg(since))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query history for %s at %s: %v", river, station, err)
+	}
+	defer rows.Close()
+
+	return scanRiverData(rows)
+}
+
+// GetStationLatest returns the most recent reading of a station, reporting false when it has none
+func (r *sqlRiverRepository) GetStationLatest(river, station string) (entities.RiverData, bool, error) {
+	query := `
+		SELECT ` + riverDataColumns + `
+		FROM river_data
+		WHERE river = ? AND station = ?
+		ORDER BY timestamp DESC
+		LIMIT 1`
+
+	rows, err := r.db.Query(r.rebind(query), river, station)
+	if err != nil {
+		return entities.RiverData{}, false, fmt.Errorf("failed to query latest reading for %s at %s: %v", river, station, err)
+	}
+	defer rows.Close()
+
+	data, err := scanRiverData(rows)
+	if err != nil {
+		return entities.RiverData{}, false, err
+	}
+	if len(data) == 0 {
+		return entities.RiverData{}, false, nil
+	}
+	return data[0], true, nil
+}
+
+// GetLastTwoReadings returns the two newest readings of a station, newest first. Fewer
+// are returned when the station has stored only one reading or none.
+func (r *sqlRiverRepository) GetLastTwoReadings(river, station string) ([]entities.RiverData, error) {
+	query := `
+		SELECT ` + riverDataColumns + `
+		FROM river_data
+		WHERE river = ? AND station = ?
+		ORDER BY timestamp DESC
+		LIMIT 2`
+
+	rows, err := r.db.Query(r.rebind(query), river, station)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query last readings for %s at %s: %v", river, station, err)
+	}
+	defer rows.Close()
+
+	return scanRiverData(rows)
+}
+
+// GetByStation returns the latest reading of every station with the given name on any
+// river, ordered by river. Names are compared case-insensitively, which SQLite's LOWER
+// doesn't do for Cyrillic, so the latest readings are filtered here rather than in SQL.
+func (r *sqlRiverRepository) GetByStation(station string) ([]entities.RiverData, error) {
+	latest, err := r.GetLatestReadings()
+	if err != nil {
+		return nil, err
+	}
+
+	station = strings.TrimSpace(station)
+	var result []entities.RiverData
+	for _, rd := range latest {
+		if strings.EqualFold(rd.Station, station) {
+			result = append(result, rd)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].River < result[j].River })
+	return result, nil
+}
+
+// GetReadingNear returns the reading of a station closest in time to t, on either side
+// of it, reporting false when the station has none
+func (r *sqlRiverRepository) GetReadingNear(river, station string, t time.Time) (entities.RiverData, bool, error) {
+	before, foundBefore, err := r.stationReading(river, station, `timestamp <= ? ORDER BY timestamp DESC`, t)
+	if err != nil {
+		return entities.RiverData{}, false, err
+	}
+	after, foundAfter, err := r.stationReading(river, station, `timestamp >= ? ORDER BY timestamp`, t)
+	if err != nil {
+		return entities.RiverData{}, false, err
+	}
+
+	switch {
+	case !foundBefore:
+		return after, foundAfter, nil
+	case !foundAfter:
+		return before, true, nil
+	case after.Timestamp.Sub(t) < t.Sub(before.Timestamp):
+		return after, true, nil
+	default:
+		return before, true, nil
+	}
+}
+
+// stationReading returns the first reading of a station matching a timestamp condition and order
+func (r *sqlRiverRepository) stationReading(river, station, condition string, t time.Time) (entities.RiverData, bool, error) {
+	query := `
+		SELECT ` + riverDataColumns + `
+		FROM river_data
+		WHERE river = ? AND station = ? AND ` + condition + `
+		LIMIT 1`
+
+	rows, err := r.db.Query(r.rebind(query), river, station, r.timeArg(t))
+	if err != nil {
+		return entities.RiverData{}, false, fmt.Errorf("failed to query reading near %v for %s at %s: %v", t, river, station, err)
+	}
+	defer rows.Close()
+
+	data, err := scanRiverData(rows)
+	if err != nil {
+		return entities.RiverData{}, false, err
+	}
+	if len(data) == 0 {
+		return entities.RiverData{}, false, nil
+	}
+	return data[0], true, nil
+}
+
 // GetUniqueRivers returns a list of all unique river names in the database
-func (r *SQLiteRiverRepository) GetUniqueRivers() ([]string, error) {
+func (r *sqlRiverRepository) GetUniqueRivers() ([]string, error) {
 	// Subquery to get only the most recent river data
 	query := `
 		SELECT DISTINCT river
@@ -183,18 +651,212 @@ func (r *SQLiteRiverRepository) GetUniqueRivers() ([]string, error) {
 	}
 	defer rows.Close()
 
+	// Names differing only in case or spacing are one river, listed under its canonical name
 	var rivers []string
+	canonical := make(map[string]bool)
 	for rows.Next() {
 		var river string
 		if err := rows.Scan(&river); err != nil {
 			return nil, fmt.Errorf("failed to scan row: %v", err)
 		}
-		rivers = append(rivers, river)
+		name := entities.NormalizeRiverName(river)
+		if canonical[name] {
+			continue
+		}
+		canonical[name] = true
+		rivers = append(rivers, name)
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("error during row iteration: %v", err)
 	}
 
+	sort.Strings(rivers)
 	return rivers, nil
 }
+
+// GetTopStations returns the latest reading of the stations with the highest water level,
+// highest first. Levels are compared numerically and readings without a numeric level are
+// skipped. A limit of zero or less returns every station.
+func (r *sqlRiverRepository) GetTopStations(limit int) ([]entities.RiverData, error) {
+	latest, err := r.GetLatestReadings()
+	if err != nil {
+		return nil, err
+	}
+
+	// Levels are stored as text, so sort them numerically here rather than in SQL
+	type rankedReading struct {
+		data  entities.RiverData
+		level float64
+	}
+	var ranked []rankedReading
+	for _, rd := range latest {
+		level, ok := rd.LevelValue()
+		if !ok {
+			continue
+		}
+		ranked = append(ranked, rankedReading{data: rd, level: level})
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].level != ranked[j].level {
+			return ranked[i].level > ranked[j].level
+		}
+		if ranked[i].data.River != ranked[j].data.River {
+			return ranked[i].data.River < ranked[j].data.River
+		}
+		return ranked[i].data.Station < ranked[j].data.Station
+	})
+
+	if limit > 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+
+	result := make([]entities.RiverData, len(ranked))
+	for i, r := range ranked {
+		result[i] = r.data
+	}
+	return result, nil
+}
+
+// GetStationsByTempRange returns the latest reading of the stations whose water temperature
+// is between min and max inclusive, coldest first. Readings without a numeric temperature are skipped.
+func (r *sqlRiverRepository) GetStationsByTempRange(min, max float64) ([]entities.RiverData, error) {
+	latest, err := r.GetLatestReadings()
+	if err != nil {
+		return nil, err
+	}
+
+	type tempReading struct {
+		data entities.RiverData
+		temp float64
+	}
+	var matching []tempReading
+	for _, rd := range latest {
+		temp, ok := rd.TempValue()
+		if !ok || temp < min || temp > max {
+			continue
+		}
+		matching = append(matching, tempReading{data: rd, temp: temp})
+	}
+	sort.SliceStable(matching, func(i, j int) bool {
+		if matching[i].temp != matching[j].temp {
+			return matching[i].temp < matching[j].temp
+		}
+		if matching[i].data.River != matching[j].data.River {
+			return matching[i].data.River < matching[j].data.River
+		}
+		return matching[i].data.Station < matching[j].data.Station
+	})
+
+	result := make([]entities.RiverData, len(matching))
+	for i, m := range matching {
+		result[i] = m.data
+	}
+	return result, nil
+}
+
+// GetStationsByDischarge returns the stations whose current discharge is at least min m³/s,
+// highest first. Stations without a discharge reading are skipped.
+func (r *sqlRiverRepository) GetStationsByDischarge(min float64) ([]entities.RiverData, error) {
+	latest, err := r.GetLatestReadings()
+	if err != nil {
+		return nil, err
+	}
+
+	type dischargeReading struct {
+		data      entities.RiverData
+		discharge float64
+	}
+	var matching []dischargeReading
+	for _, rd := range latest {
+		discharge, ok := rd.DischargeValue()
+		if !ok || discharge < min {
+			continue
+		}
+		matching = append(matching, dischargeReading{data: rd, discharge: discharge})
+	}
+	sort.SliceStable(matching, func(i, j int) bool {
+		if matching[i].discharge != matching[j].discharge {
+			return matching[i].discharge > matching[j].discharge
+		}
+		if matching[i].data.River != matching[j].data.River {
+			return matching[i].data.River < matching[j].data.River
+		}
+		return matching[i].data.Station < matching[j].data.Station
+	})
+
+	result := make([]entities.RiverData, len(matching))
+	for i, m := range matching {
+		result[i] = m.data
+	}
+	return result, nil
+}
+
+// GetLatestReadings returns the most recent reading of every station
+func (r *sqlRiverRepository) GetLatestReadings() ([]entities.RiverData, error) {
+	query := `
+		SELECT ` + riverDataColumns + `
+		FROM river_data
+		WHERE (river, station, timestamp) IN (
+			SELECT river, station, MAX(timestamp)
+			FROM river_data
+			GROUP BY river, station
+		)`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query latest readings: %v", err)
+	}
+	defer rows.Close()
+
+	return scanRiverData(rows)
+}
+
+// CountRows returns the number of stored readings
+func (r *sqlRiverRepository) CountRows() (int64, error) {
+	var count int64
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM river_data`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count readings: %v", err)
+	}
+	return count, nil
+}
+
+// GetLastUpdateTime returns the timestamp of the most recent reading, or the zero time if there is none
+func (r *sqlRiverRepository) GetLastUpdateTime() (time.Time, error) {
+	var lastUpdate dbTime
+	if err := r.db.QueryRow(`SELECT MAX(timestamp) FROM river_data`).Scan(&lastUpdate); err != nil {
+		return time.Time{}, fmt.Errorf("failed to query last update time: %v", err)
+	}
+	return lastUpdate.Time, nil
+}
+
+// GetLastUpdateTimeBySource returns the timestamp of the most recent reading from each source
+func (r *sqlRiverRepository) GetLastUpdateTimeBySource() (map[string]time.Time, error) {
+	query := `
+		SELECT source, MAX(timestamp)
+		FROM river_data
+		WHERE source IS NOT NULL AND source <> ''
+		GROUP BY source`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query last update time by source: %v", err)
+	}
+	defer rows.Close()
+
+	result := make(map[string]time.Time)
+	for rows.Next() {
+		var source string
+		var lastUpdate dbTime
+		if err := rows.Scan(&source, &lastUpdate); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %v", err)
+		}
+		result[source] = lastUpdate.Time
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during row iteration: %v", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,693 @@
+package repository
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// newTestSQLiteRepository creates a repository backed by a temporary database file
+func newTestSQLiteRepository(t *testing.T) *SQLiteRiverRepository {
+	t.Helper()
+	repo, err := NewSQLiteRiverRepository(filepath.Join(t.TempDir(), "test-riverdata.db"))
+	if err != nil {
+		t.Fatalf("Failed to initialize repository: %v", err)
+	}
+	t.Cleanup(func() { repo.Close() })
+	return repo
+}
+
+// TestCountRows verifies every stored reading is counted, not only the latest per station
+func TestCountRows(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+
+	count, err := repo.CountRows()
+	if err != nil {
+		t.Fatalf("Failed to count rows: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("Expected an empty database, got %d rows", count)
+	}
+
+	if err := repo.SaveRiverData(generateReadings(12)); err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+	count, err = repo.CountRows()
+	if err != nil {
+		t.Fatalf("Failed to count rows: %v", err)
+	}
+	if count != 12 {
+		t.Errorf("Expected 12 rows, got %d", count)
+	}
+}
+
+// TestGetStationLatest verifies the newest reading of a station is returned and unknown stations are reported
+func TestGetStationLatest(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+	readings := generateReadings(12)
+	if err := repo.SaveRiverData(readings); err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+
+	want := readings[len(readings)-1]
+	latest, found, err := repo.GetStationLatest(want.River, want.Station)
+	if err != nil {
+		t.Fatalf("Failed to get latest reading: %v", err)
+	}
+	if !found {
+		t.Fatal("Expected a reading to be found")
+	}
+	if !latest.Timestamp.Equal(want.Timestamp) || latest.WaterLevel != want.WaterLevel {
+		t.Errorf("Expected %s cm at %v, got %s cm at %v", want.WaterLevel, want.Timestamp, latest.WaterLevel, latest.Timestamp)
+	}
+
+	if _, found, err := repo.GetStationLatest(want.River, "NOWHERE"); err != nil || found {
+		t.Errorf("Expected no reading for an unknown station, got found=%v err=%v", found, err)
+	}
+}
+
+// TestGetLastTwoReadings verifies the two newest readings of a station are returned
+// newest first, and stations with fewer readings return what they have
+func TestGetLastTwoReadings(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+	readings := generateReadings(12)
+	if err := repo.SaveRiverData(readings); err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+
+	// Станица 0 has readings at 0, 1 and 2 hours, the newest two are 110 and 105 cm
+	last, err := repo.GetLastTwoReadings("ГРАДАЦ", "Станица 0")
+	if err != nil {
+		t.Fatalf("Failed to get last readings: %v", err)
+	}
+	if len(last) != 2 || last[0].WaterLevel != "110" || last[1].WaterLevel != "105" || !last[0].Timestamp.After(last[1].Timestamp) {
+		t.Errorf("Expected 110 cm then 105 cm, got %+v", last)
+	}
+
+	single := entities.RiverData{River: "ДРИНА", Station: "Радаљ", WaterLevel: "142", Timestamp: time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC)}
+	if err := repo.SaveRiverData([]entities.RiverData{single}); err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+	if last, err := repo.GetLastTwoReadings("ДРИНА", "Радаљ"); err != nil || len(last) != 1 {
+		t.Errorf("Expected the station's only reading, got %d, %v", len(last), err)
+	}
+	if last, err := repo.GetLastTwoReadings("ДРИНА", "NOWHERE"); err != nil || len(last) != 0 {
+		t.Errorf("Expected no readings for an unknown station, got %d, %v", len(last), err)
+	}
+}
+
+// TestGetByStation verifies a station name finds the latest reading on every river it is on
+func TestGetByStation(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+
+	base := time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC)
+	err := repo.SaveRiverData([]entities.RiverData{
+		{River: "САВА", Station: "Шабац", WaterLevel: "310", Timestamp: base.Add(-time.Hour)},
+		{River: "САВА", Station: "Шабац", WaterLevel: "312", Timestamp: base},
+		{River: "ДРИНА", Station: "ШАБАЦ", WaterLevel: "95", Timestamp: base},
+		{River: "ДРИНА", Station: "Радаљ", WaterLevel: "142", Timestamp: base},
+	})
+	if err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+
+	matches, err := repo.GetByStation("шабац")
+	if err != nil {
+		t.Fatalf("Failed to get station: %v", err)
+	}
+	if len(matches) != 2 || matches[0].River != "ДРИНА" || matches[1].River != "САВА" || matches[1].WaterLevel != "312" {
+		t.Errorf("Expected the latest readings on ДРИНА and САВА, got %+v", matches)
+	}
+
+	if matches, err := repo.GetByStation("Зворник"); err != nil || len(matches) != 0 {
+		t.Errorf("Expected no readings for an unknown station, got %+v (err: %v)", matches, err)
+	}
+}
+
+// TestGetReadingNear verifies the reading closest to a target time is found on either side of it
+func TestGetReadingNear(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+
+	// Readings at irregular intervals, as the sources publish them
+	base := time.Date(2025, 4, 17, 0, 0, 0, 0, time.UTC)
+	offsets := []time.Duration{0, 3 * time.Hour, 4 * time.Hour, 10 * time.Hour, 24 * time.Hour}
+	var readings []entities.RiverData
+	for i, offset := range offsets {
+		readings = append(readings, entities.RiverData{
+			River: "ДРИНА", Station: "Радаљ", WaterLevel: strconv.Itoa(100 + i), Timestamp: base.Add(offset),
+		})
+	}
+	if err := repo.SaveRiverData(readings); err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		target time.Duration
+		want   string
+	}{
+		{"exact match", 3 * time.Hour, "101"},
+		{"closer to the earlier reading", 5 * time.Hour, "102"},
+		{"closer to the later reading", 8 * time.Hour, "103"},
+		{"before the first reading", -6 * time.Hour, "100"},
+		{"after the last reading", 48 * time.Hour, "104"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reading, found, err := repo.GetReadingNear("ДРИНА", "Радаљ", base.Add(tt.target))
+			if err != nil {
+				t.Fatalf("Failed to get reading: %v", err)
+			}
+			if !found || reading.WaterLevel != tt.want {
+				t.Errorf("Expected %s cm, got %s cm (found: %v)", tt.want, reading.WaterLevel, found)
+			}
+		})
+	}
+
+	if _, found, err := repo.GetReadingNear("ДРИНА", "NOWHERE", base); err != nil || found {
+		t.Errorf("Expected no reading for an unknown station, got found=%v err=%v", found, err)
+	}
+}
+
+// TestGetLastUpdateTimeRoundTrip verifies timestamps in any zone are read back as the same instant
+func TestGetLastUpdateTimeRoundTrip(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+
+	belgrade, err := time.LoadLocation("Europe/Belgrade")
+	if err != nil {
+		t.Fatalf("Failed to load location: %v", err)
+	}
+	older := time.Date(2025, 4, 18, 8, 0, 0, 0, belgrade)
+	newer := time.Date(2025, 4, 18, 7, 30, 0, 0, time.UTC) // 09:30 in Belgrade
+
+	data := []entities.RiverData{
+		{River: "ДУНАВ", Station: "БЕЗДАН", WaterLevel: "300", Timestamp: older},
+		{River: "ДУНАВ", Station: "БЕЗДАН", WaterLevel: "305", Timestamp: newer},
+	}
+	if err := repo.SaveRiverData(data); err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+
+	lastUpdate, err := repo.GetLastUpdateTime()
+	if err != nil {
+		t.Fatalf("Failed to get last update time: %v", err)
+	}
+	if !lastUpdate.Equal(newer) {
+		t.Errorf("Expected last update %v, got %v", newer, lastUpdate)
+	}
+
+	retrieved, err := repo.GetRiverDataByName("ДУНАВ")
+	if err != nil {
+		t.Fatalf("Failed to retrieve river data: %v", err)
+	}
+	if len(retrieved) != 1 || retrieved[0].WaterLevel != "305" || !retrieved[0].Timestamp.Equal(newer) {
+		t.Errorf("Expected the latest reading 305 at %v, got %+v", newer, retrieved)
+	}
+}
+
+// TestGetLastUpdateTimeEmpty verifies an empty database reports the zero time
+func TestGetLastUpdateTimeEmpty(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+
+	lastUpdate, err := repo.GetLastUpdateTime()
+	if err != nil {
+		t.Fatalf("Failed to get last update time: %v", err)
+	}
+	if !lastUpdate.IsZero() {
+		t.Errorf("Expected zero time for empty database, got %v", lastUpdate)
+	}
+}
+
+// TestLegacyTimestampsAreNormalized verifies rows written in the driver's old layout are
+// rewritten by the timestamp migration
+func TestLegacyTimestampsAreNormalized(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "legacy-riverdata.db")
+	repo, err := NewSQLiteRiverRepository(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to initialize repository: %v", err)
+	}
+	_, err = repo.db.Exec(`INSERT INTO river_data(river, station, water_level, water_temp, timestamp)
+		VALUES('САВА', 'ШАБАЦ', '250', '12.0', '2025-04-18 08:00:00.123456789+02:00')`)
+	if err != nil {
+		t.Fatalf("Failed to insert legacy row: %v", err)
+	}
+	// Forget the migration so reopening runs it, as on a database from before it existed
+	if _, err := repo.db.Exec(`DELETE FROM schema_migrations WHERE name = 'normalize river_data timestamps'`); err != nil {
+		t.Fatalf("Failed to forget the timestamp migration: %v", err)
+	}
+	repo.Close()
+
+	repo, err = NewSQLiteRiverRepository(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to reopen repository: %v", err)
+	}
+	defer repo.Close()
+
+	var stored string
+	if err := repo.db.QueryRow(`SELECT CAST(timestamp AS TEXT) FROM river_data`).Scan(&stored); err != nil {
+		t.Fatalf("Failed to read stored timestamp: %v", err)
+	}
+	if stored != "2025-04-18T06:00:00Z" {
+		t.Errorf("Expected normalized timestamp 2025-04-18T06:00:00Z, got %s", stored)
+	}
+
+	lastUpdate, err := repo.GetLastUpdateTime()
+	if err != nil {
+		t.Fatalf("Failed to get last update time: %v", err)
+	}
+	if want := time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC); !lastUpdate.Equal(want) {
+		t.Errorf("Expected last update %v, got %v", want, lastUpdate)
+	}
+}
+
+// TestParseStoredTimestamp verifies the canonical layout and the legacy fallback
+func TestParseStoredTimestamp(t *testing.T) {
+	want := time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC)
+	for _, value := range []string{"2025-04-18T06:00:00Z", "2025-04-18 08:00:00+02:00"} {
+		got, err := parseStoredTimestamp(value)
+		if err != nil {
+			t.Errorf("Failed to parse %q: %v", value, err)
+			continue
+		}
+		if !got.Equal(want) {
+			t.Errorf("Parsing %q: expected %v, got %v", value, want, got)
+		}
+	}
+
+	if _, err := parseStoredTimestamp("18.04.2025 08:00"); err == nil {
+		t.Error("Expected an error for an unrecognized layout")
+	}
+}
+
+// TestGetLastUpdateTimeBySource verifies each source reports its own latest timestamp
+func TestGetLastUpdateTimeBySource(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+
+	hidmetTime := time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC)
+	gradacTime := time.Date(2025, 4, 18, 7, 50, 0, 0, time.UTC)
+	rhmzTime := time.Date(2025, 4, 17, 5, 0, 0, 0, time.UTC)
+
+	data := []entities.RiverData{
+		{River: "ДУНАВ", Station: "БЕЗДАН", WaterLevel: "300", Source: "hidmet", Timestamp: hidmetTime},
+		{River: "ГРАДАЦ", Station: "ДЕГУРИЋ", WaterLevel: "40", Source: "gradac", Timestamp: gradacTime.Add(-10 * time.Minute)},
+		{River: "ГРАДАЦ", Station: "ДЕГУРИЋ", WaterLevel: "41", Source: "gradac", Timestamp: gradacTime},
+		{River: "ДРИНА", Station: "Радаљ", WaterLevel: "142", Source: "rhmzrs", Timestamp: rhmzTime},
+		{River: "САВА", Station: "ШАБАЦ", WaterLevel: "250", Timestamp: gradacTime.Add(time.Hour)}, // no source
+	}
+	if err := repo.SaveRiverData(data); err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+
+	lastUpdates, err := repo.GetLastUpdateTimeBySource()
+	if err != nil {
+		t.Fatalf("Failed to get last update time by source: %v", err)
+	}
+
+	expected := map[string]time.Time{
+		"hidmet": hidmetTime,
+		"gradac": gradacTime,
+		"rhmzrs": rhmzTime,
+	}
+	if len(lastUpdates) != len(expected) {
+		t.Fatalf("Expected %d sources, got %d: %v", len(expected), len(lastUpdates), lastUpdates)
+	}
+	for source, want := range expected {
+		if got := lastUpdates[source]; !got.Equal(want) {
+			t.Errorf("Source %s: expected %v, got %v", source, want, got)
+		}
+	}
+
+	retrieved, err := repo.GetRiverDataByName("ДРИНА")
+	if err != nil {
+		t.Fatalf("Failed to retrieve river data: %v", err)
+	}
+	if len(retrieved) != 1 || retrieved[0].Source != "rhmzrs" {
+		t.Errorf("Expected the reading to carry its source, got %+v", retrieved)
+	}
+}
+
+// TestGetTopStations verifies stations are ranked by numeric level using their latest reading
+func TestGetTopStations(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+
+	older := time.Date(2025, 4, 17, 6, 0, 0, 0, time.UTC)
+	latest := time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC)
+	err := repo.SaveRiverData([]entities.RiverData{
+		{River: "ДУНАВ", Station: "Земун", WaterLevel: "350", Timestamp: latest},
+		{River: "САВА", Station: "Шабац", WaterLevel: "1000", Timestamp: older}, // Superseded below
+		{River: "САВА", Station: "Шабац", WaterLevel: "90", Timestamp: latest},
+		{River: "ДРИНА", Station: "Радаљ", WaterLevel: "-15", Timestamp: latest},
+		{River: "ТИСА", Station: "Тител", WaterLevel: "1200", Timestamp: latest}, // Sorts first numerically but last as text
+		{River: "ИБАР", Station: "Рашка", WaterLevel: "n/a", Timestamp: latest},
+	})
+	if err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+
+	top, err := repo.GetTopStations(0)
+	if err != nil {
+		t.Fatalf("Failed to get top stations: %v", err)
+	}
+
+	var got []string
+	for _, rd := range top {
+		got = append(got, rd.Station+"="+rd.WaterLevel)
+	}
+	want := []string{"Тител=1200", "Земун=350", "Шабац=90", "Радаљ=-15"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected ranking %v, got %v", want, got)
+	}
+
+	top, err = repo.GetTopStations(2)
+	if err != nil {
+		t.Fatalf("Failed to get top stations: %v", err)
+	}
+	if len(top) != 2 || top[1].Station != "Земун" {
+		t.Errorf("Expected the top 2 stations, got %+v", top)
+	}
+}
+
+// TestGetStationsByTempRange verifies only stations whose latest temperature is in range are returned, coldest first
+func TestGetStationsByTempRange(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+
+	older := time.Date(2025, 4, 17, 6, 0, 0, 0, time.UTC)
+	latest := time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC)
+	err := repo.SaveRiverData([]entities.RiverData{
+		{River: "ДРИНА", Station: "Радаљ", WaterLevel: "142", WaterTemp: "12.4", Timestamp: latest},
+		{River: "ЛИМ", Station: "Пријепоље", WaterLevel: "80", WaterTemp: "8", Timestamp: latest},  // Lower bound
+		{River: "ИБАР", Station: "Рашка", WaterLevel: "60", WaterTemp: "14,0", Timestamp: latest},  // Upper bound, comma decimal
+		{River: "САВА", Station: "Шабац", WaterLevel: "325", WaterTemp: "9", Timestamp: older},     // Superseded below
+		{River: "САВА", Station: "Шабац", WaterLevel: "320", WaterTemp: "16.2", Timestamp: latest}, // Too warm
+		{River: "ДУНАВ", Station: "Земун", WaterLevel: "350", WaterTemp: "7.9", Timestamp: latest}, // Too cold
+		{River: "ТИСА", Station: "Тител", WaterLevel: "400", Timestamp: latest},                    // No temperature
+		{River: "МОРАВА", Station: "Варварин", WaterLevel: "200", WaterTemp: "-", Timestamp: latest},
+	})
+	if err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+
+	stations, err := repo.GetStationsByTempRange(8, 14)
+	if err != nil {
+		t.Fatalf("Failed to get stations: %v", err)
+	}
+
+	var got []string
+	for _, rd := range stations {
+		got = append(got, rd.Station+"="+rd.WaterTemp)
+	}
+	want := []string{"Пријепоље=8", "Радаљ=12.4", "Рашка=14,0"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+// TestGetRiverDataByNameWithNullColumns verifies NULL levels and temperatures don't break a lookup
+func TestGetRiverDataByNameWithNullColumns(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+
+	ts := time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC)
+	_, err := repo.db.Exec(`
+		INSERT INTO river_data (river, station, water_level, water_temp, tendency, source, timestamp)
+		VALUES (?, ?, ?, NULL, NULL, NULL, ?), (?, ?, NULL, NULL, NULL, NULL, ?)`,
+		"ДРИНА", "Радаљ", "142", sqliteTimeArg(ts),
+		"ДРИНА", "Фоча", sqliteTimeArg(ts))
+	if err != nil {
+		t.Fatalf("Failed to insert rows: %v", err)
+	}
+
+	data, err := repo.GetRiverDataByName("ДРИНА")
+	if err != nil {
+		t.Fatalf("Expected the lookup to succeed, got %v", err)
+	}
+	if len(data) != 2 {
+		t.Fatalf("Expected 2 readings, got %+v", data)
+	}
+	for _, rd := range data {
+		if rd.WaterTemp != "" {
+			t.Errorf("%s: expected an empty temperature, got %q", rd.Station, rd.WaterTemp)
+		}
+	}
+
+	history, err := repo.GetStationHistory("ДРИНА", "Фоча", ts.Add(-time.Hour))
+	if err != nil || len(history) != 1 || history[0].WaterLevel != "" {
+		t.Errorf("Expected one reading with an empty level, got %+v (err %v)", history, err)
+	}
+}
+
+// TestSQLiteConcurrentReadWrite verifies two repositories sharing a file, like the bot and
+// the scraper, can read and write at the same time without lock errors
+func TestSQLiteConcurrentReadWrite(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "shared-riverdata.db")
+	open := func() *SQLiteRiverRepository {
+		repo, err := NewSQLiteRiverRepository(dbPath)
+		if err != nil {
+			t.Fatalf("Failed to initialize repository: %v", err)
+		}
+		t.Cleanup(func() { repo.Close() })
+		return repo
+	}
+	writer, reader := open(), open()
+
+	var journalMode string
+	if err := reader.db.QueryRow(`PRAGMA journal_mode`).Scan(&journalMode); err != nil {
+		t.Fatalf("Failed to read journal mode: %v", err)
+	}
+	if journalMode != "wal" {
+		t.Errorf("Expected WAL journal mode, got %q", journalMode)
+	}
+
+	const iterations = 100
+	start := time.Date(2025, 4, 18, 0, 0, 0, 0, time.UTC)
+	errs := make(chan error, 2*iterations)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < iterations; i++ {
+			data := []entities.RiverData{{
+				River:      "ДУНАВ",
+				Station:    "БЕЗДАН",
+				WaterLevel: fmt.Sprint(300 + i),
+				Timestamp:  start.Add(time.Duration(i) * time.Minute),
+			}}
+			if err := writer.SaveRiverData(data); err != nil {
+				errs <- fmt.Errorf("write %d: %v", i, err)
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < iterations; i++ {
+			if _, err := reader.GetRiverDataByName("ДУНАВ"); err != nil {
+				errs <- fmt.Errorf("read %d: %v", i, err)
+			}
+			if _, err := reader.GetLastUpdateTime(); err != nil {
+				errs <- fmt.Errorf("read %d: %v", i, err)
+			}
+		}
+	}()
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+
+	lastUpdate, err := reader.GetLastUpdateTime()
+	if err != nil {
+		t.Fatalf("Failed to get last update time: %v", err)
+	}
+	if want := start.Add((iterations - 1) * time.Minute); !lastUpdate.Equal(want) {
+		t.Errorf("Expected all writes to land, last update %v, want %v", lastUpdate, want)
+	}
+}
+
+// TestSQLiteConcurrentReads verifies many concurrent readers, alongside a writer, share the pool without errors
+func TestSQLiteConcurrentReads(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+	if err := repo.SaveRiverData(generateReadings(500)); err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+	if got := repo.db.Stats().MaxOpenConnections; got != defaultSQLiteMaxOpenConns {
+		t.Errorf("Expected a pool of %d connections, got %d", defaultSQLiteMaxOpenConns, got)
+	}
+
+	const readers, reads = 32, 20
+	errs := make(chan error, readers*reads+1)
+
+	var wg sync.WaitGroup
+	for r := 0; r < readers; r++ {
+		wg.Add(1)
+		go func(r int) {
+			defer wg.Done()
+			for i := 0; i < reads; i++ {
+				data, err := repo.GetRiverDataByName("ГРАДАЦ")
+				if err != nil {
+					errs <- fmt.Errorf("reader %d read %d: %v", r, i, err)
+					return
+				}
+				if len(data) != 5 {
+					errs <- fmt.Errorf("reader %d read %d: expected 5 stations, got %d", r, i, len(data))
+					return
+				}
+			}
+		}(r)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := repo.SaveRiverData(generateReadings(100)); err != nil {
+			errs <- fmt.Errorf("write: %v", err)
+		}
+	}()
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+// TestSQLitePoolOptions verifies the connection pool can be tuned when opening the repository
+func TestSQLitePoolOptions(t *testing.T) {
+	repo, err := NewSQLiteRiverRepository(filepath.Join(t.TempDir(), "test-riverdata.db"), WithMaxOpenConns(1), WithMaxIdleConns(1))
+	if err != nil {
+		t.Fatalf("Failed to initialize repository: %v", err)
+	}
+	defer repo.Close()
+
+	if got := repo.db.Stats().MaxOpenConnections; got != 1 {
+		t.Errorf("Expected a single connection, got %d", got)
+	}
+}
+
+// BenchmarkConcurrentGetRiverDataByName compares concurrent reads over a single connection and the default pool
+func BenchmarkConcurrentGetRiverDataByName(b *testing.B) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	for _, bench := range []struct {
+		name string
+		opts []SQLiteOption
+	}{
+		{"single connection", []SQLiteOption{WithMaxOpenConns(1)}},
+		{"default pool", nil},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			repo, err := NewSQLiteRiverRepository(filepath.Join(b.TempDir(), "bench-riverdata.db"), bench.opts...)
+			if err != nil {
+				b.Fatalf("Failed to initialize repository: %v", err)
+			}
+			defer repo.Close()
+			if err := repo.SaveRiverData(generateReadings(5000)); err != nil {
+				b.Fatalf("Failed to save data: %v", err)
+			}
+
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					if _, err := repo.GetRiverDataByName("ГРАДАЦ"); err != nil {
+						b.Errorf("Failed to read data: %v", err)
+						return
+					}
+				}
+			})
+		})
+	}
+}
+
+// generateReadings returns n hourly readings spread over a few stations
+func generateReadings(n int) []entities.RiverData {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	data := make([]entities.RiverData, n)
+	for i := range data {
+		data[i] = entities.RiverData{
+			River:      "ГРАДАЦ",
+			Station:    fmt.Sprintf("Станица %d", i%5),
+			WaterLevel: fmt.Sprint(100 + i%50),
+			WaterTemp:  "12.5",
+			Tendency:   entities.Rising,
+			Source:     "gradac",
+			Timestamp:  start.Add(time.Duration(i/5) * time.Hour),
+		}
+	}
+	return data
+}
+
+// BenchmarkSaveRiverData measures saving a large series such as the ГРАДАЦ history
+func BenchmarkSaveRiverData(b *testing.B) {
+	repo, err := NewSQLiteRiverRepository(filepath.Join(b.TempDir(), "bench-riverdata.db"))
+	if err != nil {
+		b.Fatalf("Failed to initialize repository: %v", err)
+	}
+	defer repo.Close()
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	data := generateReadings(1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := repo.SaveRiverData(data); err != nil {
+			b.Fatalf("Failed to save data: %v", err)
+		}
+	}
+}
+
+// TestSaveRiverDataLargeBatch verifies a series spanning several batches is saved in full
+func TestSaveRiverDataLargeBatch(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+	data := generateReadings(1000)
+
+	// A duplicate of the last reading with another level, the later one wins
+	last := data[len(data)-1]
+	last.WaterLevel = "999"
+	data = append(data, last)
+
+	if err := repo.SaveRiverData(data); err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+
+	var count int
+	if err := repo.db.QueryRow(`SELECT COUNT(*) FROM river_data`).Scan(&count); err != nil {
+		t.Fatalf("Failed to count rows: %v", err)
+	}
+	if count != 1000 {
+		t.Errorf("Expected 1000 rows, got %d", count)
+	}
+
+	history, err := repo.GetStationHistory(last.River, last.Station, time.Time{})
+	if err != nil {
+		t.Fatalf("Failed to get history: %v", err)
+	}
+	if len(history) != 200 {
+		t.Fatalf("Expected 200 readings for %s, got %d", last.Station, len(history))
+	}
+	for i, rd := range history {
+		want := data[i*5+4]
+		if i == len(history)-1 {
+			want = last
+		}
+		if rd.WaterLevel != want.WaterLevel || rd.WaterTemp != "12.5" || rd.Tendency != entities.Rising || !rd.Timestamp.Equal(want.Timestamp) {
+			t.Fatalf("Reading %d: expected %+v, got %+v", i, want, rd)
+		}
+	}
+
+	// Saving again updates in place
+	if err := repo.SaveRiverData(data); err != nil {
+		t.Fatalf("Failed to save data again: %v", err)
+	}
+	if err := repo.db.QueryRow(`SELECT COUNT(*) FROM river_data`).Scan(&count); err != nil || count != 1000 {
+		t.Errorf("Expected 1000 rows after re-saving, got %d (err %v)", count, err)
+	}
+}
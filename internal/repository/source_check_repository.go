@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// SaveSourceCheck records the latest successful fetch of a source, replacing the previous one
+func (r *sqlRiverRepository) SaveSourceCheck(check entities.SourceCheck) error {
+	query := `
+		INSERT INTO source_checks(source, checked_at, unchanged)
+		VALUES(?, ?, ?)
+		ON CONFLICT(source) DO UPDATE SET
+		checked_at=excluded.checked_at,
+		unchanged=excluded.unchanged`
+
+	if _, err := r.db.Exec(r.rebind(query), check.Source, r.timeArg(check.CheckedAt), check.Unchanged); err != nil {
+		return fmt.Errorf("failed to save check of source %s: %v", check.Source, err)
+	}
+	return nil
+}
+
+// GetSourceChecks returns the latest successful fetch of each source, keyed by source
+func (r *sqlRiverRepository) GetSourceChecks() (map[string]entities.SourceCheck, error) {
+	rows, err := r.db.Query(`SELECT source, checked_at, unchanged FROM source_checks`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query source checks: %v", err)
+	}
+	defer rows.Close()
+
+	checks := make(map[string]entities.SourceCheck)
+	for rows.Next() {
+		var check entities.SourceCheck
+		var checkedAt dbTime
+		if err := rows.Scan(&check.Source, &checkedAt, &check.Unchanged); err != nil {
+			return nil, fmt.Errorf("failed to scan source check: %v", err)
+		}
+		check.CheckedAt = checkedAt.Time
+		checks[check.Source] = check
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during row iteration: %v", err)
+	}
+
+	return checks, nil
+}
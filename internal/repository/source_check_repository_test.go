@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// TestSaveSourceCheckReplacesPrevious verifies only the latest check of each source is kept
+func TestSaveSourceCheckReplacesPrevious(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+
+	first := time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC)
+	checks := []entities.SourceCheck{
+		{Source: "rhmzrs", CheckedAt: first, Unchanged: false},
+		{Source: "rhmzrs", CheckedAt: first.Add(time.Hour), Unchanged: true},
+		{Source: "hidmet", CheckedAt: first, Unchanged: false},
+	}
+	for _, check := range checks {
+		if err := repo.SaveSourceCheck(check); err != nil {
+			t.Fatalf("Failed to save source check: %v", err)
+		}
+	}
+
+	stored, err := repo.GetSourceChecks()
+	if err != nil {
+		t.Fatalf("Failed to get source checks: %v", err)
+	}
+	if len(stored) != 2 {
+		t.Fatalf("Expected 2 sources, got %+v", stored)
+	}
+	if rhmz := stored["rhmzrs"]; !rhmz.Unchanged || !rhmz.CheckedAt.Equal(first.Add(time.Hour)) {
+		t.Errorf("Expected the second rhmzrs check, got %+v", rhmz)
+	}
+	if hidmet := stored["hidmet"]; hidmet.Unchanged || !hidmet.CheckedAt.Equal(first) {
+		t.Errorf("Expected the hidmet check, got %+v", hidmet)
+	}
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// UpdateSourceStatus records the outcome of fetching a source. The change time only
+// moves when the source goes down or comes back up, and the announced state is kept.
+func (r *sqlRiverRepository) UpdateSourceStatus(source string, down bool, errText string, at time.Time) error {
+	query := `
+		INSERT INTO source_status(source, down, error, changed_at, announced_down)
+		VALUES(?, ?, ?, ?, ?)
+		ON CONFLICT(source) DO UPDATE SET
+		changed_at=CASE WHEN source_status.down = excluded.down THEN source_status.changed_at ELSE excluded.changed_at END,
+		down=excluded.down,
+		error=excluded.error`
+
+	if _, err := r.db.Exec(r.rebind(query), source, down, errText, r.timeArg(at), false); err != nil {
+		return fmt.Errorf("failed to update status of source %s: %v", source, err)
+	}
+	return nil
+}
+
+// GetSourceStatuses returns the status of every source that was refreshed, ordered by source
+func (r *sqlRiverRepository) GetSourceStatuses() ([]entities.SourceStatus, error) {
+	rows, err := r.db.Query(`SELECT source, down, error, changed_at, announced_down FROM source_status ORDER BY source`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query source statuses: %v", err)
+	}
+	defer rows.Close()
+
+	var statuses []entities.SourceStatus
+	for rows.Next() {
+		var status entities.SourceStatus
+		var changedAt dbTime
+		if err := rows.Scan(&status.Source, &status.Down, &status.Error, &changedAt, &status.AnnouncedDown); err != nil {
+			return nil, fmt.Errorf("failed to scan source status: %v", err)
+		}
+		status.ChangedAt = changedAt.Time
+		statuses = append(statuses, status)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during row iteration: %v", err)
+	}
+
+	return statuses, nil
+}
+
+// SetSourceAnnounced records the state of a source last announced to watching chats
+func (r *sqlRiverRepository) SetSourceAnnounced(source string, down bool) error {
+	if _, err := r.db.Exec(r.rebind(`UPDATE source_status SET announced_down = ? WHERE source = ?`), down, source); err != nil {
+		return fmt.Errorf("failed to update announced status of source %s: %v", source, err)
+	}
+	return nil
+}
+
+// AddSourceWatcher subscribes a chat to source outage notifications.
+// Returns false if the chat was already subscribed.
+func (r *sqlRiverRepository) AddSourceWatcher(chatID int64) (bool, error) {
+	query := `
+		INSERT INTO source_watchers(chat_id, created_at)
+		VALUES(?, ?)
+		ON CONFLICT(chat_id) DO NOTHING`
+
+	result, err := r.db.Exec(r.rebind(query), chatID, r.timeArg(time.Now()))
+	if err != nil {
+		return false, fmt.Errorf("failed to add source watcher %d: %v", chatID, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check added source watcher %d: %v", chatID, err)
+	}
+
+	return affected > 0, nil
+}
+
+// RemoveSourceWatcher unsubscribes a chat from source outage notifications.
+// Returns false if the chat was not subscribed.
+func (r *sqlRiverRepository) RemoveSourceWatcher(chatID int64) (bool, error) {
+	result, err := r.db.Exec(r.rebind(`DELETE FROM source_watchers WHERE chat_id = ?`), chatID)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove source watcher %d: %v", chatID, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check removed source watcher %d: %v", chatID, err)
+	}
+
+	return affected > 0, nil
+}
+
+// GetSourceWatchers returns the chats subscribed to source outage notifications
+func (r *sqlRiverRepository) GetSourceWatchers() ([]int64, error) {
+	rows, err := r.db.Query(`SELECT chat_id FROM source_watchers ORDER BY chat_id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query source watchers: %v", err)
+	}
+	defer rows.Close()
+
+	var chatIDs []int64
+	for rows.Next() {
+		var chatID int64
+		if err := rows.Scan(&chatID); err != nil {
+			return nil, fmt.Errorf("failed to scan source watcher: %v", err)
+		}
+		chatIDs = append(chatIDs, chatID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during row iteration: %v", err)
+	}
+
+	return chatIDs, nil
+}
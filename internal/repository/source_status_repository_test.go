@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"testing"
+	"time"
+)
+
+// TestUpdateSourceStatusKeepsChangeTimeAndAnnouncement verifies repeated outcomes don't
+// move the change time and the announced state survives later refreshes
+func TestUpdateSourceStatusKeepsChangeTimeAndAnnouncement(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+
+	start := time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC)
+	steps := []struct {
+		down        bool
+		wantChanged time.Time
+	}{
+		{true, start},                     // first refresh, down
+		{true, start},                     // still down
+		{false, start.Add(2 * time.Hour)}, // back up
+		{false, start.Add(2 * time.Hour)}, // still up
+	}
+	for i, step := range steps {
+		errText := ""
+		if step.down {
+			errText = "timeout"
+		}
+		if err := repo.UpdateSourceStatus("hidmet", step.down, errText, start.Add(time.Duration(i)*time.Hour)); err != nil {
+			t.Fatalf("Step %d: failed to update source status: %v", i, err)
+		}
+		if i == 0 {
+			if err := repo.SetSourceAnnounced("hidmet", true); err != nil {
+				t.Fatalf("Failed to set announced state: %v", err)
+			}
+		}
+
+		statuses, err := repo.GetSourceStatuses()
+		if err != nil {
+			t.Fatalf("Step %d: failed to get source statuses: %v", i, err)
+		}
+		if len(statuses) != 1 {
+			t.Fatalf("Step %d: expected one status, got %+v", i, statuses)
+		}
+		status := statuses[0]
+		if status.Down != step.down || status.Error != errText || !status.ChangedAt.Equal(step.wantChanged) || !status.AnnouncedDown {
+			t.Errorf("Step %d: expected down=%v changed at %v and announced down, got %+v", i, step.down, step.wantChanged, status)
+		}
+	}
+}
+
+// TestSourceWatchers verifies chats subscribe to outage notifications once and can unsubscribe
+func TestSourceWatchers(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+
+	for i, want := range []bool{true, false} {
+		added, err := repo.AddSourceWatcher(100)
+		if err != nil || added != want {
+			t.Errorf("Add %d: expected added=%v, got %v, %v", i+1, want, added, err)
+		}
+	}
+	if _, err := repo.AddSourceWatcher(200); err != nil {
+		t.Fatalf("Failed to add source watcher: %v", err)
+	}
+	if removed, err := repo.RemoveSourceWatcher(100); err != nil || !removed {
+		t.Errorf("Expected the watcher removed, got %v, %v", removed, err)
+	}
+
+	watchers, err := repo.GetSourceWatchers()
+	if err != nil {
+		t.Fatalf("Failed to get source watchers: %v", err)
+	}
+	if len(watchers) != 1 || watchers[0] != 200 {
+		t.Errorf("Expected [200], got %v", watchers)
+	}
+}
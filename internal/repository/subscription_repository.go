@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// subscriptionColumns is the column list expected by scanSubscriptions
+const subscriptionColumns = `id, chat_id, river, kind, threshold, direction, triggered, last_triggered, created_at`
+
+// AddSubscription stores a new alert subscription and returns its ID
+func (r *sqlRiverRepository) AddSubscription(sub entities.Subscription) (int64, error) {
+	query := `
+		INSERT INTO subscriptions(chat_id, river, kind, threshold, direction, triggered, created_at)
+		VALUES(?, ?, ?, ?, ?, ?, ?)
+		RETURNING id`
+
+	createdAt := sub.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	kind := sub.Kind
+	if kind == "" {
+		kind = entities.AlertLevel
+	}
+
+	var id int64
+	err := r.db.QueryRow(r.rebind(query),
+		sub.ChatID,
+		sub.River,
+		string(kind),
+		sub.Threshold,
+		string(sub.Direction),
+		sub.Triggered,
+		r.timeArg(createdAt),
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to add subscription for chat %d: %v", sub.ChatID, err)
+	}
+
+	return id, nil
+}
+
+// GetSubscriptionsByChat returns all subscriptions belonging to a chat
+func (r *sqlRiverRepository) GetSubscriptionsByChat(chatID int64) ([]entities.Subscription, error) {
+	query := `
+		SELECT ` + subscriptionColumns + `
+		FROM subscriptions
+		WHERE chat_id = ?
+		ORDER BY id`
+
+	rows, err := r.db.Query(r.rebind(query), chatID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query subscriptions for chat %d: %v", chatID, err)
+	}
+	defer rows.Close()
+
+	return scanSubscriptions(rows)
+}
+
+// GetAllSubscriptions returns every stored subscription
+func (r *sqlRiverRepository) GetAllSubscriptions() ([]entities.Subscription, error) {
+	query := `
+		SELECT ` + subscriptionColumns + `
+		FROM subscriptions
+		ORDER BY id`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query subscriptions: %v", err)
+	}
+	defer rows.Close()
+
+	return scanSubscriptions(rows)
+}
+
+// DeleteSubscription removes a subscription owned by the given chat.
+// Returns false if no such subscription belongs to the chat.
+func (r *sqlRiverRepository) DeleteSubscription(chatID, id int64) (bool, error) {
+	result, err := r.db.Exec(r.rebind(`DELETE FROM subscriptions WHERE id = ? AND chat_id = ?`), id, chatID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete subscription %d: %v", id, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check deleted subscription %d: %v", id, err)
+	}
+
+	return affected > 0, nil
+}
+
+// UpdateSubscriptionState persists whether an alert is currently triggered and when it last fired
+func (r *sqlRiverRepository) UpdateSubscriptionState(id int64, triggered bool, lastTriggered time.Time) error {
+	var lastTriggeredArg interface{}
+	if !lastTriggered.IsZero() {
+		lastTriggeredArg = r.timeArg(lastTriggered)
+	}
+
+	_, err := r.db.Exec(r.rebind(`UPDATE subscriptions SET triggered = ?, last_triggered = ? WHERE id = ?`),
+		triggered, lastTriggeredArg, id)
+	if err != nil {
+		return fmt.Errorf("failed to update subscription %d: %v", id, err)
+	}
+
+	return nil
+}
+
+// scanSubscriptions reads all rows selected with subscriptionColumns
+func scanSubscriptions(rows *sql.Rows) ([]entities.Subscription, error) {
+	var result []entities.Subscription
+	for rows.Next() {
+		var sub entities.Subscription
+		var kind, direction string
+		var lastTriggered, createdAt dbTime
+		if err := rows.Scan(
+			&sub.ID,
+			&sub.ChatID,
+			&sub.River,
+			&kind,
+			&sub.Threshold,
+			&direction,
+			&sub.Triggered,
+			&lastTriggered,
+			&createdAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan subscription: %v", err)
+		}
+		sub.Kind = entities.AlertKind(kind)
+		sub.Direction = entities.AlertDirection(direction)
+		sub.LastTriggered = lastTriggered.Time
+		sub.CreatedAt = createdAt.Time
+		result = append(result, sub)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during row iteration: %v", err)
+	}
+
+	return result, nil
+}
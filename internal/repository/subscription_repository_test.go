@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// TestSubscriptionsScopedToChat verifies listing and deleting only touch the requesting chat's rows
+func TestSubscriptionsScopedToChat(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+
+	const chatA, chatB = int64(100), int64(200)
+	idA, err := repo.AddSubscription(entities.Subscription{ChatID: chatA, River: "ДРИНА", Threshold: 300, Direction: entities.AlertAbove})
+	if err != nil {
+		t.Fatalf("Failed to add subscription: %v", err)
+	}
+	if _, err := repo.AddSubscription(entities.Subscription{ChatID: chatA, River: "САВА", Kind: entities.AlertTemp, Threshold: 16.5, Direction: entities.AlertBelow}); err != nil {
+		t.Fatalf("Failed to add subscription: %v", err)
+	}
+	idB, err := repo.AddSubscription(entities.Subscription{ChatID: chatB, River: "ДУНАВ", Threshold: 500, Direction: entities.AlertAbove})
+	if err != nil {
+		t.Fatalf("Failed to add subscription: %v", err)
+	}
+
+	subsA, err := repo.GetSubscriptionsByChat(chatA)
+	if err != nil {
+		t.Fatalf("Failed to list subscriptions: %v", err)
+	}
+	if len(subsA) != 2 {
+		t.Fatalf("Expected 2 subscriptions for chat A, got %d", len(subsA))
+	}
+	if subsA[0].River != "ДРИНА" || subsA[0].Kind != entities.AlertLevel || subsA[0].Direction != entities.AlertAbove || subsA[0].Threshold != 300 {
+		t.Errorf("Unexpected first subscription: %+v", subsA[0])
+	}
+	if subsA[1].Kind != entities.AlertTemp || subsA[1].Threshold != 16.5 {
+		t.Errorf("Expected a temperature alert at 16.5 °C, got %+v", subsA[1])
+	}
+	if !subsA[0].LastTriggered.IsZero() || subsA[0].CreatedAt.IsZero() {
+		t.Errorf("Expected a new subscription to have a creation time and no trigger time: %+v", subsA[0])
+	}
+
+	// Chat B must not be able to delete chat A's subscription
+	deleted, err := repo.DeleteSubscription(chatB, idA)
+	if err != nil {
+		t.Fatalf("Failed to delete subscription: %v", err)
+	}
+	if deleted {
+		t.Error("Expected deleting another chat's subscription to fail")
+	}
+
+	deleted, err = repo.DeleteSubscription(chatA, idA)
+	if err != nil {
+		t.Fatalf("Failed to delete subscription: %v", err)
+	}
+	if !deleted {
+		t.Error("Expected deleting own subscription to succeed")
+	}
+
+	subsA, _ = repo.GetSubscriptionsByChat(chatA)
+	subsB, _ := repo.GetSubscriptionsByChat(chatB)
+	if len(subsA) != 1 || len(subsB) != 1 || subsB[0].ID != idB {
+		t.Errorf("Expected 1 subscription left per chat, got A=%v B=%v", subsA, subsB)
+	}
+}
+
+// TestUpdateSubscriptionState verifies the triggered flag and time are persisted
+func TestUpdateSubscriptionState(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+
+	id, err := repo.AddSubscription(entities.Subscription{ChatID: 1, River: "ДРИНА", Threshold: 300, Direction: entities.AlertAbove})
+	if err != nil {
+		t.Fatalf("Failed to add subscription: %v", err)
+	}
+
+	triggeredAt := time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC)
+	if err := repo.UpdateSubscriptionState(id, true, triggeredAt); err != nil {
+		t.Fatalf("Failed to update subscription: %v", err)
+	}
+
+	subs, err := repo.GetAllSubscriptions()
+	if err != nil {
+		t.Fatalf("Failed to list subscriptions: %v", err)
+	}
+	if len(subs) != 1 || !subs[0].Triggered || !subs[0].LastTriggered.Equal(triggeredAt) {
+		t.Errorf("Expected triggered subscription at %v, got %+v", triggeredAt, subs)
+	}
+}
@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// userColumns is the column list expected by scanUser
+const userColumns = `chat_id, username, language_code, first_seen, last_seen, time_zone`
+
+// UpsertUser registers a user, or refreshes the username, language and last_seen
+// of one already registered. The first_seen of an existing user is kept.
+func (r *sqlRiverRepository) UpsertUser(user entities.User) error {
+	query := `
+		INSERT INTO users(chat_id, username, language_code, first_seen, last_seen)
+		VALUES(?, ?, ?, ?, ?)
+		ON CONFLICT(chat_id) DO UPDATE SET
+			username = excluded.username,
+			language_code = excluded.language_code,
+			last_seen = excluded.last_seen`
+
+	lastSeen := user.LastSeen
+	if lastSeen.IsZero() {
+		lastSeen = time.Now()
+	}
+	firstSeen := user.FirstSeen
+	if firstSeen.IsZero() {
+		firstSeen = lastSeen
+	}
+
+	_, err := r.db.Exec(r.rebind(query),
+		user.ChatID,
+		user.Username,
+		user.LanguageCode,
+		r.timeArg(firstSeen),
+		r.timeArg(lastSeen),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save user for chat %d: %v", user.ChatID, err)
+	}
+
+	return nil
+}
+
+// TouchUser updates the last_seen of a registered user.
+// Chats that never started the bot are left unregistered.
+func (r *sqlRiverRepository) TouchUser(chatID int64, seen time.Time) error {
+	_, err := r.db.Exec(r.rebind(`UPDATE users SET last_seen = ? WHERE chat_id = ?`), r.timeArg(seen), chatID)
+	if err != nil {
+		return fmt.Errorf("failed to update last seen for chat %d: %v", chatID, err)
+	}
+
+	return nil
+}
+
+// SetUserTimeZone stores the IANA time zone a chat wants timestamps shown in,
+// registering the chat if it never started the bot
+func (r *sqlRiverRepository) SetUserTimeZone(chatID int64, timeZone string) error {
+	query := `
+		INSERT INTO users(chat_id, first_seen, last_seen, time_zone)
+		VALUES(?, ?, ?, ?)
+		ON CONFLICT(chat_id) DO UPDATE SET
+			time_zone = excluded.time_zone`
+
+	now := r.timeArg(time.Now())
+	if _, err := r.db.Exec(r.rebind(query), chatID, now, now, timeZone); err != nil {
+		return fmt.Errorf("failed to save time zone for chat %d: %v", chatID, err)
+	}
+
+	return nil
+}
+
+// GetUser returns the registered user of a chat, reporting false if it never started the bot
+func (r *sqlRiverRepository) GetUser(chatID int64) (entities.User, bool, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE chat_id = ?`
+
+	user, err := scanUser(r.db.QueryRow(r.rebind(query), chatID))
+	if err == sql.ErrNoRows {
+		return entities.User{}, false, nil
+	}
+	if err != nil {
+		return entities.User{}, false, fmt.Errorf("failed to query user for chat %d: %v", chatID, err)
+	}
+
+	return user, true, nil
+}
+
+// GetAllUsers returns every registered user
+func (r *sqlRiverRepository) GetAllUsers() ([]entities.User, error) {
+	rows, err := r.db.Query(`SELECT ` + userColumns + ` FROM users ORDER BY chat_id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query users: %v", err)
+	}
+	defer rows.Close()
+
+	var result []entities.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %v", err)
+		}
+		result = append(result, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during row iteration: %v", err)
+	}
+
+	return result, nil
+}
+
+// DeleteUser unregisters the user of a chat.
+// Returns false if the chat was not registered.
+func (r *sqlRiverRepository) DeleteUser(chatID int64) (bool, error) {
+	result, err := r.db.Exec(r.rebind(`DELETE FROM users WHERE chat_id = ?`), chatID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete user for chat %d: %v", chatID, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check deleted user for chat %d: %v", chatID, err)
+	}
+
+	return affected > 0, nil
+}
+
+// userTables lists every table holding data of a single chat, keyed by chat_id
+var userTables = []string{"subscriptions", "daily_digests", "favorites", "follows", "source_watchers", "users"}
+
+// ForgetUser deletes every row in userTables for a chat in one transaction and returns how many were removed.
+func (r *sqlRiverRepository) ForgetUser(chatID int64) (int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+
+	var removed int64
+	for _, table := range userTables {
+		result, err := tx.Exec(r.rebind(`DELETE FROM `+table+` WHERE chat_id = ?`), chatID)
+		if err != nil {
+			tx.Rollback()
+			return 0, fmt.Errorf("failed to delete %s for chat %d: %v", table, chatID, err)
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			tx.Rollback()
+			return 0, fmt.Errorf("failed to check deleted %s for chat %d: %v", table, chatID, err)
+		}
+		removed += affected
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %v", err)
+	}
+
+	return removed, nil
+}
+
+// scanUser reads a single row selected with userColumns
+func scanUser(row rowScanner) (entities.User, error) {
+	var user entities.User
+	var firstSeen, lastSeen dbTime
+	if err := row.Scan(&user.ChatID, &user.Username, &user.LanguageCode, &firstSeen, &lastSeen, &user.TimeZone); err != nil {
+		return entities.User{}, err
+	}
+	user.FirstSeen = firstSeen.Time
+	user.LastSeen = lastSeen.Time
+	return user, nil
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// TestUpsertUserKeepsFirstSeen verifies registering a user again updates it in place
+func TestUpsertUserKeepsFirstSeen(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+
+	const chatID = int64(100)
+	first := time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC)
+	second := first.Add(24 * time.Hour)
+
+	if err := repo.UpsertUser(entities.User{ChatID: chatID, Username: "old", LanguageCode: "sr", LastSeen: first}); err != nil {
+		t.Fatalf("Failed to upsert user: %v", err)
+	}
+	if err := repo.UpsertUser(entities.User{ChatID: chatID, Username: "new", LanguageCode: "en", LastSeen: second}); err != nil {
+		t.Fatalf("Failed to upsert user: %v", err)
+	}
+
+	var count int
+	if err := repo.db.QueryRow(`SELECT COUNT(*) FROM users`).Scan(&count); err != nil {
+		t.Fatalf("Failed to count users: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("Expected 1 user, got %d", count)
+	}
+
+	user, found, err := repo.GetUser(chatID)
+	if err != nil || !found {
+		t.Fatalf("Expected user to be found, got found=%v err=%v", found, err)
+	}
+	if user.Username != "new" || user.LanguageCode != "en" {
+		t.Errorf("Expected updated details, got %q/%q", user.Username, user.LanguageCode)
+	}
+	if !user.FirstSeen.Equal(first) {
+		t.Errorf("Expected first seen %v, got %v", first, user.FirstSeen)
+	}
+	if !user.LastSeen.Equal(second) {
+		t.Errorf("Expected last seen %v, got %v", second, user.LastSeen)
+	}
+}
+
+// TestTouchUserSkipsUnregisteredChats verifies activity is only tracked for users who started the bot
+func TestTouchUserSkipsUnregisteredChats(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+
+	seen := time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC)
+	if err := repo.TouchUser(100, seen); err != nil {
+		t.Fatalf("Failed to touch user: %v", err)
+	}
+
+	if _, found, err := repo.GetUser(100); err != nil || found {
+		t.Errorf("Expected no user to be registered, got found=%v err=%v", found, err)
+	}
+}
+
+// TestForgetUserRemovesOnlyThatChat verifies forgetting a chat clears every per-chat
+// table for it and leaves other chats untouched
+func TestForgetUserRemovesOnlyThatChat(t *testing.T) {
+	repo := newTestSQLiteRepository(t)
+
+	now := time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC)
+	for _, chatID := range []int64{100, 200} {
+		if err := repo.UpsertUser(entities.User{ChatID: chatID, Username: "user", LastSeen: now}); err != nil {
+			t.Fatalf("Failed to upsert user: %v", err)
+		}
+		for _, river := range []string{"ДРИНА", "САВА"} {
+			if _, err := repo.AddSubscription(entities.Subscription{ChatID: chatID, River: river, Threshold: 200, Direction: entities.AlertAbove, CreatedAt: now}); err != nil {
+				t.Fatalf("Failed to add subscription: %v", err)
+			}
+			if _, err := repo.AddFavorite(chatID, river); err != nil {
+				t.Fatalf("Failed to add favorite: %v", err)
+			}
+		}
+		if _, err := repo.AddFollow(entities.Follow{ChatID: chatID, River: "ДРИНА", Station: "Радаљ", CreatedAt: now}); err != nil {
+			t.Fatalf("Failed to add follow: %v", err)
+		}
+		if _, err := repo.AddSourceWatcher(chatID); err != nil {
+			t.Fatalf("Failed to add source watcher: %v", err)
+		}
+		if err := repo.SaveDigest(entities.Digest{ChatID: chatID, Rivers: []string{"ДРИНА"}, SendHour: 8, TimeZone: "Europe/Belgrade", CreatedAt: now}); err != nil {
+			t.Fatalf("Failed to save digest: %v", err)
+		}
+	}
+
+	removed, err := repo.ForgetUser(100)
+	if err != nil {
+		t.Fatalf("Failed to forget user: %v", err)
+	}
+	// 1 user, 2 subscriptions, 2 favorites, 1 follow, 1 source watcher and 1 digest
+	if removed != 8 {
+		t.Errorf("Expected 8 records removed, got %d", removed)
+	}
+
+	for _, table := range userTables {
+		for chatID, want := range map[int64]bool{100: false, 200: true} {
+			var count int
+			if err := repo.db.QueryRow(`SELECT COUNT(*) FROM `+table+` WHERE chat_id = ?`, chatID).Scan(&count); err != nil {
+				t.Fatalf("Failed to count %s: %v", table, err)
+			}
+			if (count > 0) != want {
+				t.Errorf("Expected %s rows of chat %d kept=%v, got %d", table, chatID, want, count)
+			}
+		}
+	}
+
+	if removed, err := repo.ForgetUser(100); err != nil || removed != 0 {
+		t.Errorf("Expected nothing left to forget, got %d, %v", removed, err)
+	}
+}
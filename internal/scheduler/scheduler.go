@@ -0,0 +1,108 @@
+// Package scheduler runs the periodic river data refresh, either in the scraper
+// or embedded in the bot, and the scraper's daily database backups
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/health"
+	"github.com/abelzeko/water-bot/internal/repository"
+	"github.com/abelzeko/water-bot/internal/usecases"
+	"github.com/robfig/cron/v3"
+)
+
+// refreshTimeout bounds how long a single refresh run may take
+const refreshTimeout = 10 * time.Minute
+
+// Refresh runs one data refresh bounded by refreshTimeout, logs its summary and
+// records its success with the checker, which may be nil
+func Refresh(useCase *usecases.RiverUseCase, checker *health.Checker) error {
+	ctx, cancel := context.WithTimeout(context.Background(), refreshTimeout)
+	defer cancel()
+	summary, err := useCase.RefreshRiverData(ctx)
+	if errors.Is(err, usecases.ErrRefreshInProgress) {
+		// Nothing was fetched, the running refresh reports its own summary
+		return err
+	}
+	slog.Info("Refresh summary",
+		"saved", summary.Saved,
+		"sources", len(summary.Sources),
+		"failed", summary.FailedSources(),
+		"duration", summary.Duration)
+	if err != nil {
+		return err
+	}
+	if checker != nil {
+		checker.RecordRefresh(time.Now())
+	}
+	return nil
+}
+
+// Start refreshes immediately in the background and then on the given cron schedule.
+// The returned scheduler is already running, stop it to end the scheduled refreshes.
+func Start(useCase *usecases.RiverUseCase, schedule string, checker *health.Checker) (*cron.Cron, error) {
+	c := cron.New()
+	_, err := c.AddFunc(schedule, func() {
+		if err := Refresh(useCase, checker); err != nil {
+			slog.Error("Scheduled data refresh failed", "error", err)
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up cron job: %v", err)
+	}
+
+	go func() {
+		if err := Refresh(useCase, checker); err != nil {
+			slog.Error("Initial data refresh failed", "error", err)
+		}
+	}()
+	c.Start()
+
+	slog.Info("Data refresh has been scheduled", "schedule", schedule)
+	return c, nil
+}
+
+// backupCron takes the daily database backup at 03:30, away from the top of the hour
+const backupCron = "30 3 * * *"
+
+// Backupper snapshots a database into a directory, returning the new file's path
+type Backupper interface {
+	Backup(destDir string) (string, error)
+}
+
+// Backup takes one backup into dir and deletes all but the newest keep backups
+func Backup(repo Backupper, dir string, keep int) error {
+	if _, err := repo.Backup(dir); err != nil {
+		return err
+	}
+	removed, err := repository.PruneBackups(dir, keep)
+	if err != nil {
+		return err
+	}
+	if removed > 0 {
+		slog.Info("Deleted old database backups", "removed", removed)
+	}
+	return nil
+}
+
+// StartBackups backs up the database into dir every day, keeping the newest keep backups.
+// The returned scheduler is already running, stop it to end the backups.
+func StartBackups(repo Backupper, dir string, keep int) (*cron.Cron, error) {
+	c := cron.New()
+	_, err := c.AddFunc(backupCron, func() {
+		if err := Backup(repo, dir, keep); err != nil {
+			slog.Error("Scheduled database backup failed", "error", err)
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up backup cron job: %v", err)
+	}
+	c.Start()
+
+	slog.Info("Daily database backups have been scheduled", "dir", dir, "keep", keep)
+	return c, nil
+}
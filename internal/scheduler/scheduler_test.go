@@ -0,0 +1,73 @@
+package scheduler
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+	"github.com/abelzeko/water-bot/internal/health"
+	"github.com/abelzeko/water-bot/internal/repository"
+	"github.com/abelzeko/water-bot/internal/usecases"
+)
+
+// TestStartRefreshesImmediately verifies the scheduler refreshes on startup without waiting for the schedule
+func TestStartRefreshesImmediately(t *testing.T) {
+	repo, err := repository.NewSQLiteRiverRepository(filepath.Join(t.TempDir(), "test-riverdata.db"))
+	if err != nil {
+		t.Fatalf("Failed to initialize repository: %v", err)
+	}
+	t.Cleanup(func() { repo.Close() })
+
+	refreshed := make(chan struct{}, 1)
+	useCase := usecases.NewRiverUseCaseWithSources(repo, []usecases.DataSource{
+		usecases.NewDataSource("fake", func(ctx context.Context) ([]entities.RiverData, error) {
+			defer func() { refreshed <- struct{}{} }()
+			return []entities.RiverData{{
+				River:      "ДРИНА",
+				Station:    "Радаљ",
+				WaterLevel: "142",
+				Timestamp:  time.Now(),
+			}}, nil
+		}),
+	}, nil)
+	checker := health.NewChecker()
+
+	// A yearly schedule, so only the startup refresh can run during the test
+	c, err := Start(useCase, "0 0 1 1 *", checker)
+	if err != nil {
+		t.Fatalf("Failed to start scheduler: %v", err)
+	}
+	defer c.Stop()
+
+	select {
+	case <-refreshed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected a refresh on startup")
+	}
+
+	// The fetch has returned, wait for the save to land
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		rivers, err := repo.GetUniqueRivers()
+		if err != nil {
+			t.Fatalf("Failed to get rivers: %v", err)
+		}
+		if len(rivers) == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the startup refresh to save data, got rivers %v", rivers)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// TestStartRejectsInvalidSchedule verifies a bad cron spec is reported instead of ignored
+func TestStartRejectsInvalidSchedule(t *testing.T) {
+	useCase := usecases.NewRiverUseCaseWithSources(nil, nil, nil)
+	if _, err := Start(useCase, "not a schedule", nil); err == nil {
+		t.Error("Expected an error for an invalid schedule")
+	}
+}
@@ -0,0 +1,124 @@
+package units
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ErrNoValue is returned by ParseNumber for the placeholders sources publish instead of
+// a reading, such as an empty cell, "-" or "N/A"
+var ErrNoValue = errors.New("no value")
+
+// noValueMarkers lists the upper-cased placeholders that stand for a missing reading
+var noValueMarkers = map[string]bool{"": true, "-": true, "–": true, "—": true, "N/A": true, "NA": true}
+
+// ParseNumber parses a number as published by the sources or typed by users. It accepts
+// an explicit "+" or "-" sign, a decimal comma or point and thousands separators, so
+// "1.234", "1 234" and "1.234,5" parse as 1234 and 1234.5. A single "." or "," is a
+// decimal separator, except that a "." followed by exactly three digits, as in "1.234",
+// groups thousands. Returns ErrNoValue for placeholders of a missing reading.
+func ParseNumber(raw string) (float64, error) {
+	return parseNumber(raw, true)
+}
+
+// ParseDecimal parses a number like ParseNumber, except that a single "." is always a
+// decimal point, for values published with three decimals such as "178.520"
+func ParseDecimal(raw string) (float64, error) {
+	return parseNumber(raw, false)
+}
+
+// parseNumber implements ParseNumber and ParseDecimal, loneDotGroups telling whether a
+// single "." followed by three digits groups thousands
+func parseNumber(raw string, loneDotGroups bool) (float64, error) {
+	value := strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, raw)
+	if noValueMarkers[strings.ToUpper(value)] {
+		return 0, ErrNoValue
+	}
+
+	sign := 1.0
+	switch {
+	case strings.HasPrefix(value, "+"):
+		value = value[1:]
+	case strings.HasPrefix(value, "-"):
+		sign, value = -1, value[1:]
+	case strings.HasPrefix(value, "−"):
+		sign, value = -1, strings.TrimPrefix(value, "−")
+	}
+
+	normalized, ok := normalizeSeparators(value, loneDotGroups)
+	if !ok || !isDecimal(normalized) {
+		return 0, fmt.Errorf("invalid number %q", raw)
+	}
+	number, err := strconv.ParseFloat(normalized, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", raw)
+	}
+	return sign * number, nil
+}
+
+// normalizeSeparators rewrites an unsigned number to use "." as its only decimal
+// separator and no thousands separators, reporting false when the grouping is invalid
+func normalizeSeparators(value string, loneDotGroups bool) (string, bool) {
+	lastDot, lastComma := strings.LastIndex(value, "."), strings.LastIndex(value, ",")
+	switch {
+	case lastDot >= 0 && lastComma >= 0:
+		// With both present the later one is the decimal separator
+		decimal, thousands := lastComma, "."
+		if lastDot > lastComma {
+			decimal, thousands = lastDot, ","
+		}
+		integer, ok := ungroup(value[:decimal], thousands)
+		return integer + "." + value[decimal+1:], ok
+	case strings.Count(value, ",") > 1:
+		return ungroup(value, ",")
+	case lastComma >= 0:
+		return strings.Replace(value, ",", ".", 1), true
+	case strings.Count(value, ".") > 1:
+		return ungroup(value, ".")
+	case loneDotGroups && lastDot >= 0 && len(value)-lastDot-1 == 3 && lastDot <= 3 && !strings.HasPrefix(value, "0"):
+		return ungroup(value, ".")
+	default:
+		return value, true
+	}
+}
+
+// ungroup removes thousands separators from an integer, reporting false unless the first
+// group has one to three digits and every later group exactly three
+func ungroup(integer, separator string) (string, bool) {
+	groups := strings.Split(integer, separator)
+	if len(groups[0]) == 0 || len(groups[0]) > 3 {
+		return "", false
+	}
+	for _, group := range groups[1:] {
+		if len(group) != 3 {
+			return "", false
+		}
+	}
+	return strings.Join(groups, ""), true
+}
+
+// isDecimal reports whether value is digits with at most one decimal point, which keeps
+// ParseFloat from accepting forms such as "Inf", "1e3" or hex
+func isDecimal(value string) bool {
+	digits := 0
+	points := 0
+	for _, r := range value {
+		switch {
+		case r >= '0' && r <= '9':
+			digits++
+		case r == '.':
+			points++
+		default:
+			return false
+		}
+	}
+	return digits > 0 && points <= 1
+}
@@ -0,0 +1,104 @@
+package units
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestParseNumber verifies signs, decimal commas, thousands separators and missing-value
+// placeholders are understood, and malformed numbers rejected
+func TestParseNumber(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    float64
+		wantErr error // ErrNoValue, or errInvalid for any other error
+	}{
+		{"142", 142, nil},
+		{" 142 ", 142, nil},
+		{"12,5", 12.5, nil},
+		{"12.5", 12.5, nil},
+		{"0.125", 0.125, nil},
+		{".5", 0.5, nil},
+		{"1.234", 1234, nil},
+		{"12.345", 12345, nil},
+		{"178.520", 178520, nil},
+		{"12.500", 12500, nil},
+		{"1234.5", 1234.5, nil},
+		{"1.234.567", 1234567, nil},
+		{"1,234,567", 1234567, nil},
+		{"1.234,5", 1234.5, nil},
+		{"1,234.5", 1234.5, nil},
+		{"1 234", 1234, nil},
+		{"1 234,5", 1234.5, nil},
+		{"+5", 5, nil},
+		{"-5", -5, nil},
+		{"−12", -12, nil},
+		{"+1.234", 1234, nil},
+		{"-1.234,5", -1234.5, nil},
+		{"-0,8", -0.8, nil},
+		{"", 0, ErrNoValue},
+		{"  ", 0, ErrNoValue},
+		{"-", 0, ErrNoValue},
+		{"–", 0, ErrNoValue},
+		{"N/A", 0, ErrNoValue},
+		{"n/a", 0, ErrNoValue},
+		{"abc", 0, errInvalid},
+		{"+", 0, errInvalid},
+		{"--5", 0, errInvalid},
+		{"1.23.4", 0, errInvalid},
+		{"12,5,3", 0, errInvalid},
+		{"1234.567.8", 0, errInvalid},
+		{"Inf", 0, errInvalid},
+		{"1e3", 0, errInvalid},
+		{"0x1p3", 0, errInvalid},
+		{"5 cm", 0, errInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := ParseNumber(tt.raw)
+			switch {
+			case tt.wantErr == nil && err != nil:
+				t.Fatalf("ParseNumber(%q) failed: %v", tt.raw, err)
+			case tt.wantErr == ErrNoValue && !errors.Is(err, ErrNoValue):
+				t.Fatalf("ParseNumber(%q) = %v, %v, want ErrNoValue", tt.raw, got, err)
+			case tt.wantErr == errInvalid && (err == nil || errors.Is(err, ErrNoValue)):
+				t.Fatalf("ParseNumber(%q) = %v, %v, want an invalid number error", tt.raw, got, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseNumber(%q) = %v, want %v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseDecimal verifies a single "." is read as a decimal point while the other
+// separators keep ParseNumber's rules
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    float64
+		wantErr bool
+	}{
+		{"178.520", 178.52, false},
+		{"12.345", 12.345, false},
+		{"0.125", 0.125, false},
+		{"129,47", 129.47, false},
+		{"1.234.567", 1234567, false},
+		{"1.234,5", 1234.5, false},
+		{"-0.850", -0.85, false},
+		{"-", 0, true},
+		{"1.23.4", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseDecimal(tt.raw)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("ParseDecimal(%q) failed: %v", tt.raw, err)
+		}
+		if got != tt.want {
+			t.Errorf("ParseDecimal(%q) = %v, want %v", tt.raw, got, tt.want)
+		}
+	}
+}
+
+// errInvalid marks the test cases expected to be rejected as malformed
+var errInvalid = errors.New("invalid")
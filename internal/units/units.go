@@ -0,0 +1,55 @@
+// Package units converts water levels between units of length
+package units
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Unit is a unit of length used for water levels
+type Unit string
+
+// Supported units
+const (
+	Centimeter Unit = "cm"
+	Meter      Unit = "m"
+	Foot       Unit = "ft"
+)
+
+// centimetersPer holds the length of each unit in centimeters
+var centimetersPer = map[Unit]float64{
+	Centimeter: 1,
+	Meter:      100,
+	Foot:       30.48,
+}
+
+// unitAliases maps the accepted spellings, including Serbian ones, to units
+var unitAliases = map[string]Unit{
+	"cm": Centimeter, "centimeter": Centimeter, "centimeters": Centimeter, "см": Centimeter,
+	"m": Meter, "meter": Meter, "meters": Meter, "metre": Meter, "metres": Meter, "м": Meter,
+	"ft": Foot, "foot": Foot, "feet": Foot,
+}
+
+// Units lists the supported units in display order
+var Units = []Unit{Centimeter, Meter, Foot}
+
+// ParseUnit parses a unit name such as "cm", "meters" or "ft"
+func ParseUnit(name string) (Unit, error) {
+	unit, ok := unitAliases[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return "", fmt.Errorf("unknown unit %q", name)
+	}
+	return unit, nil
+}
+
+// Convert converts value from one unit to another
+func Convert(value float64, from, to Unit) float64 {
+	return value * centimetersPer[from] / centimetersPer[to]
+}
+
+// Round rounds value to the given number of decimal places
+func Round(value float64, decimals int) float64 {
+	pow := math.Pow(10, float64(decimals))
+	return math.Round(value*pow) / pow
+}
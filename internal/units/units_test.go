@@ -0,0 +1,71 @@
+package units
+
+import "testing"
+
+// TestConvert verifies every conversion direction, rounded to two decimals
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		value    float64
+		from, to Unit
+		want     float64
+	}{
+		{325, Centimeter, Meter, 3.25},
+		{3.25, Meter, Centimeter, 325},
+		{325, Centimeter, Foot, 10.66},
+		{10, Foot, Centimeter, 304.8},
+		{3, Meter, Foot, 9.84},
+		{10, Foot, Meter, 3.05},
+		{-15, Centimeter, Meter, -0.15},
+		{42, Meter, Meter, 42},
+	}
+	for _, tt := range tests {
+		if got := Round(Convert(tt.value, tt.from, tt.to), 2); got != tt.want {
+			t.Errorf("Convert(%v %s to %s) = %v, want %v", tt.value, tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+// TestRound verifies rounding half away from zero at the requested precision
+func TestRound(t *testing.T) {
+	tests := []struct {
+		value    float64
+		decimals int
+		want     float64
+	}{
+		{10.663, 2, 10.66},
+		{10.665, 1, 10.7},
+		{2.5, 0, 3},
+		{-2.5, 0, -3},
+		{1.005, 3, 1.005},
+	}
+	for _, tt := range tests {
+		if got := Round(tt.value, tt.decimals); got != tt.want {
+			t.Errorf("Round(%v, %d) = %v, want %v", tt.value, tt.decimals, got, tt.want)
+		}
+	}
+}
+
+// TestParseUnit verifies unit names and aliases are recognized and unknown ones rejected
+func TestParseUnit(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Unit
+		wantErr bool
+	}{
+		{"cm", Centimeter, false},
+		{"CM", Centimeter, false},
+		{"см", Centimeter, false},
+		{"meters", Meter, false},
+		{" m ", Meter, false},
+		{"feet", Foot, false},
+		{"ft", Foot, false},
+		{"inch", "", true},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseUnit(tt.name)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseUnit(%q) = %q, %v; want %q, error %v", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
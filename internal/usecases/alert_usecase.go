@@ -0,0 +1,159 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// maxSubscriptionsPerChat limits how many alerts a single chat can register
+const maxSubscriptionsPerChat = 10
+
+// ErrUnknownRiver is returned when a river has no stored data
+var ErrUnknownRiver = errors.New("unknown river")
+
+// ErrTooManySubscriptions is returned when a chat already has the maximum number of alerts
+var ErrTooManySubscriptions = fmt.Errorf("a chat can have at most %d alerts", maxSubscriptionsPerChat)
+
+// Notification is a message that should be delivered to a chat
+type Notification struct {
+	ChatID int64
+	Text   string
+}
+
+// Subscribe registers a water level or temperature alert for a chat
+func (uc *RiverUseCase) Subscribe(chatID int64, river string, kind entities.AlertKind, direction entities.AlertDirection, threshold float64) (entities.Subscription, error) {
+	slog.Debug("Subscribing chat", "chat_id", chatID, "river", river, "kind", kind, "direction", direction, "threshold", threshold)
+
+	riverData, err := uc.findRiverData(river)
+	if err != nil {
+		return entities.Subscription{}, err
+	}
+	if len(riverData) == 0 {
+		return entities.Subscription{}, ErrUnknownRiver
+	}
+
+	existing, err := uc.repo.GetSubscriptionsByChat(chatID)
+	if err != nil {
+		return entities.Subscription{}, err
+	}
+	if len(existing) >= maxSubscriptionsPerChat {
+		return entities.Subscription{}, ErrTooManySubscriptions
+	}
+
+	sub := entities.Subscription{
+		ChatID:    chatID,
+		River:     riverData[0].River,
+		Kind:      kind,
+		Threshold: threshold,
+		Direction: direction,
+		CreatedAt: time.Now(),
+	}
+	sub.ID, err = uc.repo.AddSubscription(sub)
+	if err != nil {
+		return entities.Subscription{}, err
+	}
+
+	return sub, nil
+}
+
+// GetSubscriptions returns the alerts registered by a chat
+func (uc *RiverUseCase) GetSubscriptions(chatID int64) ([]entities.Subscription, error) {
+	slog.Debug("Retrieving subscriptions", "chat_id", chatID)
+	return uc.repo.GetSubscriptionsByChat(chatID)
+}
+
+// Unsubscribe deletes one of a chat's alerts, returning false if it doesn't belong to the chat
+func (uc *RiverUseCase) Unsubscribe(chatID, id int64) (bool, error) {
+	slog.Debug("Deleting subscription", "id", id, "chat_id", chatID)
+	return uc.repo.DeleteSubscription(chatID, id)
+}
+
+// CheckAlerts evaluates every subscription against the latest readings and returns
+// notifications for alerts whose condition has just started to hold. The triggered
+// state is persisted so an alert fires once per crossing rather than on every check.
+// A subscription that can't be checked is logged and retried on the next check, without
+// losing the notifications of the others.
+func (uc *RiverUseCase) CheckAlerts(now time.Time) ([]Notification, error) {
+	subs, err := uc.repo.GetAllSubscriptions()
+	if err != nil {
+		return nil, err
+	}
+
+	latest := make(map[string][]entities.RiverData)
+	var notifications []Notification
+	for _, sub := range subs {
+		riverData, ok := latest[sub.River]
+		if !ok {
+			riverData, err = uc.repo.GetRiverDataByName(sub.River)
+			if err != nil {
+				slog.Error("Failed to get river data for alert", "id", sub.ID, "river", sub.River, "error", err)
+				continue
+			}
+			latest[sub.River] = riverData
+		}
+
+		// Stations that don't publish the watched reading can't cross the threshold
+		var matching []entities.RiverData
+		for _, rd := range riverData {
+			value, ok := sub.Value(rd)
+			if ok && sub.Matches(value) {
+				matching = append(matching, rd)
+			}
+		}
+
+		switch {
+		case len(matching) > 0 && !sub.Triggered:
+			// Left untriggered when its state can't be saved, so the next check fires it
+			if err := uc.repo.UpdateSubscriptionState(sub.ID, true, now); err != nil {
+				slog.Error("Failed to save triggered alert", "id", sub.ID, "error", err)
+				continue
+			}
+			slog.Info("Alert triggered", "id", sub.ID, "chat_id", sub.ChatID, "river", sub.River, "kind", sub.Kind, "direction", sub.Direction, "threshold", sub.Threshold)
+			notifications = append(notifications, Notification{
+				ChatID: sub.ChatID,
+				Text:   formatAlert(sub, matching),
+			})
+		case len(matching) == 0 && sub.Triggered:
+			// The condition no longer holds, re-arm the alert for the next crossing
+			if err := uc.repo.UpdateSubscriptionState(sub.ID, false, sub.LastTriggered); err != nil {
+				slog.Error("Failed to re-arm alert", "id", sub.ID, "error", err)
+			}
+		}
+	}
+
+	return notifications, nil
+}
+
+// formatAlert formats the message sent when an alert fires
+func formatAlert(sub entities.Subscription, matching []entities.RiverData) string {
+	var result strings.Builder
+	if sub.Kind == entities.AlertTemp {
+		result.WriteString(fmt.Sprintf("🔔 Alert: river %s water temperature is %s %s\n\n", sub.River, sub.Direction, sub.FormatThreshold()))
+		for _, rd := range matching {
+			result.WriteString(fmt.Sprintf("📍 %s: %s °C\n", rd.Station, rd.WaterTemp))
+		}
+		return result.String()
+	}
+	result.WriteString(fmt.Sprintf("🔔 Alert: river %s is %s %s\n\n", sub.River, sub.Direction, sub.FormatThreshold()))
+	for _, rd := range matching {
+		result.WriteString(fmt.Sprintf("📍 %s: %s cm\n", rd.Station, rd.WaterLevel))
+	}
+	return result.String()
+}
+
+// FormatSubscription formats a single alert subscription for display
+func (uc *RiverUseCase) FormatSubscription(sub entities.Subscription) string {
+	lastTriggered := "never"
+	if !sub.LastTriggered.IsZero() {
+		lastTriggered = sub.LastTriggered.Format("2006-01-02 15:04 MST")
+	}
+	if sub.Kind == entities.AlertTemp {
+		return fmt.Sprintf("🌡️ %s water temperature %s %s (last triggered: %s)", sub.River, sub.Direction, sub.FormatThreshold(), lastTriggered)
+	}
+	return fmt.Sprintf("🔔 %s %s %s (last triggered: %s)", sub.River, sub.Direction, sub.FormatThreshold(), lastTriggered)
+}
@@ -0,0 +1,79 @@
+package usecases
+
+import (
+	"log/slog"
+	"strings"
+	"unicode"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+	"github.com/abelzeko/water-bot/internal/refdata"
+)
+
+// ResolveRiverAlias returns the river name an alias refers to, or name unchanged
+// when it is not a known alias.
+func ResolveRiverAlias(name string) string {
+	if river, ok := refdata.RiverAlias(name); ok {
+		return river
+	}
+	return name
+}
+
+// findRiverData returns the latest readings for a river, looking the name up as an
+// alias when nothing is stored under it. The name is tried in its canonical spelling
+// first, so a source that publishes a Latin name such as "SAVA" is never hidden by its alias.
+func (uc *RiverUseCase) findRiverData(river string) ([]entities.RiverData, error) {
+	river = entities.NormalizeRiverName(river)
+	riverData, err := uc.repo.GetRiverDataByName(river)
+	if err != nil || len(riverData) > 0 {
+		return riverData, err
+	}
+	canonical := ResolveRiverAlias(river)
+	if canonical == river {
+		return riverData, nil
+	}
+	slog.Debug("Resolved river alias", "alias", river, "river", canonical)
+	return uc.repo.GetRiverDataByName(canonical)
+}
+
+// latinDigraphs are the Serbian Latin letter pairs written as one Cyrillic letter
+var latinDigraphs = strings.NewReplacer(
+	"Lj", "Љ", "LJ", "Љ", "lj", "љ",
+	"Nj", "Њ", "NJ", "Њ", "nj", "њ",
+	"Dž", "Џ", "DŽ", "Џ", "dž", "џ",
+)
+
+// latinLetters maps the remaining Serbian Latin letters to Cyrillic
+var latinLetters = map[rune]rune{
+	'a': 'а', 'b': 'б', 'c': 'ц', 'č': 'ч', 'ć': 'ћ', 'd': 'д', 'đ': 'ђ', 'e': 'е',
+	'f': 'ф', 'g': 'г', 'h': 'х', 'i': 'и', 'j': 'ј', 'k': 'к', 'l': 'л', 'm': 'м',
+	'n': 'н', 'o': 'о', 'p': 'п', 'r': 'р', 's': 'с', 'š': 'ш', 't': 'т', 'u': 'у',
+	'v': 'в', 'z': 'з', 'ž': 'ж',
+}
+
+// latinToCyrillic transliterates Serbian Latin text to Cyrillic, so "Radalj" can
+// be matched against the stored "Радаљ". Other characters are kept as they are.
+func latinToCyrillic(text string) string {
+	return strings.Map(func(r rune) rune {
+		lower := unicode.ToLower(r)
+		cyrillic, ok := latinLetters[lower]
+		if !ok {
+			return r
+		}
+		if lower != r {
+			return unicode.ToUpper(cyrillic)
+		}
+		return cyrillic
+	}, latinDigraphs.Replace(text))
+}
+
+// matchStation finds the stored name of a station among a river's readings. Names
+// match case-insensitively, and Serbian Latin spellings match their Cyrillic names.
+func matchStation(riverData []entities.RiverData, station string) (string, bool) {
+	cyrillic := latinToCyrillic(station)
+	for _, rd := range riverData {
+		if strings.EqualFold(rd.Station, station) || strings.EqualFold(rd.Station, cyrillic) {
+			return rd.Station, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,62 @@
+package usecases
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// TestGetRiverDataByAlias verifies configured aliases reach the stored Cyrillic river
+func TestGetRiverDataByAlias(t *testing.T) {
+	repo := newTestRepository(t)
+	uc := NewRiverUseCase(repo, nil, nil)
+
+	now := time.Now()
+	err := repo.SaveRiverData([]entities.RiverData{
+		{River: "ГРАДАЦ", Station: "Дегурић", WaterLevel: "85", Timestamp: now},
+		{River: "ДРИНА", Station: "Радаљ", WaterLevel: "142", Timestamp: now},
+		{River: "ДУНАВ", Station: "Београд", WaterLevel: "310", Timestamp: now},
+		{River: "SAVA", Station: "Zagreb", WaterLevel: "-105", Timestamp: now},
+		{River: "САВА", Station: "Шабац", WaterLevel: "220", Timestamp: now},
+	})
+	if err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		query   string
+		river   string
+		station string
+	}{
+		{"latin transliteration", "Gradac", "ГРАДАЦ", "Дегурић"},
+		{"upper case latin", "DRINA", "ДРИНА", "Радаљ"},
+		{"english name", "danube", "ДУНАВ", "Београд"},
+		{"station name", "Degurić", "ГРАДАЦ", "Дегурић"},
+		{"stored name wins over alias", "SAVA", "SAVA", "Zagreb"},
+		{"canonical name", "САВА", "САВА", "Шабац"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			riverData, err := uc.GetRiverDataByName(tt.query)
+			if err != nil {
+				t.Fatalf("Failed to get data: %v", err)
+			}
+			if len(riverData) != 1 {
+				t.Fatalf("Expected 1 reading, got %d", len(riverData))
+			}
+			if riverData[0].River != tt.river || riverData[0].Station != tt.station {
+				t.Errorf("Expected %s/%s, got %s/%s", tt.river, tt.station, riverData[0].River, riverData[0].Station)
+			}
+		})
+	}
+
+	riverData, err := uc.GetRiverDataByName("Nonexistent")
+	if err != nil {
+		t.Fatalf("Failed to get data: %v", err)
+	}
+	if len(riverData) != 0 {
+		t.Errorf("Expected no data for an unknown name, got %d readings", len(riverData))
+	}
+}
@@ -0,0 +1,76 @@
+package usecases
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+	"github.com/abelzeko/water-bot/internal/units"
+)
+
+// StationDiff is a station's latest reading next to the reading stored before it
+type StationDiff struct {
+	Current     entities.RiverData
+	Previous    entities.RiverData // Valid when HasPrevious is set
+	HasPrevious bool
+	Change      float64 // Change in cm since Previous, valid when HasChange is set
+	HasChange   bool
+	Interval    time.Duration // Time between Previous and Current, valid when HasPrevious is set
+}
+
+// DiffLastReadings pairs the latest reading of each station of a river with the one
+// stored before it. Stations with a single stored reading are returned without one.
+func (uc *RiverUseCase) DiffLastReadings(riverData []entities.RiverData) ([]StationDiff, error) {
+	diffs := make([]StationDiff, 0, len(riverData))
+	for _, rd := range riverData {
+		diff := StationDiff{Current: rd}
+
+		last, err := uc.repo.GetLastTwoReadings(rd.River, rd.Station)
+		if err != nil {
+			return nil, err
+		}
+		if len(last) == 2 {
+			previous := last[1]
+			previous.Timestamp = previous.Timestamp.In(rd.Timestamp.Location())
+			diff.Previous = previous
+			diff.HasPrevious = true
+			diff.Interval = rd.Timestamp.Sub(previous.Timestamp)
+
+			current, currentOK := rd.LevelValue()
+			past, pastOK := previous.LevelValue()
+			if currentOK && pastOK {
+				diff.Change = units.Round(current-past, 1)
+				diff.HasChange = true
+			}
+		}
+		diffs = append(diffs, diff)
+	}
+	return diffs, nil
+}
+
+// FormatDiff formats each station's latest level with its change since the previous reading
+func (uc *RiverUseCase) FormatDiff(diffs []StationDiff) string {
+	if len(diffs) == 0 {
+		return "No information available for this river."
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Changes since the previous reading for river %s:\n\n", diffs[0].Current.River))
+	for _, diff := range diffs {
+		current := diff.Current
+		result.WriteString(fmt.Sprintf("📍 Station: %s\n", current.Station))
+		result.WriteString(fmt.Sprintf("💧 Now: %s cm (%s)\n", current.WaterLevel, current.Timestamp.Format("2006-01-02 15:04")))
+		if !diff.HasPrevious {
+			result.WriteString("🕰️ Previous: only one reading stored\n\n")
+			continue
+		}
+		previous := diff.Previous
+		result.WriteString(fmt.Sprintf("🕰️ Previous: %s cm (%s, %s earlier)\n", previous.WaterLevel, previous.Timestamp.Format("2006-01-02 15:04"), formatWindow(diff.Interval.Round(time.Minute))))
+		if diff.HasChange {
+			result.WriteString(fmt.Sprintf("📏 Change: %+g cm\n", diff.Change))
+		}
+		result.WriteString("\n")
+	}
+	return result.String()
+}
@@ -0,0 +1,60 @@
+package usecases
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// TestDiffLastReadings verifies each station is compared with the reading stored just
+// before its latest one, and stations with a single reading are reported as such
+func TestDiffLastReadings(t *testing.T) {
+	repo := newTestRepository(t)
+	uc := NewRiverUseCase(repo, nil, nil)
+
+	now := time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC)
+	err := repo.SaveRiverData([]entities.RiverData{
+		{River: "ДРИНА", Station: "Радаљ", WaterLevel: "130", Timestamp: now.Add(-3 * time.Hour)},
+		{River: "ДРИНА", Station: "Радаљ", WaterLevel: "135", Timestamp: now.Add(-90 * time.Minute)},
+		{River: "ДРИНА", Station: "Радаљ", WaterLevel: "142", Timestamp: now},
+		{River: "ДРИНА", Station: "Бајина Башта", WaterLevel: "88", Timestamp: now},
+	})
+	if err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+
+	riverData, err := uc.GetRiverDataByName("ДРИНА")
+	if err != nil {
+		t.Fatalf("Failed to get river data: %v", err)
+	}
+	diffs, err := uc.DiffLastReadings(riverData)
+	if err != nil {
+		t.Fatalf("Failed to diff readings: %v", err)
+	}
+
+	byStation := make(map[string]StationDiff)
+	for _, diff := range diffs {
+		byStation[diff.Current.Station] = diff
+	}
+	radalj := byStation["Радаљ"]
+	if !radalj.HasPrevious || radalj.Previous.WaterLevel != "135" || !radalj.HasChange || radalj.Change != 7 || radalj.Interval != 90*time.Minute {
+		t.Errorf("Expected Радаљ compared with 135 cm 1h30m earlier for +7 cm, got %+v", radalj)
+	}
+	if bajina := byStation["Бајина Башта"]; bajina.HasPrevious || bajina.HasChange {
+		t.Errorf("Expected no previous reading for Бајина Башта, got %+v", bajina)
+	}
+
+	text := uc.FormatDiff(diffs)
+	for _, want := range []string{
+		"Changes since the previous reading for river ДРИНА",
+		"🕰️ Previous: 135 cm (2025-04-18 04:30, 1h30m earlier)",
+		"📏 Change: +7 cm",
+		"🕰️ Previous: only one reading stored",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in:\n%s", want, text)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// DefaultTimeZone is the time zone of the sources, used for digest hours and
+// timestamps when the user hasn't set one with /tz
+const DefaultTimeZone = "Europe/Belgrade"
+
+// maxDigestRivers limits how many rivers a single digest can include
+const maxDigestRivers = 10
+
+// ErrTooManyDigestRivers is returned when a digest already includes the maximum number of rivers
+var ErrTooManyDigestRivers = fmt.Errorf("a digest can include at most %d rivers", maxDigestRivers)
+
+// ErrInvalidHour is returned when a digest send hour is outside 0-23
+var ErrInvalidHour = errors.New("hour must be between 0 and 23")
+
+// AddDigestRiver adds a river to a chat's daily digest and sets the hour it is sent at
+func (uc *RiverUseCase) AddDigestRiver(chatID int64, river string, hour int) (entities.Digest, error) {
+	slog.Debug("Adding river to daily digest", "river", river, "chat_id", chatID, "hour", hour)
+
+	if hour < 0 || hour > 23 {
+		return entities.Digest{}, ErrInvalidHour
+	}
+
+	riverData, err := uc.findRiverData(river)
+	if err != nil {
+		return entities.Digest{}, err
+	}
+	if len(riverData) == 0 {
+		return entities.Digest{}, ErrUnknownRiver
+	}
+	river = riverData[0].River
+
+	digest, found, err := uc.repo.GetDigest(chatID)
+	if err != nil {
+		return entities.Digest{}, err
+	}
+	if !found {
+		timeZone, err := uc.UserTimeZone(chatID)
+		if err != nil {
+			return entities.Digest{}, err
+		}
+		digest = entities.Digest{ChatID: chatID, TimeZone: timeZone, CreatedAt: time.Now()}
+	}
+
+	if !containsRiver(digest.Rivers, river) {
+		if len(digest.Rivers) >= maxDigestRivers {
+			return entities.Digest{}, ErrTooManyDigestRivers
+		}
+		digest.Rivers = append(digest.Rivers, river)
+	}
+	digest.SendHour = hour
+
+	if err := uc.repo.SaveDigest(digest); err != nil {
+		return entities.Digest{}, err
+	}
+	return digest, nil
+}
+
+// RemoveDigestRiver removes a river from a chat's daily digest, deleting the digest once empty.
+// Returns false if the river wasn't part of the digest.
+func (uc *RiverUseCase) RemoveDigestRiver(chatID int64, river string) (bool, error) {
+	slog.Debug("Removing river from daily digest", "river", river, "chat_id", chatID)
+
+	digest, found, err := uc.repo.GetDigest(chatID)
+	if err != nil || !found {
+		return false, err
+	}
+
+	var remaining []string
+	for _, r := range digest.Rivers {
+		if !strings.EqualFold(r, river) {
+			remaining = append(remaining, r)
+		}
+	}
+	if len(remaining) == len(digest.Rivers) {
+		return false, nil
+	}
+
+	if len(remaining) == 0 {
+		_, err := uc.repo.DeleteDigest(chatID)
+		return err == nil, err
+	}
+
+	digest.Rivers = remaining
+	if err := uc.repo.SaveDigest(digest); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// GetDigest returns a chat's daily digest, reporting false if it has none
+func (uc *RiverUseCase) GetDigest(chatID int64) (entities.Digest, bool, error) {
+	slog.Debug("Retrieving daily digest", "chat_id", chatID)
+	return uc.repo.GetDigest(chatID)
+}
+
+// DueDigests builds the digests that should be sent during the hour containing now
+func (uc *RiverUseCase) DueDigests(now time.Time) ([]Notification, error) {
+	digests, err := uc.repo.GetAllDigests()
+	if err != nil {
+		return nil, err
+	}
+
+	var notifications []Notification
+	for _, digest := range digests {
+		if !digest.IsDue(now) {
+			continue
+		}
+
+		var result strings.Builder
+		result.WriteString("📰 Your daily river digest\n\n")
+		for _, river := range digest.Rivers {
+			riverData, err := uc.repo.GetRiverDataByName(river)
+			if err != nil {
+				return nil, err
+			}
+			if len(riverData) == 0 {
+				result.WriteString(fmt.Sprintf("No information available for river %s.\n\n", river))
+				continue
+			}
+			entities.MarkStale(riverData, now, uc.staleAfter)
+			uc.LocalizeForChat(digest.ChatID, riverData)
+			result.WriteString(uc.FormatRiverInfo(riverData))
+		}
+
+		notifications = append(notifications, Notification{ChatID: digest.ChatID, Text: result.String()})
+	}
+
+	slog.Debug("Daily digests due", "due", len(notifications), "total", len(digests), "at", now.Format(time.RFC3339))
+	return notifications, nil
+}
+
+// FormatDigest formats a chat's daily digest settings for display
+func (uc *RiverUseCase) FormatDigest(digest entities.Digest) string {
+	return fmt.Sprintf("📰 Daily digest at %02d:00 (%s):\n\n• %s",
+		digest.SendHour, digest.TimeZone, strings.Join(digest.Rivers, "\n• "))
+}
+
+// containsRiver reports whether rivers includes river, ignoring case
+func containsRiver(rivers []string, river string) bool {
+	for _, r := range rivers {
+		if strings.EqualFold(r, river) {
+			return true
+		}
+	}
+	return false
+}
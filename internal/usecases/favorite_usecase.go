@@ -0,0 +1,116 @@
+package usecases
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// maxFavorites limits how many rivers a single chat can save as favorites
+const maxFavorites = 10
+
+// ErrTooManyFavorites is returned when a chat already has the maximum number of favorites
+var ErrTooManyFavorites = fmt.Errorf("at most %d rivers can be saved as favorites", maxFavorites)
+
+// AddFavorite saves a river as one of a chat's favorites and returns its canonical name.
+// Adding a river that is already a favorite succeeds without changing anything.
+func (uc *RiverUseCase) AddFavorite(chatID int64, river string) (string, error) {
+	slog.Debug("Adding favorite river", "river", river, "chat_id", chatID)
+
+	riverData, err := uc.findRiverData(river)
+	if err != nil {
+		return "", err
+	}
+	if len(riverData) == 0 {
+		return "", ErrUnknownRiver
+	}
+	river = riverData[0].River
+
+	favorites, err := uc.repo.GetFavorites(chatID)
+	if err != nil {
+		return "", err
+	}
+	if containsRiver(favorites, river) {
+		return river, nil
+	}
+	if len(favorites) >= maxFavorites {
+		return "", ErrTooManyFavorites
+	}
+
+	if _, err := uc.repo.AddFavorite(chatID, river); err != nil {
+		return "", err
+	}
+	return river, nil
+}
+
+// RemoveFavorite removes a river from a chat's favorites.
+// Returns false if the river wasn't a favorite.
+func (uc *RiverUseCase) RemoveFavorite(chatID int64, river string) (bool, error) {
+	slog.Debug("Removing favorite river", "river", river, "chat_id", chatID)
+
+	favorites, err := uc.repo.GetFavorites(chatID)
+	if err != nil {
+		return false, err
+	}
+	for _, favorite := range favorites {
+		if strings.EqualFold(favorite, river) {
+			return uc.repo.RemoveFavorite(chatID, favorite)
+		}
+	}
+	return false, nil
+}
+
+// GetFavorites returns a chat's favorite rivers in the order they were added
+func (uc *RiverUseCase) GetFavorites(chatID int64) ([]string, error) {
+	slog.Debug("Retrieving favorites", "chat_id", chatID)
+	return uc.repo.GetFavorites(chatID)
+}
+
+// FormatFavorites formats the highest current reading of each favorite river of a chat
+func (uc *RiverUseCase) FormatFavorites(chatID int64, favorites []string) (string, error) {
+	now := time.Now()
+
+	var result strings.Builder
+	result.WriteString("⭐ Your favorite rivers\n\n")
+	for _, river := range favorites {
+		riverData, err := uc.repo.GetRiverDataByName(river)
+		if err != nil {
+			return "", err
+		}
+
+		result.WriteString(fmt.Sprintf("River %s:\n", river))
+		entities.MarkStale(riverData, now, uc.staleAfter)
+		uc.LocalizeForChat(chatID, riverData)
+		top, ok := topStation(riverData)
+		if !ok {
+			result.WriteString("No current readings.\n\n")
+			continue
+		}
+		writeStationInfo(&result, top)
+	}
+	return result.String(), nil
+}
+
+// topStation returns the reading with the highest water level, falling back
+// to the first reading when no level can be parsed
+func topStation(riverData []entities.RiverData) (entities.RiverData, bool) {
+	if len(riverData) == 0 {
+		return entities.RiverData{}, false
+	}
+
+	top := riverData[0]
+	topLevel, found := 0.0, false
+	for _, rd := range riverData {
+		level, ok := rd.LevelValue()
+		if !ok {
+			continue
+		}
+		if !found || level > topLevel {
+			top, topLevel, found = rd, level, true
+		}
+	}
+	return top, true
+}
@@ -0,0 +1,96 @@
+package usecases
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// FloodStatus is a station's latest reading classified against its flood defence thresholds
+type FloodStatus struct {
+	Reading entities.RiverData
+	Level   entities.FloodLevel
+	Stage   entities.FloodStage
+	Value   float64 // Parsed water level in cm
+}
+
+// floodKey identifies a station independently of how each source capitalizes its name
+func floodKey(river, station string) string {
+	return strings.ToUpper(strings.TrimSpace(river)) + "|" + strings.ToUpper(strings.TrimSpace(station))
+}
+
+// GetFloodStatus returns the stations whose latest reading is at or above their flood
+// warning level, danger first and then by how far above the warning level they are
+func (uc *RiverUseCase) GetFloodStatus() ([]FloodStatus, error) {
+	slog.Debug("Checking latest readings against flood levels")
+	levels, err := uc.repo.GetFloodLevels()
+	if err != nil {
+		return nil, err
+	}
+	thresholds := make(map[string]entities.FloodLevel, len(levels))
+	for _, level := range levels {
+		thresholds[floodKey(level.River, level.Station)] = level
+	}
+
+	latest, err := uc.repo.GetLatestReadings()
+	if err != nil {
+		return nil, err
+	}
+	entities.MarkStale(latest, time.Now(), uc.staleAfter)
+
+	return classifyFloods(latest, thresholds), nil
+}
+
+// classifyFloods returns the readings at or above their station's warning level, most severe first
+func classifyFloods(latest []entities.RiverData, thresholds map[string]entities.FloodLevel) []FloodStatus {
+	var statuses []FloodStatus
+	for _, rd := range latest {
+		level, ok := thresholds[floodKey(rd.River, rd.Station)]
+		if !ok {
+			continue
+		}
+		value, ok := rd.LevelValue()
+		if !ok {
+			continue
+		}
+		if stage := level.Classify(value); stage != entities.FloodNormal {
+			statuses = append(statuses, FloodStatus{Reading: rd, Level: level, Stage: stage, Value: value})
+		}
+	}
+
+	sort.SliceStable(statuses, func(i, j int) bool {
+		if statuses[i].Stage != statuses[j].Stage {
+			return statuses[i].Stage == entities.FloodDanger
+		}
+		return statuses[i].Value-statuses[i].Level.WarningCm > statuses[j].Value-statuses[j].Level.WarningCm
+	})
+	return statuses
+}
+
+// FormatFloodStatus formats the stations above flood levels with timestamps shown in loc
+func (uc *RiverUseCase) FormatFloodStatus(statuses []FloodStatus, loc *time.Location) string {
+	if len(statuses) == 0 {
+		return "✅ No station is above its flood warning level."
+	}
+
+	var result strings.Builder
+	result.WriteString("🌊 Stations above flood levels:\n\n")
+	for _, status := range statuses {
+		rd := status.Reading
+		if status.Stage == entities.FloodDanger {
+			result.WriteString(fmt.Sprintf("🔴 %s - %s: %s cm, danger level %g cm\n", rd.River, rd.Station, rd.WaterLevel, status.Level.DangerCm))
+		} else {
+			result.WriteString(fmt.Sprintf("🟠 %s - %s: %s cm, warning level %g cm\n", rd.River, rd.Station, rd.WaterLevel, status.Level.WarningCm))
+		}
+		result.WriteString(fmt.Sprintf("   🕒 %s", rd.Timestamp.In(loc).Format("2006-01-02 15:04 MST")))
+		if rd.Stale {
+			result.WriteString(" (outdated)")
+		}
+		result.WriteString("\n")
+	}
+	return result.String()
+}
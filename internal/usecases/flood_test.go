@@ -0,0 +1,62 @@
+package usecases
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// TestGetFloodStatus verifies latest readings are classified against the seeded flood levels
+func TestGetFloodStatus(t *testing.T) {
+	repo := newTestRepository(t)
+	uc := NewRiverUseCaseWithSources(repo, nil, nil)
+
+	now := time.Now().UTC().Truncate(time.Minute)
+	err := repo.SaveRiverData([]entities.RiverData{
+		// An older reading above danger is superseded by the latest one
+		{River: "ДУНАВ", Station: "Бездан", WaterLevel: "700", Timestamp: now.Add(-2 * time.Hour)},
+		{River: "ДУНАВ", Station: "Бездан", WaterLevel: "480", Timestamp: now},
+		{River: "ДУНАВ", Station: "Земун", WaterLevel: "612", Timestamp: now},
+		{River: "САВА", Station: "Шабац", WaterLevel: "510", Timestamp: now},
+		{River: "ДУНАВ", Station: "НОВИ САД", WaterLevel: "560", Timestamp: now},
+		{River: "ДРИНА", Station: "Радаљ", WaterLevel: "142", Timestamp: now},
+		{River: "ДРИНА", Station: "Фоча", WaterLevel: "900", Timestamp: now}, // No thresholds
+		{River: "САВА", Station: "Београд", WaterLevel: "-", Timestamp: now},
+	})
+	if err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+
+	statuses, err := uc.GetFloodStatus()
+	if err != nil {
+		t.Fatalf("Failed to get flood status: %v", err)
+	}
+	want := []struct {
+		station string
+		stage   entities.FloodStage
+	}{
+		{"Земун", entities.FloodDanger},
+		{"НОВИ САД", entities.FloodWarning},
+		{"Шабац", entities.FloodWarning},
+	}
+	if len(statuses) != len(want) {
+		t.Fatalf("Expected %d stations above flood levels, got %+v", len(want), statuses)
+	}
+	for i, w := range want {
+		if statuses[i].Reading.Station != w.station || statuses[i].Stage != w.stage {
+			t.Errorf("Status %d: expected %s at %s, got %s at %s", i, w.station, w.stage, statuses[i].Reading.Station, statuses[i].Stage)
+		}
+	}
+
+	text := uc.FormatFloodStatus(statuses, time.UTC)
+	for _, want := range []string{"🔴 ДУНАВ - Земун: 612 cm, danger level 600 cm", "🟠 САВА - Шабац: 510 cm, warning level 500 cm"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in:\n%s", want, text)
+		}
+	}
+	if text := uc.FormatFloodStatus(nil, time.UTC); !strings.Contains(text, "No station is above") {
+		t.Errorf("Expected an all-clear message, got:\n%s", text)
+	}
+}
@@ -0,0 +1,146 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// maxFollowsPerChat limits how many stations a single chat can follow
+const maxFollowsPerChat = 5
+
+// ErrTooManyFollows is returned when a chat already follows the maximum number of stations
+var ErrTooManyFollows = fmt.Errorf("a chat can follow at most %d stations", maxFollowsPerChat)
+
+// Follow makes a chat receive every new water level of a station and returns the follow
+// with the stored river and station names. Following a station again succeeds without
+// changing anything. The current level counts as sent, only later changes are pushed.
+func (uc *RiverUseCase) Follow(chatID int64, river, station string) (entities.Follow, error) {
+	slog.Debug("Following station", "chat_id", chatID, "river", river, "station", station)
+
+	river, station, err := uc.resolveRiverStation(river, station)
+	if err != nil {
+		return entities.Follow{}, err
+	}
+
+	follows, err := uc.repo.GetFollowsByChat(chatID)
+	if err != nil {
+		return entities.Follow{}, err
+	}
+	for _, follow := range follows {
+		if follow.River == river && follow.Station == station {
+			return follow, nil
+		}
+	}
+	if len(follows) >= maxFollowsPerChat {
+		return entities.Follow{}, ErrTooManyFollows
+	}
+
+	latest, _, err := uc.repo.GetStationLatest(river, station)
+	if err != nil {
+		return entities.Follow{}, err
+	}
+	follow := entities.Follow{
+		ChatID:    chatID,
+		River:     river,
+		Station:   station,
+		LastLevel: latest.WaterLevel,
+		CreatedAt: time.Now(),
+	}
+	if _, err := uc.repo.AddFollow(follow); err != nil {
+		return entities.Follow{}, err
+	}
+	return follow, nil
+}
+
+// Unfollow stops the updates of a station to a chat. Names match like in Follow, and
+// also when the station no longer has data. Returns false if the chat didn't follow it.
+func (uc *RiverUseCase) Unfollow(chatID int64, river, station string) (bool, error) {
+	slog.Debug("Unfollowing station", "chat_id", chatID, "river", river, "station", station)
+
+	if resolvedRiver, resolvedStation, err := uc.resolveRiverStation(river, station); err == nil {
+		river, station = resolvedRiver, resolvedStation
+	} else if !errors.Is(err, ErrUnknownRiver) && !errors.Is(err, ErrUnknownStation) {
+		return false, err
+	}
+
+	follows, err := uc.repo.GetFollowsByChat(chatID)
+	if err != nil {
+		return false, err
+	}
+	for _, follow := range follows {
+		if strings.EqualFold(follow.River, river) && strings.EqualFold(follow.Station, station) {
+			return uc.repo.RemoveFollow(chatID, follow.River, follow.Station)
+		}
+	}
+	return false, nil
+}
+
+// GetFollows returns the stations a chat follows
+func (uc *RiverUseCase) GetFollows(chatID int64) ([]entities.Follow, error) {
+	slog.Debug("Retrieving follows", "chat_id", chatID)
+	return uc.repo.GetFollowsByChat(chatID)
+}
+
+// CheckFollows returns an update for each followed station whose latest water level
+// differs from the one last sent to the chat, and records the level as sent. A new
+// reading at the same level sends nothing. A follow that can't be checked is logged and
+// retried on the next check, without losing the updates of the others.
+func (uc *RiverUseCase) CheckFollows() ([]Notification, error) {
+	follows, err := uc.repo.GetAllFollows()
+	if err != nil {
+		return nil, err
+	}
+
+	var notifications []Notification
+	for _, follow := range follows {
+		latest, found, err := uc.repo.GetStationLatest(follow.River, follow.Station)
+		if err != nil {
+			slog.Error("Failed to get followed station", "chat_id", follow.ChatID, "river", follow.River, "station", follow.Station, "error", err)
+			continue
+		}
+		if !found || strings.TrimSpace(latest.WaterLevel) == "" || latest.WaterLevel == follow.LastLevel {
+			continue
+		}
+
+		level, err := uc.stationLevel(latest)
+		if err != nil {
+			slog.Error("Failed to get followed station level", "chat_id", follow.ChatID, "river", follow.River, "station", follow.Station, "error", err)
+			continue
+		}
+		// The level counts as unsent when it can't be recorded, so the next check sends it
+		if err := uc.repo.UpdateFollowLevel(follow.ChatID, follow.River, follow.Station, latest.WaterLevel); err != nil {
+			slog.Error("Failed to record followed station level", "chat_id", follow.ChatID, "river", follow.River, "station", follow.Station, "error", err)
+			continue
+		}
+		slog.Debug("Followed station changed", "chat_id", follow.ChatID, "river", follow.River, "station", follow.Station, "level", latest.WaterLevel)
+		notifications = append(notifications, Notification{
+			ChatID: follow.ChatID,
+			Text:   "📡 Followed station update\n\n" + uc.FormatStationLevel(level, uc.UserLocation(follow.ChatID)),
+		})
+	}
+	return notifications, nil
+}
+
+// FormatFollows formats the stations a chat follows
+func FormatFollows(follows []entities.Follow) string {
+	if len(follows) == 0 {
+		return "You don't follow any stations. Use /follow [river] [station] to get its new levels as they come in."
+	}
+
+	var result strings.Builder
+	result.WriteString("Stations you follow:\n\n")
+	for _, follow := range follows {
+		result.WriteString(fmt.Sprintf("📡 %s - %s", follow.River, follow.Station))
+		if follow.LastLevel != "" {
+			result.WriteString(fmt.Sprintf(" (last sent: %s cm)", follow.LastLevel))
+		}
+		result.WriteString("\n")
+	}
+	result.WriteString("\nUse /unfollow [river] [station] to stop the updates.")
+	return result.String()
+}
@@ -0,0 +1,135 @@
+package usecases
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+	"github.com/abelzeko/water-bot/internal/repository"
+)
+
+// TestCheckFollowsSkipsUnchangedLevels verifies a followed station is only pushed
+// when a refresh brings a level different from the one last sent
+func TestCheckFollowsSkipsUnchangedLevels(t *testing.T) {
+	repo := newTestRepository(t)
+	uc := NewRiverUseCaseWithSources(repo, nil, nil)
+
+	save := func(level string, ts time.Time) {
+		t.Helper()
+		err := repo.SaveRiverData([]entities.RiverData{
+			{River: "ДРИНА", Station: "Радаљ", WaterLevel: level, Timestamp: ts},
+			{River: "ДРИНА", Station: "Бајина Башта", WaterLevel: level, Timestamp: ts},
+		})
+		if err != nil {
+			t.Fatalf("Failed to save data: %v", err)
+		}
+	}
+
+	start := time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC)
+	save("142", start)
+
+	follow, err := uc.Follow(42, "drina", "radalj")
+	if err != nil {
+		t.Fatalf("Failed to follow: %v", err)
+	}
+	if follow.River != "ДРИНА" || follow.Station != "Радаљ" {
+		t.Fatalf("Expected the stored names, got %s - %s", follow.River, follow.Station)
+	}
+
+	steps := []struct {
+		level string
+		sends bool
+	}{
+		{"142", false}, // same level as when followed
+		{"150", true},  // rose
+		{"150", false}, // new reading, unchanged level
+		{"", false},    // no level published
+		{"148", true},  // fell
+	}
+	for i, step := range steps {
+		save(step.level, start.Add(time.Duration(i+1)*time.Hour))
+		notifications, err := uc.CheckFollows()
+		if err != nil {
+			t.Fatalf("Step %d: failed to check follows: %v", i, err)
+		}
+		if sent := len(notifications) == 1; sent != step.sends {
+			t.Errorf("Step %d (level %q): expected sent=%v, got %d notifications", i, step.level, step.sends, len(notifications))
+		}
+		if step.sends && len(notifications) == 1 {
+			n := notifications[0]
+			if n.ChatID != 42 || !strings.Contains(n.Text, "ДРИНА - Радаљ") || !strings.Contains(n.Text, step.level+" cm") {
+				t.Errorf("Step %d: unexpected update to chat %d:\n%s", i, n.ChatID, n.Text)
+			}
+		}
+	}
+
+	removed, err := uc.Unfollow(42, "ДРИНА", "радаљ")
+	if err != nil || !removed {
+		t.Fatalf("Expected the follow removed, got %v, %v", removed, err)
+	}
+	save("160", start.Add(10*time.Hour))
+	if notifications, err := uc.CheckFollows(); err != nil || len(notifications) != 0 {
+		t.Errorf("Expected no updates after unfollowing, got %d, %v", len(notifications), err)
+	}
+}
+
+// failingStationRepository fails to read the latest reading of the stations in failing
+type failingStationRepository struct {
+	*repository.SQLiteRiverRepository
+	failing map[string]bool
+}
+
+// GetStationLatest fails for a failing station
+func (r failingStationRepository) GetStationLatest(river, station string) (entities.RiverData, bool, error) {
+	if r.failing[station] {
+		return entities.RiverData{}, false, errors.New("database is locked")
+	}
+	return r.SQLiteRiverRepository.GetStationLatest(river, station)
+}
+
+// TestCheckFollowsKeepsUpdatesWhenOneFails verifies a follow that can't be checked doesn't
+// drop the updates of the others and is sent on the next check
+func TestCheckFollowsKeepsUpdatesWhenOneFails(t *testing.T) {
+	repo := failingStationRepository{SQLiteRiverRepository: newTestRepository(t), failing: map[string]bool{}}
+	uc := NewRiverUseCaseWithSources(repo, nil, nil)
+
+	save := func(level string, ts time.Time) {
+		t.Helper()
+		err := repo.SaveRiverData([]entities.RiverData{
+			{River: "ДРИНА", Station: "Радаљ", WaterLevel: level, Timestamp: ts},
+			{River: "ДРИНА", Station: "Бајина Башта", WaterLevel: level, Timestamp: ts},
+		})
+		if err != nil {
+			t.Fatalf("Failed to save data: %v", err)
+		}
+	}
+
+	start := time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC)
+	save("142", start)
+	for _, station := range []string{"Радаљ", "Бајина Башта"} {
+		if _, err := uc.Follow(42, "ДРИНА", station); err != nil {
+			t.Fatalf("Failed to follow %s: %v", station, err)
+		}
+	}
+	save("150", start.Add(time.Hour))
+
+	repo.failing["Бајина Башта"] = true
+	notifications, err := uc.CheckFollows()
+	if err != nil {
+		t.Fatalf("Expected the check to succeed, got %v", err)
+	}
+	if len(notifications) != 1 || !strings.Contains(notifications[0].Text, "ДРИНА - Радаљ") {
+		t.Fatalf("Expected only the Радаљ update, got %+v", notifications)
+	}
+
+	repo.failing["Бајина Башта"] = false
+	notifications, err = uc.CheckFollows()
+	if err != nil {
+		t.Fatalf("Failed to check follows: %v", err)
+	}
+	if len(notifications) != 1 || !strings.Contains(notifications[0].Text, "ДРИНА - Бајина Башта") {
+		t.Errorf("Expected the Бајина Башта update on the next check, got %+v", notifications)
+	}
+}
@@ -0,0 +1,22 @@
+package usecases
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// FreshnessBanner returns a warning to put above replies when the newest stored reading
+// is older than the staleness window, with its time shown in loc. It is empty while the
+// data is fresh, before the first refresh and when the update time can't be read.
+func (uc *RiverUseCase) FreshnessBanner(now time.Time, loc *time.Location) string {
+	lastUpdate, err := uc.repo.GetLastUpdateTime()
+	if err != nil {
+		slog.Error("Error fetching last update time for the freshness banner", "error", err)
+		return ""
+	}
+	if lastUpdate.IsZero() || now.Sub(lastUpdate) <= uc.staleAfter {
+		return ""
+	}
+	return fmt.Sprintf("⚠️ Data may be outdated (last update: %s)\n\n", lastUpdate.In(loc).Format("2006-01-02 15:04 MST"))
+}
@@ -0,0 +1,50 @@
+package usecases
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// TestFreshnessBanner verifies the banner appears only once the newest reading is older than the staleness window
+func TestFreshnessBanner(t *testing.T) {
+	repo := newTestRepository(t)
+	uc := NewRiverUseCase(repo, nil, nil)
+	uc.SetStaleAfter(2 * time.Hour)
+
+	if banner := uc.FreshnessBanner(time.Now(), time.UTC); banner != "" {
+		t.Errorf("Expected no banner before the first refresh, got %q", banner)
+	}
+
+	newest := time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC)
+	err := repo.SaveRiverData([]entities.RiverData{
+		{River: "ДРИНА", Station: "Радаљ", WaterLevel: "142", Timestamp: newest.Add(-5 * time.Hour)},
+		{River: "САВА", Station: "Шабац", WaterLevel: "312", Timestamp: newest},
+	})
+	if err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		age        time.Duration
+		wantBanner bool
+	}{
+		{"fresh", 30 * time.Minute, false},
+		{"at the threshold", 2 * time.Hour, false},
+		{"past the threshold", 2*time.Hour + time.Minute, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			banner := uc.FreshnessBanner(newest.Add(tt.age), time.UTC)
+			if (banner != "") != tt.wantBanner {
+				t.Fatalf("Expected banner=%v, got %q", tt.wantBanner, banner)
+			}
+			if tt.wantBanner && !strings.Contains(banner, "Data may be outdated (last update: 2025-04-18 06:00 UTC)") {
+				t.Errorf("Expected the newest reading's time in the banner, got %q", banner)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package usecases
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// DefaultMaxGap is the longest expected interval between two readings of a station.
+// Sources publish at most hourly, so a longer silence means a missed scrape or an outage.
+const DefaultMaxGap = 90 * time.Minute
+
+// Gap is an interval without readings that is longer than expected
+type Gap struct {
+	Start time.Time // Timestamp of the last reading before the gap
+	End   time.Time // Timestamp of the first reading after the gap
+}
+
+// Duration returns how long the gap lasted
+func (g Gap) Duration() time.Duration {
+	return g.End.Sub(g.Start)
+}
+
+// FindDataGaps returns the intervals since the given time in which a station
+// went more than maxGap without a stored reading, oldest first
+func (uc *RiverUseCase) FindDataGaps(river, station string, since time.Time, maxGap time.Duration) ([]Gap, error) {
+	slog.Debug("Looking for data gaps", "max_gap", maxGap, "river", river, "station", station, "since", since.Format(time.RFC3339))
+	history, err := uc.repo.GetStationHistory(river, station, since)
+	if err != nil {
+		return nil, err
+	}
+	return findGaps(history, maxGap), nil
+}
+
+// findGaps returns the gaps longer than maxGap between consecutive readings in time order
+func findGaps(history []entities.RiverData, maxGap time.Duration) []Gap {
+	var gaps []Gap
+	for i := 1; i < len(history); i++ {
+		previous, current := history[i-1].Timestamp, history[i].Timestamp
+		if current.Sub(previous) > maxGap {
+			gaps = append(gaps, Gap{Start: previous, End: current})
+		}
+	}
+	return gaps
+}
+
+// FormatDataGaps formats the gaps of a station with their times shown in loc
+func FormatDataGaps(river, station string, gaps []Gap, maxGap time.Duration, loc *time.Location) string {
+	if len(gaps) == 0 {
+		return fmt.Sprintf("No gaps longer than %s in the data of %s - %s.", formatWindow(maxGap), river, station)
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Gaps longer than %s in the data of %s - %s:\n\n", formatWindow(maxGap), river, station))
+	for _, gap := range gaps {
+		result.WriteString(fmt.Sprintf("• %s → %s (%s)\n",
+			gap.Start.In(loc).Format("2006-01-02 15:04"),
+			gap.End.In(loc).Format("2006-01-02 15:04 MST"),
+			formatWindow(gap.Duration().Round(time.Minute))))
+	}
+	return result.String()
+}
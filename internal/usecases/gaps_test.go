@@ -0,0 +1,81 @@
+package usecases
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// TestFindDataGaps verifies a deliberate hole in a 10-minute series is reported with its bounds
+func TestFindDataGaps(t *testing.T) {
+	repo := newTestRepository(t)
+	uc := NewRiverUseCase(repo, nil, nil)
+
+	// Readings every 10 minutes for two hours, then nothing until 6:00
+	start := time.Date(2025, 4, 18, 0, 0, 0, 0, time.UTC)
+	var data []entities.RiverData
+	for i := 0; i <= 12; i++ {
+		data = append(data, entities.RiverData{River: "ГРАДАЦ", Station: "ДЕГУРИЋ", WaterLevel: "40", Timestamp: start.Add(time.Duration(i) * 10 * time.Minute)})
+	}
+	resumed := start.Add(6 * time.Hour)
+	for i := 0; i < 6; i++ {
+		data = append(data, entities.RiverData{River: "ГРАДАЦ", Station: "ДЕГУРИЋ", WaterLevel: "41", Timestamp: resumed.Add(time.Duration(i) * 10 * time.Minute)})
+	}
+	if err := repo.SaveRiverData(data); err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+
+	gaps, err := uc.FindDataGaps("ГРАДАЦ", "ДЕГУРИЋ", start, DefaultMaxGap)
+	if err != nil {
+		t.Fatalf("Failed to find gaps: %v", err)
+	}
+	if len(gaps) != 1 {
+		t.Fatalf("Expected 1 gap, got %+v", gaps)
+	}
+	wantStart := start.Add(2 * time.Hour)
+	if !gaps[0].Start.Equal(wantStart) || !gaps[0].End.Equal(resumed) {
+		t.Errorf("Expected gap %v - %v, got %v - %v", wantStart, resumed, gaps[0].Start, gaps[0].End)
+	}
+	if gaps[0].Duration() != 4*time.Hour {
+		t.Errorf("Expected a 4h gap, got %v", gaps[0].Duration())
+	}
+
+	text := FormatDataGaps("ГРАДАЦ", "ДЕГУРИЋ", gaps, DefaultMaxGap, time.UTC)
+	if !strings.Contains(text, "2025-04-18 02:00 → 2025-04-18 06:00 UTC (4h)") {
+		t.Errorf("Expected the gap to be listed, got:\n%s", text)
+	}
+}
+
+// TestFindGapsThreshold verifies only intervals longer than the maximum gap are reported
+func TestFindGapsThreshold(t *testing.T) {
+	start := time.Date(2025, 4, 18, 0, 0, 0, 0, time.UTC)
+	series := func(offsets ...time.Duration) []entities.RiverData {
+		var data []entities.RiverData
+		for _, offset := range offsets {
+			data = append(data, entities.RiverData{Timestamp: start.Add(offset)})
+		}
+		return data
+	}
+
+	tests := []struct {
+		name    string
+		history []entities.RiverData
+		want    int
+	}{
+		{"empty", nil, 0},
+		{"single reading", series(0), 0},
+		{"hourly", series(0, time.Hour, 2*time.Hour), 0},
+		{"exactly the maximum", series(0, 90*time.Minute), 0},
+		{"just over the maximum", series(0, 91*time.Minute), 1},
+		{"two gaps", series(0, 3*time.Hour, 4*time.Hour, 8*time.Hour), 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := findGaps(tt.history, DefaultMaxGap); len(got) != tt.want {
+				t.Errorf("Expected %d gaps, got %+v", tt.want, got)
+			}
+		})
+	}
+}
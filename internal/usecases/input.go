@@ -0,0 +1,37 @@
+package usecases
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// maxRiverNameLength caps river name arguments, the longest known name is far shorter
+const maxRiverNameLength = 64
+
+// ErrEmptyRiverName is returned when a river name argument has no usable characters
+var ErrEmptyRiverName = errors.New("river name is empty")
+
+// SanitizeRiverName cleans a user-supplied river name before it is used in a query.
+// It strips control characters, collapses whitespace and caps the length at
+// maxRiverNameLength characters.
+func SanitizeRiverName(input string) (string, error) {
+	cleaned := strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return ' '
+		}
+		if unicode.IsControl(r) || r == unicode.ReplacementChar {
+			return -1
+		}
+		return r
+	}, input)
+	cleaned = strings.Join(strings.Fields(cleaned), " ")
+
+	if runes := []rune(cleaned); len(runes) > maxRiverNameLength {
+		cleaned = strings.TrimSpace(string(runes[:maxRiverNameLength]))
+	}
+	if cleaned == "" {
+		return "", ErrEmptyRiverName
+	}
+	return cleaned, nil
+}
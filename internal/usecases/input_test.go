@@ -0,0 +1,52 @@
+package usecases
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// TestSanitizeRiverName verifies river name arguments are cleaned before querying
+func TestSanitizeRiverName(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr error
+	}{
+		{"plain", "ДРИНА", "ДРИНА", nil},
+		{"surrounding whitespace", "  ВЕЛИКА МОРАВА \n", "ВЕЛИКА МОРАВА", nil},
+		{"inner whitespace collapsed", "ВЕЛИКА\t\tМОРАВА", "ВЕЛИКА МОРАВА", nil},
+		{"control characters", "ДР\x00И\x1bНА\x7f", "ДРИНА", nil},
+		{"invalid utf8", "САВА\xff", "САВА", nil},
+		{"empty", "", "", ErrEmptyRiverName},
+		{"whitespace only", " \t\n ", "", ErrEmptyRiverName},
+		{"control characters only", "\x00\x01\x02", "", ErrEmptyRiverName},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SanitizeRiverName(tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestSanitizeRiverNameOversized verifies long input is truncated to whole characters
+func TestSanitizeRiverNameOversized(t *testing.T) {
+	got, err := SanitizeRiverName(strings.Repeat("Д", 1000))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n := utf8.RuneCountInString(got); n != maxRiverNameLength {
+		t.Errorf("Expected %d characters, got %d", maxRiverNameLength, n)
+	}
+	if !utf8.ValidString(got) {
+		t.Errorf("Expected valid UTF-8, got %q", got)
+	}
+}
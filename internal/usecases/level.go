@@ -0,0 +1,105 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// ErrUnknownStation is returned when a river has no station with the requested name
+var ErrUnknownStation = errors.New("unknown station")
+
+// StationLevel is a station's latest reading and how its level changed since the one before
+type StationLevel struct {
+	Reading   entities.RiverData
+	Change    float64 // Change in cm since the previous reading, valid when HasChange is set
+	HasChange bool
+}
+
+// GetStationLevel returns the latest water level of one station. River names may be
+// aliases and station names match case-insensitively or in Serbian Latin script.
+func (uc *RiverUseCase) GetStationLevel(river, station string) (StationLevel, error) {
+	slog.Debug("Retrieving latest level", "river", river, "station", station)
+
+	river, station, err := uc.resolveRiverStation(river, station)
+	if err != nil {
+		return StationLevel{}, err
+	}
+
+	latest, found, err := uc.repo.GetStationLatest(river, station)
+	if err != nil {
+		return StationLevel{}, err
+	}
+	if !found {
+		return StationLevel{}, ErrUnknownStation
+	}
+	return uc.stationLevel(latest)
+}
+
+// stationLevel pairs a reading with the change since the newest earlier reading within trendPeriod
+func (uc *RiverUseCase) stationLevel(latest entities.RiverData) (StationLevel, error) {
+	level := StationLevel{Reading: latest}
+
+	current, ok := latest.LevelValue()
+	if !ok {
+		return level, nil
+	}
+	history, err := uc.repo.GetStationHistory(latest.River, latest.Station, latest.Timestamp.Add(-trendPeriod))
+	if err != nil {
+		return StationLevel{}, err
+	}
+	for i := len(history) - 1; i >= 0; i-- {
+		if !history[i].Timestamp.Before(latest.Timestamp) {
+			continue
+		}
+		previous, ok := history[i].LevelValue()
+		if !ok {
+			continue
+		}
+		level.Change = current - previous
+		level.HasChange = true
+		break
+	}
+	return level, nil
+}
+
+// resolveRiverStation returns the stored names of a river, which may be an alias, and
+// of one of its stations. Returns ErrUnknownRiver or ErrUnknownStation when there is none.
+func (uc *RiverUseCase) resolveRiverStation(river, station string) (string, string, error) {
+	riverData, err := uc.findRiverData(river)
+	if err != nil {
+		return "", "", err
+	}
+	if len(riverData) == 0 {
+		return "", "", ErrUnknownRiver
+	}
+	station, ok := matchStation(riverData, station)
+	if !ok {
+		return "", "", ErrUnknownStation
+	}
+	return riverData[0].River, station, nil
+}
+
+// FormatStationLevel formats a station's latest level with timestamps shown in loc
+func (uc *RiverUseCase) FormatStationLevel(level StationLevel, loc *time.Location) string {
+	rd := level.Reading
+	stale := time.Since(rd.Timestamp) > uc.staleAfter
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("📍 %s - %s\n", rd.River, rd.Station))
+	result.WriteString(fmt.Sprintf("💧 Water Level: %s cm", rd.WaterLevel))
+	if level.HasChange {
+		result.WriteString(fmt.Sprintf(" (%+g cm since the previous reading)", level.Change))
+	}
+	result.WriteString("\n")
+	result.WriteString(fmt.Sprintf("🕒 Last update: %s", rd.Timestamp.In(loc).Format("2006-01-02 15:04:05 MST")))
+	if stale {
+		result.WriteString(" (outdated)")
+	}
+	result.WriteString("\n")
+	return result.String()
+}
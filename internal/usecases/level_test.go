@@ -0,0 +1,81 @@
+package usecases
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// TestGetStationLevel verifies station lookups by exact, case-insensitive, Latin and aliased names
+func TestGetStationLevel(t *testing.T) {
+	repo := newTestRepository(t)
+	uc := NewRiverUseCase(repo, nil, nil)
+
+	latest := time.Now().Truncate(time.Second)
+	err := repo.SaveRiverData([]entities.RiverData{
+		{River: "ДРИНА", Station: "Радаљ", WaterLevel: "139", Timestamp: latest.Add(-2 * time.Hour)},
+		{River: "ДРИНА", Station: "Радаљ", WaterLevel: "142", Timestamp: latest},
+		{River: "ДРИНА", Station: "Бајина Башта", WaterLevel: "88", Timestamp: latest},
+		{River: "ГРАДАЦ", Station: "Дегурић", WaterLevel: "85", Timestamp: latest},
+	})
+	if err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		river     string
+		station   string
+		want      string
+		wantLevel string
+		wantErr   error
+	}{
+		{"exact name", "ДРИНА", "Радаљ", "Радаљ", "142", nil},
+		{"case-insensitive", "ДРИНА", "РАДАЉ", "Радаљ", "142", nil},
+		{"latin spelling", "Drina", "radalj", "Радаљ", "142", nil},
+		{"multi-word latin", "DRINA", "Bajina Bašta", "Бајина Башта", "88", nil},
+		{"river alias", "Gradac", "Degurić", "Дегурић", "85", nil},
+		{"unknown station", "ДРИНА", "Зворник", "", "", ErrUnknownStation},
+		{"unknown river", "ТАРА", "Радаљ", "", "", ErrUnknownRiver},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			level, err := uc.GetStationLevel(tt.river, tt.station)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to get station level: %v", err)
+			}
+			if level.Reading.Station != tt.want || level.Reading.WaterLevel != tt.wantLevel {
+				t.Errorf("Expected %s at %s cm, got %s at %s cm", tt.want, tt.wantLevel, level.Reading.Station, level.Reading.WaterLevel)
+			}
+		})
+	}
+
+	level, err := uc.GetStationLevel("ДРИНА", "Радаљ")
+	if err != nil {
+		t.Fatalf("Failed to get station level: %v", err)
+	}
+	if !level.HasChange || level.Change != 3 {
+		t.Errorf("Expected a change of 3 cm, got %v (known: %v)", level.Change, level.HasChange)
+	}
+	text := uc.FormatStationLevel(level, time.UTC)
+	if !strings.Contains(text, "142 cm (+3 cm since the previous reading)") {
+		t.Errorf("Expected the level and change in %q", text)
+	}
+
+	level, err = uc.GetStationLevel("ДРИНА", "Бајина Башта")
+	if err != nil {
+		t.Fatalf("Failed to get station level: %v", err)
+	}
+	if level.HasChange {
+		t.Errorf("Expected no change for a station with a single reading, got %v", level.Change)
+	}
+}
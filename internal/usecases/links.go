@@ -0,0 +1,48 @@
+package usecases
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+	"github.com/abelzeko/water-bot/internal/integration"
+)
+
+// readingURL returns the page a reading was published on. Readings stored before
+// pages were recorded fall back to their source's website.
+func readingURL(rd entities.RiverData) string {
+	if rd.URL != "" {
+		return rd.URL
+	}
+	info, _ := integration.LookupSource(rd.Source)
+	return info.Website
+}
+
+// FormatRiverLinks lists the source pages the latest readings of a river were taken from,
+// grouping the stations published on the same page
+func FormatRiverLinks(riverData []entities.RiverData) string {
+	if len(riverData) == 0 {
+		return "No information available for this river."
+	}
+
+	var urls []string
+	stations := make(map[string][]string)
+	for _, rd := range riverData {
+		pageURL := readingURL(rd)
+		if _, ok := stations[pageURL]; !ok {
+			urls = append(urls, pageURL)
+		}
+		stations[pageURL] = append(stations[pageURL], rd.Station)
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("🔗 Sources for river %s:\n\n", riverData[0].River))
+	for _, pageURL := range urls {
+		if pageURL == "" {
+			result.WriteString(fmt.Sprintf("• %s: source page unknown\n", strings.Join(stations[pageURL], ", ")))
+			continue
+		}
+		result.WriteString(fmt.Sprintf("• %s: %s\n", strings.Join(stations[pageURL], ", "), pageURL))
+	}
+	return result.String()
+}
@@ -0,0 +1,43 @@
+package usecases
+
+import (
+	"log/slog"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+	"github.com/abelzeko/water-bot/internal/integration"
+)
+
+// mergeBySourcePriority keeps one reading per river, station and timestamp when
+// several sources report it, preferring the source with the higher priority.
+// Ties go to the reading fetched first, so the outcome doesn't depend on timing.
+func mergeBySourcePriority(data []entities.RiverData) []entities.RiverData {
+	type key struct {
+		river, station string
+		timestamp      int64
+	}
+	index := make(map[key]int, len(data))
+	result := make([]entities.RiverData, 0, len(data))
+	for _, rd := range data {
+		k := key{rd.River, rd.Station, rd.Timestamp.UnixNano()}
+		i, ok := index[k]
+		if !ok {
+			index[k] = len(result)
+			result = append(result, rd)
+			continue
+		}
+
+		kept := result[i]
+		if kept.Source == rd.Source {
+			// A source repeating itself keeps its latest reading, as SaveRiverData does
+			result[i] = rd
+			continue
+		}
+		if integration.SourcePriority(rd.Source) > integration.SourcePriority(kept.Source) {
+			result[i] = rd
+			kept, rd = rd, kept
+		}
+		slog.Debug("Merged reading reported by several sources",
+			"river", rd.River, "station", rd.Station, "timestamp", rd.Timestamp, "kept", kept.Source, "dropped", rd.Source)
+	}
+	return result
+}
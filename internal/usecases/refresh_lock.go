@@ -0,0 +1,49 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// DefaultRefreshWait is how long a refresh waits for a running one to finish, zero
+// makes it give up straight away
+const DefaultRefreshWait = 0
+
+// ErrRefreshInProgress is returned when another refresh is still running after the configured wait
+var ErrRefreshInProgress = errors.New("refresh already in progress")
+
+// SetRefreshWait changes how long RefreshRiverData waits for a refresh that is already
+// running before returning ErrRefreshInProgress.
+// It must be called before the use case is shared between goroutines.
+func (uc *RiverUseCase) SetRefreshWait(d time.Duration) {
+	uc.refreshWait = d
+}
+
+// acquireRefresh takes the refresh lock, waiting up to refreshWait for a running refresh
+func (uc *RiverUseCase) acquireRefresh(ctx context.Context) error {
+	select {
+	case uc.refreshLock <- struct{}{}:
+		return nil
+	default:
+	}
+	if uc.refreshWait <= 0 {
+		return ErrRefreshInProgress
+	}
+
+	timer := time.NewTimer(uc.refreshWait)
+	defer timer.Stop()
+	select {
+	case uc.refreshLock <- struct{}{}:
+		return nil
+	case <-timer.C:
+		return ErrRefreshInProgress
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// releaseRefresh releases the refresh lock taken by acquireRefresh
+func (uc *RiverUseCase) releaseRefresh() {
+	<-uc.refreshLock
+}
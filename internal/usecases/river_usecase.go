@@ -3,116 +3,555 @@ package usecases
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
+	"math"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/abelzeko/water-bot/internal/charts"
 	"github.com/abelzeko/water-bot/internal/entities"
 	"github.com/abelzeko/water-bot/internal/integration"
 	"github.com/abelzeko/water-bot/internal/integration/openai"
 	"github.com/abelzeko/water-bot/internal/repository"
+	"github.com/abelzeko/water-bot/internal/units"
 )
 
+// maxConcurrentFetches bounds how many sources are scraped at the same time
+const maxConcurrentFetches = 4
+
+// largeLevelCm is the water level from which river info also shows it in meters
+const largeLevelCm = 100
+
+// DefaultStaleAfter is how old a reading may get before users are warned it may be outdated
+const DefaultStaleAfter = time.Hour
+
+// formatWindow formats a duration without zero trailing units, e.g. "1h" rather than "1h0m0s"
+func formatWindow(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// DataSource is a named external provider of river readings
+type DataSource interface {
+	Name() string
+	Fetch(ctx context.Context) ([]entities.RiverData, error)
+}
+
+// funcSource adapts a fetch function to the DataSource interface
+type funcSource struct {
+	name  string
+	fetch func(ctx context.Context) ([]entities.RiverData, error)
+}
+
+// Name returns the source identifier
+func (s funcSource) Name() string { return s.name }
+
+// Fetch retrieves the source's current readings
+func (s funcSource) Fetch(ctx context.Context) ([]entities.RiverData, error) { return s.fetch(ctx) }
+
+// NewDataSource creates a DataSource from a name and a fetch function
+func NewDataSource(name string, fetch func(ctx context.Context) ([]entities.RiverData, error)) DataSource {
+	return funcSource{name: name, fetch: fetch}
+}
+
+// ScraperSources returns the data sources backed by the water scraper, primary source first
+func ScraperSources(scraper *integration.WaterScraper) []DataSource {
+	return []DataSource{
+		NewDataSource(integration.SourceHidmet, func(ctx context.Context) ([]entities.RiverData, error) {
+			return scraper.FetchWaterData(ctx)
+		}),
+		NewDataSource(integration.SourceGradac, func(ctx context.Context) ([]entities.RiverData, error) {
+			return scraper.FetchGradacRiverData(ctx)
+		}),
+		NewDataSource(integration.SourceRhmzRs, func(ctx context.Context) ([]entities.RiverData, error) {
+			return scraper.FetchRhmzRsData(ctx)
+		}),
+		NewDataSource(integration.SourceDhmz, func(ctx context.Context) ([]entities.RiverData, error) {
+			return scraper.FetchDhmzData(ctx)
+		}),
+	}
+}
+
 // RiverUseCase handles business logic related to river data
 type RiverUseCase struct {
 	repo          repository.RiverRepository
-	scraper       *integration.WaterScraper
+	sources       []DataSource
 	openAIService openai.OpenAIService
+	staleAfter    time.Duration // Age after which readings are flagged as outdated
+
+	suggestionDistance int           // Largest edit distance at which a river is suggested for a misspelled name
+	bounds             ReadingBounds // Plausible ranges, fetched readings outside them are rejected
+	defaultRiver       string        // River shown alongside replies to free text that wasn't understood
+	summaryThreshold   int           // Stations above which river info is summarized, 0 never summarizes
+
+	refreshLock chan struct{} // Holds a token while a refresh runs, so only one runs at a time
+	refreshWait time.Duration // How long a refresh waits for a running one before giving up
 }
 
-// NewRiverUseCase creates a new river use case
+// NewRiverUseCase creates a new river use case.
+// openAIService may be nil, which disables natural-language queries.
 func NewRiverUseCase(repo repository.RiverRepository, scraper *integration.WaterScraper, openAIService openai.OpenAIService) *RiverUseCase {
+	var sources []DataSource
+	if scraper != nil {
+		sources = ScraperSources(scraper)
+	}
+	return NewRiverUseCaseWithSources(repo, sources, openAIService)
+}
+
+// NewRiverUseCaseWithSources creates a river use case refreshing from the given sources
+func NewRiverUseCaseWithSources(repo repository.RiverRepository, sources []DataSource, openAIService openai.OpenAIService) *RiverUseCase {
 	return &RiverUseCase{
 		repo:          repo,
-		scraper:       scraper,
+		sources:       sources,
 		openAIService: openAIService,
+		staleAfter:    DefaultStaleAfter,
+
+		suggestionDistance: DefaultSuggestionDistance,
+		bounds:             DefaultReadingBounds,
+		defaultRiver:       DefaultRiver,
+		summaryThreshold:   DefaultSummaryThreshold,
+
+		refreshLock: make(chan struct{}, 1),
+		refreshWait: DefaultRefreshWait,
 	}
 }
 
-// RefreshRiverData fetches fresh data and updates the repository
-func (uc *RiverUseCase) RefreshRiverData() error {
-	log.Println("Starting river data refresh process...")
+// SetStaleAfter changes how old readings may get before they are flagged as outdated.
+// It must be called before the use case is shared between goroutines.
+func (uc *RiverUseCase) SetStaleAfter(d time.Duration) {
+	uc.staleAfter = d
+}
+
+// fetchResult holds the outcome of fetching a single source
+type fetchResult struct {
+	source   string
+	data     []entities.RiverData
+	err      error
+	duration time.Duration
+}
 
-	// Fetch main water data from external source
-	data, err := uc.scraper.FetchWaterData()
+// SourceRefresh is the outcome of refreshing a single source
+type SourceRefresh struct {
+	Source    string
+	Rows      int
+	Err       error
+	Duration  time.Duration
+	Unchanged bool  // Fetched, but nothing newer than the readings already stored
+	Saved     int   // Readings of this source written to the repository
+	SaveErr   error // Set when the source's readings were fetched but could not be saved
+}
+
+// RefreshResult summarizes a data refresh run
+type RefreshResult struct {
+	Sources  []SourceRefresh
+	Saved    int // Readings written to the repository
+	Rejected int // Readings dropped for implausible values
+	Dropped  int // Readings dropped for a missing river or station name
+	Duration time.Duration
+}
+
+// FailedSources returns the names of the sources that could not be fetched
+func (r RefreshResult) FailedSources() []string {
+	var failed []string
+	for _, source := range r.Sources {
+		if source.Err != nil {
+			failed = append(failed, source.Source)
+		}
+	}
+	return failed
+}
+
+// RefreshRiverData fetches fresh data from all sources concurrently and updates the repository.
+// A failing source, the primary one included, is reported only in the summary and the
+// others are still saved. Each source is saved in its own transaction, so a source whose
+// readings can't be saved is reported in the summary too and the others are kept. The
+// error is set when every source failed or nothing could be saved, existing data is left
+// untouched in both cases.
+// Only one refresh runs at a time, others wait up to the refresh wait and then return
+// ErrRefreshInProgress.
+func (uc *RiverUseCase) RefreshRiverData(ctx context.Context) (summary RefreshResult, err error) {
+	slog.Info("Starting river data refresh")
+	start := time.Now()
+	defer func() { summary.Duration = time.Since(start) }()
+	if len(uc.sources) == 0 {
+		return summary, fmt.Errorf("no data sources configured")
+	}
+
+	// Overlapping refreshes, e.g. /refresh during a scheduled one, would fetch and write twice
+	if err := uc.acquireRefresh(ctx); err != nil {
+		return summary, err
+	}
+	defer uc.releaseRefresh()
+
+	results := uc.fetchAll(ctx)
+
+	var data []entities.RiverData
+	for _, result := range results {
+		summary.Sources = append(summary.Sources, SourceRefresh{
+			Source:   result.source,
+			Rows:     len(result.data),
+			Err:      result.err,
+			Duration: result.duration,
+		})
+		if errors.Is(result.err, integration.ErrNoData) {
+			// The page still has its table but nothing in it parses, the site likely changed
+			slog.Error("Source returned no data rows, its page layout may have changed", "source", result.source, "duration", result.duration, "error", result.err)
+			continue
+		}
+		if result.err != nil {
+			// Continue with the other sources, keeping the stored data of this one
+			slog.Warn("Failed to fetch source data", "source", result.source, "duration", result.duration, "error", result.err)
+			continue
+		}
+		slog.Info("Fetched source data", "source", result.source, "rows", len(result.data), "duration", result.duration)
+		data = append(data, result.data...)
+	}
+	uc.recordSourceStatuses(summary.Sources, time.Now())
+	if failed := summary.FailedSources(); len(failed) == len(results) {
+		return summary, fmt.Errorf("failed to fetch data from all sources: %v", results[0].err)
+	}
+
+	// A corrupt row must not abort the save of the good ones around it
+	data, summary.Dropped = dropIncomplete(data)
+
+	// Readings whose source page had no usable timestamp must not look freshly measured
+	data, err = uc.stampUndated(data)
 	if err != nil {
-		return fmt.Errorf("failed to fetch general water data: %v", err)
+		return summary, err
+	}
+
+	// Flag sources that republished data already stored, so /status can tell them apart
+	uc.markUnchanged(summary.Sources, data)
+
+	// Sources spell rivers with differing case and spacing, merge and store one name per river
+	entities.NormalizeRiverNames(data)
+
+	// Drop obviously wrong values before they reach charts and alerts, and before the
+	// merge so a rejected reading can't displace a valid one from another source
+	data, summary.Rejected = rejectImplausible(data, uc.bounds)
+
+	// Sources may overlap, the most authoritative one decides each reading
+	data = mergeBySourcePriority(data)
+
+	// Save each source on its own, one failing save must not lose the others' readings
+	summary.Saved, err = uc.saveBySource(summary.Sources, data)
+	if err != nil && summary.Saved == 0 {
+		return summary, fmt.Errorf("failed to save data to repository: %v", err)
+	}
+	uc.recordSourceChecks(summary.Sources, time.Now())
+
+	return summary, nil
+}
+
+// stampUndated gives readings without a timestamp the time of their source's
+// previous stored readings, so a page that lost its timestamp doesn't make stale
+// values look current. Readings of sources without stored data are dropped.
+func (uc *RiverUseCase) stampUndated(data []entities.RiverData) ([]entities.RiverData, error) {
+	undated := make(map[string]int)
+	for _, rd := range data {
+		if rd.Timestamp.IsZero() {
+			undated[rd.Source]++
+		}
+	}
+	if len(undated) == 0 {
+		return data, nil
 	}
-	log.Printf("Successfully fetched %d river data entries", len(data))
 
-	// Fetch ГРАДАЦ river data
-	gradacData, err := uc.scraper.FetchGradacRiverData()
+	lastUpdates, err := uc.repo.GetLastUpdateTimeBySource()
 	if err != nil {
-		log.Printf("Warning: failed to fetch ГРАДАЦ river data: %v", err)
-		// Continue with the main data if ГРАДАЦ fetch fails
-	} else {
-		log.Printf("Successfully fetched %d ГРАДАЦ river data entries", len(gradacData))
-		// Append ГРАДАЦ data to the main data set
-		data = append(data, gradacData...)
+		return nil, fmt.Errorf("failed to get last update times: %v", err)
+	}
+	for source, count := range undated {
+		if previous, ok := lastUpdates[source]; ok {
+			slog.Warn("Readings have no timestamp, reusing the previous one", "source", source, "rows", count, "timestamp", previous)
+		} else {
+			slog.Warn("Dropping readings without a timestamp", "source", source, "rows", count)
+		}
 	}
 
-	// Fetch RHMZ RS data
-	rhmzRsData, err := uc.scraper.FetchRhmzRsData()
+	kept := data[:0]
+	for _, rd := range data {
+		if rd.Timestamp.IsZero() {
+			previous, ok := lastUpdates[rd.Source]
+			if !ok {
+				continue
+			}
+			rd.Timestamp = previous
+		}
+		kept = append(kept, rd)
+	}
+	return kept, nil
+}
+
+// FormatRefreshResult formats a refresh summary for display
+func (uc *RiverUseCase) FormatRefreshResult(summary RefreshResult, err error) string {
+	var sb strings.Builder
+	if errors.Is(err, ErrRefreshInProgress) {
+		return "Another refresh is already running, try again when it has finished."
+	}
 	if err != nil {
-		log.Printf("Warning: failed to fetch RHMZ RS data: %v", err)
-		// Continue with the main data if RHMZ RS fetch fails
+		sb.WriteString(fmt.Sprintf("Refresh failed: %v\n", err))
 	} else {
-		log.Printf("Successfully fetched %d RHMZ RS data entries", len(rhmzRsData))
-		// Append RHMZ RS data to the main data set
-		data = append(data, rhmzRsData...)
+		sb.WriteString(fmt.Sprintf("Refresh finished in %v, saved %d readings.\n", summary.Duration.Round(time.Millisecond), summary.Saved))
 	}
+	if summary.Rejected > 0 {
+		sb.WriteString(fmt.Sprintf("Rejected %d readings with implausible values.\n", summary.Rejected))
+	}
+	if summary.Dropped > 0 {
+		sb.WriteString(fmt.Sprintf("Dropped %d readings without a river or station name.\n", summary.Dropped))
+	}
+	if len(summary.Sources) > 0 {
+		sb.WriteString("\n")
+	}
+	// Failures show their duration too, a source timing out is degrading rather than down
+	for _, source := range summary.Sources {
+		if source.Err != nil {
+			sb.WriteString(fmt.Sprintf("• %s: error: %v (%v)\n", source.Source, source.Err, source.Duration.Round(time.Millisecond)))
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("• %s: %d rows (%v)", source.Source, source.Rows, source.Duration.Round(time.Millisecond)))
+		if source.Unchanged {
+			sb.WriteString(", unchanged")
+		}
+		if source.SaveErr != nil {
+			sb.WriteString(fmt.Sprintf(", not saved: %v", source.SaveErr))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// fetchAll fetches every source concurrently and returns the results in source order
+func (uc *RiverUseCase) fetchAll(ctx context.Context) []fetchResult {
+	results := make([]fetchResult, len(uc.sources))
+	semaphore := make(chan struct{}, maxConcurrentFetches)
 
-	// Save all data to repository
-	if err := uc.repo.SaveRiverData(data); err != nil {
-		return fmt.Errorf("failed to save data to repository: %v", err)
+	var wg sync.WaitGroup
+	for i, source := range uc.sources {
+		wg.Add(1)
+		go func(i int, source DataSource) {
+			defer wg.Done()
+			results[i].source = source.Name()
+
+			select {
+			case semaphore <- struct{}{}:
+				defer func() { <-semaphore }()
+			case <-ctx.Done():
+				results[i].err = ctx.Err()
+				return
+			}
+
+			start := time.Now()
+			results[i].data, results[i].err = fetchWithContext(ctx, source)
+			results[i].duration = time.Since(start)
+		}(i, source)
 	}
+	wg.Wait()
 
-	return nil
+	return results
+}
+
+// fetchWithContext runs a source fetch and stops waiting for it once the context is done
+func fetchWithContext(ctx context.Context, source DataSource) ([]entities.RiverData, error) {
+	type outcome struct {
+		data []entities.RiverData
+		err  error
+	}
+
+	done := make(chan outcome, 1)
+	go func() {
+		data, err := source.Fetch(ctx)
+		done <- outcome{data: data, err: err}
+	}()
+
+	select {
+	case o := <-done:
+		return o.data, o.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
 }
 
 // GetRiverDataByName retrieves data for a specific river
 func (uc *RiverUseCase) GetRiverDataByName(riverName string) ([]entities.RiverData, error) {
-	log.Printf("Retrieving data for river: %s", riverName)
-	return uc.repo.GetRiverDataByName(riverName)
+	slog.Debug("Retrieving river data", "river", riverName)
+	riverData, err := uc.findRiverData(riverName)
+	if err != nil {
+		return nil, err
+	}
+	if entities.MarkStale(riverData, time.Now(), uc.staleAfter) {
+		slog.Warn("River data is stale", "river", riverName, "stale_after", uc.staleAfter)
+	}
+	return riverData, nil
+}
+
+// GetLatestReadings returns the most recent reading of every station, ordered by river and station
+func (uc *RiverUseCase) GetLatestReadings() ([]entities.RiverData, error) {
+	latest, err := uc.repo.GetLatestReadings()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(latest, func(i, j int) bool {
+		if latest[i].River != latest[j].River {
+			return latest[i].River < latest[j].River
+		}
+		return latest[i].Station < latest[j].Station
+	})
+	entities.MarkStale(latest, time.Now(), uc.staleAfter)
+	return latest, nil
 }
 
 // GetAvailableRivers returns a list of all river names
 func (uc *RiverUseCase) GetAvailableRivers() ([]string, error) {
-	log.Println("Retrieving list of available rivers")
+	slog.Debug("Retrieving list of available rivers")
 	return uc.repo.GetUniqueRivers()
 }
 
+// GetLastUpdateTime returns the timestamp of the most recent stored reading
+func (uc *RiverUseCase) GetLastUpdateTime() (time.Time, error) {
+	return uc.repo.GetLastUpdateTime()
+}
+
+// HasData reports whether any readings have been stored yet.
+// It is false until the first refresh of a new database completes.
+func (uc *RiverUseCase) HasData() (bool, error) {
+	count, err := uc.repo.CountRows()
+	return count > 0, err
+}
+
+// GetLastUpdateTimeBySource returns when each data source last delivered a reading
+func (uc *RiverUseCase) GetLastUpdateTimeBySource() (map[string]time.Time, error) {
+	slog.Debug("Retrieving last update time per source")
+	return uc.repo.GetLastUpdateTimeBySource()
+}
+
+// ExportRiverData writes a river's readings since the given time to w as CSV
+func (uc *RiverUseCase) ExportRiverData(w io.Writer, river string, since time.Time) error {
+	slog.Debug("Exporting river data", "river", river, "since", since.Format(time.RFC3339))
+	return uc.repo.StreamRiverData(w, river, since)
+}
+
+// Dense series, such as ГРАДАЦ's 10-minute readings, are smoothed before charting
+const (
+	denseSeriesPoints    = 200
+	denseSeriesSmoothing = 6
+)
+
+// RenderLevelChart draws a PNG chart of a station's water level since the given time.
+// Returns charts.ErrNotEnoughPoints when the stored history is too short to plot.
+func (uc *RiverUseCase) RenderLevelChart(river, station string, since time.Time) ([]byte, error) {
+	slog.Debug("Rendering water level chart", "river", river, "station", station, "since", since.Format(time.RFC3339))
+	history, err := uc.repo.GetStationHistory(river, station, since)
+	if err != nil {
+		return nil, err
+	}
+	if len(history) > denseSeriesPoints {
+		history = SmoothSeries(history, denseSeriesSmoothing)
+	}
+
+	var points []charts.Point
+	for _, rd := range history {
+		level, ok := rd.LevelValue()
+		if !ok {
+			continue // Skip readings without a numeric level
+		}
+		points = append(points, charts.Point{Time: rd.Timestamp, Level: level})
+	}
+
+	return charts.RenderLevelChart(fmt.Sprintf("%s - %s", river, station), points)
+}
+
+// RenderRiverTable draws a PNG table of a river's current readings
+func (uc *RiverUseCase) RenderRiverTable(riverData []entities.RiverData) ([]byte, error) {
+	slog.Debug("Rendering table image", "readings", len(riverData))
+	return charts.RenderTableImage(riverData)
+}
+
+// notUnderstoodMessage is the reply to free text when natural-language mode is disabled
+const notUnderstoodMessage = "I don't understand. Use /help to see available commands."
+
+// DefaultRiver is the river shown with fallback replies unless DEFAULT_RIVER says otherwise
+const DefaultRiver = "ГРАДАЦ"
+
+// SetDefaultRiver changes the river shown alongside replies to free text that wasn't understood.
+// An empty name disables it.
+// It must be called before the use case is shared between goroutines.
+func (uc *RiverUseCase) SetDefaultRiver(river string) {
+	uc.defaultRiver = river
+}
+
+// withDefaultRiver appends the default river's current readings to a fallback reply.
+// The reply is returned unchanged when the default river has no data.
+func (uc *RiverUseCase) withDefaultRiver(reply string) string {
+	if uc.defaultRiver == "" {
+		return reply
+	}
+
+	riverData, err := uc.GetRiverDataByName(uc.defaultRiver)
+	if err != nil {
+		slog.Error("Error fetching default river", "river", uc.defaultRiver, "error", err)
+		return reply
+	}
+	if len(riverData) == 0 {
+		return reply
+	}
+	return reply + "\n\n" + uc.FormatRiverInfo(riverData)
+}
+
 // HandleNaturalLanguageQuery interprets a user's free-text query using the AI service
 // and returns an appropriate response string.
+// Without an AI service it replies that the message wasn't understood,
+// followed by the default river's readings when it has any.
 func (uc *RiverUseCase) HandleNaturalLanguageQuery(ctx context.Context, query string) (string, error) {
-	log.Printf("Interpreting natural language query: %s", query)
+	if uc.openAIService == nil {
+		return uc.withDefaultRiver(notUnderstoodMessage), nil
+	}
+
+	slog.Debug("Interpreting natural language query", "query", query)
 
 	rivers, err := uc.GetAvailableRivers()
 	if err != nil {
-		log.Printf("Error fetching available rivers: %v", err)
+		slog.Error("Error fetching available rivers", "error", err)
 		return "Sorry, I couldn't fetch the list of rivers right now.", nil
 	}
 
 	// Call the OpenAI service to interpret the query
 	agentResp, err := uc.openAIService.InterpretUserQuery(ctx, query, rivers)
 	if err != nil {
-		log.Printf("Error interpreting user query via OpenAI: %v", err)
+		slog.Error("Error interpreting user query via OpenAI", "error", err)
 		// Return a generic error message for the user
 		return "Sorry, I'm having trouble understanding right now. Please try again later or use /help.", nil
 	}
 
-	log.Printf("Agent response: Command='%s', River='%s', Message='%s'",
-		agentResp.CommandName, agentResp.SerbianRiverName, agentResp.UserMessage)
+	slog.Debug("Agent response",
+		"command", agentResp.CommandName, "river", agentResp.SerbianRiverName, "message", agentResp.UserMessage)
+
+	// The river name comes from the model, clean it like user input before querying
+	riverName, err := SanitizeRiverName(agentResp.SerbianRiverName)
+	if err != nil {
+		riverName = ""
+	}
 
 	// Process the agent's response
 	switch agentResp.CommandName {
 	case "GetRiverDataByName":
-		if agentResp.SerbianRiverName != "" {
+		if riverName != "" {
 			// Agent identified intent and river name, fetch and format data
-			log.Printf("Agent identified river: %s. Fetching data...", agentResp.SerbianRiverName)
-			riverData, err := uc.GetRiverDataByName(agentResp.SerbianRiverName)
+			slog.Debug("Agent identified river", "river", riverName)
+			riverData, err := uc.GetRiverDataByName(riverName)
 			if err != nil {
-				log.Printf("Error fetching river data after agent interpretation: %v", err)
+				slog.Error("Error fetching river data after agent interpretation", "error", err)
 				return "Sorry, I couldn't fetch the data for that river right now.", nil
 			}
 			if len(riverData) == 0 {
@@ -121,7 +560,7 @@ func (uc *RiverUseCase) HandleNaturalLanguageQuery(ctx context.Context, query st
 				if msg != "" {
 					msg += "\n\n"
 				}
-				msg += fmt.Sprintf("However, I couldn't find any information for river '%s'. Use /rivers to see available ones.", agentResp.SerbianRiverName)
+				msg += fmt.Sprintf("However, I couldn't find any information for river '%s'. Use /rivers to see available ones.", riverName)
 				return msg, nil
 			}
 			// Combine agent's confirmation (if any) with the formatted data
@@ -133,18 +572,18 @@ func (uc *RiverUseCase) HandleNaturalLanguageQuery(ctx context.Context, query st
 			return msg, nil
 		} else {
 			// Agent identified intent but not a specific river, use the agent's message
-			log.Printf("Agent identified intent GetRiverDataByName but no specific river found.")
+			slog.Debug("Agent identified intent GetRiverDataByName but no specific river")
 			// Return the agent's message (e.g., "Which river?")
 			return agentResp.UserMessage, nil
 		}
 	case "GeneralQuery":
 		// Agent determined it's a general query, just return the generated message
-		log.Printf("Agent identified general query.")
+		slog.Debug("Agent identified general query")
 		return agentResp.UserMessage, nil
 	default:
 		// Fallback if agent returns an unexpected command or empty response
-		log.Printf("Agent returned unexpected command: %s", agentResp.CommandName)
-		return "I'm not sure how to respond to that. You can use /help for commands.", nil
+		slog.Warn("Agent returned unexpected command", "command", agentResp.CommandName)
+		return uc.withDefaultRiver("I'm not sure how to respond to that. You can use /help for commands."), nil
 	}
 }
 
@@ -156,22 +595,252 @@ func (uc *RiverUseCase) FormatRiverInfo(riverData []entities.RiverData) string {
 
 	var result strings.Builder
 	result.WriteString(fmt.Sprintf("Information for river %s:\n\n", riverData[0].River))
+	for _, data := range riverData {
+		if data.Stale {
+			result.WriteString(fmt.Sprintf("⚠️ Some readings are older than %s, the data may be outdated.\n\n", formatWindow(uc.staleAfter)))
+			break
+		}
+	}
 
+	// Rivers crossing borders get one section per country
+	groups, countries := groupByCountry(riverData)
+	for _, country := range countries {
+		if len(countries) > 1 {
+			result.WriteString(fmt.Sprintf("%s:\n\n", country))
+		}
+		for _, data := range groups[country] {
+			writeStationInfo(&result, data)
+		}
+	}
+
+	return result.String()
+}
+
+// groupByCountry groups readings by the country of their source, keeping the
+// order in which countries first appear
+func groupByCountry(riverData []entities.RiverData) (map[string][]entities.RiverData, []string) {
+	groups := make(map[string][]entities.RiverData)
+	var countries []string
 	for _, data := range riverData {
-		result.WriteString(fmt.Sprintf("📍 Station: %s\n", data.Station))
-		result.WriteString(fmt.Sprintf("💧 Water Level: %s cm\n", data.WaterLevel))
+		country := integration.SourceCountry(data.Source)
+		if country == "" {
+			country = "Other"
+		}
+		if _, ok := groups[country]; !ok {
+			countries = append(countries, country)
+		}
+		groups[country] = append(groups[country], data)
+	}
+	return groups, countries
+}
+
+// writeStationInfo writes one station's reading for FormatRiverInfo
+func writeStationInfo(result *strings.Builder, data entities.RiverData) {
+	result.WriteString(fmt.Sprintf("📍 Station: %s\n", data.Station))
+	result.WriteString(fmt.Sprintf("💧 Water Level: %s\n", formatWaterLevel(data)))
+
+	// Only include fields that have values
+	if data.WaterTemp != "" {
+		result.WriteString(fmt.Sprintf("🌡️ Water Temperature: %s °C\n", data.WaterTemp))
+	}
+	if _, ok := data.DischargeValue(); ok {
+		result.WriteString(fmt.Sprintf("🌊 Discharge: %s m³/s\n", data.Discharge))
+	}
+	if data.Tendency.IsKnown() {
+		result.WriteString(fmt.Sprintf("%s Tendency: %s\n", data.Tendency.Symbol(), data.Tendency.Label()))
+	}
+
+	result.WriteString(fmt.Sprintf("🕒 Last update: %s", data.Timestamp.Format("2006-01-02 15:04:05 MST")))
+	if data.Stale {
+		result.WriteString(" (outdated)")
+	}
+
+	result.WriteString("\n\n")
+}
+
+// formatWaterLevel formats a reading's water level in cm, large levels in meters as well
+func formatWaterLevel(data entities.RiverData) string {
+	text := data.WaterLevel + " cm"
+	if level, ok := data.LevelValue(); ok && math.Abs(level) >= largeLevelCm {
+		text += fmt.Sprintf(" (%v m)", units.Round(units.Convert(level, units.Centimeter, units.Meter), 2))
+	}
+	return text
+}
+
+// GetTopStations returns the stations with the highest current water level.
+// With risingOnly set only stations whose level is rising are ranked.
+func (uc *RiverUseCase) GetTopStations(limit int, risingOnly bool) ([]entities.RiverData, error) {
+	slog.Debug("Retrieving top stations", "limit", limit, "rising_only", risingOnly)
+	if !risingOnly {
+		return uc.repo.GetTopStations(limit)
+	}
+
+	all, err := uc.repo.GetTopStations(0)
+	if err != nil {
+		return nil, err
+	}
+	var rising []entities.RiverData
+	for _, rd := range all {
+		if rd.Tendency == entities.Rising {
+			rising = append(rising, rd)
+			if len(rising) == limit {
+				break
+			}
+		}
+	}
+	return rising, nil
+}
 
-		// Only include fields that have values
-		if data.WaterTemp != "" {
-			result.WriteString(fmt.Sprintf("🌡️ Water Temperature: %s °C\n", data.WaterTemp))
+// FormatTopStations formats a station ranking for display
+func (uc *RiverUseCase) FormatTopStations(data []entities.RiverData, risingOnly bool) string {
+	if len(data) == 0 {
+		if risingOnly {
+			return "No stations are currently rising."
+		}
+		return "No water level data available yet."
+	}
+
+	var result strings.Builder
+	if risingOnly {
+		result.WriteString("🏆 Highest rising water levels:\n\n")
+	} else {
+		result.WriteString("🏆 Highest water levels:\n\n")
+	}
+	for i, rd := range data {
+		result.WriteString(fmt.Sprintf("%d. %s - %s: %s cm", i+1, rd.River, rd.Station, rd.WaterLevel))
+		if rd.Tendency.IsKnown() {
+			result.WriteString(" " + rd.Tendency.Symbol())
 		}
+		result.WriteString("\n")
+	}
+	return result.String()
+}
+
+// GetStationsByTempRange returns the stations whose current water temperature is between min and max
+func (uc *RiverUseCase) GetStationsByTempRange(min, max float64) ([]entities.RiverData, error) {
+	slog.Debug("Retrieving stations by water temperature", "min", min, "max", max)
+	return uc.repo.GetStationsByTempRange(min, max)
+}
 
-		result.WriteString(fmt.Sprintf("🕒 Last update: %s", data.Timestamp.Format("2006-01-02 15:04:05 MST")))
+// FormatTempRange formats the stations within a water temperature range for display
+func (uc *RiverUseCase) FormatTempRange(data []entities.RiverData, min, max float64) string {
+	if len(data) == 0 {
+		return fmt.Sprintf("No stations currently report a water temperature between %v and %v °C.", min, max)
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("🌡️ Stations with water between %v and %v °C:\n\n", min, max))
+	for _, rd := range data {
+		result.WriteString(fmt.Sprintf("• %s - %s: %s °C\n", rd.River, rd.Station, rd.WaterTemp))
+	}
+	return result.String()
+}
+
+// GetStationsByDischarge returns the stations whose current discharge is at least min m³/s
+func (uc *RiverUseCase) GetStationsByDischarge(min float64) ([]entities.RiverData, error) {
+	slog.Debug("Retrieving stations by discharge", "min", min)
+	return uc.repo.GetStationsByDischarge(min)
+}
 
-		result.WriteString("\n\n")
+// FormatDischarge formats the stations above a discharge threshold for display
+func (uc *RiverUseCase) FormatDischarge(data []entities.RiverData, min float64) string {
+	if len(data) == 0 {
+		return fmt.Sprintf("No stations currently report a discharge of at least %v m³/s.", min)
 	}
 
-	// Add last update time with timezone
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("🌊 Stations with discharge of at least %v m³/s:\n\n", min))
+	for _, rd := range data {
+		result.WriteString(fmt.Sprintf("• %s - %s: %s m³/s\n", rd.River, rd.Station, rd.Discharge))
+	}
+	return result.String()
+}
+
+// FormatConversion formats a water level converted to every supported unit
+func (uc *RiverUseCase) FormatConversion(value float64, from units.Unit) string {
+	parts := []string{fmt.Sprintf("%v %s", value, from)}
+	for _, to := range units.Units {
+		if to != from {
+			parts = append(parts, fmt.Sprintf("%v %s", units.Round(units.Convert(value, from, to), 2), to))
+		}
+	}
+	return strings.Join(parts, " = ")
+}
+
+// SourceCoverage counts the rivers and stations a source reported in the latest data
+type SourceCoverage struct {
+	Rivers   int
+	Stations int
+}
+
+// GetSourceCoverage returns the coverage of each source in the latest reading of every station
+func (uc *RiverUseCase) GetSourceCoverage() (map[string]SourceCoverage, error) {
+	latest, err := uc.repo.GetLatestReadings()
+	if err != nil {
+		return nil, err
+	}
+
+	rivers := make(map[string]map[string]bool)
+	coverage := make(map[string]SourceCoverage)
+	for _, rd := range latest {
+		if rivers[rd.Source] == nil {
+			rivers[rd.Source] = make(map[string]bool)
+		}
+		rivers[rd.Source][rd.River] = true
+		c := coverage[rd.Source]
+		c.Stations++
+		c.Rivers = len(rivers[rd.Source])
+		coverage[rd.Source] = c
+	}
+	return coverage, nil
+}
+
+// FormatSources describes every supported source with its coverage and last update
+func (uc *RiverUseCase) FormatSources(lastUpdates map[string]time.Time, coverage map[string]SourceCoverage) string {
+	var result strings.Builder
+	result.WriteString("Data sources:\n\n")
+	for _, source := range integration.Sources() {
+		result.WriteString(fmt.Sprintf("🏛️ %s (%s)\n", source.Authority, source.Country))
+		result.WriteString(fmt.Sprintf("🔗 %s\n", source.Website))
+		if c, ok := coverage[source.Name]; ok {
+			result.WriteString(fmt.Sprintf("📍 %d rivers, %d stations\n", c.Rivers, c.Stations))
+		}
+		if lastUpdate, ok := lastUpdates[source.Name]; ok {
+			result.WriteString(fmt.Sprintf("🕒 Last update: %s\n", lastUpdate.Format("2006-01-02 15:04 MST")))
+		} else {
+			result.WriteString("🕒 No data yet\n")
+		}
+		result.WriteString("\n")
+	}
+	return result.String()
+}
+
+// FormatSourceStatus formats the last refresh time of each data source for display,
+// noting the sources whose last check brought no new data
+func (uc *RiverUseCase) FormatSourceStatus(lastUpdates map[string]time.Time, checks map[string]entities.SourceCheck) string {
+	if len(lastUpdates) == 0 {
+		return "No data has been collected yet."
+	}
+
+	sources := make([]string, 0, len(lastUpdates))
+	for source := range lastUpdates {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	var result strings.Builder
+	result.WriteString("Data source status:\n\n")
+	for _, source := range sources {
+		lastUpdate := lastUpdates[source]
+		age := time.Since(lastUpdate).Truncate(time.Minute)
+		result.WriteString(fmt.Sprintf("• %s: %s (%s ago)\n",
+			source, lastUpdate.Format("2006-01-02 15:04 MST"), age))
+		if check, ok := checks[source]; ok && check.Unchanged {
+			result.WriteString(fmt.Sprintf("  ⚠️ %s data unchanged since %s, last checked %s\n",
+				source, lastUpdate.Format("2006-01-02 15:04 MST"), check.CheckedAt.In(lastUpdate.Location()).Format("2006-01-02 15:04 MST")))
+		}
+	}
+	result.WriteString(fmt.Sprintf("\nReadings older than %s are flagged as outdated.\n", formatWindow(uc.staleAfter)))
 
 	return result.String()
 }
@@ -0,0 +1,940 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+	"github.com/abelzeko/water-bot/internal/integration"
+	"github.com/abelzeko/water-bot/internal/repository"
+)
+
+// newTestRepository creates a SQLite repository in a temporary directory
+func newTestRepository(t *testing.T) *repository.SQLiteRiverRepository {
+	t.Helper()
+	repo, err := repository.NewSQLiteRiverRepository(filepath.Join(t.TempDir(), "test-riverdata.db"))
+	if err != nil {
+		t.Fatalf("Failed to initialize repository: %v", err)
+	}
+	t.Cleanup(func() { repo.Close() })
+	return repo
+}
+
+// fakeSource returns a data source producing one reading for river after the given delay
+func fakeSource(name, river string, delay time.Duration, err error) DataSource {
+	return NewDataSource(name, func(ctx context.Context) ([]entities.RiverData, error) {
+		time.Sleep(delay)
+		if err != nil {
+			return nil, err
+		}
+		return []entities.RiverData{{
+			River:      river,
+			Station:    "STATION",
+			WaterLevel: "100",
+			Source:     name,
+			Timestamp:  time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC),
+		}}, nil
+	})
+}
+
+// TestRefreshRiverDataFetchesConcurrently verifies the run takes about as long as the slowest source
+func TestRefreshRiverDataFetchesConcurrently(t *testing.T) {
+	repo := newTestRepository(t)
+	uc := NewRiverUseCaseWithSources(repo, []DataSource{
+		fakeSource("fast", "ДУНАВ", 100*time.Millisecond, nil),
+		fakeSource("medium", "САВА", 200*time.Millisecond, nil),
+		fakeSource("slow", "ДРИНА", 300*time.Millisecond, nil),
+	}, nil)
+
+	start := time.Now()
+	if _, err := uc.RefreshRiverData(context.Background()); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	elapsed := time.Since(start)
+
+	// Sequential fetching would take at least 600ms
+	if elapsed >= 450*time.Millisecond {
+		t.Errorf("Expected refresh to take close to the slowest source (300ms), took %v", elapsed)
+	}
+
+	rivers, err := repo.GetUniqueRivers()
+	if err != nil {
+		t.Fatalf("Failed to get rivers: %v", err)
+	}
+	if len(rivers) != 3 {
+		t.Errorf("Expected data from all 3 sources, got rivers %v", rivers)
+	}
+}
+
+// TestRefreshRiverDataContinuesWhenOptionalSourceFails verifies a failing secondary source doesn't abort the run
+func TestRefreshRiverDataContinuesWhenOptionalSourceFails(t *testing.T) {
+	repo := newTestRepository(t)
+	uc := NewRiverUseCaseWithSources(repo, []DataSource{
+		fakeSource("primary", "ДУНАВ", 0, nil),
+		fakeSource("broken", "САВА", 0, errors.New("site down")),
+		fakeSource("secondary", "ДРИНА", 0, nil),
+	}, nil)
+
+	if _, err := uc.RefreshRiverData(context.Background()); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+
+	rivers, err := repo.GetUniqueRivers()
+	if err != nil {
+		t.Fatalf("Failed to get rivers: %v", err)
+	}
+	if len(rivers) != 2 {
+		t.Errorf("Expected data from the 2 working sources, got rivers %v", rivers)
+	}
+}
+
+// TestRefreshRiverDataNormalizesRiverNames verifies sources spelling a river differently store it under one name
+func TestRefreshRiverDataNormalizesRiverNames(t *testing.T) {
+	repo := newTestRepository(t)
+	uc := NewRiverUseCaseWithSources(repo, []DataSource{
+		fakeSource("primary", "ДРИНА", 0, nil),
+		fakeSource("secondary", " Дрина  ", 0, nil),
+	}, nil)
+
+	summary, err := uc.RefreshRiverData(context.Background())
+	if err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if summary.Saved != 1 {
+		t.Errorf("Expected the two spellings to merge into 1 reading, saved %d", summary.Saved)
+	}
+
+	rivers, err := uc.GetAvailableRivers()
+	if err != nil {
+		t.Fatalf("Failed to get rivers: %v", err)
+	}
+	if len(rivers) != 1 || rivers[0] != "ДРИНА" {
+		t.Errorf("Expected only ДРИНА, got %v", rivers)
+	}
+	if data, err := uc.GetRiverDataByName("дрина"); err != nil || len(data) != 1 {
+		t.Errorf("Expected a lower-case lookup to find ДРИНА, got %v (err %v)", data, err)
+	}
+}
+
+// TestRefreshRiverDataSavesWhenPrimaryFails verifies the other sources are saved when the primary one is down
+func TestRefreshRiverDataSavesWhenPrimaryFails(t *testing.T) {
+	repo := newTestRepository(t)
+	uc := NewRiverUseCaseWithSources(repo, []DataSource{
+		fakeSource("hidmet", "ДУНАВ", 0, errors.New("site down")),
+		fakeSource("gradac", "ГРАДАЦ", 0, nil),
+	}, nil)
+
+	summary, err := uc.RefreshRiverData(context.Background())
+	if err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if summary.Saved != 1 {
+		t.Errorf("Expected 1 saved reading, got %d", summary.Saved)
+	}
+
+	data, err := repo.GetRiverDataByName("ГРАДАЦ")
+	if err != nil || len(data) != 1 {
+		t.Errorf("Expected the ГРАДАЦ reading to be saved, got %v (err %v)", data, err)
+	}
+}
+
+// TestRefreshRiverDataFailsWhenAllSourcesFail verifies a fully failed run reports an error and keeps old data
+func TestRefreshRiverDataFailsWhenAllSourcesFail(t *testing.T) {
+	repo := newTestRepository(t)
+	if err := repo.SaveRiverData([]entities.RiverData{{River: "ДУНАВ", Station: "STATION", WaterLevel: "90", Timestamp: time.Now()}}); err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+	uc := NewRiverUseCaseWithSources(repo, []DataSource{
+		fakeSource("hidmet", "ДУНАВ", 0, errors.New("site down")),
+		fakeSource("gradac", "ГРАДАЦ", 0, errors.New("timeout")),
+	}, nil)
+
+	if _, err := uc.RefreshRiverData(context.Background()); err == nil {
+		t.Error("Expected an error when every source fails")
+	}
+	if data, _ := repo.GetRiverDataByName("ДУНАВ"); len(data) != 1 || data[0].WaterLevel != "90" {
+		t.Errorf("Expected the stored reading to be kept, got %v", data)
+	}
+}
+
+// TestFormatRiverInfoWarnsWhenStale verifies old readings are flagged as outdated
+func TestFormatRiverInfoWarnsWhenStale(t *testing.T) {
+	repo := newTestRepository(t)
+	uc := NewRiverUseCaseWithSources(repo, nil, nil)
+
+	err := repo.SaveRiverData([]entities.RiverData{
+		{River: "ДРИНА", Station: "Радаљ", WaterLevel: "142", Timestamp: time.Now().Add(-5 * time.Hour)},
+		{River: "САВА", Station: "Шабац", WaterLevel: "325", Timestamp: time.Now().Add(-30 * time.Minute)},
+	})
+	if err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+
+	for _, tt := range []struct {
+		river string
+		stale bool
+	}{
+		{"ДРИНА", true},
+		{"САВА", false},
+	} {
+		data, err := uc.GetRiverDataByName(tt.river)
+		if err != nil || len(data) != 1 {
+			t.Fatalf("Expected one reading for %s, got %v (err %v)", tt.river, data, err)
+		}
+		text := uc.FormatRiverInfo(data)
+		if warned := strings.Contains(text, "may be outdated"); warned != tt.stale {
+			t.Errorf("%s: expected stale warning %v, got:\n%s", tt.river, tt.stale, text)
+		}
+	}
+}
+
+// TestFormatRiverInfoDischarge verifies discharge is shown only for stations that report it
+func TestFormatRiverInfoDischarge(t *testing.T) {
+	repo := newTestRepository(t)
+	uc := NewRiverUseCaseWithSources(repo, nil, nil)
+
+	ts := time.Now()
+	err := repo.SaveRiverData([]entities.RiverData{
+		{River: "ДУНАВ", Station: "Земун", WaterLevel: "350", Discharge: "4820", Timestamp: ts},
+		{River: "САВА", Station: "Шабац", WaterLevel: "325", Discharge: "", Timestamp: ts},
+		{River: "ДРИНА", Station: "Радаљ", WaterLevel: "142", Discharge: "-", Timestamp: ts},
+	})
+	if err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+
+	for _, tt := range []struct {
+		river string
+		want  string
+	}{
+		{"ДУНАВ", "🌊 Discharge: 4820 m³/s"},
+		{"САВА", ""},
+		{"ДРИНА", ""},
+	} {
+		data, err := uc.GetRiverDataByName(tt.river)
+		if err != nil || len(data) != 1 {
+			t.Fatalf("Expected one reading for %s, got %v (err %v)", tt.river, data, err)
+		}
+		text := uc.FormatRiverInfo(data)
+		if tt.want == "" && strings.Contains(text, "Discharge") {
+			t.Errorf("%s: expected no discharge, got:\n%s", tt.river, text)
+		}
+		if tt.want != "" && !strings.Contains(text, tt.want) {
+			t.Errorf("%s: expected %q, got:\n%s", tt.river, tt.want, text)
+		}
+	}
+
+	stations, err := uc.GetStationsByDischarge(1000)
+	if err != nil {
+		t.Fatalf("Failed to get stations by discharge: %v", err)
+	}
+	if len(stations) != 1 || stations[0].Station != "Земун" {
+		t.Errorf("Expected only Земун above 1000 m³/s, got %+v", stations)
+	}
+}
+
+// TestFormatSourceStatusShowsStaleWindow verifies /status reports the configured freshness window
+func TestFormatSourceStatusShowsStaleWindow(t *testing.T) {
+	uc := NewRiverUseCaseWithSources(newTestRepository(t), nil, nil)
+	lastUpdates := map[string]time.Time{"hidmet": time.Now()}
+
+	if text := uc.FormatSourceStatus(lastUpdates, nil); !strings.Contains(text, "older than 1h are flagged") {
+		t.Errorf("Expected the default window, got:\n%s", text)
+	}
+	uc.SetStaleAfter(90 * time.Minute)
+	if text := uc.FormatSourceStatus(lastUpdates, nil); !strings.Contains(text, "older than 1h30m are flagged") {
+		t.Errorf("Expected the configured window, got:\n%s", text)
+	}
+}
+
+// TestRefreshFlagsUnchangedBulletin verifies a source returning the same bulletin twice is flagged
+// on the second refresh and shown as unchanged in /status
+func TestRefreshFlagsUnchangedBulletin(t *testing.T) {
+	repo := newTestRepository(t)
+	uc := NewRiverUseCaseWithSources(repo, []DataSource{
+		fakeSource(integration.SourceRhmzRs, "ДРИНА", 0, nil),
+	}, nil)
+
+	summary, err := uc.RefreshRiverData(context.Background())
+	if err != nil {
+		t.Fatalf("First refresh failed: %v", err)
+	}
+	if summary.Sources[0].Unchanged {
+		t.Errorf("Expected the first bulletin to count as new")
+	}
+
+	summary, err = uc.RefreshRiverData(context.Background())
+	if err != nil {
+		t.Fatalf("Second refresh failed: %v", err)
+	}
+	if !summary.Sources[0].Unchanged {
+		t.Errorf("Expected the repeated bulletin to be flagged as unchanged")
+	}
+
+	checks, err := uc.GetSourceChecks()
+	if err != nil {
+		t.Fatalf("Failed to get source checks: %v", err)
+	}
+	if !checks[integration.SourceRhmzRs].Unchanged {
+		t.Errorf("Expected the unchanged check to be stored, got %+v", checks)
+	}
+
+	lastUpdates, err := uc.GetLastUpdateTimeBySource()
+	if err != nil {
+		t.Fatalf("Failed to get last updates: %v", err)
+	}
+	if text := uc.FormatSourceStatus(lastUpdates, checks); !strings.Contains(text, "rhmzrs data unchanged since 2025-04-18 06:00") {
+		t.Errorf("Expected /status to report the unchanged bulletin, got:\n%s", text)
+	}
+}
+
+// TestRefreshRiverDataRespectsContext verifies a hung source doesn't outlive the run's deadline
+func TestRefreshRiverDataRespectsContext(t *testing.T) {
+	repo := newTestRepository(t)
+	uc := NewRiverUseCaseWithSources(repo, []DataSource{
+		fakeSource("primary", "ДУНАВ", 0, nil),
+		fakeSource("hung", "САВА", 5*time.Second, nil),
+	}, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := uc.RefreshRiverData(ctx); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected refresh to stop at the context deadline, took %v", elapsed)
+	}
+}
+
+// TestRefreshRiverDataCancelsScraperRequests verifies the scraper's requests are aborted
+// when the run's context ends, rather than left running after the refresh returned
+func TestRefreshRiverDataCancelsScraperRequests(t *testing.T) {
+	aborted := make(chan struct{}, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+			aborted <- struct{}{}
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	scraper := integration.NewWaterScraperWithConfig(integration.ScraperConfig{
+		HidmetURL:        server.URL + "/hidmet",
+		GradacURL:        server.URL + "/gradac",
+		RhmzRsListingURL: server.URL + "/listing",
+		DhmzURL:          server.URL + "/dhmz",
+	})
+	uc := NewRiverUseCase(newTestRepository(t), scraper, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := uc.RefreshRiverData(ctx); err == nil {
+		t.Fatal("Expected the refresh to fail when every source times out")
+	}
+
+	select {
+	case <-aborted:
+	case <-time.After(time.Second):
+		t.Error("Expected the in-flight request to be aborted with the run's context")
+	}
+}
+
+// TestCheckAlertsFiresOncePerCrossing verifies alerts fire when crossing and re-arm when the level recovers
+func TestCheckAlertsFiresOncePerCrossing(t *testing.T) {
+	repo := newTestRepository(t)
+	uc := NewRiverUseCaseWithSources(repo, nil, nil)
+
+	save := func(level string, ts time.Time) {
+		t.Helper()
+		err := repo.SaveRiverData([]entities.RiverData{{River: "ДРИНА", Station: "Радаљ", WaterLevel: level, Timestamp: ts}})
+		if err != nil {
+			t.Fatalf("Failed to save data: %v", err)
+		}
+	}
+
+	start := time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC)
+	save("250", start)
+
+	if _, err := uc.Subscribe(42, "ДРИНА", entities.AlertLevel, entities.AlertAbove, 300); err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+
+	steps := []struct {
+		level string
+		fires bool
+	}{
+		{"250", false}, // below threshold
+		{"320", true},  // crosses above
+		{"330", false}, // still above, already triggered
+		{"280", false}, // back below, re-arms
+		{"310", true},  // crosses again
+	}
+	for i, step := range steps {
+		now := start.Add(time.Duration(i+1) * time.Hour)
+		save(step.level, now)
+		notifications, err := uc.CheckAlerts(now)
+		if err != nil {
+			t.Fatalf("Step %d: failed to check alerts: %v", i, err)
+		}
+		if fired := len(notifications) == 1; fired != step.fires {
+			t.Errorf("Step %d (level %s): expected fired=%v, got %d notifications", i, step.level, step.fires, len(notifications))
+		}
+		if step.fires && len(notifications) == 1 && notifications[0].ChatID != 42 {
+			t.Errorf("Step %d: expected notification for chat 42, got %d", i, notifications[0].ChatID)
+		}
+	}
+
+	subs, err := uc.GetSubscriptions(42)
+	if err != nil || len(subs) != 1 {
+		t.Fatalf("Expected one subscription, got %v (err %v)", subs, err)
+	}
+	if want := start.Add(5 * time.Hour); !subs[0].LastTriggered.Equal(want) {
+		t.Errorf("Expected last triggered %v, got %v", want, subs[0].LastTriggered)
+	}
+}
+
+// failingRiverRepository fails to read the data of the rivers in failing
+type failingRiverRepository struct {
+	*repository.SQLiteRiverRepository
+	failing map[string]bool
+}
+
+// GetRiverDataByName fails for a failing river
+func (r failingRiverRepository) GetRiverDataByName(river string) ([]entities.RiverData, error) {
+	if r.failing[river] {
+		return nil, errors.New("database is locked")
+	}
+	return r.SQLiteRiverRepository.GetRiverDataByName(river)
+}
+
+// TestCheckAlertsKeepsNotificationsWhenOneFails verifies an alert that can't be checked
+// doesn't drop the notifications of the others and stays armed for the next check
+func TestCheckAlertsKeepsNotificationsWhenOneFails(t *testing.T) {
+	repo := failingRiverRepository{SQLiteRiverRepository: newTestRepository(t), failing: map[string]bool{}}
+	uc := NewRiverUseCaseWithSources(repo, nil, nil)
+
+	now := time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC)
+	err := repo.SaveRiverData([]entities.RiverData{
+		{River: "ДРИНА", Station: "Радаљ", WaterLevel: "320", Timestamp: now},
+		{River: "САВА", Station: "Шабац", WaterLevel: "420", Timestamp: now},
+	})
+	if err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+	for _, river := range []string{"ДРИНА", "САВА"} {
+		if _, err := uc.Subscribe(42, river, entities.AlertLevel, entities.AlertAbove, 300); err != nil {
+			t.Fatalf("Failed to subscribe to %s: %v", river, err)
+		}
+	}
+
+	repo.failing["ДРИНА"] = true
+	notifications, err := uc.CheckAlerts(now)
+	if err != nil {
+		t.Fatalf("Expected the check to succeed, got %v", err)
+	}
+	if len(notifications) != 1 || !strings.Contains(notifications[0].Text, "САВА") {
+		t.Fatalf("Expected only the САВА alert, got %+v", notifications)
+	}
+
+	repo.failing["ДРИНА"] = false
+	notifications, err = uc.CheckAlerts(now)
+	if err != nil {
+		t.Fatalf("Failed to check alerts: %v", err)
+	}
+	if len(notifications) != 1 || !strings.Contains(notifications[0].Text, "ДРИНА") {
+		t.Errorf("Expected the ДРИНА alert on the next check, got %+v", notifications)
+	}
+}
+
+// TestCheckTempAlertsFiresOncePerCrossing verifies temperature alerts fire when any station
+// warms past the threshold and ignore stations without a temperature
+func TestCheckTempAlertsFiresOncePerCrossing(t *testing.T) {
+	repo := newTestRepository(t)
+	uc := NewRiverUseCaseWithSources(repo, nil, nil)
+
+	save := func(temp string, ts time.Time) {
+		t.Helper()
+		err := repo.SaveRiverData([]entities.RiverData{
+			{River: "ДРИНА", Station: "Радаљ", WaterLevel: "150", WaterTemp: temp, Timestamp: ts},
+			{River: "ДРИНА", Station: "Бајина Башта", WaterLevel: "400", WaterTemp: "-", Timestamp: ts},
+		})
+		if err != nil {
+			t.Fatalf("Failed to save data: %v", err)
+		}
+	}
+
+	start := time.Date(2025, 7, 18, 6, 0, 0, 0, time.UTC)
+	save("14.5", start)
+
+	if _, err := uc.Subscribe(42, "ДРИНА", entities.AlertTemp, entities.AlertAbove, 16); err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+
+	steps := []struct {
+		temp  string
+		fires bool
+	}{
+		{"15,8", false}, // below threshold, decimal comma
+		{"16,4", true},  // crosses above
+		{"17.0", false}, // still above, already triggered
+		{"", false},     // no temperature published, re-arms
+		{"16.2", true},  // crosses again
+	}
+	for i, step := range steps {
+		now := start.Add(time.Duration(i+1) * time.Hour)
+		save(step.temp, now)
+		notifications, err := uc.CheckAlerts(now)
+		if err != nil {
+			t.Fatalf("Step %d: failed to check alerts: %v", i, err)
+		}
+		if fired := len(notifications) == 1; fired != step.fires {
+			t.Errorf("Step %d (temperature %q): expected fired=%v, got %d notifications", i, step.temp, step.fires, len(notifications))
+		}
+		if step.fires && len(notifications) == 1 {
+			text := notifications[0].Text
+			if !strings.Contains(text, "water temperature is above 16 °C") || strings.Contains(text, "Бајина Башта") {
+				t.Errorf("Step %d: unexpected alert text:\n%s", i, text)
+			}
+		}
+	}
+
+	subs, err := uc.GetSubscriptions(42)
+	if err != nil || len(subs) != 1 {
+		t.Fatalf("Expected one subscription, got %v (err %v)", subs, err)
+	}
+	if subs[0].Kind != entities.AlertTemp {
+		t.Errorf("Expected a temperature alert, got kind %q", subs[0].Kind)
+	}
+	if text := uc.FormatSubscription(subs[0]); !strings.Contains(text, "water temperature above 16 °C") {
+		t.Errorf("Unexpected subscription text: %s", text)
+	}
+}
+
+// TestSubscribeUnknownRiver verifies alerts can't be created for rivers without data
+func TestSubscribeUnknownRiver(t *testing.T) {
+	uc := NewRiverUseCaseWithSources(newTestRepository(t), nil, nil)
+	if _, err := uc.Subscribe(1, "НЕПОЗНАТА", entities.AlertLevel, entities.AlertAbove, 100); !errors.Is(err, ErrUnknownRiver) {
+		t.Errorf("Expected ErrUnknownRiver, got %v", err)
+	}
+}
+
+// TestDueDigestsMatchesSendHour verifies only digests whose local hour matches are dispatched
+func TestDueDigestsMatchesSendHour(t *testing.T) {
+	repo := newTestRepository(t)
+	uc := NewRiverUseCaseWithSources(repo, nil, nil)
+
+	err := repo.SaveRiverData([]entities.RiverData{
+		{River: "ДРИНА", Station: "Радаљ", WaterLevel: "142", Timestamp: time.Date(2025, 7, 1, 5, 0, 0, 0, time.UTC)},
+		{River: "САВА", Station: "Шабац", WaterLevel: "325", Timestamp: time.Date(2025, 7, 1, 5, 0, 0, 0, time.UTC)},
+	})
+	if err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+
+	if _, err := uc.AddDigestRiver(1, "ДРИНА", 8); err != nil {
+		t.Fatalf("Failed to add digest river: %v", err)
+	}
+	if _, err := uc.AddDigestRiver(1, "САВА", 8); err != nil {
+		t.Fatalf("Failed to add digest river: %v", err)
+	}
+	if _, err := uc.AddDigestRiver(2, "САВА", 9); err != nil {
+		t.Fatalf("Failed to add digest river: %v", err)
+	}
+	if _, err := uc.AddDigestRiver(3, "САВА", 24); !errors.Is(err, ErrInvalidHour) {
+		t.Errorf("Expected ErrInvalidHour, got %v", err)
+	}
+
+	// 06:05 UTC is 08:05 in Belgrade during summer time
+	notifications, err := uc.DueDigests(time.Date(2025, 7, 1, 6, 5, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Failed to build digests: %v", err)
+	}
+	if len(notifications) != 1 || notifications[0].ChatID != 1 {
+		t.Fatalf("Expected one digest for chat 1, got %+v", notifications)
+	}
+	for _, river := range []string{"ДРИНА", "САВА"} {
+		if !strings.Contains(notifications[0].Text, "Information for river "+river) {
+			t.Errorf("Expected digest to include river %s, got:\n%s", river, notifications[0].Text)
+		}
+	}
+
+	notifications, err = uc.DueDigests(time.Date(2025, 7, 1, 7, 5, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Failed to build digests: %v", err)
+	}
+	if len(notifications) != 1 || notifications[0].ChatID != 2 {
+		t.Errorf("Expected one digest for chat 2, got %+v", notifications)
+	}
+
+	// Removing the last river deletes the digest
+	for _, river := range []string{"ДРИНА", "САВА"} {
+		if removed, err := uc.RemoveDigestRiver(1, river); err != nil || !removed {
+			t.Fatalf("Failed to remove %s: removed=%v err=%v", river, removed, err)
+		}
+	}
+	if _, found, _ := uc.GetDigest(1); found {
+		t.Error("Expected the empty digest to be deleted")
+	}
+}
+
+// TestUseCaseWithoutOpenAI verifies commands and free text work when no OpenAI service is configured
+func TestUseCaseWithoutOpenAI(t *testing.T) {
+	repo := newTestRepository(t)
+	uc := NewRiverUseCase(repo, nil, nil)
+
+	err := repo.SaveRiverData([]entities.RiverData{
+		{River: "ДРИНА", Station: "Радаљ", WaterLevel: "142", Timestamp: time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC)},
+	})
+	if err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+
+	data, err := uc.GetRiverDataByName("ДРИНА")
+	if err != nil || len(data) != 1 {
+		t.Fatalf("Expected one reading, got %v (err %v)", data, err)
+	}
+
+	reply, err := uc.HandleNaturalLanguageQuery(context.Background(), "how is the Drina today?")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if reply != notUnderstoodMessage {
+		t.Errorf("Expected the not-understood reply, got %q", reply)
+	}
+}
+
+// TestFallbackShowsDefaultRiver verifies replies to free text that wasn't understood include the configured default river
+func TestFallbackShowsDefaultRiver(t *testing.T) {
+	repo := newTestRepository(t)
+	err := repo.SaveRiverData([]entities.RiverData{
+		{River: "ДРИНА", Station: "Радаљ", WaterLevel: "142", Timestamp: time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC)},
+	})
+	if err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		river string
+		want  string
+	}{
+		{"configured river", "ДРИНА", notUnderstoodMessage + "\n\nInformation for river ДРИНА:"},
+		{"river without data", "ГРАДАЦ", notUnderstoodMessage},
+		{"disabled", "", notUnderstoodMessage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := NewRiverUseCase(repo, nil, nil)
+			uc.SetDefaultRiver(tt.river)
+
+			reply, err := uc.HandleNaturalLanguageQuery(context.Background(), "what's up?")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !strings.HasPrefix(reply, tt.want) {
+				t.Errorf("Expected reply starting with %q, got %q", tt.want, reply)
+			}
+			if tt.want == notUnderstoodMessage && reply != notUnderstoodMessage {
+				t.Errorf("Expected no default river block, got %q", reply)
+			}
+		})
+	}
+}
+
+// TestRefreshRiverDataSummary verifies the summary reports every source's rows and errors
+func TestRefreshRiverDataSummary(t *testing.T) {
+	repo := newTestRepository(t)
+	uc := NewRiverUseCaseWithSources(repo, []DataSource{
+		fakeSource("primary", "ДУНАВ", 0, nil),
+		fakeSource("broken", "САВА", 0, errors.New("site down")),
+		fakeSource("secondary", "ДРИНА", 20*time.Millisecond, nil),
+	}, nil)
+
+	summary, err := uc.RefreshRiverData(context.Background())
+	if err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+
+	if summary.Saved != 2 {
+		t.Errorf("Expected 2 saved readings, got %d", summary.Saved)
+	}
+	if len(summary.Sources) != 3 {
+		t.Fatalf("Expected 3 source results, got %+v", summary.Sources)
+	}
+	for i, want := range []struct {
+		source string
+		rows   int
+		failed bool
+	}{
+		{"primary", 1, false},
+		{"broken", 0, true},
+		{"secondary", 1, false},
+	} {
+		got := summary.Sources[i]
+		if got.Source != want.source || got.Rows != want.rows || (got.Err != nil) != want.failed {
+			t.Errorf("Source %d: expected %+v, got %+v", i, want, got)
+		}
+	}
+	if failed := summary.FailedSources(); len(failed) != 1 || failed[0] != "broken" {
+		t.Errorf("Expected only the broken source to fail, got %v", failed)
+	}
+	if summary.Sources[2].Duration < 20*time.Millisecond || summary.Duration < summary.Sources[2].Duration {
+		t.Errorf("Expected durations to cover the slow source, got source %v, total %v", summary.Sources[2].Duration, summary.Duration)
+	}
+}
+
+// TestRefreshRiverDataTimesEachSource verifies every source, failed ones included, reports
+// how long its own fetch took and that the summary shows it
+func TestRefreshRiverDataTimesEachSource(t *testing.T) {
+	delays := map[string]time.Duration{
+		"hidmet": 30 * time.Millisecond,
+		"gradac": 10 * time.Millisecond,
+		"dhmz":   20 * time.Millisecond,
+	}
+	uc := NewRiverUseCaseWithSources(newTestRepository(t), []DataSource{
+		fakeSource("hidmet", "ДУНАВ", delays["hidmet"], nil),
+		fakeSource("gradac", "ГРАДАЦ", delays["gradac"], nil),
+		fakeSource("dhmz", "SAVA", delays["dhmz"], errors.New("timeout")),
+	}, nil)
+
+	summary, err := uc.RefreshRiverData(context.Background())
+	if err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	for _, source := range summary.Sources {
+		want := delays[source.Source]
+		if source.Duration < want || source.Duration > summary.Duration {
+			t.Errorf("%s: expected a duration between %v and the total %v, got %v", source.Source, want, summary.Duration, source.Duration)
+		}
+	}
+
+	text := uc.FormatRefreshResult(summary, nil)
+	for _, want := range []string{"• hidmet: 1 rows (", "• gradac: 1 rows (", "• dhmz: error: timeout ("} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in the summary, got:\n%s", want, text)
+		}
+	}
+}
+
+// TestFormatRiverInfoGroupsByCountry verifies stations of a river reported by several countries are grouped
+func TestFormatRiverInfoGroupsByCountry(t *testing.T) {
+	uc := NewRiverUseCaseWithSources(newTestRepository(t), nil, nil)
+	ts := time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC)
+
+	text := uc.FormatRiverInfo([]entities.RiverData{
+		{River: "ДРИНА", Station: "Радаљ", WaterLevel: "142", Source: integration.SourceHidmet, Timestamp: ts},
+		{River: "ДРИНА", Station: "Фоча", WaterLevel: "98", Source: integration.SourceRhmzRs, Timestamp: ts},
+		{River: "ДРИНА", Station: "Бајина Башта", WaterLevel: "210", Source: integration.SourceHidmet, Timestamp: ts},
+	})
+
+	serbia := strings.Index(text, "Serbia:")
+	srpska := strings.Index(text, "Republika Srpska:")
+	if serbia < 0 || srpska < 0 {
+		t.Fatalf("Expected a section per country, got:\n%s", text)
+	}
+	for _, station := range []string{"Радаљ", "Бајина Башта"} {
+		if i := strings.Index(text, station); i < serbia || i > srpska {
+			t.Errorf("Expected %s in the Serbia section, got:\n%s", station, text)
+		}
+	}
+	if i := strings.Index(text, "Фоча"); i < srpska {
+		t.Errorf("Expected Фоча in the Republika Srpska section, got:\n%s", text)
+	}
+
+	// A single country needs no subheader
+	text = uc.FormatRiverInfo([]entities.RiverData{
+		{River: "САВА", Station: "Шабац", WaterLevel: "325", Source: integration.SourceHidmet, Timestamp: ts},
+	})
+	if strings.Contains(text, "Serbia:") {
+		t.Errorf("Expected no country subheader for a single country, got:\n%s", text)
+	}
+}
+
+// TestRefreshPrefersSourcePriority verifies the higher-priority source's reading is stored when two sources disagree
+func TestRefreshPrefersSourcePriority(t *testing.T) {
+	timestamp := time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC)
+	reading := func(source, level string) DataSource {
+		return NewDataSource(source, func(ctx context.Context) ([]entities.RiverData, error) {
+			return []entities.RiverData{{River: "ГРАДАЦ", Station: "ДЕГУРИЋ", WaterLevel: level, Source: source, Timestamp: timestamp}}, nil
+		})
+	}
+
+	// Either fetch order must give the same result
+	orders := map[string][]DataSource{
+		"preferred first": {reading(integration.SourceHidmet, "41"), reading(integration.SourceGradac, "43")},
+		"preferred last":  {reading(integration.SourceGradac, "43"), reading(integration.SourceHidmet, "41")},
+	}
+	for name, sources := range orders {
+		t.Run(name, func(t *testing.T) {
+			repo := newTestRepository(t)
+			uc := NewRiverUseCaseWithSources(repo, sources, nil)
+
+			summary, err := uc.RefreshRiverData(context.Background())
+			if err != nil {
+				t.Fatalf("Refresh failed: %v", err)
+			}
+			if summary.Saved != 1 {
+				t.Errorf("Expected 1 saved reading, got %d", summary.Saved)
+			}
+
+			data, err := repo.GetRiverDataByName("ГРАДАЦ")
+			if err != nil {
+				t.Fatalf("Failed to get data: %v", err)
+			}
+			if len(data) != 1 {
+				t.Fatalf("Expected 1 reading, got %d", len(data))
+			}
+			if data[0].WaterLevel != "41" || data[0].Source != integration.SourceHidmet {
+				t.Errorf("Expected level 41 from %s, got %s from %s", integration.SourceHidmet, data[0].WaterLevel, data[0].Source)
+			}
+		})
+	}
+}
+
+// TestRefreshKeepsFallbackForImplausiblePriorityReading verifies an implausible reading of the
+// higher-priority source is rejected without discarding a valid reading of another source
+func TestRefreshKeepsFallbackForImplausiblePriorityReading(t *testing.T) {
+	timestamp := time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC)
+	reading := func(source, level string) DataSource {
+		return NewDataSource(source, func(ctx context.Context) ([]entities.RiverData, error) {
+			return []entities.RiverData{{River: "ГРАДАЦ", Station: "ДЕГУРИЋ", WaterLevel: level, Source: source, Timestamp: timestamp}}, nil
+		})
+	}
+	repo := newTestRepository(t)
+	uc := NewRiverUseCaseWithSources(repo, []DataSource{
+		reading(integration.SourceHidmet, "9999"),
+		reading(integration.SourceGradac, "43"),
+	}, nil)
+
+	summary, err := uc.RefreshRiverData(context.Background())
+	if err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if summary.Rejected != 1 || summary.Saved != 1 {
+		t.Errorf("Expected 1 rejected and 1 saved reading, got %d and %d", summary.Rejected, summary.Saved)
+	}
+
+	data, err := repo.GetRiverDataByName("ГРАДАЦ")
+	if err != nil {
+		t.Fatalf("Failed to get data: %v", err)
+	}
+	if len(data) != 1 || data[0].WaterLevel != "43" || data[0].Source != integration.SourceGradac {
+		t.Errorf("Expected level 43 from %s, got %+v", integration.SourceGradac, data)
+	}
+}
+
+// TestRefreshStampsUndatedReadings verifies readings without a timestamp reuse the source's previous one instead of the current time
+func TestRefreshStampsUndatedReadings(t *testing.T) {
+	repo := newTestRepository(t)
+	previous := time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC)
+	err := repo.SaveRiverData([]entities.RiverData{
+		{River: "ДРИНА", Station: "Радаљ", WaterLevel: "142", Source: "known", Timestamp: previous},
+	})
+	if err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+
+	undated := func(source, river string) DataSource {
+		return NewDataSource(source, func(ctx context.Context) ([]entities.RiverData, error) {
+			return []entities.RiverData{{River: river, Station: "STATION", WaterLevel: "150", Source: source}}, nil
+		})
+	}
+	uc := NewRiverUseCaseWithSources(repo, []DataSource{undated("known", "ДРИНА"), undated("new", "САВА")}, nil)
+
+	summary, err := uc.RefreshRiverData(context.Background())
+	if err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if summary.Saved != 1 {
+		t.Errorf("Expected 1 saved reading, got %d", summary.Saved)
+	}
+
+	history, err := repo.GetStationHistory("ДРИНА", "STATION", time.Time{})
+	if err != nil {
+		t.Fatalf("Failed to get history: %v", err)
+	}
+	if len(history) != 1 || !history[0].Timestamp.Equal(previous) {
+		t.Errorf("Expected one reading stamped %v, got %+v", previous, history)
+	}
+
+	if data, err := repo.GetRiverDataByName("САВА"); err != nil || len(data) != 0 {
+		t.Errorf("Expected the undated reading of a source without history to be dropped, got %+v (err %v)", data, err)
+	}
+}
+
+// TestRefreshRiverDataSerializesConcurrentRuns fires two refreshes at once and verifies they
+// never fetch at the same time, the second either waiting or giving up depending on the wait
+func TestRefreshRiverDataSerializesConcurrentRuns(t *testing.T) {
+	tests := []struct {
+		name        string
+		wait        time.Duration
+		wantSkipped bool
+	}{
+		{name: "no wait", wait: 0, wantSkipped: true},
+		{name: "wait for the running refresh", wait: 2 * time.Second, wantSkipped: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var running, maxRunning, runs atomic.Int32
+			started := make(chan struct{}, 2)
+			source := NewDataSource("slow", func(ctx context.Context) ([]entities.RiverData, error) {
+				started <- struct{}{}
+				now := running.Add(1)
+				defer running.Add(-1)
+				for {
+					seen := maxRunning.Load()
+					if now <= seen || maxRunning.CompareAndSwap(seen, now) {
+						break
+					}
+				}
+				runs.Add(1)
+				time.Sleep(200 * time.Millisecond)
+				return []entities.RiverData{{River: "ДУНАВ", Station: "STATION", WaterLevel: "100", Timestamp: time.Now()}}, nil
+			})
+			uc := NewRiverUseCaseWithSources(newTestRepository(t), []DataSource{source}, nil)
+			uc.SetRefreshWait(tt.wait)
+
+			// Start the second refresh once the first one is fetching
+			errs := make([]error, 2)
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, errs[0] = uc.RefreshRiverData(context.Background())
+			}()
+			<-started
+			_, errs[1] = uc.RefreshRiverData(context.Background())
+			wg.Wait()
+
+			if maxRunning.Load() != 1 {
+				t.Errorf("Expected refreshes to run one at a time, %d ran together", maxRunning.Load())
+			}
+			skipped := 0
+			for _, err := range errs {
+				switch {
+				case errors.Is(err, ErrRefreshInProgress):
+					skipped++
+				case err != nil:
+					t.Errorf("Refresh failed: %v", err)
+				}
+			}
+			if tt.wantSkipped && (skipped != 1 || runs.Load() != 1) {
+				t.Errorf("Expected one refresh to be skipped, %d skipped and %d ran", skipped, runs.Load())
+			}
+			if !tt.wantSkipped && (skipped != 0 || runs.Load() != 2) {
+				t.Errorf("Expected both refreshes to run, %d skipped and %d ran", skipped, runs.Load())
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package usecases
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// SmoothSeries returns the moving average of the water level over window consecutive readings.
+// Each averaged reading is timestamped at the center of its window and carries the
+// river, station and source of the center reading. Readings without a numeric level
+// are skipped. A window of 1 or less returns the numeric readings unchanged.
+func SmoothSeries(data []entities.RiverData, window int) []entities.RiverData {
+	var readings []entities.RiverData
+	var levels []float64
+	for _, rd := range data {
+		level, ok := rd.LevelValue()
+		if !ok {
+			continue
+		}
+		readings = append(readings, rd)
+		levels = append(levels, level)
+	}
+
+	if window <= 1 {
+		return readings
+	}
+	if len(readings) < window {
+		return nil
+	}
+
+	result := make([]entities.RiverData, 0, len(readings)-window+1)
+	sum := 0.0
+	for i, level := range levels {
+		sum += level
+		if i >= window {
+			sum -= levels[i-window]
+		}
+		if i < window-1 {
+			continue
+		}
+
+		first, last := readings[i-window+1], readings[i]
+		center := readings[i-window/2]
+		average := math.Round(sum/float64(window)*10) / 10
+
+		result = append(result, entities.RiverData{
+			River:      center.River,
+			Station:    center.Station,
+			WaterLevel: strconv.FormatFloat(average, 'f', -1, 64),
+			Tendency:   entities.Unknown,
+			Source:     center.Source,
+			Timestamp:  first.Timestamp.Add(last.Timestamp.Sub(first.Timestamp) / 2),
+		})
+	}
+
+	return result
+}
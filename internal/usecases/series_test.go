@@ -0,0 +1,55 @@
+package usecases
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// TestSmoothSeries verifies the moving average values and centered timestamps
+func TestSmoothSeries(t *testing.T) {
+	start := time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC)
+	var data []entities.RiverData
+	for i, level := range []string{"10", "20", "n/a", "30", "45", "50"} {
+		data = append(data, entities.RiverData{
+			River:      "ГРАДАЦ",
+			Station:    "ДЕГУРИЋ",
+			WaterLevel: level,
+			Timestamp:  start.Add(time.Duration(i) * 10 * time.Minute),
+		})
+	}
+
+	smoothed := SmoothSeries(data, 3)
+
+	// The non-numeric reading is skipped, leaving 10, 20, 30, 45, 50
+	expected := []struct {
+		level     string
+		timestamp time.Time
+	}{
+		{"20", start.Add(15 * time.Minute)},   // 10, 20, 30 over 0-30 min
+		{"31.7", start.Add(25 * time.Minute)}, // 20, 30, 45 over 10-40 min
+		{"41.7", start.Add(40 * time.Minute)}, // 30, 45, 50 over 30-50 min
+	}
+	if len(smoothed) != len(expected) {
+		t.Fatalf("Expected %d smoothed readings, got %d: %+v", len(expected), len(smoothed), smoothed)
+	}
+	for i, e := range expected {
+		if smoothed[i].WaterLevel != e.level {
+			t.Errorf("Reading %d: expected level %s, got %s", i, e.level, smoothed[i].WaterLevel)
+		}
+		if !smoothed[i].Timestamp.Equal(e.timestamp) {
+			t.Errorf("Reading %d: expected timestamp %v, got %v", i, e.timestamp, smoothed[i].Timestamp)
+		}
+		if smoothed[i].River != "ГРАДАЦ" || smoothed[i].Station != "ДЕГУРИЋ" {
+			t.Errorf("Reading %d: expected river and station to be kept, got %+v", i, smoothed[i])
+		}
+	}
+
+	if got := SmoothSeries(data, 1); len(got) != 5 {
+		t.Errorf("Expected a window of 1 to keep all 5 numeric readings, got %d", len(got))
+	}
+	if got := SmoothSeries(data, 10); len(got) != 0 {
+		t.Errorf("Expected no readings when the window exceeds the series, got %d", len(got))
+	}
+}
@@ -0,0 +1,55 @@
+package usecases
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// markUnchanged flags the fetched sources whose newest reading is no newer than the
+// newest one already stored. A source republishing its last page, such as RHMZ RS
+// before the day's bulletin is posted, looks fetched but brings nothing new.
+func (uc *RiverUseCase) markUnchanged(sources []SourceRefresh, data []entities.RiverData) {
+	stored, err := uc.repo.GetLastUpdateTimeBySource()
+	if err != nil {
+		slog.Error("Failed to read the stored update times, not checking sources for unchanged data", "error", err)
+		return
+	}
+
+	newest := make(map[string]time.Time)
+	for _, rd := range data {
+		if rd.Timestamp.After(newest[rd.Source]) {
+			newest[rd.Source] = rd.Timestamp
+		}
+	}
+
+	for i := range sources {
+		source := &sources[i]
+		previous, ok := stored[source.Source]
+		if source.Err != nil || !ok || newest[source.Source].After(previous) {
+			continue
+		}
+		source.Unchanged = true
+		slog.Warn("Source data has not advanced since the previous refresh", "source", source.Source, "latest", previous)
+	}
+}
+
+// recordSourceChecks stores when each source fetched and saved successfully was checked and
+// whether it had new data, for /status in any process sharing the repository
+func (uc *RiverUseCase) recordSourceChecks(sources []SourceRefresh, checkedAt time.Time) {
+	for _, source := range sources {
+		if source.Err != nil || source.SaveErr != nil {
+			continue
+		}
+		check := entities.SourceCheck{Source: source.Source, CheckedAt: checkedAt, Unchanged: source.Unchanged}
+		if err := uc.repo.SaveSourceCheck(check); err != nil {
+			slog.Error("Failed to record source check", "source", source.Source, "error", err)
+		}
+	}
+}
+
+// GetSourceChecks returns the last successful fetch of each source, keyed by source
+func (uc *RiverUseCase) GetSourceChecks() (map[string]entities.SourceCheck, error) {
+	return uc.repo.GetSourceChecks()
+}
@@ -0,0 +1,85 @@
+package usecases
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// recordSourceStatuses stores whether each source could be fetched, so the process
+// running the bot can announce outages and recoveries of refreshes run elsewhere
+func (uc *RiverUseCase) recordSourceStatuses(sources []SourceRefresh, at time.Time) {
+	for _, source := range sources {
+		errText := ""
+		if source.Err != nil {
+			errText = source.Err.Error()
+		}
+		if err := uc.repo.UpdateSourceStatus(source.Source, source.Err != nil, errText, at); err != nil {
+			slog.Error("Failed to record source status", "source", source.Source, "error", err)
+		}
+	}
+}
+
+// WatchSources subscribes a chat to notifications of source outages and recoveries.
+// Returns false if the chat was already subscribed.
+func (uc *RiverUseCase) WatchSources(chatID int64) (bool, error) {
+	slog.Debug("Subscribing chat to source outages", "chat_id", chatID)
+	return uc.repo.AddSourceWatcher(chatID)
+}
+
+// UnwatchSources unsubscribes a chat from source outage notifications.
+// Returns false if the chat was not subscribed.
+func (uc *RiverUseCase) UnwatchSources(chatID int64) (bool, error) {
+	slog.Debug("Unsubscribing chat from source outages", "chat_id", chatID)
+	return uc.repo.RemoveSourceWatcher(chatID)
+}
+
+// CheckSourceOutages returns a notice for each watching chat about every source that
+// went down or came back up since the last check, and records the new states as
+// announced so each transition is sent once. Transitions are recorded as announced
+// even when nobody watches, so a new watcher isn't sent old news.
+func (uc *RiverUseCase) CheckSourceOutages() ([]Notification, error) {
+	statuses, err := uc.repo.GetSourceStatuses()
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []entities.SourceStatus
+	for _, status := range statuses {
+		if status.Down == status.AnnouncedDown {
+			continue
+		}
+		if err := uc.repo.SetSourceAnnounced(status.Source, status.Down); err != nil {
+			return nil, err
+		}
+		slog.Info("Source status changed", "source", status.Source, "down", status.Down, "error", status.Error)
+		changed = append(changed, status)
+	}
+	if len(changed) == 0 {
+		return nil, nil
+	}
+
+	watchers, err := uc.repo.GetSourceWatchers()
+	if err != nil {
+		return nil, err
+	}
+	var notifications []Notification
+	for _, chatID := range watchers {
+		loc := uc.UserLocation(chatID)
+		for _, status := range changed {
+			notifications = append(notifications, Notification{ChatID: chatID, Text: formatSourceStatusChange(status, loc)})
+		}
+	}
+	return notifications, nil
+}
+
+// formatSourceStatusChange formats the notice of a source going down or coming back up
+func formatSourceStatusChange(status entities.SourceStatus, loc *time.Location) string {
+	changedAt := status.ChangedAt.In(loc).Format("2006-01-02 15:04 MST")
+	if status.Down {
+		return fmt.Sprintf("🔴 Source %s is down since %s: %s", status.Source, changedAt, status.Error)
+	}
+	return fmt.Sprintf("🟢 Source %s is back up since %s.", status.Source, changedAt)
+}
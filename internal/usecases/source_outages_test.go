@@ -0,0 +1,74 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+	"github.com/abelzeko/water-bot/internal/integration"
+)
+
+// TestCheckSourceOutagesAnnouncesTransitions verifies watching chats are told once when a
+// source goes down and once when it comes back up, and nothing while its state holds
+func TestCheckSourceOutagesAnnouncesTransitions(t *testing.T) {
+	repo := newTestRepository(t)
+
+	var failure error
+	flaky := NewDataSource(integration.SourceDhmz, func(ctx context.Context) ([]entities.RiverData, error) {
+		if failure != nil {
+			return nil, failure
+		}
+		return []entities.RiverData{{River: "САВА", Station: "Загреб", WaterLevel: "100", Source: integration.SourceDhmz, Timestamp: time.Now()}}, nil
+	})
+	uc := NewRiverUseCaseWithSources(repo, []DataSource{flaky, fakeSource("stable", "ДРИНА", 0, nil)}, nil)
+
+	for _, chatID := range []int64{42, 43} {
+		if _, err := uc.WatchSources(chatID); err != nil {
+			t.Fatalf("Failed to watch sources: %v", err)
+		}
+	}
+
+	steps := []struct {
+		name    string
+		failure error
+		want    string // Expected start of the notice to each chat, empty for none
+	}{
+		{"up from the start", nil, ""},
+		{"goes down", fmt.Errorf("DHMZ: %w", integration.ErrNoData), "🔴 Source dhmz is down since"},
+		{"stays down", fmt.Errorf("DHMZ: %w", integration.ErrNoData), ""},
+		{"recovers", nil, "🟢 Source dhmz is back up since"},
+		{"stays up", nil, ""},
+		{"fails again", context.DeadlineExceeded, "🔴 Source dhmz is down since"},
+	}
+	for _, step := range steps {
+		failure = step.failure
+		if _, err := uc.RefreshRiverData(context.Background()); err != nil {
+			t.Fatalf("%s: refresh failed: %v", step.name, err)
+		}
+
+		notifications, err := uc.CheckSourceOutages()
+		if err != nil {
+			t.Fatalf("%s: failed to check source outages: %v", step.name, err)
+		}
+		if step.want == "" {
+			if len(notifications) != 0 {
+				t.Errorf("%s: expected no notifications, got %+v", step.name, notifications)
+			}
+			continue
+		}
+		if len(notifications) != 2 {
+			t.Fatalf("%s: expected a notice to each of the 2 watchers, got %+v", step.name, notifications)
+		}
+		for _, n := range notifications {
+			if !strings.HasPrefix(n.Text, step.want) {
+				t.Errorf("%s: expected the notice to chat %d to start with %q, got %q", step.name, n.ChatID, step.want, n.Text)
+			}
+			if step.failure != nil && !strings.Contains(n.Text, step.failure.Error()) {
+				t.Errorf("%s: expected the error in the notice, got %q", step.name, n.Text)
+			}
+		}
+	}
+}
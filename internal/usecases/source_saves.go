@@ -0,0 +1,49 @@
+package usecases
+
+import (
+	"log/slog"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// saveBySource saves the readings of each source in a transaction of its own, so a row
+// one source got wrong can't roll back the readings of the others. The outcome of each
+// save is recorded in sources. Returns how many readings were saved and the first error.
+func (uc *RiverUseCase) saveBySource(sources []SourceRefresh, data []entities.RiverData) (saved int, err error) {
+	groups := make(map[string][]entities.RiverData)
+	var order []string
+	for _, rd := range data {
+		if _, ok := groups[rd.Source]; !ok {
+			order = append(order, rd.Source)
+		}
+		groups[rd.Source] = append(groups[rd.Source], rd)
+	}
+
+	index := make(map[string]int, len(sources))
+	for i, source := range sources {
+		index[source.Source] = i
+	}
+
+	for _, name := range order {
+		readings := groups[name]
+		saveErr := uc.repo.SaveRiverData(readings)
+		if saveErr != nil {
+			slog.Error("Failed to save source data, keeping the other sources", "source", name, "rows", len(readings), "error", saveErr)
+			if err == nil {
+				err = saveErr
+			}
+		} else {
+			saved += len(readings)
+		}
+
+		// Readings labelled with a source that wasn't fetched are saved, just not reported
+		if i, ok := index[name]; ok {
+			if saveErr != nil {
+				sources[i].SaveErr = saveErr
+			} else {
+				sources[i].Saved = len(readings)
+			}
+		}
+	}
+	return saved, err
+}
@@ -0,0 +1,97 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+	"github.com/abelzeko/water-bot/internal/repository"
+)
+
+// failingSaveRepository refuses to save the readings of the sources in failing
+type failingSaveRepository struct {
+	*repository.SQLiteRiverRepository
+	failing map[string]bool
+}
+
+// SaveRiverData fails when data holds a reading of a failing source
+func (r failingSaveRepository) SaveRiverData(data []entities.RiverData) error {
+	for _, rd := range data {
+		if r.failing[rd.Source] {
+			return errors.New("UNIQUE constraint failed")
+		}
+	}
+	return r.SQLiteRiverRepository.SaveRiverData(data)
+}
+
+// TestRefreshRiverDataSavesSourcesSeparately verifies a source whose readings can't be
+// saved is reported while the readings of the other sources are kept
+func TestRefreshRiverDataSavesSourcesSeparately(t *testing.T) {
+	repo := failingSaveRepository{SQLiteRiverRepository: newTestRepository(t), failing: map[string]bool{"hidmet": true}}
+	uc := NewRiverUseCaseWithSources(repo, []DataSource{
+		fakeSource("hidmet", "ДУНАВ", 0, nil),
+		fakeSource("gradac", "ГРАДАЦ", 0, nil),
+	}, nil)
+
+	summary, err := uc.RefreshRiverData(context.Background())
+	if err != nil {
+		t.Fatalf("Expected the refresh to succeed with one source saved, got %v", err)
+	}
+	if summary.Saved != 1 {
+		t.Errorf("Expected 1 saved reading, got %d", summary.Saved)
+	}
+	for _, source := range summary.Sources {
+		switch source.Source {
+		case "hidmet":
+			if source.SaveErr == nil || source.Saved != 0 {
+				t.Errorf("Expected hidmet's save to fail, got %+v", source)
+			}
+		case "gradac":
+			if source.SaveErr != nil || source.Saved != 1 {
+				t.Errorf("Expected gradac's reading to be saved, got %+v", source)
+			}
+		}
+	}
+
+	if data, err := repo.GetRiverDataByName("ГРАДАЦ"); err != nil || len(data) != 1 {
+		t.Errorf("Expected ГРАДАЦ to be stored, got %v (%v)", data, err)
+	}
+	if data, err := repo.GetRiverDataByName("ДУНАВ"); err != nil || len(data) != 0 {
+		t.Errorf("Expected ДУНАВ not to be stored, got %v (%v)", data, err)
+	}
+
+	checks, err := repo.GetSourceChecks()
+	if err != nil {
+		t.Fatalf("Failed to get source checks: %v", err)
+	}
+	if _, ok := checks["hidmet"]; ok {
+		t.Error("Expected no check recorded for the source that wasn't saved")
+	}
+	if _, ok := checks["gradac"]; !ok {
+		t.Error("Expected a check recorded for the saved source")
+	}
+
+	text := uc.FormatRefreshResult(summary, nil)
+	if !strings.Contains(text, "• hidmet: 1 rows (") || !strings.Contains(text, "not saved: UNIQUE constraint failed") {
+		t.Errorf("Expected hidmet's save failure in the summary, got:\n%s", text)
+	}
+}
+
+// TestRefreshRiverDataFailsWhenNothingSaved verifies the refresh fails when no source could be saved
+func TestRefreshRiverDataFailsWhenNothingSaved(t *testing.T) {
+	repo := failingSaveRepository{SQLiteRiverRepository: newTestRepository(t), failing: map[string]bool{"hidmet": true, "gradac": true}}
+	uc := NewRiverUseCaseWithSources(repo, []DataSource{
+		fakeSource("hidmet", "ДУНАВ", 0, nil),
+		fakeSource("gradac", "ГРАДАЦ", 0, nil),
+	}, nil)
+
+	summary, err := uc.RefreshRiverData(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "failed to save data") {
+		t.Fatalf("Expected a save error, got %v", err)
+	}
+	if summary.Saved != 0 {
+		t.Errorf("Expected nothing saved, got %d", summary.Saved)
+	}
+}
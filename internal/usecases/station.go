@@ -0,0 +1,68 @@
+package usecases
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// FindStation returns the latest level of every station with the given name, whatever
+// river it is on. Names match case-insensitively and in Serbian Latin script. Returns
+// ErrUnknownStation when no river has such a station.
+func (uc *RiverUseCase) FindStation(station string) ([]StationLevel, error) {
+	slog.Debug("Looking up station on all rivers", "station", station)
+
+	names := []string{station}
+	if cyrillic := latinToCyrillic(station); cyrillic != station {
+		names = append(names, cyrillic)
+	}
+
+	var readings []entities.RiverData
+	seen := make(map[string]bool)
+	for _, name := range names {
+		matches, err := uc.repo.GetByStation(name)
+		if err != nil {
+			return nil, err
+		}
+		for _, rd := range matches {
+			key := rd.River + "\x00" + rd.Station
+			if !seen[key] {
+				seen[key] = true
+				readings = append(readings, rd)
+			}
+		}
+	}
+	if len(readings) == 0 {
+		return nil, ErrUnknownStation
+	}
+
+	levels := make([]StationLevel, 0, len(readings))
+	for _, rd := range readings {
+		level, err := uc.stationLevel(rd)
+		if err != nil {
+			return nil, err
+		}
+		levels = append(levels, level)
+	}
+	return levels, nil
+}
+
+// FormatStationMatches formats the levels found for a station name, listing every
+// river it is on when several share the name
+func (uc *RiverUseCase) FormatStationMatches(levels []StationLevel, loc *time.Location) string {
+	if len(levels) == 1 {
+		return uc.FormatStationLevel(levels[0], loc)
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Station %s is on %d rivers:\n\n", levels[0].Reading.Station, len(levels)))
+	for _, level := range levels {
+		result.WriteString(uc.FormatStationLevel(level, loc))
+		result.WriteString("\n")
+	}
+	result.WriteString(fmt.Sprintf("Use /level [river] %s to show only one of them.", levels[0].Reading.Station))
+	return result.String()
+}
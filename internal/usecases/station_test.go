@@ -0,0 +1,80 @@
+package usecases
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// TestFindStation verifies a station is found on any river by its Cyrillic or Latin name
+// and that a name shared by several rivers lists all of them
+func TestFindStation(t *testing.T) {
+	repo := newTestRepository(t)
+	uc := NewRiverUseCase(repo, nil, nil)
+
+	latest := time.Now().Truncate(time.Second)
+	err := repo.SaveRiverData([]entities.RiverData{
+		{River: "САВА", Station: "Сремска Митровица", WaterLevel: "250", Timestamp: latest.Add(-2 * time.Hour)},
+		{River: "САВА", Station: "Сремска Митровица", WaterLevel: "255", Timestamp: latest},
+		{River: "САВА", Station: "Шабац", WaterLevel: "312", Timestamp: latest},
+		{River: "ДРИНА", Station: "Шабац", WaterLevel: "95", Timestamp: latest},
+	})
+	if err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		station    string
+		wantRivers []string
+		wantErr    error
+	}{
+		{"cyrillic name", "Сремска Митровица", []string{"САВА"}, nil},
+		{"latin name", "sremska mitrovica", []string{"САВА"}, nil},
+		{"shared name", "ШАБАЦ", []string{"ДРИНА", "САВА"}, nil},
+		{"unknown station", "Зворник", nil, ErrUnknownStation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			levels, err := uc.FindStation(tt.station)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to find station: %v", err)
+			}
+			var rivers []string
+			for _, level := range levels {
+				rivers = append(rivers, level.Reading.River)
+			}
+			if strings.Join(rivers, ",") != strings.Join(tt.wantRivers, ",") {
+				t.Errorf("Expected rivers %v, got %v", tt.wantRivers, rivers)
+			}
+		})
+	}
+
+	levels, err := uc.FindStation("Sremska Mitrovica")
+	if err != nil {
+		t.Fatalf("Failed to find station: %v", err)
+	}
+	if !levels[0].HasChange || levels[0].Change != 5 {
+		t.Errorf("Expected a +5 cm change, got %+v", levels[0])
+	}
+
+	levels, err = uc.FindStation("Шабац")
+	if err != nil {
+		t.Fatalf("Failed to find station: %v", err)
+	}
+	text := uc.FormatStationMatches(levels, time.UTC)
+	for _, want := range []string{"Station Шабац is on 2 rivers", "ДРИНА - Шабац", "САВА - Шабац", "/level [river] Шабац"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in:\n%s", want, text)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package usecases
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+	"github.com/abelzeko/water-bot/internal/units"
+)
+
+// sparseStatsReadings is the number of readings below which statistics are flagged as unreliable
+const sparseStatsReadings = 24
+
+// LevelStats is the distribution of a station's water level over a period, in cm
+type LevelStats struct {
+	River    string
+	Station  string
+	Readings int // Readings with a numeric level the statistics are computed from
+	Min      float64
+	Max      float64
+	Mean     float64
+	Median   float64
+	P90      float64 // 90th percentile
+	From, To time.Time
+}
+
+// StationStats computes the water level statistics of a station since the given time.
+// River names may be aliases and station names match as in GetStationLevel.
+// Returns ErrInsufficientHistory when the station has no numeric readings in the period.
+func (uc *RiverUseCase) StationStats(river, station string, since time.Time) (LevelStats, error) {
+	slog.Debug("Computing statistics", "river", river, "station", station, "since", since.Format(time.RFC3339))
+
+	river, station, err := uc.resolveRiverStation(river, station)
+	if err != nil {
+		return LevelStats{}, err
+	}
+	history, err := uc.repo.GetStationHistory(river, station, since)
+	if err != nil {
+		return LevelStats{}, err
+	}
+	return computeStats(river, station, history)
+}
+
+// computeStats computes the statistics of the numeric levels among readings ordered oldest first
+func computeStats(river, station string, history []entities.RiverData) (LevelStats, error) {
+	var levels []float64
+	var from, to time.Time
+	for _, rd := range history {
+		level, ok := rd.LevelValue()
+		if !ok {
+			continue
+		}
+		if len(levels) == 0 {
+			from = rd.Timestamp
+		}
+		to = rd.Timestamp
+		levels = append(levels, level)
+	}
+	if len(levels) == 0 {
+		return LevelStats{}, ErrInsufficientHistory
+	}
+
+	sort.Float64s(levels)
+	sum := 0.0
+	for _, level := range levels {
+		sum += level
+	}
+
+	return LevelStats{
+		River:    river,
+		Station:  station,
+		Readings: len(levels),
+		Min:      levels[0],
+		Max:      levels[len(levels)-1],
+		Mean:     units.Round(sum/float64(len(levels)), 1),
+		Median:   units.Round(percentile(levels, 0.5), 1),
+		P90:      units.Round(percentile(levels, 0.9), 1),
+		From:     from,
+		To:       to,
+	}, nil
+}
+
+// percentile returns the p-th quantile (0-1) of sorted values, interpolating linearly between ranks
+func percentile(sorted []float64, p float64) float64 {
+	rank := p * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
+
+// FormatStationStats formats water level statistics over the given number of days,
+// with timestamps shown in loc
+func FormatStationStats(stats LevelStats, days int, loc *time.Location) string {
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("📊 Water level at %s - %s over the last %d days:\n\n", stats.River, stats.Station, days))
+	result.WriteString(fmt.Sprintf("• Min: %v cm\n", stats.Min))
+	result.WriteString(fmt.Sprintf("• Max: %v cm\n", stats.Max))
+	result.WriteString(fmt.Sprintf("• Mean: %v cm\n", stats.Mean))
+	result.WriteString(fmt.Sprintf("• Median: %v cm\n", stats.Median))
+	result.WriteString(fmt.Sprintf("• 90th percentile: %v cm\n", stats.P90))
+	result.WriteString(fmt.Sprintf("\nBased on %d readings from %s to %s.", stats.Readings,
+		stats.From.In(loc).Format("2006-01-02 15:04"), stats.To.In(loc).Format("2006-01-02 15:04")))
+	if stats.Readings < sparseStatsReadings {
+		result.WriteString("\n⚠️ Few readings are stored for this period, the figures may not be representative.")
+	}
+	return result.String()
+}
@@ -0,0 +1,118 @@
+package usecases
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// levelHistory builds hourly readings with the given water levels, oldest first
+func levelHistory(start time.Time, levels ...string) []entities.RiverData {
+	history := make([]entities.RiverData, len(levels))
+	for i, level := range levels {
+		history[i] = entities.RiverData{
+			River:      "ДРИНА",
+			Station:    "Радаљ",
+			WaterLevel: level,
+			Timestamp:  start.Add(time.Duration(i) * time.Hour),
+		}
+	}
+	return history
+}
+
+// TestComputeStats verifies the statistics of known synthetic datasets
+func TestComputeStats(t *testing.T) {
+	start := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		levels  []string
+		want    LevelStats
+		wantErr error
+	}{
+		{
+			name:   "ten readings",
+			levels: []string{"40", "10", "100", "70", "20", "90", "30", "80", "50", "60"},
+			want:   LevelStats{Readings: 10, Min: 10, Max: 100, Mean: 55, Median: 55, P90: 91},
+		},
+		{
+			name:   "odd count",
+			levels: []string{"-5", "15", "7"},
+			want:   LevelStats{Readings: 3, Min: -5, Max: 15, Mean: 5.7, Median: 7, P90: 13.4},
+		},
+		{
+			name:   "single reading",
+			levels: []string{"142"},
+			want:   LevelStats{Readings: 1, Min: 142, Max: 142, Mean: 142, Median: 142, P90: 142},
+		},
+		{
+			name:   "non-numeric readings skipped",
+			levels: []string{"-", "120", "", "n/a", "130"},
+			want:   LevelStats{Readings: 2, Min: 120, Max: 130, Mean: 125, Median: 125, P90: 129},
+		},
+		{
+			name:    "no numeric readings",
+			levels:  []string{"-", ""},
+			wantErr: ErrInsufficientHistory,
+		},
+		{
+			name:    "no readings",
+			wantErr: ErrInsufficientHistory,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := computeStats("ДРИНА", "Радаљ", levelHistory(start, tt.levels...))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to compute stats: %v", err)
+			}
+			got.River, got.Station, got.From, got.To = "", "", time.Time{}, time.Time{}
+			if got != tt.want {
+				t.Errorf("Expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestStationStats verifies statistics only cover the requested period and resolve Latin names
+func TestStationStats(t *testing.T) {
+	repo := newTestRepository(t)
+	uc := NewRiverUseCase(repo, nil, nil)
+
+	start := time.Now().Add(-48 * time.Hour).Truncate(time.Hour)
+	var levels []string
+	for i := 0; i < 48; i++ {
+		levels = append(levels, strconv.Itoa(100+i))
+	}
+	if err := repo.SaveRiverData(levelHistory(start, levels...)); err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+
+	stats, err := uc.StationStats("Drina", "Radalj", start.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to compute stats: %v", err)
+	}
+	if stats.Readings != 24 || stats.Min != 124 || stats.Max != 147 {
+		t.Errorf("Expected 24 readings from 124 to 147 cm, got %d from %v to %v", stats.Readings, stats.Min, stats.Max)
+	}
+	if stats.River != "ДРИНА" || stats.Station != "Радаљ" {
+		t.Errorf("Expected the stored names, got %s/%s", stats.River, stats.Station)
+	}
+
+	text := FormatStationStats(stats, 1, time.UTC)
+	if !strings.Contains(text, "• Median: 135.5 cm") {
+		t.Errorf("Expected the median in %q", text)
+	}
+	if strings.Contains(text, "may not be representative") {
+		t.Errorf("Expected no sparse data warning for 24 readings in %q", text)
+	}
+}
@@ -0,0 +1,96 @@
+package usecases
+
+import (
+	"log/slog"
+	"sort"
+	"strings"
+)
+
+// DefaultSuggestionDistance is the largest edit distance at which a river is suggested for a misspelled name
+const DefaultSuggestionDistance = 2
+
+// SetSuggestionDistance changes how many edits a misspelled name may be from a river to
+// still be suggested. 0 disables suggestions.
+// It must be called before the use case is shared between goroutines.
+func (uc *RiverUseCase) SetSuggestionDistance(d int) {
+	uc.suggestionDistance = d
+}
+
+// SuggestRivers returns up to n known rivers whose names are closest to input, closest first.
+// Rivers further than the suggestion distance are left out, so unrelated input gets no suggestions.
+func (uc *RiverUseCase) SuggestRivers(input string, n int) []string {
+	rivers, err := uc.repo.GetUniqueRivers()
+	if err != nil {
+		slog.Error("Error fetching rivers for suggestions", "error", err)
+		return nil
+	}
+	return closestNames(input, rivers, n, uc.suggestionDistance)
+}
+
+// closestNames returns up to n candidates within maxDistance edits of input, ordered by
+// distance and then by name. Names are compared case-insensitively.
+func closestNames(input string, candidates []string, n, maxDistance int) []string {
+	input = strings.ToUpper(strings.TrimSpace(input))
+	if input == "" || n <= 0 {
+		return nil
+	}
+
+	type match struct {
+		name     string
+		distance int
+	}
+	var matches []match
+	for _, name := range candidates {
+		upper := strings.ToUpper(name)
+		distance := levenshtein(input, upper)
+		// A short name is within a couple of edits of almost anything, so at least
+		// half of it must survive for the suggestion to make sense
+		if distance > maxDistance || distance*2 >= len([]rune(upper)) {
+			continue
+		}
+		matches = append(matches, match{name: name, distance: distance})
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].distance != matches[j].distance {
+			return matches[i].distance < matches[j].distance
+		}
+		return matches[i].name < matches[j].name
+	})
+
+	if len(matches) == 0 {
+		return nil
+	}
+	if len(matches) > n {
+		matches = matches[:n]
+	}
+	result := make([]string, len(matches))
+	for i, m := range matches {
+		result[i] = m.name
+	}
+	return result
+}
+
+// levenshtein returns the number of single-character insertions, deletions and
+// substitutions needed to turn a into b
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,55 @@
+package usecases
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// TestSuggestRivers verifies misspelled names suggest the closest rivers and unrelated ones suggest nothing
+func TestSuggestRivers(t *testing.T) {
+	repo := newTestRepository(t)
+	ts := time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC)
+	var data []entities.RiverData
+	for _, river := range []string{"ДУНАВ", "ДРИНА", "САВА", "ТАРА", "ЛИМ"} {
+		data = append(data, entities.RiverData{River: river, Station: "Станица", WaterLevel: "100", Timestamp: ts})
+	}
+	if err := repo.SaveRiverData(data); err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+	uc := NewRiverUseCase(repo, nil, nil)
+
+	tests := []struct {
+		name  string
+		input string
+		n     int
+		want  []string
+	}{
+		{"clear typo", "Дунв", 3, []string{"ДУНАВ"}},
+		{"ambiguous", "САРА", 3, []string{"САВА", "ТАРА"}},
+		{"ambiguous limited", "САРА", 1, []string{"САВА"}},
+		{"unrelated", "Morava River", 3, nil},
+		{"short name not matched by anything", "ЛУГ", 3, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := uc.SuggestRivers(tt.input, tt.n)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestSetSuggestionDistance verifies the distance threshold is tunable
+func TestSetSuggestionDistance(t *testing.T) {
+	candidates := []string{"ДУНАВ"}
+	if got := closestNames("ДНВ", candidates, 3, DefaultSuggestionDistance); !reflect.DeepEqual(got, []string{"ДУНАВ"}) {
+		t.Errorf("Expected a suggestion within the default distance, got %v", got)
+	}
+	if got := closestNames("ДНВ", candidates, 3, 1); got != nil {
+		t.Errorf("Expected no suggestion with a stricter distance, got %v", got)
+	}
+}
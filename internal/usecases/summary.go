@@ -0,0 +1,98 @@
+package usecases
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+	"github.com/abelzeko/water-bot/internal/units"
+)
+
+// DefaultSummaryThreshold is the number of stations above which river info is summarized
+const DefaultSummaryThreshold = 5
+
+// SetSummaryThreshold changes how many stations a river may have before its info is
+// summarized rather than listed station by station. 0 disables summaries.
+// It must be called before the use case is shared between goroutines.
+func (uc *RiverUseCase) SetSummaryThreshold(n int) {
+	uc.summaryThreshold = n
+}
+
+// ShouldSummarize reports whether a river's readings are numerous enough to be shown
+// as a summary, with the per-station detail available on request
+func (uc *RiverUseCase) ShouldSummarize(riverData []entities.RiverData) bool {
+	return uc.summaryThreshold > 0 && len(riverData) > uc.summaryThreshold
+}
+
+// FormatRiverSummary formats a river's readings as one summary: the average level,
+// the temperature range and the tendency most stations share
+func (uc *RiverUseCase) FormatRiverSummary(riverData []entities.RiverData) string {
+	if len(riverData) == 0 {
+		return "No information available for this river."
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Summary for river %s (%d stations):\n\n", riverData[0].River, len(riverData)))
+	for _, data := range riverData {
+		if data.Stale {
+			result.WriteString(fmt.Sprintf("⚠️ Some readings are older than %s, the data may be outdated.\n\n", formatWindow(uc.staleAfter)))
+			break
+		}
+	}
+
+	var levelSum float64
+	var levels, temps int
+	var minTemp, maxTemp float64
+	tendencies := make(map[entities.Tendency]int)
+	latest := riverData[0].Timestamp
+	for _, data := range riverData {
+		if level, ok := data.LevelValue(); ok {
+			levelSum += level
+			levels++
+		}
+		if temp, ok := data.TempValue(); ok {
+			if temps == 0 || temp < minTemp {
+				minTemp = temp
+			}
+			if temps == 0 || temp > maxTemp {
+				maxTemp = temp
+			}
+			temps++
+		}
+		if data.Tendency.IsKnown() {
+			tendencies[data.Tendency]++
+		}
+		if data.Timestamp.After(latest) {
+			latest = data.Timestamp
+		}
+	}
+
+	if levels > 0 {
+		result.WriteString(fmt.Sprintf("💧 Average Water Level: %v cm (%d of %d stations)\n", units.Round(levelSum/float64(levels), 1), levels, len(riverData)))
+	}
+	if temps > 0 {
+		if minTemp == maxTemp {
+			result.WriteString(fmt.Sprintf("🌡️ Water Temperature: %v °C\n", minTemp))
+		} else {
+			result.WriteString(fmt.Sprintf("🌡️ Water Temperature: %v–%v °C\n", minTemp, maxTemp))
+		}
+	}
+	if tendency, count := dominantTendency(tendencies); count > 0 {
+		result.WriteString(fmt.Sprintf("%s Mostly %s (%d of %d stations)\n", tendency.Symbol(), tendency.Label(), count, len(riverData)))
+	}
+	result.WriteString(fmt.Sprintf("🕒 Last update: %s\n", latest.Format("2006-01-02 15:04:05 MST")))
+
+	return result.String()
+}
+
+// dominantTendency returns the most common known tendency and how many stations share
+// it. Ties go to rising, then falling, so a summary errs on the side of caution.
+func dominantTendency(counts map[entities.Tendency]int) (entities.Tendency, int) {
+	best, bestCount := entities.Unknown, 0
+	for _, tendency := range []entities.Tendency{entities.Rising, entities.Falling, entities.Stable} {
+		if counts[tendency] > bestCount {
+			best, bestCount = tendency, counts[tendency]
+		}
+	}
+	return best, bestCount
+}
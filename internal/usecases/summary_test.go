@@ -0,0 +1,62 @@
+package usecases
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// manyStations builds one reading for each of n stations on the same river
+func manyStations(n int) []entities.RiverData {
+	tendencies := []entities.Tendency{entities.Rising, entities.Rising, entities.Falling, entities.Unknown}
+	data := make([]entities.RiverData, n)
+	for i := range data {
+		data[i] = entities.RiverData{
+			River:      "ДУНАВ",
+			Station:    fmt.Sprintf("Станица %d", i+1),
+			WaterLevel: fmt.Sprint(100 + 10*i),
+			WaterTemp:  fmt.Sprintf("%d,5", 10+i),
+			Tendency:   tendencies[i%len(tendencies)],
+			Timestamp:  time.Date(2025, 4, 18, 6, i, 0, 0, time.UTC),
+		}
+	}
+	return data
+}
+
+// TestFormatRiverSummary verifies a river with many stations is summarized with the
+// average level, the temperature range and the dominant tendency
+func TestFormatRiverSummary(t *testing.T) {
+	uc := NewRiverUseCaseWithSources(newTestRepository(t), nil, nil)
+	data := manyStations(8)
+	data[3].WaterLevel = "-" // Missing levels are left out of the average
+
+	if !uc.ShouldSummarize(data) {
+		t.Fatal("Expected 8 stations to be summarized with the default threshold")
+	}
+	text := uc.FormatRiverSummary(data)
+	for _, want := range []string{
+		"Summary for river ДУНАВ (8 stations):",
+		"💧 Average Water Level: 135.7 cm (7 of 8 stations)",
+		"🌡️ Water Temperature: 10.5–17.5 °C",
+		"⬆️ Mostly rising (4 of 8 stations)",
+		"🕒 Last update: 2025-04-18 06:07:00 UTC",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in the summary, got:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "Станица") {
+		t.Errorf("Expected no per-station detail in the summary, got:\n%s", text)
+	}
+
+	if uc.ShouldSummarize(data[:DefaultSummaryThreshold]) {
+		t.Error("Expected rivers at the threshold to be shown in full")
+	}
+	uc.SetSummaryThreshold(0)
+	if uc.ShouldSummarize(data) {
+		t.Error("Expected a zero threshold to disable summaries")
+	}
+}
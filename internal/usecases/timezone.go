@@ -0,0 +1,91 @@
+package usecases
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// ErrInvalidTimeZone is returned when a time zone name isn't a known IANA zone
+var ErrInvalidTimeZone = errors.New("unknown time zone, use an IANA name such as Europe/Belgrade")
+
+// SetUserTimeZone validates an IANA time zone name and stores it for a chat, moving the
+// chat's daily digest to the new zone. Returns the canonical zone name.
+func (uc *RiverUseCase) SetUserTimeZone(chatID int64, name string) (string, error) {
+	slog.Debug("Setting time zone", "chat_id", chatID, "time_zone", name)
+
+	loc, err := loadTimeZone(name)
+	if err != nil {
+		return "", err
+	}
+	if err := uc.repo.SetUserTimeZone(chatID, loc.String()); err != nil {
+		return "", err
+	}
+
+	// The digest keeps its send hour, now counted in the new zone
+	digest, found, err := uc.repo.GetDigest(chatID)
+	if err != nil {
+		return "", err
+	}
+	if found && digest.TimeZone != loc.String() {
+		digest.TimeZone = loc.String()
+		if err := uc.repo.SaveDigest(digest); err != nil {
+			return "", err
+		}
+	}
+	return loc.String(), nil
+}
+
+// UserTimeZone returns the time zone a chat wants timestamps shown in,
+// DefaultTimeZone unless it set another one with SetUserTimeZone
+func (uc *RiverUseCase) UserTimeZone(chatID int64) (string, error) {
+	user, found, err := uc.repo.GetUser(chatID)
+	if err != nil {
+		return "", err
+	}
+	if !found || user.TimeZone == "" {
+		return DefaultTimeZone, nil
+	}
+	return user.TimeZone, nil
+}
+
+// LocalizeForChat converts the timestamps of readings to the chat's time zone
+func (uc *RiverUseCase) LocalizeForChat(chatID int64, data []entities.RiverData) {
+	entities.InLocation(data, uc.UserLocation(chatID))
+}
+
+// UserLocation returns the time zone a chat wants timestamps shown in.
+// It falls back to DefaultTimeZone, the zone of the sources, if the chat's can't be loaded.
+func (uc *RiverUseCase) UserLocation(chatID int64) *time.Location {
+	name, err := uc.UserTimeZone(chatID)
+	if err != nil {
+		slog.Error("Error fetching time zone", "chat_id", chatID, "error", err)
+		name = DefaultTimeZone
+	}
+
+	loc, err := loadTimeZone(name)
+	if err != nil {
+		slog.Error("Error loading time zone", "time_zone", name, "error", err)
+		if loc, err = time.LoadLocation(DefaultTimeZone); err != nil {
+			return time.UTC
+		}
+	}
+	return loc
+}
+
+// loadTimeZone loads an IANA time zone. Empty names and "Local", which
+// time.LoadLocation accepts, are rejected as they don't name a zone.
+func loadTimeZone(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || strings.EqualFold(name, "Local") {
+		return nil, ErrInvalidTimeZone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, ErrInvalidTimeZone
+	}
+	return loc, nil
+}
@@ -0,0 +1,113 @@
+package usecases
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// TestLocalizeForChat verifies stored Belgrade timestamps are shown in the chat's configured time zone
+func TestLocalizeForChat(t *testing.T) {
+	repo := newTestRepository(t)
+	uc := NewRiverUseCase(repo, nil, nil)
+
+	belgrade, err := time.LoadLocation("Europe/Belgrade")
+	if err != nil {
+		t.Fatalf("Failed to load time zone: %v", err)
+	}
+	err = repo.SaveRiverData([]entities.RiverData{
+		{River: "ДРИНА", Station: "Радаљ", WaterLevel: "142", Timestamp: time.Date(2025, 4, 18, 8, 0, 0, 0, belgrade)},
+	})
+	if err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+
+	const chatID = int64(100)
+	zone, err := uc.SetUserTimeZone(chatID, "Europe/Moscow")
+	if err != nil {
+		t.Fatalf("Failed to set time zone: %v", err)
+	}
+	if zone != "Europe/Moscow" {
+		t.Errorf("Expected Europe/Moscow, got %s", zone)
+	}
+
+	tests := []struct {
+		name   string
+		chatID int64
+		want   string
+	}{
+		{"configured zone", chatID, "2025-04-18 09:00:00 MSK"},
+		{"default zone", chatID + 1, "2025-04-18 08:00:00 CEST"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			riverData, err := repo.GetRiverDataByName("ДРИНА")
+			if err != nil {
+				t.Fatalf("Failed to get data: %v", err)
+			}
+			uc.LocalizeForChat(tt.chatID, riverData)
+
+			info := uc.FormatRiverInfo(riverData)
+			if !strings.Contains(info, "Last update: "+tt.want) {
+				t.Errorf("Expected last update %s in:\n%s", tt.want, info)
+			}
+		})
+	}
+}
+
+// TestSetUserTimeZoneRejectsUnknownZones verifies only IANA zone names are accepted
+func TestSetUserTimeZoneRejectsUnknownZones(t *testing.T) {
+	uc := NewRiverUseCase(newTestRepository(t), nil, nil)
+
+	for _, name := range []string{"", "Local", "Mars/Olympus", "../etc/passwd"} {
+		if _, err := uc.SetUserTimeZone(1, name); !errors.Is(err, ErrInvalidTimeZone) {
+			t.Errorf("SetUserTimeZone(%q): expected ErrInvalidTimeZone, got %v", name, err)
+		}
+	}
+
+	zone, err := uc.UserTimeZone(1)
+	if err != nil {
+		t.Fatalf("Failed to get time zone: %v", err)
+	}
+	if zone != DefaultTimeZone {
+		t.Errorf("Expected the default time zone, got %s", zone)
+	}
+}
+
+// TestSetUserTimeZoneMovesDigest verifies a digest added before /tz is sent at its hour in the new zone
+func TestSetUserTimeZoneMovesDigest(t *testing.T) {
+	repo := newTestRepository(t)
+	uc := NewRiverUseCase(repo, nil, nil)
+	err := repo.SaveRiverData([]entities.RiverData{
+		{River: "ДРИНА", Station: "Радаљ", WaterLevel: "142", Timestamp: time.Date(2025, 7, 1, 6, 0, 0, 0, time.UTC)},
+	})
+	if err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+
+	if _, err := uc.AddDigestRiver(42, "ДРИНА", 8); err != nil {
+		t.Fatalf("Failed to add digest: %v", err)
+	}
+	if _, err := uc.SetUserTimeZone(42, "America/New_York"); err != nil {
+		t.Fatalf("Failed to set time zone: %v", err)
+	}
+
+	digest, found, err := uc.GetDigest(42)
+	if err != nil || !found {
+		t.Fatalf("Expected the digest, got found=%v (err %v)", found, err)
+	}
+	if digest.TimeZone != "America/New_York" || digest.SendHour != 8 {
+		t.Errorf("Expected the digest at 08:00 America/New_York, got %02d:00 %s", digest.SendHour, digest.TimeZone)
+	}
+
+	// 08:00 in Belgrade no longer sends it, 08:00 in New York does
+	if due, err := uc.DueDigests(time.Date(2025, 7, 1, 6, 0, 0, 0, time.UTC)); err != nil || len(due) != 0 {
+		t.Errorf("Expected no digest at 08:00 Belgrade time, got %d (err %v)", len(due), err)
+	}
+	if due, err := uc.DueDigests(time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)); err != nil || len(due) != 1 {
+		t.Errorf("Expected the digest at 08:00 New York time, got %d (err %v)", len(due), err)
+	}
+}
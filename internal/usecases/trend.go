@@ -0,0 +1,112 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// trendPeriod is how much history a station trend covers
+const trendPeriod = 24 * time.Hour
+
+// flatTrendThreshold is the net change in cm below which a station is considered flat
+const flatTrendThreshold = 2.0
+
+// ErrInsufficientHistory is returned when a station has too few readings to compute a trend
+var ErrInsufficientHistory = errors.New("insufficient history")
+
+// StationTrend is the change of a station's water level over trendPeriod
+type StationTrend struct {
+	River        string
+	Station      string
+	Change       float64 // Net change in cm from the first to the last reading
+	SlopePerHour float64 // Least-squares slope in cm per hour
+	Readings     int
+	From, To     time.Time
+}
+
+// Summary describes the trend as "up 12cm", "down 3cm" or "flat"
+func (t StationTrend) Summary() string {
+	switch {
+	case t.Change >= flatTrendThreshold:
+		return fmt.Sprintf("%s up %vcm (%+g cm/h)", entities.Rising.Symbol(), t.Change, t.SlopePerHour)
+	case t.Change <= -flatTrendThreshold:
+		return fmt.Sprintf("%s down %vcm (%+g cm/h)", entities.Falling.Symbol(), -t.Change, t.SlopePerHour)
+	default:
+		return fmt.Sprintf("%s flat", entities.Stable.Symbol())
+	}
+}
+
+// GetStationTrend computes a station's water level trend over the last 24 hours
+func (uc *RiverUseCase) GetStationTrend(river, station string) (StationTrend, error) {
+	history, err := uc.repo.GetStationHistory(river, station, time.Now().Add(-trendPeriod))
+	if err != nil {
+		return StationTrend{}, err
+	}
+	return computeTrend(river, station, history)
+}
+
+// computeTrend computes the net change and least-squares slope of a station's numeric
+// readings, ordered oldest first. At least two readings at different times are needed.
+func computeTrend(river, station string, history []entities.RiverData) (StationTrend, error) {
+	var times []time.Time
+	var levels []float64
+	for _, rd := range history {
+		level, ok := rd.LevelValue()
+		if !ok {
+			continue
+		}
+		times = append(times, rd.Timestamp)
+		levels = append(levels, level)
+	}
+	if len(levels) < 2 || !times[len(times)-1].After(times[0]) {
+		return StationTrend{}, ErrInsufficientHistory
+	}
+
+	// Fit level = a + slope * hours since the first reading
+	var sumX, sumY, sumXY, sumXX float64
+	for i, level := range levels {
+		x := times[i].Sub(times[0]).Hours()
+		sumX += x
+		sumY += level
+		sumXY += x * level
+		sumXX += x * x
+	}
+	n := float64(len(levels))
+	slope := (n*sumXY - sumX*sumY) / (n*sumXX - sumX*sumX)
+
+	return StationTrend{
+		River:        river,
+		Station:      station,
+		Change:       levels[len(levels)-1] - levels[0],
+		SlopePerHour: math.Round(slope*100) / 100,
+		Readings:     len(levels),
+		From:         times[0],
+		To:           times[len(times)-1],
+	}, nil
+}
+
+// FormatTrends formats the 24-hour trend of every station of a river.
+// Stations whose trend couldn't be computed are listed as lacking history.
+func (uc *RiverUseCase) FormatTrends(river string, riverData []entities.RiverData) string {
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("📊 24-hour trend for river %s:\n\n", river))
+	for _, rd := range riverData {
+		trend, err := uc.GetStationTrend(river, rd.Station)
+		switch {
+		case errors.Is(err, ErrInsufficientHistory):
+			result.WriteString(fmt.Sprintf("• %s: not enough history yet\n", rd.Station))
+		case err != nil:
+			slog.Error("Error computing trend", "river", river, "station", rd.Station, "error", err)
+			result.WriteString(fmt.Sprintf("• %s: trend unavailable\n", rd.Station))
+		default:
+			result.WriteString(fmt.Sprintf("• %s: %s\n", rd.Station, trend.Summary()))
+		}
+	}
+	return result.String()
+}
@@ -0,0 +1,75 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// TestGetStationTrend verifies the net change and slope of rising, falling and flat series
+func TestGetStationTrend(t *testing.T) {
+	repo := newTestRepository(t)
+	uc := NewRiverUseCaseWithSources(repo, nil, nil)
+
+	// Hourly readings over the last 12 hours, plus one older than the trend period
+	now := time.Now().Truncate(time.Hour)
+	var data []entities.RiverData
+	for i := 0; i <= 12; i++ {
+		ts := now.Add(time.Duration(i-12) * time.Hour)
+		data = append(data,
+			entities.RiverData{River: "ДРИНА", Station: "Радаљ", WaterLevel: fmt.Sprint(100 + 2*i), Timestamp: ts},
+			entities.RiverData{River: "ДРИНА", Station: "Фоча", WaterLevel: fmt.Sprint(250 - i/4), Timestamp: ts},
+			entities.RiverData{River: "ДРИНА", Station: "Вишеград", WaterLevel: fmt.Sprint(300 + i%2), Timestamp: ts},
+		)
+	}
+	data = append(data, entities.RiverData{River: "ДРИНА", Station: "Радаљ", WaterLevel: "500", Timestamp: now.Add(-30 * time.Hour)})
+	data = append(data, entities.RiverData{River: "ДРИНА", Station: "Бајина Башта", WaterLevel: "180", Timestamp: now})
+	if err := repo.SaveRiverData(data); err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+
+	tests := []struct {
+		station string
+		change  float64
+		slope   float64
+		summary string
+	}{
+		{"Радаљ", 24, 2, "up 24cm"},
+		{"Фоча", -3, -0.25, "down 3cm"},
+		{"Вишеград", 0, 0, "flat"},
+	}
+	for _, tt := range tests {
+		trend, err := uc.GetStationTrend("ДРИНА", tt.station)
+		if err != nil {
+			t.Fatalf("%s: failed to get trend: %v", tt.station, err)
+		}
+		if trend.Change != tt.change || trend.Readings != 13 {
+			t.Errorf("%s: expected change %v over 13 readings, got %v over %d", tt.station, tt.change, trend.Change, trend.Readings)
+		}
+		if diff := trend.SlopePerHour - tt.slope; diff > 0.02 || diff < -0.02 {
+			t.Errorf("%s: expected slope about %v cm/h, got %v", tt.station, tt.slope, trend.SlopePerHour)
+		}
+		if !strings.Contains(trend.Summary(), tt.summary) {
+			t.Errorf("%s: expected summary %q, got %q", tt.station, tt.summary, trend.Summary())
+		}
+	}
+
+	if _, err := uc.GetStationTrend("ДРИНА", "Бајина Башта"); !errors.Is(err, ErrInsufficientHistory) {
+		t.Errorf("Expected ErrInsufficientHistory for a single reading, got %v", err)
+	}
+
+	riverData, err := uc.GetRiverDataByName("ДРИНА")
+	if err != nil {
+		t.Fatalf("Failed to get river data: %v", err)
+	}
+	text := uc.FormatTrends("ДРИНА", riverData)
+	for _, want := range []string{"Радаљ: ⬆️ up 24cm (+2 cm/h)", "Фоча: ⬇️ down 3cm", "Вишеград: ➡️ flat", "Бајина Башта: not enough history yet"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in:\n%s", want, text)
+		}
+	}
+}
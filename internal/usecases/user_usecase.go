@@ -0,0 +1,43 @@
+package usecases
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// RegisterUser records that a chat started the bot. Registering again is safe,
+// it only refreshes the user's details and last_seen.
+func (uc *RiverUseCase) RegisterUser(user entities.User) error {
+	slog.Debug("Registering user", "user", user.Username, "chat_id", user.ChatID)
+	if user.LastSeen.IsZero() {
+		user.LastSeen = time.Now()
+	}
+	return uc.repo.UpsertUser(user)
+}
+
+// TouchUser records that a registered chat sent a message at seen
+func (uc *RiverUseCase) TouchUser(chatID int64, seen time.Time) error {
+	return uc.repo.TouchUser(chatID, seen)
+}
+
+// GetAllUsers returns every user that started the bot
+func (uc *RiverUseCase) GetAllUsers() ([]entities.User, error) {
+	return uc.repo.GetAllUsers()
+}
+
+// RemoveUser unregisters a chat that blocked the bot, deleting its alerts, digest and
+// follows with it so no notification keeps trying to reach the chat
+func (uc *RiverUseCase) RemoveUser(chatID int64) error {
+	slog.Info("Removing user", "chat_id", chatID)
+	_, err := uc.repo.ForgetUser(chatID)
+	return err
+}
+
+// ForgetUser deletes all data stored about a chat, for users who want the bot to
+// forget them. Returns how many records were removed.
+func (uc *RiverUseCase) ForgetUser(chatID int64) (int64, error) {
+	slog.Info("Forgetting all data of chat", "chat_id", chatID)
+	return uc.repo.ForgetUser(chatID)
+}
@@ -0,0 +1,93 @@
+package usecases
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+	"github.com/abelzeko/water-bot/internal/units"
+)
+
+// Bounds is the plausible range of a numeric reading field, inclusive
+type Bounds struct {
+	Min, Max float64
+}
+
+// Contains reports whether value is within the bounds
+func (b Bounds) Contains(value float64) bool {
+	return value >= b.Min && value <= b.Max
+}
+
+// ReadingBounds holds the plausible range of each validated reading field
+type ReadingBounds struct {
+	WaterLevel Bounds // In cm, relative to the gauge zero so it may be negative
+	WaterTemp  Bounds // In °C
+}
+
+// DefaultReadingBounds are wide enough for any Balkan station, but reject placeholders
+// such as 9999 and temperatures no unfrozen river reaches
+var DefaultReadingBounds = ReadingBounds{
+	WaterLevel: Bounds{Min: -500, Max: 2000},
+	WaterTemp:  Bounds{Min: -1, Max: 40},
+}
+
+// SetReadingBounds changes the ranges outside which fetched readings are rejected.
+// It must be called before the use case is shared between goroutines.
+func (uc *RiverUseCase) SetReadingBounds(bounds ReadingBounds) {
+	uc.bounds = bounds
+}
+
+// dropIncomplete drops readings without a river or station name, which the repository
+// can't store, logging how many each source had
+func dropIncomplete(data []entities.RiverData) (valid []entities.RiverData, dropped int) {
+	valid = make([]entities.RiverData, 0, len(data))
+	bySource := make(map[string]int)
+	for _, rd := range data {
+		if strings.TrimSpace(rd.River) == "" || strings.TrimSpace(rd.Station) == "" {
+			bySource[rd.Source]++
+			dropped++
+			continue
+		}
+		valid = append(valid, rd)
+	}
+	for source, count := range bySource {
+		slog.Warn("Dropping readings without a river or station name", "source", source, "rows", count)
+	}
+	return valid, dropped
+}
+
+// rejectImplausible drops readings whose water level or temperature is outside bounds,
+// logging each one. Fields that are missing or not numeric are left to the formatters.
+func rejectImplausible(data []entities.RiverData, bounds ReadingBounds) (valid []entities.RiverData, rejected int) {
+	valid = make([]entities.RiverData, 0, len(data))
+	for _, rd := range data {
+		if field, value, ok := outOfBounds(rd, bounds); ok {
+			slog.Warn("Rejecting implausible reading", "field", field, "value", value, "river", rd.River, "station", rd.Station, "source", rd.Source)
+			rejected++
+			continue
+		}
+		valid = append(valid, rd)
+	}
+	return valid, rejected
+}
+
+// outOfBounds returns the first field of a reading outside bounds, reporting false if there is none
+func outOfBounds(rd entities.RiverData, bounds ReadingBounds) (field, value string, ok bool) {
+	for _, check := range []struct {
+		field  string
+		value  string
+		bounds Bounds
+	}{
+		{"water level", rd.WaterLevel, bounds.WaterLevel},
+		{"water temperature", rd.WaterTemp, bounds.WaterTemp},
+	} {
+		number, err := units.ParseNumber(check.value)
+		if err != nil {
+			continue
+		}
+		if !check.bounds.Contains(number) {
+			return check.field, check.value, true
+		}
+	}
+	return "", "", false
+}
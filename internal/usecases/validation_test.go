@@ -0,0 +1,83 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// TestRefreshRiverDataRejectsOutliers verifies implausible readings are not stored
+func TestRefreshRiverDataRejectsOutliers(t *testing.T) {
+	repo := newTestRepository(t)
+	ts := time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC)
+	source := NewDataSource("hidmet", func(ctx context.Context) ([]entities.RiverData, error) {
+		return []entities.RiverData{
+			{River: "ДРИНА", Station: "Радаљ", WaterLevel: "142", WaterTemp: "9.5", Timestamp: ts},
+			{River: "САВА", Station: "Шабац", WaterLevel: "9999", WaterTemp: "11.4", Timestamp: ts},
+			{River: "ДУНАВ", Station: "Земун", WaterLevel: "350", WaterTemp: "-12", Timestamp: ts},
+			{River: "ЛИМ", Station: "Пријепоље", WaterLevel: "-", WaterTemp: "", Timestamp: ts},
+		}, nil
+	})
+	uc := NewRiverUseCaseWithSources(repo, []DataSource{source}, nil)
+
+	summary, err := uc.RefreshRiverData(context.Background())
+	if err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if summary.Saved != 2 || summary.Rejected != 2 {
+		t.Errorf("Expected 2 saved and 2 rejected readings, got %d saved and %d rejected", summary.Saved, summary.Rejected)
+	}
+
+	for river, want := range map[string]int{"ДРИНА": 1, "САВА": 0, "ДУНАВ": 0, "ЛИМ": 1} {
+		data, err := repo.GetRiverDataByName(river)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", river, err)
+		}
+		if len(data) != want {
+			t.Errorf("%s: expected %d stored readings, got %d", river, want, len(data))
+		}
+	}
+}
+
+// TestRefreshRiverDataDropsIncompleteRows verifies rows without a river or station are
+// dropped while the valid rows fetched with them are stored
+func TestRefreshRiverDataDropsIncompleteRows(t *testing.T) {
+	repo := newTestRepository(t)
+	ts := time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC)
+	source := NewDataSource("hidmet", func(ctx context.Context) ([]entities.RiverData, error) {
+		return []entities.RiverData{
+			{River: "ДРИНА", Station: "Радаљ", WaterLevel: "142", Source: "hidmet", Timestamp: ts},
+			{River: "", Station: "Шабац", WaterLevel: "310", Source: "hidmet", Timestamp: ts},
+			{River: "САВА", Station: "  ", WaterLevel: "312", Source: "hidmet", Timestamp: ts},
+			{River: "ДУНАВ", Station: "Земун", WaterLevel: "350", Source: "hidmet", Timestamp: ts},
+		}, nil
+	})
+	uc := NewRiverUseCaseWithSources(repo, []DataSource{source}, nil)
+
+	summary, err := uc.RefreshRiverData(context.Background())
+	if err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if summary.Saved != 2 || summary.Dropped != 2 {
+		t.Errorf("Expected 2 saved and 2 dropped readings, got %d saved and %d dropped", summary.Saved, summary.Dropped)
+	}
+
+	count, err := repo.CountRows()
+	if err != nil {
+		t.Fatalf("Failed to count rows: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 stored readings, got %d", count)
+	}
+	for _, river := range []string{"ДРИНА", "ДУНАВ"} {
+		data, err := repo.GetRiverDataByName(river)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", river, err)
+		}
+		if len(data) != 1 {
+			t.Errorf("%s: expected 1 stored reading, got %d", river, len(data))
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package usecases
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+	"github.com/abelzeko/water-bot/internal/integration"
+	"github.com/abelzeko/water-bot/internal/units"
+)
+
+// GetStationLevels pairs each reading of a river with the change of its level since
+// the station's previous reading
+func (uc *RiverUseCase) GetStationLevels(riverData []entities.RiverData) ([]StationLevel, error) {
+	levels := make([]StationLevel, 0, len(riverData))
+	for _, rd := range riverData {
+		level, err := uc.stationLevel(rd)
+		if err != nil {
+			return nil, err
+		}
+		levels = append(levels, level)
+	}
+	return levels, nil
+}
+
+// FormatRiverInfoVerbose formats every stored field of a river's readings, including
+// the level change, source and source page that FormatRiverInfo leaves out. Fields
+// without a value are skipped.
+func (uc *RiverUseCase) FormatRiverInfoVerbose(levels []StationLevel) string {
+	if len(levels) == 0 {
+		return "No information available for this river."
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("All readings for river %s:\n\n", levels[0].Reading.River))
+	for _, level := range levels {
+		data := level.Reading
+		result.WriteString(fmt.Sprintf("📍 Station: %s\n", data.Station))
+		if strings.TrimSpace(data.WaterLevel) != "" {
+			result.WriteString(fmt.Sprintf("💧 Water Level: %s\n", formatWaterLevel(data)))
+		}
+		if level.HasChange {
+			result.WriteString(fmt.Sprintf("📏 Change: %+g cm since the previous reading\n", level.Change))
+		}
+		if zero, ok := data.GaugeZeroValue(); ok {
+			result.WriteString(fmt.Sprintf("📐 Gauge Zero: %v m above sea level\n", zero))
+			if elevation, ok := data.Elevation(); ok {
+				result.WriteString(fmt.Sprintf("⛰️ Water Surface: %v m above sea level\n", units.Round(elevation, 2)))
+			}
+		}
+		if data.WaterTemp != "" {
+			result.WriteString(fmt.Sprintf("🌡️ Water Temperature: %s °C\n", data.WaterTemp))
+		}
+		if _, ok := data.DischargeValue(); ok {
+			result.WriteString(fmt.Sprintf("🌊 Discharge: %s m³/s\n", data.Discharge))
+		}
+		if data.Tendency.IsKnown() {
+			result.WriteString(fmt.Sprintf("%s Tendency: %s\n", data.Tendency.Symbol(), data.Tendency.Label()))
+		}
+		if data.Source != "" {
+			source := data.Source
+			if info, ok := integration.LookupSource(data.Source); ok {
+				source += fmt.Sprintf(" (%s)", info.Authority)
+			}
+			result.WriteString(fmt.Sprintf("🏛️ Source: %s\n", source))
+		}
+		if data.URL != "" {
+			result.WriteString(fmt.Sprintf("🔗 Page: %s\n", data.URL))
+		}
+		result.WriteString(fmt.Sprintf("🕒 Last update: %s", data.Timestamp.Format("2006-01-02 15:04:05 MST")))
+		if data.Stale {
+			result.WriteString(" (outdated)")
+		}
+		result.WriteString("\n\n")
+	}
+	return result.String()
+}
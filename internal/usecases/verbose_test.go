@@ -0,0 +1,75 @@
+package usecases
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+	"github.com/abelzeko/water-bot/internal/integration"
+)
+
+// TestFormatRiverInfoVerbose verifies the verbose output has every field of a fully
+// populated reading, the compact one only its own subset
+func TestFormatRiverInfoVerbose(t *testing.T) {
+	repo := newTestRepository(t)
+	uc := NewRiverUseCaseWithSources(repo, nil, nil)
+
+	recorded := time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC)
+	full := entities.RiverData{
+		River:      "ДРИНА",
+		Station:    "Радаљ",
+		WaterLevel: "142",
+		WaterTemp:  "9.8",
+		Discharge:  "310",
+		Tendency:   entities.Rising,
+		Source:     integration.SourceHidmet,
+		URL:        "https://www.hidmet.gov.rs/latin/hidrologija/izvestajne/radalj.php",
+		GaugeZero:  "129.47",
+		Timestamp:  recorded,
+	}
+	previous := full
+	previous.WaterLevel = "135"
+	previous.Timestamp = recorded.Add(-time.Hour)
+	sparse := entities.RiverData{River: "ДРИНА", Station: "Фоча", WaterLevel: "88", Timestamp: recorded}
+	if err := repo.SaveRiverData([]entities.RiverData{previous, full, sparse}); err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+
+	levels, err := uc.GetStationLevels([]entities.RiverData{full, sparse})
+	if err != nil {
+		t.Fatalf("Failed to get station levels: %v", err)
+	}
+	verbose := uc.FormatRiverInfoVerbose(levels)
+	compact := uc.FormatRiverInfo([]entities.RiverData{full, sparse})
+
+	tests := []struct {
+		field     string
+		inCompact bool
+	}{
+		{"💧 Water Level: 142 cm (1.42 m)", true},
+		{"🌡️ Water Temperature: 9.8 °C", true},
+		{"🌊 Discharge: 310 m³/s", true},
+		{"⬆️ Tendency: rising", true},
+		{"🕒 Last update: 2025-04-18 06:00:00 UTC", true},
+		{"📏 Change: +7 cm since the previous reading", false},
+		{"📐 Gauge Zero: 129.47 m above sea level", false},
+		{"⛰️ Water Surface: 130.89 m above sea level", false},
+		{"🏛️ Source: hidmet (Republic Hydrometeorological Service of Serbia)", false},
+		{"🔗 Page: https://www.hidmet.gov.rs/latin/hidrologija/izvestajne/radalj.php", false},
+	}
+	for _, tt := range tests {
+		if !strings.Contains(verbose, tt.field) {
+			t.Errorf("Expected %q in the verbose output:\n%s", tt.field, verbose)
+		}
+		if strings.Contains(compact, tt.field) != tt.inCompact {
+			t.Errorf("Expected %q in the compact output to be %v:\n%s", tt.field, tt.inCompact, compact)
+		}
+	}
+
+	// Empty fields are skipped rather than shown blank
+	_, foca, _ := strings.Cut(verbose, "📍 Station: Фоча\n")
+	if foca != "💧 Water Level: 88 cm\n🕒 Last update: 2025-04-18 06:00:00 UTC\n\n" {
+		t.Errorf("Expected only the level and update time of a sparse reading, got:\n%s", foca)
+	}
+}
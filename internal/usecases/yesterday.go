@@ -0,0 +1,82 @@
+package usecases
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+	"github.com/abelzeko/water-bot/internal/units"
+)
+
+// comparisonPeriod is how far back /yesterday compares a station's level
+const comparisonPeriod = 24 * time.Hour
+
+// comparisonTolerance is how far from comparisonPeriod ago the nearest stored reading
+// may be and still count as yesterday's
+const comparisonTolerance = 3 * time.Hour
+
+// StationComparison is a station's latest reading next to its reading about a day earlier
+type StationComparison struct {
+	Current   entities.RiverData
+	Past      entities.RiverData // Valid when HasPast is set
+	HasPast   bool
+	Change    float64 // Change in cm since Past, valid when HasChange is set
+	HasChange bool
+}
+
+// CompareWithYesterday pairs each reading of a river with the station's stored reading
+// nearest to a day before it. Stations without a reading within comparisonTolerance of
+// that time are returned without one.
+func (uc *RiverUseCase) CompareWithYesterday(riverData []entities.RiverData) ([]StationComparison, error) {
+	comparisons := make([]StationComparison, 0, len(riverData))
+	for _, rd := range riverData {
+		comparison := StationComparison{Current: rd}
+
+		target := rd.Timestamp.Add(-comparisonPeriod)
+		past, found, err := uc.repo.GetReadingNear(rd.River, rd.Station, target)
+		if err != nil {
+			return nil, err
+		}
+		if found && past.Timestamp.Before(rd.Timestamp) && past.Timestamp.Sub(target).Abs() <= comparisonTolerance {
+			past.Timestamp = past.Timestamp.In(rd.Timestamp.Location())
+			comparison.Past = past
+			comparison.HasPast = true
+
+			current, currentOK := rd.LevelValue()
+			previous, previousOK := past.LevelValue()
+			if currentOK && previousOK {
+				comparison.Change = units.Round(current-previous, 1)
+				comparison.HasChange = true
+			}
+		}
+		comparisons = append(comparisons, comparison)
+	}
+	return comparisons, nil
+}
+
+// FormatComparison formats each station's level now and a day earlier with the change between them
+func (uc *RiverUseCase) FormatComparison(comparisons []StationComparison) string {
+	if len(comparisons) == 0 {
+		return "No information available for this river."
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("Changes since yesterday for river %s:\n\n", comparisons[0].Current.River))
+	for _, comparison := range comparisons {
+		current := comparison.Current
+		result.WriteString(fmt.Sprintf("📍 Station: %s\n", current.Station))
+		result.WriteString(fmt.Sprintf("💧 Now: %s cm (%s)\n", current.WaterLevel, current.Timestamp.Format("2006-01-02 15:04")))
+		if !comparison.HasPast {
+			result.WriteString("🕰️ 24h ago: no reading stored\n\n")
+			continue
+		}
+		past := comparison.Past
+		result.WriteString(fmt.Sprintf("🕰️ 24h ago: %s cm (%s)\n", past.WaterLevel, past.Timestamp.Format("2006-01-02 15:04")))
+		if comparison.HasChange {
+			result.WriteString(fmt.Sprintf("📏 Change: %+g cm\n", comparison.Change))
+		}
+		result.WriteString("\n")
+	}
+	return result.String()
+}
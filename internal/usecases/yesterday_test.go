@@ -0,0 +1,62 @@
+package usecases
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/abelzeko/water-bot/internal/entities"
+)
+
+// TestCompareWithYesterday verifies stations are compared with the reading nearest to
+// a day earlier, and stations without one are reported as such
+func TestCompareWithYesterday(t *testing.T) {
+	repo := newTestRepository(t)
+	uc := NewRiverUseCase(repo, nil, nil)
+
+	now := time.Date(2025, 4, 18, 6, 0, 0, 0, time.UTC)
+	err := repo.SaveRiverData([]entities.RiverData{
+		{River: "ДРИНА", Station: "Радаљ", WaterLevel: "130", Timestamp: now.Add(-25 * time.Hour)},
+		{River: "ДРИНА", Station: "Радаљ", WaterLevel: "135", Timestamp: now.Add(-12 * time.Hour)},
+		{River: "ДРИНА", Station: "Радаљ", WaterLevel: "142", Timestamp: now},
+		// Only recent readings, nothing close to a day ago
+		{River: "ДРИНА", Station: "Бајина Башта", WaterLevel: "87", Timestamp: now.Add(-6 * time.Hour)},
+		{River: "ДРИНА", Station: "Бајина Башта", WaterLevel: "88", Timestamp: now},
+	})
+	if err != nil {
+		t.Fatalf("Failed to save data: %v", err)
+	}
+
+	riverData, err := uc.GetRiverDataByName("ДРИНА")
+	if err != nil {
+		t.Fatalf("Failed to get river data: %v", err)
+	}
+	comparisons, err := uc.CompareWithYesterday(riverData)
+	if err != nil {
+		t.Fatalf("Failed to compare with yesterday: %v", err)
+	}
+
+	byStation := make(map[string]StationComparison)
+	for _, comparison := range comparisons {
+		byStation[comparison.Current.Station] = comparison
+	}
+	radalj := byStation["Радаљ"]
+	if !radalj.HasPast || radalj.Past.WaterLevel != "130" || !radalj.HasChange || radalj.Change != 12 {
+		t.Errorf("Expected Радаљ compared with 130 cm for +12 cm, got %+v", radalj)
+	}
+	if bajina := byStation["Бајина Башта"]; bajina.HasPast || bajina.HasChange {
+		t.Errorf("Expected no reading a day ago for Бајина Башта, got %+v", bajina)
+	}
+
+	text := uc.FormatComparison(comparisons)
+	for _, want := range []string{
+		"Changes since yesterday for river ДРИНА",
+		"🕰️ 24h ago: 130 cm (2025-04-17 05:00)",
+		"📏 Change: +12 cm",
+		"🕰️ 24h ago: no reading stored",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in:\n%s", want, text)
+		}
+	}
+}